@@ -32,11 +32,26 @@ struct RegisterRequest: Codable {
     let username: String
     let password: String
     let publicKey: String
+    let nonce: String
+    let signature: String
 
     enum CodingKeys: String, CodingKey {
         case username
         case password
         case publicKey = "public_key"
+        case nonce
+        case signature
+    }
+}
+
+/// A single-use nonce signed with the identity key to register
+struct RegistrationChallenge: Codable {
+    let nonce: String
+    let expiresAt: Date
+
+    enum CodingKeys: String, CodingKey {
+        case nonce
+        case expiresAt = "expires_at"
     }
 }
 
@@ -59,8 +59,18 @@ final class APIService: @unchecked Sendable {
 
     // MARK: - Auth Endpoints
 
-    func register(username: String, password: String, publicKey: String) async throws -> AuthResponse {
-        let request = RegisterRequest(username: username, password: password, publicKey: publicKey)
+    func registrationChallenge() async throws -> RegistrationChallenge {
+        try await post("/auth/challenge", body: EmptyRequest())
+    }
+
+    func register(username: String, password: String, publicKey: String, nonce: String, signature: String) async throws -> AuthResponse {
+        let request = RegisterRequest(
+            username: username,
+            password: password,
+            publicKey: publicKey,
+            nonce: nonce,
+            signature: signature
+        )
         return try await post("/auth/register", body: request)
     }
 
@@ -62,11 +62,21 @@ final class AuthManager: ObservableObject {
         // Store private key
         try crypto.storePrivateKey(privateKey)
 
+        // Prove we hold the private key by signing a fresh server nonce with it
+        let challenge = try await api.registrationChallenge()
+        let signature = try crypto.signRegistration(
+            nonce: challenge.nonce,
+            username: username,
+            privateKey: privateKey
+        )
+
         // Register with server
         let response = try await api.register(
             username: username,
             password: password,
-            publicKey: publicKeyBase64
+            publicKey: publicKeyBase64,
+            nonce: challenge.nonce,
+            signature: signature
         )
 
         try saveAuthResponse(response)
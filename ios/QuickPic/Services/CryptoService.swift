@@ -51,6 +51,19 @@ final class CryptoService: Sendable {
         return try Curve25519.KeyAgreement.PrivateKey(rawRepresentation: data)
     }
 
+    // MARK: - Registration
+
+    /// Sign a registration challenge, proving possession of the identity key.
+    /// Must match the server's RegistrationMessage byte for byte.
+    func signRegistration(
+        nonce: String,
+        username: String,
+        privateKey: Curve25519.KeyAgreement.PrivateKey
+    ) throws -> String {
+        let message = Data("quickpic-register\n\(nonce)\n\(username)".utf8)
+        return try XEdDSA.sign(message, with: privateKey).base64EncodedString()
+    }
+
     // MARK: - Encryption
 
     /// Encrypt message content for a recipient
@@ -89,9 +102,9 @@ final class CryptoService: Sendable {
         result.append(encryptedSymmetricKey.combined)
         result.append(encryptedContent)
 
-        // 6. Sign the encrypted data
-        let signingKey = try Curve25519.Signing.PrivateKey(rawRepresentation: senderPrivateKey.rawRepresentation)
-        let signature = try signingKey.signature(for: result)
+        // 6. Sign the encrypted data with the identity key, so recipients and
+        // the server can check it against the sender's X25519 public key
+        let signature = try XEdDSA.sign(result, with: senderPrivateKey)
 
         return (result, signature.base64EncodedString())
     }
@@ -105,12 +118,13 @@ final class CryptoService: Sendable {
         senderPublicKey: Curve25519.KeyAgreement.PublicKey,
         recipientPrivateKey: Curve25519.KeyAgreement.PrivateKey
     ) throws -> Data {
-        // TODO: Signature verification is currently skipped because X25519 and Ed25519
-        // public keys are not interchangeable. To fix properly, we need to store
-        // Ed25519 public keys separately on the server for signature verification.
-        // For now, we rely on the encryption itself for authenticity (AEAD).
+        // 1. Verify the sender's signature over the encrypted data
+        guard let signatureData = Data(base64Encoded: signature),
+              XEdDSA.isValidSignature(signatureData, for: encryptedData, publicKey: senderPublicKey) else {
+            throw CryptoError.verificationFailed
+        }
 
-        // 1. Extract encrypted symmetric key length
+        // 2. Extract encrypted symmetric key length
         guard encryptedData.count >= 4 else {
             throw CryptoError.decryptionFailed
         }
@@ -122,11 +136,11 @@ final class CryptoService: Sendable {
             throw CryptoError.decryptionFailed
         }
 
-        // 2. Extract encrypted symmetric key and encrypted content
+        // 3. Extract encrypted symmetric key and encrypted content
         let encryptedKeyData = encryptedData.subdata(in: 4..<(4 + Int(keyLength)))
         let encryptedContent = encryptedData.subdata(in: (4 + Int(keyLength))..<encryptedData.count)
 
-        // 3. Derive shared secret and decrypt symmetric key
+        // 4. Derive shared secret and decrypt symmetric key
         let sharedSecret = try recipientPrivateKey.sharedSecretFromKeyAgreement(with: senderPublicKey)
         let derivedKey = sharedSecret.hkdfDerivedSymmetricKey(
             using: SHA256.self,
@@ -139,11 +153,11 @@ final class CryptoService: Sendable {
         let symmetricKeyData = try ChaChaPoly.open(encryptedKeyBox, using: derivedKey)
         let symmetricKey = SymmetricKey(data: symmetricKeyData)
 
-        // 4. Decrypt content
+        // 5. Decrypt content
         let contentBox = try ChaChaPoly.SealedBox(combined: encryptedContent)
         let compressedData = try ChaChaPoly.open(contentBox, using: symmetricKey)
 
-        // 5. Decompress
+        // 6. Decompress
         return try decompress(compressedData)
     }
 
@@ -0,0 +1,327 @@
+//
+//  XEdDSA.swift
+//  QuickPic
+//
+
+import Foundation
+import CryptoKit
+import Security
+
+/// XEdDSA signatures made with X25519 identity keys
+/// (https://signal.org/docs/specifications/xeddsa/).
+///
+/// The server checks registration proofs and message signatures against the
+/// X25519 key a user uploaded, so they must be signed with that same key.
+/// CryptoKit only signs with separate Ed25519 keys, so signing does the
+/// Edwards arithmetic itself, following TweetNaCl. Verification maps the
+/// X25519 key to its Edwards form and lets CryptoKit check the signature.
+enum XEdDSA {
+    static let signatureSize = 64
+
+    /// Sign message with an X25519 private key
+    static func sign(_ message: Data, with privateKey: Curve25519.KeyAgreement.PrivateKey) throws -> Data {
+        var a = [UInt8](privateKey.rawRepresentation)
+        a[0] &= 248
+        a[31] &= 127
+        a[31] |= 64
+
+        // The Edwards public key must have sign bit 0, negate the scalar if needed
+        var publicKey = packPoint(scalarBase(a))
+        var scalar = reduce(a)
+        if publicKey[31] & 0x80 != 0 {
+            scalar = negate(scalar)
+            publicKey[31] &= 0x7F
+        }
+
+        var random = [UInt8](repeating: 0, count: 64)
+        guard SecRandomCopyBytes(kSecRandomDefault, random.count, &random) == errSecSuccess else {
+            throw CryptoError.signingFailed
+        }
+
+        // r = hash1(a || M || Z) where hash1 prefixes 0xFE followed by 31 0xFF bytes
+        var nonceHash = SHA512()
+        nonceHash.update(data: [0xFE] + [UInt8](repeating: 0xFF, count: 31))
+        nonceHash.update(data: scalar)
+        nonceHash.update(data: message)
+        nonceHash.update(data: random)
+        let r = reduce([UInt8](nonceHash.finalize()))
+
+        let R = packPoint(scalarBase(r))
+
+        var challengeHash = SHA512()
+        challengeHash.update(data: R)
+        challengeHash.update(data: publicKey)
+        challengeHash.update(data: message)
+        let k = reduce([UInt8](challengeHash.finalize()))
+
+        // s = r + k * a mod L
+        var x = [Int64](repeating: 0, count: 64)
+        for i in 0..<32 {
+            x[i] = Int64(r[i])
+        }
+        for i in 0..<32 {
+            for j in 0..<32 {
+                x[i + j] += Int64(k[i]) * Int64(scalar[j])
+            }
+        }
+        let s = modL(&x)
+
+        return Data(R + s)
+    }
+
+    /// Check an XEdDSA signature against an X25519 public key
+    static func isValidSignature(_ signature: Data, for message: Data, publicKey: Curve25519.KeyAgreement.PublicKey) -> Bool {
+        guard signature.count == signatureSize,
+              let edwardsKey = edwardsPublicKey(publicKey),
+              let verifyingKey = try? Curve25519.Signing.PublicKey(rawRepresentation: edwardsKey) else {
+            return false
+        }
+        return verifyingKey.isValidSignature(signature, for: message)
+    }
+
+    /// Map a Montgomery u-coordinate to the Edwards point with
+    /// y = (u - 1) / (u + 1) and sign bit 0
+    private static func edwardsPublicKey(_ publicKey: Curve25519.KeyAgreement.PublicKey) -> Data? {
+        let u = unpack([UInt8](publicKey.rawRepresentation))
+        let denominator = add(u, one)
+        if pack(denominator).allSatisfy({ $0 == 0 }) {
+            return nil
+        }
+        return Data(pack(multiply(subtract(u, one), invert(denominator))))
+    }
+}
+
+// MARK: - Field Arithmetic
+
+/// Elements of GF(2^255 - 19) as 16 signed limbs of 16 bits
+private typealias FieldElement = [Int64]
+
+private let zero: FieldElement = [Int64](repeating: 0, count: 16)
+private let one: FieldElement = [1] + [Int64](repeating: 0, count: 15)
+
+private let d2: FieldElement = [
+    0xf159, 0x26b2, 0x9b94, 0xebd6, 0xb156, 0x8283, 0x149a, 0x00e0,
+    0xd130, 0xeef3, 0x80f2, 0x198e, 0xfce7, 0x56df, 0xd9dc, 0x2406,
+]
+
+private let baseX: FieldElement = [
+    0xd51a, 0x8f25, 0x2d60, 0xc956, 0xa7b2, 0x9525, 0xc760, 0x692c,
+    0xdc5c, 0xfdd6, 0xe231, 0xc0a4, 0x53fe, 0xcd6e, 0x36d3, 0x2169,
+]
+
+private let baseY: FieldElement = [
+    0x6658, 0x6666, 0x6666, 0x6666, 0x6666, 0x6666, 0x6666, 0x6666,
+    0x6666, 0x6666, 0x6666, 0x6666, 0x6666, 0x6666, 0x6666, 0x6666,
+]
+
+private func propagateCarries(_ o: inout FieldElement) {
+    for i in 0..<16 {
+        o[i] += 1 << 16
+        let c = o[i] >> 16
+        if i < 15 {
+            o[i + 1] += c - 1
+        } else {
+            o[0] += 38 * (c - 1)
+        }
+        o[i] -= c << 16
+    }
+}
+
+/// Swap p and q when b is 1, in constant time
+private func conditionalSwap(_ p: inout FieldElement, _ q: inout FieldElement, _ b: Int64) {
+    let c = ~(b - 1)
+    for i in 0..<16 {
+        let t = c & (p[i] ^ q[i])
+        p[i] ^= t
+        q[i] ^= t
+    }
+}
+
+private func pack(_ n: FieldElement) -> [UInt8] {
+    var t = n
+    var m = zero
+    propagateCarries(&t)
+    propagateCarries(&t)
+    propagateCarries(&t)
+    for _ in 0..<2 {
+        m[0] = t[0] - 0xffed
+        for i in 1..<15 {
+            m[i] = t[i] - 0xffff - ((m[i - 1] >> 16) & 1)
+            m[i - 1] &= 0xffff
+        }
+        m[15] = t[15] - 0x7fff - ((m[14] >> 16) & 1)
+        let b = (m[15] >> 16) & 1
+        m[14] &= 0xffff
+        conditionalSwap(&t, &m, 1 - b)
+    }
+
+    var o = [UInt8](repeating: 0, count: 32)
+    for i in 0..<16 {
+        o[2 * i] = UInt8(truncatingIfNeeded: t[i])
+        o[2 * i + 1] = UInt8(truncatingIfNeeded: t[i] >> 8)
+    }
+    return o
+}
+
+private func unpack(_ n: [UInt8]) -> FieldElement {
+    var o = zero
+    for i in 0..<16 {
+        o[i] = Int64(n[2 * i]) + (Int64(n[2 * i + 1]) << 8)
+    }
+    o[15] &= 0x7fff
+    return o
+}
+
+private func add(_ a: FieldElement, _ b: FieldElement) -> FieldElement {
+    (0..<16).map { a[$0] + b[$0] }
+}
+
+private func subtract(_ a: FieldElement, _ b: FieldElement) -> FieldElement {
+    (0..<16).map { a[$0] - b[$0] }
+}
+
+private func multiply(_ a: FieldElement, _ b: FieldElement) -> FieldElement {
+    var t = [Int64](repeating: 0, count: 31)
+    for i in 0..<16 {
+        for j in 0..<16 {
+            t[i + j] += a[i] * b[j]
+        }
+    }
+    for i in 0..<15 {
+        t[i] += 38 * t[i + 16]
+    }
+    var o = Array(t[0..<16])
+    propagateCarries(&o)
+    propagateCarries(&o)
+    return o
+}
+
+/// Invert by raising to p - 2
+private func invert(_ i: FieldElement) -> FieldElement {
+    var c = i
+    for a in stride(from: 253, through: 0, by: -1) {
+        c = multiply(c, c)
+        if a != 2 && a != 4 {
+            c = multiply(c, i)
+        }
+    }
+    return c
+}
+
+// MARK: - Edwards Points
+
+/// A point in extended coordinates (X, Y, Z, T)
+private typealias Point = [FieldElement]
+
+private func addPoints(_ p: inout Point, _ q: Point) {
+    let a = multiply(subtract(p[1], p[0]), subtract(q[1], q[0]))
+    let b = multiply(add(p[0], p[1]), add(q[0], q[1]))
+    let c = multiply(multiply(p[3], q[3]), d2)
+    var d = multiply(p[2], q[2])
+    d = add(d, d)
+    let e = subtract(b, a)
+    let f = subtract(d, c)
+    let g = add(d, c)
+    let h = add(b, a)
+
+    p[0] = multiply(e, f)
+    p[1] = multiply(h, g)
+    p[2] = multiply(g, f)
+    p[3] = multiply(e, h)
+}
+
+private func conditionalSwapPoints(_ p: inout Point, _ q: inout Point, _ b: Int64) {
+    for i in 0..<4 {
+        conditionalSwap(&p[i], &q[i], b)
+    }
+}
+
+private func packPoint(_ p: Point) -> [UInt8] {
+    let zInverse = invert(p[2])
+    let x = multiply(p[0], zInverse)
+    let y = multiply(p[1], zInverse)
+    var r = pack(y)
+    r[31] ^= (pack(x)[0] & 1) << 7
+    return r
+}
+
+/// Multiply the base point by a little-endian scalar
+private func scalarBase(_ s: [UInt8]) -> Point {
+    var q: Point = [baseX, baseY, one, multiply(baseX, baseY)]
+    var p: Point = [zero, one, one, zero]
+    for i in stride(from: 255, through: 0, by: -1) {
+        let b = Int64((s[i / 8] >> (i & 7)) & 1)
+        conditionalSwapPoints(&p, &q, b)
+        addPoints(&q, p)
+        let doubled = p
+        addPoints(&p, doubled)
+        conditionalSwapPoints(&p, &q, b)
+    }
+    return p
+}
+
+// MARK: - Scalars
+
+/// The group order, little-endian
+private let groupOrder: [Int64] = [
+    0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58, 0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+    0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10,
+]
+
+/// Reduce a 64-limb little-endian number mod L into 32 bytes
+private func modL(_ x: inout [Int64]) -> [UInt8] {
+    for i in stride(from: 63, through: 32, by: -1) {
+        var carry: Int64 = 0
+        var j = i - 32
+        while j < i - 12 {
+            x[j] += carry - 16 * x[i] * groupOrder[j - (i - 32)]
+            carry = (x[j] + 128) >> 8
+            x[j] -= carry << 8
+            j += 1
+        }
+        x[j] += carry
+        x[i] = 0
+    }
+
+    var carry: Int64 = 0
+    for j in 0..<32 {
+        x[j] += carry - (x[31] >> 4) * groupOrder[j]
+        carry = x[j] >> 8
+        x[j] &= 255
+    }
+    for j in 0..<32 {
+        x[j] -= carry * groupOrder[j]
+    }
+
+    var r = [UInt8](repeating: 0, count: 32)
+    for i in 0..<32 {
+        x[i + 1] += x[i] >> 8
+        r[i] = UInt8(truncatingIfNeeded: x[i])
+    }
+    return r
+}
+
+/// Reduce up to 64 little-endian bytes mod L
+private func reduce(_ bytes: [UInt8]) -> [UInt8] {
+    var x = [Int64](repeating: 0, count: 64)
+    for (i, byte) in bytes.enumerated() {
+        x[i] = Int64(byte)
+    }
+    return modL(&x)
+}
+
+/// L - s for a reduced scalar s
+private func negate(_ s: [UInt8]) -> [UInt8] {
+    var r = [UInt8](repeating: 0, count: 32)
+    var borrow: Int64 = 0
+    for i in 0..<32 {
+        var v = groupOrder[i] - Int64(s[i]) - borrow
+        borrow = 0
+        if v < 0 {
+            v += 256
+            borrow = 1
+        }
+        r[i] = UInt8(v)
+    }
+    return r
+}
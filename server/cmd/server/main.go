@@ -1,21 +1,76 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/quickpic/server/internal/api"
+	"github.com/quickpic/server/internal/api/middleware"
 	"github.com/quickpic/server/internal/backend"
 	"github.com/quickpic/server/internal/services"
+	"github.com/quickpic/server/internal/username"
 )
 
+const defaultJWTSecret = "development-secret-change-in-production"
+
+// friendRequestExpiryInterval is how often expired friend requests are swept
+const friendRequestExpiryInterval = time.Hour
+
+// streakResetInterval is how often broken streaks are reset
+const streakResetInterval = 24 * time.Hour
+
+// challengeExpiryInterval is how often unused registration nonces are swept
+const challengeExpiryInterval = time.Minute
+
 func main() {
 	// Load configuration
 	backendType := getEnv("BACKEND_TYPE", "sqlite")
-	jwtSecret := getEnv("JWT_SECRET", "development-secret-change-in-production")
+	jwtSecret := getEnv("JWT_SECRET", defaultJWTSecret)
 	port := getEnv("PORT", "8080")
+	devMode := getEnv("DEV_MODE", "false") == "true"
+
+	// Argon2id cost for new password hashes; existing hashes are upgraded on login
+	defaultPasswordParams := services.DefaultPasswordParams()
+	passwordParams, err := services.NewPasswordParams(
+		getEnvInt("ARGON2_TIME", int(defaultPasswordParams.Time)),
+		getEnvInt("ARGON2_MEMORY_KB", int(defaultPasswordParams.Memory)),
+		getEnvInt("ARGON2_THREADS", int(defaultPasswordParams.Threads)),
+	)
+	if err != nil {
+		log.Fatalf("Invalid Argon2id parameters: %v", err)
+	}
+
+	// Wait before a rejected friend request may be sent again
+	requestCooldown := time.Duration(getEnvInt("FRIEND_REQUEST_COOLDOWN_HOURS", int(services.DefaultFriendRequestCooldown/time.Hour))) * time.Hour
+	// How long a friend request waits for an answer before it expires
+	requestTTL := time.Duration(getEnvInt("FRIEND_REQUEST_TTL_HOURS", int(services.DefaultFriendRequestTTL/time.Hour))) * time.Hour
+
+	// Usernames that can't be registered, on top of the built-in list
+	usernamePolicy := username.NewPolicy(strings.Split(getEnv("RESERVED_USERNAMES", ""), ",")...)
+	// How long an old username stays held for its owner after a rename
+	usernameReservation := time.Duration(getEnvInt("USERNAME_RESERVATION_DAYS", int(services.DefaultUsernameReservation/(24*time.Hour)))) * 24 * time.Hour
+
+	// Registration challenges each client IP may request per minute
+	challengeLimiter := middleware.NewRateLimiter(getEnvInt("CHALLENGE_RATE_LIMIT", 10), time.Minute)
+
+	// Users whose access tokens carry the admin role
+	var adminIDs []uuid.UUID
+	for _, value := range strings.Split(getEnv("ADMIN_USER_IDS", ""), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			log.Fatalf("Invalid user ID in ADMIN_USER_IDS: %q", value)
+		}
+		adminIDs = append(adminIDs, id)
+	}
 
 	// Build backend configuration based on type
 	var cfg backend.Config
@@ -29,6 +84,7 @@ func main() {
 		rpcURL := getEnv("BLOCKCHAIN_RPC_URL", "http://localhost:8545")
 		privateKey := getEnv("BLOCKCHAIN_PRIVATE_KEY", "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
 		contractAddress := getEnv("BLOCKCHAIN_CONTRACT_ADDRESS", "")
+		offchainDBPath := getEnv("BLOCKCHAIN_OFFCHAIN_DB_PATH", "./quickpic-offchain.db")
 
 		if contractAddress == "" {
 			log.Fatal("BLOCKCHAIN_CONTRACT_ADDRESS is required for blockchain backend")
@@ -39,6 +95,7 @@ func main() {
 			BlockchainRPCURL:          rpcURL,
 			BlockchainPrivateKey:      privateKey,
 			BlockchainContractAddress: contractAddress,
+			BlockchainOffchainDBPath:  offchainDBPath,
 		}
 		log.Printf("Using blockchain backend: %s (contract: %s)", rpcURL, contractAddress)
 
@@ -46,6 +103,29 @@ func main() {
 		log.Fatalf("Unknown backend type: %s (use 'sqlite' or 'blockchain')", backendType)
 	}
 
+	// Access token signing keys: asymmetric keys from files when configured,
+	// otherwise HS256 with JWT_SECRET (the default secret is dev-only)
+	var tokenKeys *services.TokenKeys
+	if signingKeyFile := getEnv("JWT_SIGNING_KEY_FILE", ""); signingKeyFile != "" {
+		var verifyKeyFiles []string
+		for _, file := range strings.Split(getEnv("JWT_VERIFY_KEY_FILES", ""), ",") {
+			if file = strings.TrimSpace(file); file != "" {
+				verifyKeyFiles = append(verifyKeyFiles, file)
+			}
+		}
+		keys, err := services.LoadTokenKeys(signingKeyFile, verifyKeyFiles)
+		if err != nil {
+			log.Fatalf("Failed to load JWT keys: %v", err)
+		}
+		tokenKeys = keys
+		log.Printf("Signing access tokens with %s (%d verification key(s))", signingKeyFile, len(keys.JWKS().Keys))
+	} else {
+		if jwtSecret == defaultJWTSecret && !devMode {
+			log.Fatal("Refusing to start with the default JWT_SECRET; set JWT_SIGNING_KEY_FILE or JWT_SECRET, or DEV_MODE=true for local development")
+		}
+		tokenKeys = services.NewHMACTokenKeys(jwtSecret)
+	}
+
 	// Initialize backend
 	result, err := backend.New(cfg)
 	if err != nil {
@@ -54,16 +134,53 @@ func main() {
 	defer func() { _ = result.Backend.Close() }()
 
 	// Initialize services
-	authService := services.NewAuthService(result.Repos.Users, jwtSecret)
-	userService := services.NewUserService(result.Repos.Users)
-	friendService := services.NewFriendService(result.Repos.Friends, result.Repos.Users)
-	messageService := services.NewMessageService(result.Repos.Messages, result.Repos.Friends)
+	notificationService := services.NewNotificationService(result.Repos.Notifications)
+	streakService := services.NewStreakService(result.Repos.Streaks, services.DefaultStreakDay)
+	interactionService := services.NewInteractionService(result.Repos.Interactions)
+	authService := services.NewAuthService(result.Repos.Users, result.Repos.Friends, result.Repos.Audit, notificationService, tokenKeys, passwordParams, usernamePolicy)
+	authService.SetAdmins(adminIDs)
+	userService := services.NewUserService(result.Repos.Users, result.Repos.Friends, result.Repos.Devices, result.Repos.Blocks, result.Repos.Profiles, result.Repos.Audit, notificationService, usernamePolicy, usernameReservation)
+	friendService := services.NewFriendService(result.Repos.Friends, result.Repos.Users, result.Repos.Blocks, streakService, interactionService, requestCooldown, requestTTL)
+	messageService := services.NewMessageService(result.Repos.Messages, result.Repos.Friends, result.Repos.Devices, result.Repos.Blocks, streakService, interactionService)
+	prekeyService := services.NewPreKeyService(result.Repos.PreKeys, result.Repos.Users, result.Repos.Friends)
+	deviceService := services.NewDeviceService(result.Repos.Devices, result.Repos.Users, result.Repos.Friends, result.Repos.Audit, notificationService)
+	inviteService := services.NewInviteService(result.Repos.Invites, result.Repos.Users, result.Repos.Blocks, tokenKeys)
+	reportService := services.NewReportService(result.Repos.Reports, result.Repos.Users, result.Repos.Messages)
+	adminService := services.NewAdminService(result.Repos.Users, result.Repos.Stats, result.Repos.Audit)
+
+	// Background jobs
+	go runPeriodically("friend request expiry", friendRequestExpiryInterval, friendService.ExpireFriendRequests)
+	go runPeriodically("streak reset", streakResetInterval, streakService.ResetBrokenStreaks)
+	go runPeriodically("challenge expiry", challengeExpiryInterval, authService.ExpireChallenges)
 
 	// Initialize router
 	router := gin.Default()
 
+	// Only trust X-Forwarded-For from known proxies, so clients can't pick
+	// the IP they are rate limited by
+	var trustedProxies []string
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Setup routes
-	api.SetupRoutes(router, authService, userService, friendService, messageService, result.Repos.Users)
+	api.SetupRoutes(router, api.Services{
+		Auth:          authService,
+		Users:         userService,
+		Friends:       friendService,
+		Messages:      messageService,
+		Notifications: notificationService,
+		PreKeys:       prekeyService,
+		Devices:       deviceService,
+		Invites:       inviteService,
+		Reports:       reportService,
+		Admin:         adminService,
+	}, challengeLimiter, result.Repos.Users)
 
 	// Start server
 	log.Printf("Starting QuickPic server on port %s", port)
@@ -73,9 +190,37 @@ func main() {
 	}
 }
 
+// runPeriodically runs job now and then every interval, logging failures
+func runPeriodically(name string, interval time.Duration, job func(context.Context) (int64, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		count, err := job(context.Background())
+		if err != nil {
+			log.Printf("%s failed: %v", name, err)
+		} else if count > 0 {
+			log.Printf("%s: %d updated", name, count)
+		}
+		<-ticker.C
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid value for %s: %q", key, value)
+	}
+	return n
+}
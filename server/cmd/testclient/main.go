@@ -16,6 +16,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/quickpic/server/internal/keys"
+	"github.com/quickpic/server/internal/services"
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/curve25519"
 	"golang.org/x/crypto/hkdf"
@@ -70,6 +72,9 @@ func main() {
 		fmt.Println("  receive <username>                         - Receive and decrypt messages")
 		fmt.Println("  accept <username> <request_id>             - Accept a friend request")
 		fmt.Println("  pending <username>                         - List pending friend requests")
+		fmt.Println("  prekeys <username> [count]                 - Upload a signed prekey and one-time prekeys")
+		fmt.Println("  bundle <username> <target>                 - Claim and verify a friend's prekey bundle")
+		fmt.Println("  safety <username> <target>                 - Check a friend's safety number against the local keys")
 		os.Exit(1)
 	}
 
@@ -190,6 +195,46 @@ func main() {
 			os.Exit(1)
 		}
 
+	case "prekeys":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: testclient prekeys <username> [count]")
+			os.Exit(1)
+		}
+		username := os.Args[2]
+		count := 20
+		if len(os.Args) > 3 {
+			if _, err := fmt.Sscanf(os.Args[3], "%d", &count); err != nil {
+				fmt.Println("Usage: testclient prekeys <username> [count]")
+				os.Exit(1)
+			}
+		}
+		if err := runPreKeys(username, count); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "bundle":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: testclient bundle <username> <target>")
+			os.Exit(1)
+		}
+		username := os.Args[2]
+		target := os.Args[3]
+		if err := runBundle(username, target); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "safety":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: testclient safety <username> <target>")
+			os.Exit(1)
+		}
+		if err := runSafety(os.Args[2], os.Args[3]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		os.Exit(1)
@@ -539,10 +584,22 @@ func (c *TestClient) generateKeyPair() error {
 func (c *TestClient) register() error {
 	publicKeyB64 := base64.StdEncoding.EncodeToString(c.publicKey[:])
 
+	// Prove we hold the private key by signing a server-issued nonce
+	nonce, err := c.registrationChallenge()
+	if err != nil {
+		return fmt.Errorf("failed to get challenge: %w", err)
+	}
+	signature, err := keys.Sign(c.privateKey, services.RegistrationMessage(nonce, c.username))
+	if err != nil {
+		return fmt.Errorf("failed to sign challenge: %w", err)
+	}
+
 	reqBody := map[string]string{
 		"username":   c.username,
 		"password":   "testpassword123",
 		"public_key": publicKeyB64,
+		"nonce":      nonce,
+		"signature":  base64.StdEncoding.EncodeToString(signature),
 	}
 	body, _ := json.Marshal(reqBody)
 
@@ -566,6 +623,27 @@ func (c *TestClient) register() error {
 	return nil
 }
 
+func (c *TestClient) registrationChallenge() (string, error) {
+	resp, err := c.httpClient.Post(baseURL+"/auth/challenge", "application/json", nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("challenge failed (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var challenge struct {
+		Nonce string `json:"nonce"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&challenge); err != nil {
+		return "", err
+	}
+	return challenge.Nonce, nil
+}
+
 func (c *TestClient) login() error {
 	reqBody := map[string]string{
 		"username": c.username,
@@ -908,6 +986,250 @@ func runDebug() error {
 	return nil
 }
 
+// Prekeys (X3DH)
+
+type oneTimePreKey struct {
+	KeyID     uint32 `json:"key_id"`
+	PublicKey string `json:"public_key"`
+}
+
+type signedPreKey struct {
+	KeyID              uint32 `json:"key_id"`
+	PublicKey          string `json:"public_key"`
+	Signature          string `json:"signature"`
+	IdentityKeyVersion int    `json:"identity_key_version,omitempty"`
+}
+
+type preKeyBundle struct {
+	Username           string         `json:"username"`
+	IdentityKey        string         `json:"identity_key"`
+	IdentityKeyVersion int            `json:"identity_key_version"`
+	SignedPreKey       signedPreKey   `json:"signed_prekey"`
+	OneTimePreKey      *oneTimePreKey `json:"one_time_prekey"`
+}
+
+// savedPreKeys holds the private halves of uploaded prekeys, keyed by key ID
+type savedPreKeys struct {
+	SignedPreKeyID uint32            `json:"signed_prekey_id"`
+	SignedPreKey   string            `json:"signed_prekey"`
+	OneTimePreKeys map[uint32]string `json:"one_time_prekeys"`
+}
+
+func runPreKeys(username string, count int) error {
+	client := &TestClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		username:   username,
+	}
+
+	if err := client.loadCredentials(); err != nil {
+		return fmt.Errorf("failed to load credentials (did you run register first?): %w", err)
+	}
+
+	saved := savedPreKeys{SignedPreKeyID: 1, OneTimePreKeys: make(map[uint32]string)}
+
+	// Signed prekey: the identity key signs the prekey's raw public bytes
+	spkPriv, spkPub, err := newX25519KeyPair()
+	if err != nil {
+		return err
+	}
+	sig, err := keys.Sign(client.privateKey, spkPub[:])
+	if err != nil {
+		return fmt.Errorf("failed to sign prekey: %w", err)
+	}
+	saved.SignedPreKey = base64.StdEncoding.EncodeToString(spkPriv[:])
+
+	upload := struct {
+		SignedPreKey   signedPreKey    `json:"signed_prekey"`
+		OneTimePreKeys []oneTimePreKey `json:"one_time_prekeys"`
+	}{
+		SignedPreKey: signedPreKey{
+			KeyID:     saved.SignedPreKeyID,
+			PublicKey: base64.StdEncoding.EncodeToString(spkPub[:]),
+			Signature: base64.StdEncoding.EncodeToString(sig),
+		},
+	}
+
+	for i := 1; i <= count; i++ {
+		priv, pub, err := newX25519KeyPair()
+		if err != nil {
+			return err
+		}
+		upload.OneTimePreKeys = append(upload.OneTimePreKeys, oneTimePreKey{
+			KeyID:     uint32(i),
+			PublicKey: base64.StdEncoding.EncodeToString(pub[:]),
+		})
+		saved.OneTimePreKeys[uint32(i)] = base64.StdEncoding.EncodeToString(priv[:])
+	}
+
+	body, _ := json.Marshal(upload)
+	req, _ := http.NewRequest("PUT", baseURL+"/keys/prekeys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+client.accessToken)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("prekey upload failed (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(username+".prekeys.json", data, 0600); err != nil {
+		return fmt.Errorf("failed to save prekeys: %w", err)
+	}
+
+	fmt.Printf("Uploaded signed prekey and %d one-time prekeys\n", count)
+	fmt.Printf("Server status: %s\n", string(respBody))
+	fmt.Printf("Private prekeys saved to %s.prekeys.json\n", username)
+
+	return nil
+}
+
+func runBundle(username, target string) error {
+	client := &TestClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		username:   username,
+	}
+
+	if err := client.loadCredentials(); err != nil {
+		return fmt.Errorf("failed to load credentials (did you run register first?): %w", err)
+	}
+
+	req, _ := http.NewRequest("POST", baseURL+"/users/"+target+"/prekey-bundle", nil)
+	req.Header.Set("Authorization", "Bearer "+client.accessToken)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("claim bundle failed (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var bundle preKeyBundle
+	if err := json.NewDecoder(resp.Body).Decode(&bundle); err != nil {
+		return err
+	}
+
+	fmt.Printf("Bundle for %s (identity key v%d)\n", bundle.Username, bundle.IdentityKeyVersion)
+	fmt.Printf("  Identity key:   %s\n", bundle.IdentityKey)
+	fmt.Printf("  Signed prekey:  #%d %s\n", bundle.SignedPreKey.KeyID, bundle.SignedPreKey.PublicKey)
+	if bundle.OneTimePreKey != nil {
+		fmt.Printf("  One-time prekey: #%d %s\n", bundle.OneTimePreKey.KeyID, bundle.OneTimePreKey.PublicKey)
+	} else {
+		fmt.Println("  One-time prekey: none left")
+	}
+
+	identityKey, err := keys.ParsePublicKey(bundle.IdentityKey)
+	if err != nil {
+		return fmt.Errorf("invalid identity key: %w", err)
+	}
+	spk, err := base64.StdEncoding.DecodeString(bundle.SignedPreKey.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid signed prekey: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(bundle.SignedPreKey.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signed prekey signature: %w", err)
+	}
+	if !keys.Verify(identityKey, spk, sig) {
+		return fmt.Errorf("signed prekey signature does NOT verify")
+	}
+	fmt.Println("Signed prekey signature verified with identity key")
+
+	return nil
+}
+
+func runSafety(username, target string) error {
+	client := &TestClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		username:   username,
+	}
+
+	if err := client.loadCredentials(); err != nil {
+		return fmt.Errorf("failed to load credentials (did you run register first?): %w", err)
+	}
+
+	var self, friend struct {
+		ID        string `json:"id"`
+		PublicKey string `json:"public_key"`
+	}
+	var server struct {
+		SafetyNumber string `json:"safety_number"`
+		KeyVersion   int    `json:"key_version"`
+		Verified     bool   `json:"verified"`
+	}
+	for path, out := range map[string]interface{}{
+		"/users/" + username:                    &self,
+		"/users/" + target:                      &friend,
+		"/friends/" + target + "/safety-number": &server,
+	} {
+		if err := client.getJSON(path, out); err != nil {
+			return err
+		}
+	}
+
+	if self.PublicKey != base64.StdEncoding.EncodeToString(client.publicKey[:]) {
+		return fmt.Errorf("server has a different public key for %s than the local one", username)
+	}
+	friendKey, err := keys.ParsePublicKey(friend.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key for %s: %w", target, err)
+	}
+
+	local := keys.SafetyNumber(self.ID, client.publicKey, friend.ID, friendKey)
+	for i := 0; i < len(local); i += 5 {
+		fmt.Print(local[i:i+5], " ")
+		if i%30 == 25 {
+			fmt.Println()
+		}
+	}
+	fmt.Printf("%s key v%d, verified: %v\n", target, server.KeyVersion, server.Verified)
+
+	if local != server.SafetyNumber {
+		return fmt.Errorf("server safety number does NOT match the local computation")
+	}
+	fmt.Println("Server safety number matches the local computation")
+
+	return nil
+}
+
+func (c *TestClient) getJSON(path string, out interface{}) error {
+	req, _ := http.NewRequest("GET", baseURL+path, nil)
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GET %s failed (%d): %s", path, resp.StatusCode, string(respBody))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func newX25519KeyPair() ([32]byte, [32]byte, error) {
+	var priv [32]byte
+	if _, err := rand.Read(priv[:]); err != nil {
+		return priv, priv, err
+	}
+	return priv, keys.PublicKey(priv), nil
+}
+
 // Credentials file handling
 type savedCredentials struct {
 	Username   string `json:"username"`
@@ -3,6 +3,7 @@ module github.com/quickpic/server
 go 1.24.0
 
 require (
+	filippo.io/edwards25519 v1.1.0
 	github.com/ethereum/go-ethereum v1.16.7
 	github.com/gin-gonic/gin v1.9.1
 	github.com/golang-jwt/jwt/v5 v5.2.0
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/services"
+)
+
+type AdminHandler struct {
+	adminService  *services.AdminService
+	reportService *services.ReportService
+}
+
+func NewAdminHandler(adminService *services.AdminService, reportService *services.ReportService) *AdminHandler {
+	return &AdminHandler{
+		adminService:  adminService,
+		reportService: reportService,
+	}
+}
+
+// GetUser looks a user up by ID or user number
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, err := h.adminService.GetUser(c.Request.Context(), c.Param("user"))
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) Suspend(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	// The reason is optional, and so is the body
+	var req models.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.adminService.Suspend(c.Request.Context(), adminID, c.Param("user"), req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrCannotSuspendSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to suspend user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) Unsuspend(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	user, err := h.adminService.Unsuspend(c.Request.Context(), adminID, c.Param("user"))
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unsuspend user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) RevokeSessions(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.adminService.RevokeSessions(c.Request.Context(), adminID, c.Param("user")); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "sessions revoked"})
+}
+
+func (h *AdminHandler) Stats(c *gin.Context) {
+	stats, err := h.adminService.GetStats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// ListReports returns the review queue, oldest first
+func (h *AdminHandler) ListReports(c *gin.Context) {
+	var query models.ReportsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reports, err := h.reportService.ListReports(c.Request.Context(), query.Status, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list reports"})
+		return
+	}
+	if reports == nil {
+		reports = []models.Report{}
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+func (h *AdminHandler) GetReport(c *gin.Context) {
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	report, err := h.reportService.GetReport(c.Request.Context(), reportID)
+	if err != nil {
+		if errors.Is(err, models.ErrReportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// UpdateReport moves a report through the review queue
+func (h *AdminHandler) UpdateReport(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var req models.UpdateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.reportService.UpdateReport(c.Request.Context(), adminID, reportID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrReportNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrReportStatusConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update report"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/quickpic/server/internal/models"
 	"github.com/quickpic/server/internal/services"
 )
@@ -26,17 +27,36 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	response, err := h.authService.Register(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, models.ErrUsernameExists) {
+		switch {
+		case errors.Is(err, models.ErrUsernameExists):
 			c.JSON(http.StatusConflict, gin.H{"error": "username already exists"})
-			return
+		case errors.Is(err, models.ErrUsernameConfusable),
+			errors.Is(err, models.ErrUsernameReserved):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrInvalidUsername),
+			errors.Is(err, models.ErrInvalidPublicKey),
+			errors.Is(err, models.ErrInvalidChallenge),
+			errors.Is(err, models.ErrInvalidKeySignature):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to register"})
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to register"})
 		return
 	}
 
 	c.JSON(http.StatusCreated, response)
 }
 
+func (h *AuthHandler) Challenge(c *gin.Context) {
+	challenge, err := h.authService.RegistrationChallenge()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create challenge"})
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
+}
+
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -46,11 +66,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	response, err := h.authService.Login(c.Request.Context(), &req)
 	if err != nil {
-		if errors.Is(err, models.ErrInvalidCredentials) {
+		switch {
+		case errors.Is(err, models.ErrInvalidCredentials):
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
-			return
+		case errors.Is(err, models.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to login"})
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to login"})
 		return
 	}
 
@@ -66,11 +89,14 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 
 	response, err := h.authService.RefreshTokens(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		if errors.Is(err, models.ErrInvalidToken) {
+		switch {
+		case errors.Is(err, models.ErrInvalidToken):
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
-			return
+		case errors.Is(err, models.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh token"})
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh token"})
 		return
 	}
 
@@ -91,3 +117,32 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 }
+
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+func (h *AuthHandler) DeleteAccount(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.DeleteAccount(c.Request.Context(), userID, req.Password); err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidCredentials):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete account"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account deleted"})
+}
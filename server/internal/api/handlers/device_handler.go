@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/services"
+)
+
+type DeviceHandler struct {
+	deviceService *services.DeviceService
+}
+
+func NewDeviceHandler(deviceService *services.DeviceService) *DeviceHandler {
+	return &DeviceHandler{deviceService: deviceService}
+}
+
+func (h *DeviceHandler) Register(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	device, err := h.deviceService.Register(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidPublicKey),
+			errors.Is(err, models.ErrInvalidKeySignature),
+			errors.Is(err, models.ErrTooManyDevices):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrDeviceExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to register device"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+func (h *DeviceHandler) List(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	devices, err := h.deviceService.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get devices"})
+		return
+	}
+
+	if devices == nil {
+		devices = []models.Device{}
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+func (h *DeviceHandler) Remove(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device id"})
+		return
+	}
+
+	if err := h.deviceService.Remove(c.Request.Context(), userID, deviceID); err != nil {
+		if errors.Is(err, models.ErrDeviceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "device not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove device"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "device removed"})
+}
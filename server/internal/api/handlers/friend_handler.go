@@ -38,6 +38,8 @@ func (h *FriendHandler) SendRequest(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": "friend request already exists"})
 		case errors.Is(err, models.ErrAlreadyFriends):
 			c.JSON(http.StatusConflict, gin.H{"error": "already friends"})
+		case errors.Is(err, models.ErrUserBlocked):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send friend request"})
 		}
@@ -63,6 +65,22 @@ func (h *FriendHandler) GetPendingRequests(c *gin.Context) {
 	c.JSON(http.StatusOK, requests)
 }
 
+func (h *FriendHandler) GetOutgoingRequests(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	requests, err := h.friendService.GetOutgoingRequests(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get friend requests"})
+		return
+	}
+
+	if requests == nil {
+		requests = []models.OutgoingFriendRequest{}
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
 func (h *FriendHandler) AcceptRequest(c *gin.Context) {
 	userID := c.MustGet("userID").(uuid.UUID)
 
@@ -113,10 +131,41 @@ func (h *FriendHandler) RejectRequest(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "friend request rejected"})
 }
 
+func (h *FriendHandler) CancelRequest(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.FriendRequestActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.friendService.CancelFriendRequest(c.Request.Context(), userID, req.RequestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrFriendRequestNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "friend request not found"})
+		case errors.Is(err, models.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized to cancel this request"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel friend request"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "friend request cancelled"})
+}
+
 func (h *FriendHandler) GetFriends(c *gin.Context) {
 	userID := c.MustGet("userID").(uuid.UUID)
 
-	friends, err := h.friendService.GetFriends(c.Request.Context(), userID)
+	var query models.FriendsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	friends, err := h.friendService.GetFriends(c.Request.Context(), userID, query.Sort)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get friends"})
 		return
@@ -126,5 +175,176 @@ func (h *FriendHandler) GetFriends(c *gin.Context) {
 		friends = []models.Friend{}
 	}
 
-	c.JSON(http.StatusOK, friends)
+	jsonWithETag(c, friends)
+}
+
+func (h *FriendHandler) GetSuggestions(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var query models.SuggestionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = models.DefaultSuggestionLimit
+	}
+
+	suggestions, err := h.friendService.GetSuggestions(c.Request.Context(), userID, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get suggestions"})
+		return
+	}
+
+	if suggestions == nil {
+		suggestions = []models.FriendSuggestion{}
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+func (h *FriendHandler) RemoveFriend(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.friendService.RemoveFriend(c.Request.Context(), userID, c.Param("username")); err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrNotFriends):
+			c.JSON(http.StatusNotFound, gin.H{"error": "not friends with this user"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove friend"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "friend removed"})
+}
+
+func (h *FriendHandler) GetSafetyNumber(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	number, err := h.friendService.SafetyNumber(c.Request.Context(), userID, c.Param("username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrNotFriends):
+			c.JSON(http.StatusForbidden, gin.H{"error": "not friends with this user"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute safety number"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, number)
+}
+
+func (h *FriendHandler) SetVerified(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.SetVerifiedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.friendService.SetVerified(c.Request.Context(), userID, c.Param("username"), &req); err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrNotFriends):
+			c.JSON(http.StatusForbidden, gin.H{"error": "not friends with this user"})
+		case errors.Is(err, models.ErrSafetyNumberMismatch):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update verification"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"verified": req.Verified})
+}
+
+// UpdateSettings changes the caller's nickname, mute, pin and message TTL
+// settings for a friend
+func (h *FriendHandler) UpdateSettings(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.UpdateFriendSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.friendService.UpdateFriendSettings(c.Request.Context(), userID, c.Param("username"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrNotFriends):
+			c.JSON(http.StatusNotFound, gin.H{"error": "not friends with this user"})
+		case errors.Is(err, models.ErrInvalidNickname):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update friend settings"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *FriendHandler) Block(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.friendService.Block(c.Request.Context(), userID, c.Param("username")); err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrCannotBlockSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrAlreadyBlocked):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to block user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user blocked"})
+}
+
+func (h *FriendHandler) Unblock(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.friendService.Unblock(c.Request.Context(), userID, c.Param("username")); err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrNotBlocked):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unblock user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user unblocked"})
+}
+
+func (h *FriendHandler) GetBlocked(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	blocked, err := h.friendService.GetBlocked(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get blocked users"})
+		return
+	}
+
+	if blocked == nil {
+		blocked = []models.BlockedUser{}
+	}
+
+	c.JSON(http.StatusOK, blocked)
 }
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/services"
+)
+
+type InviteHandler struct {
+	inviteService *services.InviteService
+}
+
+func NewInviteHandler(inviteService *services.InviteService) *InviteHandler {
+	return &InviteHandler{inviteService: inviteService}
+}
+
+func (h *InviteHandler) Create(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invite, err := h.inviteService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create invite"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+func (h *InviteHandler) List(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	invites, err := h.inviteService.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get invites"})
+		return
+	}
+
+	if invites == nil {
+		invites = []models.Invite{}
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+func (h *InviteHandler) Revoke(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	inviteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invite id"})
+		return
+	}
+
+	if err := h.inviteService.Revoke(c.Request.Context(), userID, inviteID); err != nil {
+		if errors.Is(err, models.ErrInviteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "invite not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke invite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invite revoked"})
+}
+
+func (h *InviteHandler) Redeem(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.RedeemInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	friend, err := h.inviteService.Redeem(c.Request.Context(), userID, req.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidInvite):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrCannotAddSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot redeem your own invite"})
+		case errors.Is(err, models.ErrInviteNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "invite not found"})
+		case errors.Is(err, models.ErrInviteExpired):
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrAlreadyFriends):
+			c.JSON(http.StatusConflict, gin.H{"error": "already friends"})
+		case errors.Is(err, models.ErrUserBlocked):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to redeem invite"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, friend)
+}
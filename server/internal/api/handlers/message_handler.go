@@ -45,11 +45,17 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 
 	msg, err := h.messageService.SendMessage(c.Request.Context(), userID, toUser.ID, &req)
 	if err != nil {
-		if errors.Is(err, models.ErrNotFriends) {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "recipient not found"})
+		case errors.Is(err, models.ErrNotFriends):
 			c.JSON(http.StatusForbidden, gin.H{"error": "you can only send messages to friends"})
-			return
+		case errors.Is(err, models.ErrDeviceMismatch):
+			// The sender's view of the device list is stale; it should refetch and re-encrypt
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send message"})
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send message"})
 		return
 	}
 
@@ -62,8 +68,17 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 func (h *MessageHandler) GetMessages(c *gin.Context) {
 	userID := c.MustGet("userID").(uuid.UUID)
 
-	messages, err := h.messageService.GetPendingMessages(c.Request.Context(), userID)
+	deviceID, ok := deviceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	messages, err := h.messageService.GetPendingMessages(c.Request.Context(), userID, deviceID)
 	if err != nil {
+		if errors.Is(err, models.ErrDeviceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "device not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get messages"})
 		return
 	}
@@ -75,3 +90,49 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 	c.JSON(http.StatusOK, messages)
 }
 
+func (h *MessageHandler) AcknowledgeMessage(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid message id"})
+		return
+	}
+
+	deviceID, ok := deviceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	if err := h.messageService.Acknowledge(c.Request.Context(), userID, messageID, deviceID); err != nil {
+		switch {
+		case errors.Is(err, models.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "message not found"})
+		case errors.Is(err, models.ErrDeviceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "device not found"})
+		case errors.Is(err, models.ErrDeviceIDRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to acknowledge message"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "message acknowledged"})
+}
+
+// deviceIDQuery parses the optional device_id query parameter, writing a 400
+// response when it is malformed
+func deviceIDQuery(c *gin.Context) (*uuid.UUID, bool) {
+	value := c.Query("device_id")
+	if value == "" {
+		return nil, true
+	}
+
+	deviceID, err := uuid.Parse(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device id"})
+		return nil, false
+	}
+	return &deviceID, true
+}
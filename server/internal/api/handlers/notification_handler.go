@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/services"
+)
+
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	notifications, err := h.notificationService.GetNotifications(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get notifications"})
+		return
+	}
+
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (h *NotificationHandler) Acknowledge(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.AcknowledgeNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.notificationService.Acknowledge(c.Request.Context(), userID, req.NotificationID); err != nil {
+		if errors.Is(err, models.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to acknowledge notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification acknowledged"})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/services"
+)
+
+type PreKeyHandler struct {
+	prekeyService *services.PreKeyService
+}
+
+func NewPreKeyHandler(prekeyService *services.PreKeyService) *PreKeyHandler {
+	return &PreKeyHandler{prekeyService: prekeyService}
+}
+
+func (h *PreKeyHandler) Upload(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.UploadPreKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, err := h.prekeyService.Upload(c.Request.Context(), userID, &req)
+	if err != nil {
+		writePreKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *PreKeyHandler) Replenish(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.ReplenishPreKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, err := h.prekeyService.Replenish(c.Request.Context(), userID, &req)
+	if err != nil {
+		writePreKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *PreKeyHandler) Status(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	status, err := h.prekeyService.Status(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get prekey status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *PreKeyHandler) ClaimBundle(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	username := c.Param("username")
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+
+	bundle, err := h.prekeyService.ClaimBundle(c.Request.Context(), userID, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrNotFriends):
+			c.JSON(http.StatusForbidden, gin.H{"error": "not friends with this user"})
+		case errors.Is(err, models.ErrPreKeyBundleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "no prekey bundle available"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to claim prekey bundle"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+func writePreKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidPreKey),
+		errors.Is(err, models.ErrInvalidKeySignature),
+		errors.Is(err, models.ErrTooManyPreKeys):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrPreKeyIDUsed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store prekeys"})
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/services"
+)
+
+// maxReportRequestSize leaves room for the content and ciphertext, base64
+// encoded, plus the rest of the report
+const maxReportRequestSize = 3 * models.MaxReportContentSize
+
+type ReportHandler struct {
+	reportService *services.ReportService
+}
+
+func NewReportHandler(reportService *services.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+func (h *ReportHandler) Create(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxReportRequestSize)
+	var req models.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "report is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.reportService.CreateReport(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "message not found"})
+		case errors.Is(err, models.ErrCannotReportSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrReportExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create report"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":       report.ID,
+		"status":   report.Status,
+		"verified": report.Verified,
+	})
+}
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/quickpic/server/internal/models"
 	"github.com/quickpic/server/internal/services"
 )
@@ -18,13 +20,14 @@ func NewUserHandler(userService *services.UserService) *UserHandler {
 }
 
 func (h *UserHandler) GetByUsername(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
 	username := c.Param("username")
 	if username == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
 		return
 	}
 
-	user, err := h.userService.GetByUsername(c.Request.Context(), username)
+	user, err := h.userService.GetByUsername(c.Request.Context(), userID, username)
 	if err != nil {
 		if errors.Is(err, models.ErrUserNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
@@ -34,5 +37,270 @@ func (h *UserHandler) GetByUsername(c *gin.Context) {
 		return
 	}
 
+	jsonWithETag(c, user)
+}
+
+func (h *UserHandler) Search(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var query models.UserSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, err := h.userService.Search(c.Request.Context(), userID, &query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search users"})
+		return
+	}
+
+	if users == nil {
+		users = []models.UserPublic{}
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+func (h *UserHandler) GetPrivacy(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	settings, err := h.userService.GetPrivacySettings(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get privacy settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *UserHandler) UpdatePrivacy(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.UpdatePrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.userService.UpdatePrivacySettings(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update privacy settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *UserHandler) RotatePublicKey(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.RotatePublicKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	record, err := h.userService.RotatePublicKey(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidPublicKey),
+			errors.Is(err, models.ErrInvalidKeySignature):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrKeyVersionConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to rotate public key"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+func (h *UserHandler) ChangeUsername(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.ChangeUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.userService.ChangeUsername(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidUsername):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrUsernameExists),
+			errors.Is(err, models.ErrUsernameConfusable),
+			errors.Is(err, models.ErrUsernameReserved):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to change username"})
+		}
+		return
+	}
+
 	c.JSON(http.StatusOK, user)
 }
+
+func (h *UserHandler) GetKeyHistory(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	username := c.Param("username")
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+
+	history, err := h.userService.GetKeyHistory(c.Request.Context(), userID, username)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get key history"})
+		return
+	}
+
+	if history == nil {
+		history = []models.PublicKeyVersion{}
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.userService.UpdateProfile(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidDisplayName):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update profile"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// SetAvatar takes the encrypted avatar as the raw request body
+func (h *UserHandler) SetAvatar(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, models.MaxAvatarSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "avatar is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read avatar"})
+		return
+	}
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "avatar is empty"})
+		return
+	}
+
+	avatar, err := h.userService.SetAvatar(c.Request.Context(), userID, data)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set avatar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, avatar)
+}
+
+func (h *UserHandler) SetAvatarKeys(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.SetAvatarKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userService.SetAvatarKeys(c.Request.Context(), userID, &req); err != nil {
+		switch {
+		case errors.Is(err, models.ErrNotFriends):
+			c.JSON(http.StatusForbidden, gin.H{"error": "not friends with this user"})
+		case errors.Is(err, models.ErrAvatarChanged):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set avatar keys"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "avatar keys updated"})
+}
+
+func (h *UserHandler) DeleteAvatar(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.userService.DeleteAvatar(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, models.ErrAvatarNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "avatar not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete avatar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "avatar deleted"})
+}
+
+// GetAvatar serves the encrypted avatar blob. Its id doubles as the ETag.
+func (h *UserHandler) GetAvatar(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	avatar, err := h.userService.GetAvatar(c.Request.Context(), userID, c.Param("username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, models.ErrNotFriends):
+			c.JSON(http.StatusForbidden, gin.H{"error": "not friends with this user"})
+		case errors.Is(err, models.ErrAvatarNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "avatar not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get avatar"})
+		}
+		return
+	}
+
+	if notModified(c, `"`+avatar.ID+`"`) {
+		return
+	}
+	c.Data(http.StatusOK, "application/octet-stream", avatar.Data)
+}
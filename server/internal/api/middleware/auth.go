@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/quickpic/server/internal/models"
 	"github.com/quickpic/server/internal/services"
 )
 
@@ -22,13 +24,39 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			return
 		}
 
-		userID, err := authService.ValidateAccessToken(parts[1])
+		claims, err := authService.ValidateAccessToken(parts[1])
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
 
-		c.Set("userID", userID)
+		// Suspensions take effect on tokens that were already issued
+		if err := authService.CheckActive(c.Request.Context(), claims.UserID); err != nil {
+			switch {
+			case errors.Is(err, models.ErrAccountSuspended):
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case errors.Is(err, models.ErrUserNotFound):
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": models.ErrInvalidToken.Error()})
+			default:
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check account"})
+			}
+			return
+		}
+
+		c.Set("userID", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Next()
+	}
+}
+
+// AdminMiddleware lets through only requests whose access token carries the
+// admin role. It runs after AuthMiddleware.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != models.RoleAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin role required"})
+			return
+		}
 		c.Next()
 	}
 }
@@ -8,28 +8,45 @@ import (
 	"github.com/quickpic/server/internal/storage"
 )
 
-func SetupRoutes(
-	router *gin.Engine,
-	authService *services.AuthService,
-	userService *services.UserService,
-	friendService *services.FriendService,
-	messageService *services.MessageService,
-	userRepo storage.UserRepo,
-) {
+// Services holds the services behind the routes
+type Services struct {
+	Auth          *services.AuthService
+	Users         *services.UserService
+	Friends       *services.FriendService
+	Messages      *services.MessageService
+	Notifications *services.NotificationService
+	PreKeys       *services.PreKeyService
+	Devices       *services.DeviceService
+	Invites       *services.InviteService
+	Reports       *services.ReportService
+	Admin         *services.AdminService
+}
+
+func SetupRoutes(router *gin.Engine, svc Services, challengeLimiter *middleware.RateLimiter, userRepo storage.UserRepo) {
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	userHandler := handlers.NewUserHandler(userService)
-	friendHandler := handlers.NewFriendHandler(friendService)
-	messageHandler := handlers.NewMessageHandler(messageService, userRepo)
+	authHandler := handlers.NewAuthHandler(svc.Auth)
+	userHandler := handlers.NewUserHandler(svc.Users)
+	friendHandler := handlers.NewFriendHandler(svc.Friends)
+	messageHandler := handlers.NewMessageHandler(svc.Messages, userRepo)
+	notificationHandler := handlers.NewNotificationHandler(svc.Notifications)
+	prekeyHandler := handlers.NewPreKeyHandler(svc.PreKeys)
+	deviceHandler := handlers.NewDeviceHandler(svc.Devices)
+	inviteHandler := handlers.NewInviteHandler(svc.Invites)
+	reportHandler := handlers.NewReportHandler(svc.Reports)
+	adminHandler := handlers.NewAdminHandler(svc.Admin, svc.Reports)
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Public keys for verifying access tokens
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
+
 	// Auth routes (public)
 	auth := router.Group("/auth")
 	{
+		auth.POST("/challenge", middleware.RateLimit(challengeLimiter), authHandler.Challenge)
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
 		auth.POST("/refresh", authHandler.Refresh)
@@ -38,19 +55,46 @@ func SetupRoutes(
 
 	// Protected routes
 	protected := router.Group("")
-	protected.Use(middleware.AuthMiddleware(authService))
+	protected.Use(middleware.AuthMiddleware(svc.Auth))
 	{
 		// User routes
+		protected.GET("/users", userHandler.Search)
 		protected.GET("/users/:username", userHandler.GetByUsername)
+		protected.GET("/users/:username/keys", userHandler.GetKeyHistory)
+		protected.DELETE("/users/me", authHandler.DeleteAccount)
+		protected.PUT("/users/me/public-key", userHandler.RotatePublicKey)
+		protected.PUT("/users/me/username", userHandler.ChangeUsername)
+		protected.PUT("/users/me/profile", userHandler.UpdateProfile)
+		protected.PUT("/users/me/avatar", userHandler.SetAvatar)
+		protected.PUT("/users/me/avatar/keys", userHandler.SetAvatarKeys)
+		protected.DELETE("/users/me/avatar", userHandler.DeleteAvatar)
+		protected.GET("/users/:username/avatar", userHandler.GetAvatar)
+		protected.POST("/users/:username/prekey-bundle", prekeyHandler.ClaimBundle)
+		protected.GET("/users/me/blocked", friendHandler.GetBlocked)
+		protected.GET("/users/me/privacy", userHandler.GetPrivacy)
+		protected.PUT("/users/me/privacy", userHandler.UpdatePrivacy)
+		protected.POST("/users/:username/block", friendHandler.Block)
+		protected.DELETE("/users/:username/block", friendHandler.Unblock)
 
 		// Friend routes
 		friends := protected.Group("/friends")
 		{
 			friends.POST("/request", friendHandler.SendRequest)
 			friends.GET("/requests", friendHandler.GetPendingRequests)
+			friends.GET("/requests/outgoing", friendHandler.GetOutgoingRequests)
 			friends.POST("/accept", friendHandler.AcceptRequest)
 			friends.POST("/reject", friendHandler.RejectRequest)
+			friends.POST("/cancel", friendHandler.CancelRequest)
 			friends.GET("", friendHandler.GetFriends)
+			friends.GET("/suggestions", friendHandler.GetSuggestions)
+			friends.DELETE("/:username", friendHandler.RemoveFriend)
+			friends.PATCH("/:username", friendHandler.UpdateSettings)
+			friends.GET("/:username/safety-number", friendHandler.GetSafetyNumber)
+			friends.PUT("/:username/verified", friendHandler.SetVerified)
+			friends.POST("/invites", inviteHandler.Create)
+			friends.GET("/invites", inviteHandler.List)
+			friends.DELETE("/invites/:id", inviteHandler.Revoke)
+			friends.POST("/invites/redeem", inviteHandler.Redeem)
 		}
 
 		// Message routes
@@ -58,6 +102,47 @@ func SetupRoutes(
 		{
 			messages.POST("", messageHandler.SendMessage)
 			messages.GET("", messageHandler.GetMessages)
+			messages.POST("/:id/ack", messageHandler.AcknowledgeMessage)
+		}
+
+		// Device routes
+		devices := protected.Group("/devices")
+		{
+			devices.POST("", deviceHandler.Register)
+			devices.GET("", deviceHandler.List)
+			devices.DELETE("/:id", deviceHandler.Remove)
+		}
+
+		// Prekey routes (X3DH)
+		prekeys := protected.Group("/keys/prekeys")
+		{
+			prekeys.PUT("", prekeyHandler.Upload)
+			prekeys.POST("", prekeyHandler.Replenish)
+			prekeys.GET("/count", prekeyHandler.Status)
+		}
+
+		// Notification routes
+		notifications := protected.Group("/notifications")
+		{
+			notifications.GET("", notificationHandler.GetNotifications)
+			notifications.POST("/ack", notificationHandler.Acknowledge)
+		}
+
+		// Abuse reports
+		protected.POST("/reports", reportHandler.Create)
+
+		// Operator routes; users may be given by ID or user number
+		admin := protected.Group("/admin")
+		admin.Use(middleware.AdminMiddleware())
+		{
+			admin.GET("/stats", adminHandler.Stats)
+			admin.GET("/users/:user", adminHandler.GetUser)
+			admin.POST("/users/:user/suspension", adminHandler.Suspend)
+			admin.DELETE("/users/:user/suspension", adminHandler.Unsuspend)
+			admin.DELETE("/users/:user/sessions", adminHandler.RevokeSessions)
+			admin.GET("/reports", adminHandler.ListReports)
+			admin.GET("/reports/:id", adminHandler.GetReport)
+			admin.PATCH("/reports/:id", adminHandler.UpdateReport)
 		}
 	}
 }
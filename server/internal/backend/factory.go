@@ -27,6 +27,7 @@ type Config struct {
 	BlockchainRPCURL          string
 	BlockchainPrivateKey      string
 	BlockchainContractAddress string
+	BlockchainOffchainDBPath  string
 }
 
 // Result contains the backend and its repositories
@@ -49,9 +50,20 @@ func New(cfg Config) (*Result, error) {
 		return &Result{
 			Backend: backend,
 			Repos: storage.Repositories{
-				Users:    backend.Users(),
-				Friends:  backend.Friends(),
-				Messages: backend.Messages(),
+				Users:         backend.Users(),
+				Friends:       backend.Friends(),
+				Blocks:        backend.Blocks(),
+				Invites:       backend.Invites(),
+				Messages:      backend.Messages(),
+				Devices:       backend.Devices(),
+				PreKeys:       backend.PreKeys(),
+				Notifications: backend.Notifications(),
+				Profiles:      backend.Profiles(),
+				Streaks:       backend.Streaks(),
+				Interactions:  backend.Interactions(),
+				Reports:       backend.Reports(),
+				Stats:         backend.Stats(),
+				Audit:         backend.Audit(),
 			},
 		}, nil
 
@@ -65,10 +77,14 @@ func New(cfg Config) (*Result, error) {
 		if cfg.BlockchainContractAddress == "" {
 			return nil, fmt.Errorf("blockchain contract address is required")
 		}
+		if cfg.BlockchainOffchainDBPath == "" {
+			cfg.BlockchainOffchainDBPath = "./quickpic-offchain.db"
+		}
 		backend, err := blockchain.NewBackend(blockchain.Config{
 			RPCURL:          cfg.BlockchainRPCURL,
 			PrivateKey:      cfg.BlockchainPrivateKey,
 			ContractAddress: cfg.BlockchainContractAddress,
+			OffchainDBPath:  cfg.BlockchainOffchainDBPath,
 		})
 		if err != nil {
 			return nil, err
@@ -76,9 +92,20 @@ func New(cfg Config) (*Result, error) {
 		return &Result{
 			Backend: backend,
 			Repos: storage.Repositories{
-				Users:    backend.Users(),
-				Friends:  backend.Friends(),
-				Messages: backend.Messages(),
+				Users:         backend.Users(),
+				Friends:       backend.Friends(),
+				Blocks:        backend.Blocks(),
+				Invites:       backend.Invites(),
+				Messages:      backend.Messages(),
+				Devices:       backend.Devices(),
+				PreKeys:       backend.PreKeys(),
+				Notifications: backend.Notifications(),
+				Profiles:      backend.Profiles(),
+				Streaks:       backend.Streaks(),
+				Interactions:  backend.Interactions(),
+				Reports:       backend.Reports(),
+				Stats:         backend.Stats(),
+				Audit:         backend.Audit(),
 			},
 		}, nil
 
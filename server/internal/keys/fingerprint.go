@@ -0,0 +1,42 @@
+package keys
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// fingerprintIterations slows down searching for a key whose fingerprint
+// collides with someone else's
+const fingerprintIterations = 5200
+
+// SafetyNumber computes the 60-digit number two users compare out of band to
+// check that neither key was swapped. It is built from each user's ID and
+// identity key, and is the same whichever side computes it.
+func SafetyNumber(userID string, publicKey [PublicKeySize]byte, friendID string, friendPublicKey [PublicKeySize]byte) string {
+	own := fingerprint(userID, publicKey)
+	theirs := fingerprint(friendID, friendPublicKey)
+	if own > theirs {
+		own, theirs = theirs, own
+	}
+	return own + theirs
+}
+
+// fingerprint follows Signal's numeric fingerprint: iterated SHA-512 over the
+// key and identifier, with the first 30 bytes rendered as six 5-digit groups
+func fingerprint(id string, publicKey [PublicKeySize]byte) string {
+	version := []byte{0, 0}
+	digest := sha512.Sum512(append(append(version, publicKey[:]...), id...))
+	for i := 1; i < fingerprintIterations; i++ {
+		digest = sha512.Sum512(append(digest[:], publicKey[:]...))
+	}
+
+	var b strings.Builder
+	for i := 0; i < 30; i += 5 {
+		var chunk [8]byte
+		copy(chunk[3:], digest[i:i+5])
+		fmt.Fprintf(&b, "%05d", binary.BigEndian.Uint64(chunk[:])%100000)
+	}
+	return b.String()
+}
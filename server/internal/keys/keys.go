@@ -0,0 +1,159 @@
+// Package keys validates users' X25519 identity keys and implements XEdDSA,
+// which lets an X25519 key pair produce and verify Ed25519-compatible
+// signatures (https://signal.org/docs/specifications/xeddsa/). This is how a
+// client proves it holds the private half of the public key it uploads.
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+
+	"filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+	"golang.org/x/crypto/curve25519"
+)
+
+const (
+	PublicKeySize  = 32
+	SignatureSize  = 64
+	PrivateKeySize = 32
+)
+
+var (
+	ErrInvalidPublicKey = errors.New("public key must be a base64-encoded 32-byte X25519 point")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// ParsePublicKey decodes a base64 X25519 public key and rejects values that
+// are not canonical field elements or that lie in the small-order subgroup
+func ParsePublicKey(encoded string) ([PublicKeySize]byte, error) {
+	var key [PublicKeySize]byte
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != PublicKeySize {
+		return key, ErrInvalidPublicKey
+	}
+	copy(key[:], raw)
+
+	// Canonical encoding: the top bit is unused and u must be < p
+	var u field.Element
+	if _, err := u.SetBytes(key[:]); err != nil || key[31]&0x80 != 0 || [32]byte(u.Bytes()) != key {
+		return key, ErrInvalidPublicKey
+	}
+
+	// Small-order points produce an all-zero shared secret, which X25519 reports as an error
+	var probe [32]byte
+	probe[0] = 9
+	if _, err := curve25519.X25519(probe[:], key[:]); err != nil {
+		return key, ErrInvalidPublicKey
+	}
+
+	return key, nil
+}
+
+// PublicKey derives the X25519 public key for a private key
+func PublicKey(privateKey [PrivateKeySize]byte) [PublicKeySize]byte {
+	var pub [PublicKeySize]byte
+	curve25519.ScalarBaseMult(&pub, &privateKey)
+	return pub
+}
+
+// Sign produces an XEdDSA signature over message with an X25519 private key
+func Sign(privateKey [PrivateKeySize]byte, message []byte) ([]byte, error) {
+	a, err := new(edwards25519.Scalar).SetBytesWithClamping(privateKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	// The Edwards public key must have sign bit 0, negate the scalar if needed
+	A := new(edwards25519.Point).ScalarBaseMult(a).Bytes()
+	if A[31]&0x80 != 0 {
+		a.Negate(a)
+		A[31] &= 0x7F
+	}
+
+	var z [64]byte
+	if _, err := rand.Read(z[:]); err != nil {
+		return nil, err
+	}
+
+	// r = hash1(a || M || Z) where hash1 prefixes 0xFE followed by 31 0xFF bytes
+	h := sha512.New()
+	prefix := make([]byte, 32)
+	prefix[0] = 0xFE
+	for i := 1; i < len(prefix); i++ {
+		prefix[i] = 0xFF
+	}
+	h.Write(prefix)
+	h.Write(a.Bytes())
+	h.Write(message)
+	h.Write(z[:])
+	r, err := new(edwards25519.Scalar).SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	R := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	h.Reset()
+	h.Write(R)
+	h.Write(A)
+	h.Write(message)
+	k, err := new(edwards25519.Scalar).SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	s := new(edwards25519.Scalar).MultiplyAdd(k, a, r)
+
+	return append(R, s.Bytes()...), nil
+}
+
+// Verify checks an XEdDSA signature against an X25519 public key
+func Verify(publicKey [PublicKeySize]byte, message, signature []byte) bool {
+	if len(signature) != SignatureSize {
+		return false
+	}
+
+	edPublicKey, ok := edwardsPublicKey(publicKey)
+	if !ok {
+		return false
+	}
+
+	return ed25519.Verify(edPublicKey, message, signature)
+}
+
+// VerifyEncoded is Verify for a base64 public key and signature
+func VerifyEncoded(publicKey string, message []byte, signature string) error {
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !Verify(key, message, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// edwardsPublicKey maps a Montgomery u-coordinate to the Edwards point with
+// y = (u - 1) / (u + 1) and sign bit 0
+func edwardsPublicKey(publicKey [PublicKeySize]byte) (ed25519.PublicKey, bool) {
+	var u field.Element
+	if _, err := u.SetBytes(publicKey[:]); err != nil {
+		return nil, false
+	}
+
+	one := new(field.Element).One()
+	numerator := new(field.Element).Subtract(&u, one)
+	denominator := new(field.Element).Add(&u, one)
+	if denominator.Equal(new(field.Element).Zero()) == 1 {
+		return nil, false
+	}
+
+	y := new(field.Element).Multiply(numerator, new(field.Element).Invert(denominator))
+	return ed25519.PublicKey(y.Bytes()), true
+}
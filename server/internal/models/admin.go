@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoleAdmin is the access token role that grants the /admin routes
+const RoleAdmin = "admin"
+
+// AdminUser is an account as operators see it
+type AdminUser struct {
+	ID          uuid.UUID  `json:"id"`
+	UserNumber  int64      `json:"user_number"`
+	Username    string     `json:"username"`
+	PublicKey   string     `json:"public_key"`
+	KeyVersion  int        `json:"key_version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Suspended   bool       `json:"suspended"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+}
+
+// SuspendUserRequest gives the reason for a suspension, kept in the audit log
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+// AdminStats are aggregate figures for operators. Pending messages are
+// those not yet acknowledged by every recipient device, and their bytes
+// count the ciphertexts still held for them.
+type AdminStats struct {
+	Users                 int64 `json:"users"`
+	SuspendedUsers        int64 `json:"suspended_users"`
+	PendingMessages       int64 `json:"pending_messages"`
+	PendingMessageBytes   int64 `json:"pending_message_bytes"`
+	PendingFriendRequests int64 `json:"pending_friend_requests"`
+	OpenReports           int64 `json:"open_reports"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditAccountDeleted  AuditAction = "account_deleted"
+	AuditKeyRotated      AuditAction = "key_rotated"
+	AuditUsernameChanged AuditAction = "username_changed"
+	AuditDeviceAdded     AuditAction = "device_added"
+	AuditDeviceRemoved   AuditAction = "device_removed"
+	AuditUserSuspended   AuditAction = "user_suspended"
+	AuditUserUnsuspended AuditAction = "user_unsuspended"
+	AuditSessionsRevoked AuditAction = "sessions_revoked"
+)
+
+// AuditEntry records a security-relevant operation. ActorID and TargetID are
+// plain identifiers (not foreign keys) so entries outlive the users they
+// reference.
+type AuditEntry struct {
+	ID        uuid.UUID   `json:"id"`
+	ActorID   uuid.UUID   `json:"actor_id"`
+	Action    AuditAction `json:"action"`
+	TargetID  uuid.UUID   `json:"target_id"`
+	Details   string      `json:"details,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlockedUser is an entry in the caller's block list
+type BlockedUser struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device is one of a user's clients. Each device has its own X25519 key,
+// endorsed by an XEdDSA signature from the account's identity key.
+type Device struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"-"`
+	Name      string    `json:"name"`
+	PublicKey string    `json:"public_key"`
+	Signature string    `json:"signature"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RegisterDeviceRequest struct {
+	Name      string `json:"name" binding:"required,max=64"`
+	PublicKey string `json:"public_key" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+// DeviceCiphertext is a message encrypted to a single recipient device
+type DeviceCiphertext struct {
+	DeviceID         uuid.UUID `json:"device_id" binding:"required"`
+	EncryptedContent []byte    `json:"encrypted_content" binding:"required"`
+}
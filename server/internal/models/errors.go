@@ -5,6 +5,9 @@ import "errors"
 var (
 	ErrUserNotFound          = errors.New("user not found")
 	ErrUsernameExists        = errors.New("username already exists")
+	ErrUsernameConfusable    = errors.New("username is too similar to an existing username")
+	ErrUsernameReserved      = errors.New("username is reserved")
+	ErrInvalidUsername       = errors.New("username must be 3-32 characters of a-z, 0-9, '.' and '_', start with a letter, and not end with or repeat '.'")
 	ErrInvalidCredentials    = errors.New("invalid credentials")
 	ErrInvalidToken          = errors.New("invalid token")
 	ErrTokenExpired          = errors.New("token expired")
@@ -15,4 +18,36 @@ var (
 	ErrMessageNotFound       = errors.New("message not found")
 	ErrUnauthorized          = errors.New("unauthorized")
 	ErrCannotAddSelf         = errors.New("cannot add yourself as a friend")
+	ErrNotificationNotFound  = errors.New("notification not found")
+	ErrInvalidPublicKey      = errors.New("invalid public key")
+	ErrInvalidChallenge      = errors.New("invalid or expired challenge")
+	ErrInvalidKeySignature   = errors.New("signature does not match public key")
+	ErrKeyVersionConflict    = errors.New("public key was changed concurrently")
+	ErrInvalidPreKey         = errors.New("invalid prekey")
+	ErrPreKeyIDUsed          = errors.New("prekey id already used")
+	ErrTooManyPreKeys        = errors.New("too many one-time prekeys")
+	ErrPreKeyBundleNotFound  = errors.New("no prekey bundle available")
+	ErrDeviceNotFound        = errors.New("device not found")
+	ErrDeviceExists          = errors.New("device key already registered")
+	ErrTooManyDevices        = errors.New("too many devices")
+	ErrDeviceMismatch        = errors.New("ciphertexts do not match the recipient's devices")
+	ErrDeviceIDRequired      = errors.New("device_id is required for messages delivered per device")
+	ErrCannotBlockSelf       = errors.New("cannot block yourself")
+	ErrAlreadyBlocked        = errors.New("user is already blocked")
+	ErrNotBlocked            = errors.New("user is not blocked")
+	ErrUserBlocked           = errors.New("you have blocked this user")
+	ErrInvalidInvite         = errors.New("invalid invite token")
+	ErrInviteNotFound        = errors.New("invite not found")
+	ErrInviteExpired         = errors.New("invite has expired or is no longer valid")
+	ErrSafetyNumberMismatch  = errors.New("safety number does not match the current keys")
+	ErrInvalidDisplayName    = errors.New("display name must be at most 64 characters with no control characters")
+	ErrAvatarNotFound        = errors.New("avatar not found")
+	ErrAvatarChanged         = errors.New("avatar has been replaced; wrap keys for the current one")
+	ErrInvalidNickname       = errors.New("nickname must be at most 64 characters with no control characters")
+	ErrCannotReportSelf      = errors.New("cannot report yourself")
+	ErrReportExists          = errors.New("message has already been reported")
+	ErrReportNotFound        = errors.New("report not found")
+	ErrReportStatusConflict  = errors.New("report cannot move to that status from its current one")
+	ErrAccountSuspended      = errors.New("account is suspended")
+	ErrCannotSuspendSelf     = errors.New("cannot suspend yourself")
 )
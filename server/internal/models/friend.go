@@ -9,17 +9,34 @@ import (
 type FriendRequestStatus string
 
 const (
-	FriendRequestPending  FriendRequestStatus = "pending"
-	FriendRequestAccepted FriendRequestStatus = "accepted"
-	FriendRequestRejected FriendRequestStatus = "rejected"
+	FriendRequestPending   FriendRequestStatus = "pending"
+	FriendRequestAccepted  FriendRequestStatus = "accepted"
+	FriendRequestRejected  FriendRequestStatus = "rejected"
+	FriendRequestCancelled FriendRequestStatus = "cancelled"
+	FriendRequestExpired   FriendRequestStatus = "expired"
 )
 
+// PreviousStatuses lists the statuses a request may move to s from. Only open
+// requests change: a pending one can be answered, and a rejected one still
+// looks pending to its sender, so it can be cancelled or expire.
+func (s FriendRequestStatus) PreviousStatuses() []FriendRequestStatus {
+	switch s {
+	case FriendRequestCancelled, FriendRequestExpired:
+		return []FriendRequestStatus{FriendRequestPending, FriendRequestRejected}
+	default:
+		return []FriendRequestStatus{FriendRequestPending}
+	}
+}
+
 type FriendRequest struct {
 	ID         uuid.UUID           `json:"id"`
 	FromUserID uuid.UUID           `json:"from_user_id"`
 	ToUserID   uuid.UUID           `json:"to_user_id"`
 	Status     FriendRequestStatus `json:"status"`
 	CreatedAt  time.Time           `json:"created_at"`
+	// UpdatedAt is when the status last changed. It is kept from the sender
+	// so they can't tell when, or whether, a request was answered.
+	UpdatedAt time.Time `json:"-"`
 }
 
 type FriendRequestWithUser struct {
@@ -27,6 +44,25 @@ type FriendRequestWithUser struct {
 	FromUser UserPublic `json:"from_user"`
 }
 
+type OutgoingFriendRequest struct {
+	FriendRequest
+	ToUser UserPublic `json:"to_user"`
+}
+
+// FriendSuggestion is a user who shares friends with the caller
+type FriendSuggestion struct {
+	UserPublic
+	MutualFriends int `json:"mutual_friends"`
+}
+
+// SuggestionsQuery pages GET /friends/suggestions; Limit defaults to
+// DefaultSuggestionLimit
+type SuggestionsQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+const DefaultSuggestionLimit = 20
+
 type Friendship struct {
 	ID        uuid.UUID `json:"id"`
 	UserAID   uuid.UUID `json:"user_a_id"`
@@ -35,10 +71,59 @@ type Friendship struct {
 }
 
 type Friend struct {
-	UserID    uuid.UUID `json:"user_id"`
-	Username  string    `json:"username"`
-	PublicKey string    `json:"public_key"`
-	Since     time.Time `json:"since"`
+	UserID     uuid.UUID `json:"user_id"`
+	Username   string    `json:"username"`
+	PublicKey  string    `json:"public_key"`
+	KeyVersion int       `json:"key_version"`
+	Devices    []Device  `json:"devices,omitempty"`
+	Since      time.Time `json:"since"`
+	// Verified is set once the user has compared safety numbers, and cleared
+	// when the friend's key changes
+	Verified bool `json:"verified"`
+	Profile
+	FriendSettings
+	Streak
+}
+
+const (
+	MaxNicknameLength = 64
+	// MaxMessageTTL bounds a friend's default message lifetime
+	MaxMessageTTL = 7 * 24 * 60 * 60
+)
+
+// FriendSettings are the user's own settings for one friend. They are never
+// shown to the friend.
+type FriendSettings struct {
+	Nickname string `json:"nickname,omitempty"`
+	Muted    bool   `json:"muted"`
+	Pinned   bool   `json:"pinned"`
+	// MessageTTL is the default lifetime in seconds the client applies to
+	// messages sent to the friend; 0 means no default
+	MessageTTL int `json:"message_ttl"`
+}
+
+// UpdateFriendSettingsRequest changes the fields that are present and leaves
+// the rest; an empty nickname clears it
+type UpdateFriendSettingsRequest struct {
+	Nickname   *string `json:"nickname"`
+	Muted      *bool   `json:"muted"`
+	Pinned     *bool   `json:"pinned"`
+	MessageTTL *int    `json:"message_ttl" binding:"omitempty,min=0,max=604800"`
+}
+
+// SafetyNumber lets two friends check out of band that the server hasn't
+// swapped either of their keys
+type SafetyNumber struct {
+	SafetyNumber string `json:"safety_number"`
+	KeyVersion   int    `json:"key_version"`
+	Verified     bool   `json:"verified"`
+}
+
+// SetVerifiedRequest marks a friend verified. The safety number the user
+// compared is sent back so a key change in between isn't verified by mistake.
+type SetVerifiedRequest struct {
+	Verified     bool   `json:"verified"`
+	SafetyNumber string `json:"safety_number" binding:"required_if=Verified true"`
 }
 
 type SendFriendRequestRequest struct {
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FriendSort orders GET /friends; the default is by username
+type FriendSort string
+
+const (
+	// FriendSortRecent puts the friends interacted with most recently first
+	FriendSortRecent FriendSort = "recent"
+	// FriendSortFrequent puts the friends interacted with most often first,
+	// with older interactions counting for less
+	FriendSortFrequent FriendSort = "frequent"
+)
+
+// FriendsQuery is the query for GET /friends
+type FriendsQuery struct {
+	Sort FriendSort `form:"sort" binding:"omitempty,oneof=recent frequent"`
+}
+
+// InteractionHalfLife is how long it takes an interaction to count for half
+const InteractionHalfLife = 7 * 24 * time.Hour
+
+// Interaction is how often and how recently a user has exchanged messages
+// with a friend. Sending a message and acknowledging one each count once.
+type Interaction struct {
+	FriendID uuid.UUID
+	// Score is the decayed interaction count as of ScoredAt
+	Score    float64
+	ScoredAt time.Time
+	LastAt   time.Time
+}
+
+// ScoreAt decays the score to t. Every score decays by the same factor, so
+// the order of a user's interactions only changes when one is recorded.
+func (i *Interaction) ScoreAt(t time.Time) float64 {
+	return i.Score * math.Exp2(-float64(t.Sub(i.ScoredAt))/float64(InteractionHalfLife))
+}
+
+// Record counts one more interaction at t
+func (i *Interaction) Record(t time.Time) {
+	i.Score = i.ScoreAt(t) + 1
+	i.ScoredAt = t
+	i.LastAt = t
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invite lets anyone holding its token become friends with the creator
+// without a request/accept round trip
+type Invite struct {
+	ID        uuid.UUID `json:"id"`
+	CreatorID uuid.UUID `json:"-"`
+	MaxUses   int       `json:"max_uses"`
+	Uses      int       `json:"uses"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Revoked   bool      `json:"revoked"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Usable reports whether the invite can still be redeemed
+func (i *Invite) Usable() bool {
+	return !i.Revoked && i.Uses < i.MaxUses && time.Now().Before(i.ExpiresAt)
+}
+
+type CreateInviteRequest struct {
+	MaxUses        int `json:"max_uses" binding:"omitempty,min=1,max=100"`
+	ExpiresInHours int `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`
+}
+
+// CreatedInvite is returned once, when the invite is made. The token is what
+// the app shares as a link or renders as a QR code.
+type CreatedInvite struct {
+	Invite
+	Token string `json:"token"`
+}
+
+type RedeemInviteRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -20,7 +20,13 @@ type Message struct {
 	EncryptedContent []byte      `json:"encrypted_content"`
 	ContentType      ContentType `json:"content_type"`
 	Signature        string      `json:"signature"`
-	CreatedAt        time.Time   `json:"created_at"`
+	// RecipientKeyVersion is the recipient's key version when the message was sent
+	RecipientKeyVersion int       `json:"recipient_key_version"`
+	CreatedAt           time.Time `json:"created_at"`
+	// DeviceID is set when the message was fetched as one device's ciphertext
+	DeviceID *uuid.UUID `json:"device_id,omitempty"`
+	// FanOut is set when the message is stored as one ciphertext per device
+	FanOut bool `json:"-"`
 }
 
 type MessageWithSender struct {
@@ -29,11 +35,14 @@ type MessageWithSender struct {
 	FromPublicKey string `json:"from_public_key"`
 }
 
+// SendMessageRequest carries either a single ciphertext for a recipient
+// without registered devices, or one ciphertext per recipient device
 type SendMessageRequest struct {
-	ToUsername       string      `json:"to_username" binding:"required"`
-	EncryptedContent []byte      `json:"encrypted_content" binding:"required"`
-	ContentType      ContentType `json:"content_type" binding:"required"`
-	Signature        string      `json:"signature" binding:"required"`
+	ToUsername       string             `json:"to_username" binding:"required"`
+	EncryptedContent []byte             `json:"encrypted_content" binding:"required_without=Ciphertexts"`
+	Ciphertexts      []DeviceCiphertext `json:"ciphertexts" binding:"omitempty,dive"`
+	ContentType      ContentType        `json:"content_type" binding:"required"`
+	Signature        string             `json:"signature" binding:"required"`
 }
 
 type AcknowledgeMessageRequest struct {
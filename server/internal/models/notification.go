@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	NotificationFriendDeleted   NotificationType = "friend_deleted"
+	NotificationKeyChanged      NotificationType = "key_changed"
+	NotificationDevicesChanged  NotificationType = "devices_changed"
+	NotificationUsernameChanged NotificationType = "username_changed"
+)
+
+// Notification is a metadata-only event delivered to a user, e.g. that a
+// friend's account no longer exists. ActorUsername is captured at creation
+// time because the actor may not exist anymore when it is fetched.
+type Notification struct {
+	ID            uuid.UUID        `json:"id"`
+	UserID        uuid.UUID        `json:"user_id"`
+	Type          NotificationType `json:"type"`
+	ActorID       uuid.UUID        `json:"actor_id"`
+	ActorUsername string           `json:"actor_username"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+type AcknowledgeNotificationRequest struct {
+	NotificationID uuid.UUID `json:"notification_id" binding:"required"`
+}
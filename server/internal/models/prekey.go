@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SignedPreKey is a medium-term X25519 key signed (XEdDSA) by the owner's
+// identity key over its raw 32 bytes
+type SignedPreKey struct {
+	KeyID              uint32    `json:"key_id"`
+	PublicKey          string    `json:"public_key" binding:"required"`
+	Signature          string    `json:"signature" binding:"required"`
+	IdentityKeyVersion int       `json:"identity_key_version"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// OneTimePreKey is an X25519 key handed out to at most one sender
+type OneTimePreKey struct {
+	KeyID     uint32 `json:"key_id"`
+	PublicKey string `json:"public_key" binding:"required"`
+}
+
+// PreKeyBundle is what a sender needs to start an X3DH session. OneTimePreKey
+// is nil once the recipient's supply has run out.
+type PreKeyBundle struct {
+	UserID             uuid.UUID      `json:"user_id"`
+	Username           string         `json:"username"`
+	IdentityKey        string         `json:"identity_key"`
+	IdentityKeyVersion int            `json:"identity_key_version"`
+	SignedPreKey       SignedPreKey   `json:"signed_prekey"`
+	OneTimePreKey      *OneTimePreKey `json:"one_time_prekey,omitempty"`
+}
+
+// UploadPreKeysRequest replaces the caller's prekeys. Unclaimed one-time
+// prekeys from earlier uploads are discarded.
+type UploadPreKeysRequest struct {
+	SignedPreKey   SignedPreKey    `json:"signed_prekey" binding:"required"`
+	OneTimePreKeys []OneTimePreKey `json:"one_time_prekeys" binding:"dive"`
+}
+
+// ReplenishPreKeysRequest adds one-time prekeys and optionally a new signed prekey
+type ReplenishPreKeysRequest struct {
+	SignedPreKey   *SignedPreKey   `json:"signed_prekey"`
+	OneTimePreKeys []OneTimePreKey `json:"one_time_prekeys" binding:"dive"`
+}
+
+// PreKeyStatus tells a client whether it should upload more prekeys
+type PreKeyStatus struct {
+	OneTimePreKeys    int     `json:"one_time_prekeys"`
+	SignedPreKeyID    *uint32 `json:"signed_prekey_id,omitempty"`
+	SignedPreKeyStale bool    `json:"signed_prekey_stale"`
+}
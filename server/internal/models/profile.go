@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxDisplayNameLength = 64
+	// MaxAvatarSize bounds the encrypted avatar blob
+	MaxAvatarSize = 512 << 10
+)
+
+// Profile is what a viewer sees of a user beyond their username and keys
+type Profile struct {
+	DisplayName string     `json:"display_name,omitempty"`
+	Avatar      *AvatarRef `json:"avatar,omitempty"`
+}
+
+// AvatarRef tells a viewer how to fetch and decrypt a user's avatar. It is
+// only present when the user has wrapped the current avatar's key for them.
+type AvatarRef struct {
+	// ID is the hex SHA-256 of the ciphertext, also the blob's ETag
+	ID string `json:"id"`
+	// WrappedKey is the avatar's content key encrypted to the viewer
+	WrappedKey string `json:"wrapped_key"`
+}
+
+// Avatar is an encrypted avatar image. The server never holds its key.
+type Avatar struct {
+	ID        string    `json:"id"`
+	Data      []byte    `json:"-"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AvatarKey is the avatar's content key wrapped for one viewer
+type AvatarKey struct {
+	UserID     uuid.UUID `json:"user_id" binding:"required"`
+	WrappedKey string    `json:"wrapped_key" binding:"required,base64,max=512"`
+}
+
+type UpdateProfileRequest struct {
+	DisplayName string `json:"display_name"`
+}
+
+// SetAvatarKeysRequest shares the avatar with friends. AvatarID must name the
+// current avatar so keys for a replaced one are not stored against the new one.
+type SetAvatarKeysRequest struct {
+	AvatarID string      `json:"avatar_id" binding:"required"`
+	Keys     []AvatarKey `json:"keys" binding:"required,min=1,max=500,dive"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReportReason string
+
+const (
+	ReportSpam          ReportReason = "spam"
+	ReportHarassment    ReportReason = "harassment"
+	ReportExplicit      ReportReason = "explicit"
+	ReportImpersonation ReportReason = "impersonation"
+	ReportOther         ReportReason = "other"
+)
+
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportReviewing ReportStatus = "reviewing"
+	ReportActioned  ReportStatus = "actioned"
+	ReportDismissed ReportStatus = "dismissed"
+)
+
+// PreviousStatuses lists the statuses a report may move to s from. A report
+// under review can be put back in the queue; closed reports stay closed.
+func (s ReportStatus) PreviousStatuses() []ReportStatus {
+	switch s {
+	case ReportReviewing:
+		return []ReportStatus{ReportOpen}
+	case ReportOpen:
+		return []ReportStatus{ReportReviewing}
+	default:
+		return []ReportStatus{ReportOpen, ReportReviewing}
+	}
+}
+
+// MaxReportContentSize bounds the decrypted content attached to a report
+const MaxReportContentSize = 1 << 20
+
+// Report is a recipient's complaint about a sender, optionally with the
+// message they received. Reports are kept after either account is deleted.
+type Report struct {
+	ID             uuid.UUID    `json:"id"`
+	ReporterID     uuid.UUID    `json:"reporter_id"`
+	ReportedUserID uuid.UUID    `json:"reported_user_id"`
+	MessageID      *uuid.UUID   `json:"message_id,omitempty"`
+	Reason         ReportReason `json:"reason"`
+	Comment        string       `json:"comment,omitempty"`
+	// Content is the decrypted message as the reporter claims to have read it
+	Content     []byte      `json:"content,omitempty"`
+	ContentType ContentType `json:"content_type,omitempty"`
+	// EncryptedContent and Signature are the message as the reporter
+	// received it
+	EncryptedContent []byte `json:"encrypted_content,omitempty"`
+	Signature        string `json:"signature,omitempty"`
+	// Verified is set when Signature is the sender's over EncryptedContent,
+	// and matches the stored message if it hasn't been acknowledged yet
+	Verified bool `json:"verified"`
+	// SenderKeyVersion is the sender's key version that verified it
+	SenderKeyVersion int           `json:"sender_key_version,omitempty"`
+	Status           ReportStatus  `json:"status"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+	History          []ReportEvent `json:"history,omitempty"`
+}
+
+// ReportEvent is one entry in a report's audit trail. FromStatus is empty
+// for the report's creation.
+type ReportEvent struct {
+	ActorID    uuid.UUID    `json:"actor_id"`
+	FromStatus ReportStatus `json:"from_status,omitempty"`
+	ToStatus   ReportStatus `json:"to_status"`
+	Note       string       `json:"note,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type CreateReportRequest struct {
+	Username         string       `json:"username" binding:"required"`
+	MessageID        *uuid.UUID   `json:"message_id"`
+	Reason           ReportReason `json:"reason" binding:"required,oneof=spam harassment explicit impersonation other"`
+	Comment          string       `json:"comment" binding:"max=1000"`
+	Content          []byte       `json:"content" binding:"omitempty,max=1048576"`
+	ContentType      ContentType  `json:"content_type" binding:"required_with=Content"`
+	EncryptedContent []byte       `json:"encrypted_content" binding:"omitempty,max=1048576"`
+	Signature        string       `json:"signature" binding:"required_with=EncryptedContent"`
+}
+
+// ReportsQuery pages the review queue; Status defaults to open
+type ReportsQuery struct {
+	Status ReportStatus `form:"status" binding:"omitempty,oneof=open reviewing actioned dismissed"`
+	Limit  int          `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+const DefaultReportLimit = 50
+
+// UpdateReportRequest moves a report through the review queue
+type UpdateReportRequest struct {
+	Status ReportStatus `json:"status" binding:"required,oneof=open reviewing actioned dismissed"`
+	Note   string       `json:"note" binding:"max=1000"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Streak counts the consecutive days on which a user and a friend each sent
+// the other at least one message. It is kept from message metadata only.
+type Streak struct {
+	Count int `json:"streak"`
+	// ExpiresAt is when the streak breaks unless both send a message first
+	ExpiresAt *time.Time `json:"streak_expires_at,omitempty"`
+	// WarningAt is when clients should start warning that it will expire
+	WarningAt *time.Time `json:"streak_warning_at,omitempty"`
+	// LastDay numbers the latest day that counted toward the streak
+	LastDay int64 `json:"-"`
+}
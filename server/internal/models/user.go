@@ -12,6 +12,7 @@ type User struct {
 	Username     string    `json:"username"`
 	PasswordHash string    `json:"-"` // Never expose in JSON
 	PublicKey    string    `json:"public_key"`
+	KeyVersion   int       `json:"key_version"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -21,6 +22,9 @@ type UserPublic struct {
 	UserNumber int64     `json:"user_number"`
 	Username   string    `json:"username"`
 	PublicKey  string    `json:"public_key"`
+	KeyVersion int       `json:"key_version"`
+	Devices    []Device  `json:"devices,omitempty"`
+	Profile
 }
 
 func (u *User) ToPublic() UserPublic {
@@ -29,6 +33,7 @@ func (u *User) ToPublic() UserPublic {
 		UserNumber: u.UserNumber,
 		Username:   u.Username,
 		PublicKey:  u.PublicKey,
+		KeyVersion: u.KeyVersion,
 	}
 }
 
@@ -36,6 +41,20 @@ type RegisterRequest struct {
 	Username  string `json:"username" binding:"required,min=3,max=32"`
 	Password  string `json:"password" binding:"required,min=8"`
 	PublicKey string `json:"public_key" binding:"required"`
+	Nonce     string `json:"nonce" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+// RegistrationChallenge is a nonce the client signs with its identity key
+type RegistrationChallenge struct {
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ChangeUsernameRequest renames the caller. The old username stays reserved
+// for them for a while after.
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=32"`
 }
 
 type LoginRequest struct {
@@ -53,3 +72,50 @@ type AuthResponse struct {
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
+
+// PublicKeyVersion is one entry in a user's key history. ValidUntil is nil
+// for the current key.
+type PublicKeyVersion struct {
+	Version    int        `json:"version"`
+	PublicKey  string     `json:"public_key"`
+	ValidFrom  time.Time  `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// RotatePublicKeyRequest replaces the caller's identity key. Both the current
+// and the new key sign the rotation statement.
+type RotatePublicKeyRequest struct {
+	PublicKey       string `json:"public_key" binding:"required"`
+	Signature       string `json:"signature" binding:"required"`
+	NewKeySignature string `json:"new_key_signature" binding:"required"`
+}
+
+// PrivacySettings controls how other users can come across the user
+type PrivacySettings struct {
+	// ShowInSuggestions lets the user be suggested to friends of their friends
+	ShowInSuggestions bool `json:"show_in_suggestions"`
+	// Discoverable lets the user turn up in username search. Exact lookups
+	// work either way.
+	Discoverable bool `json:"discoverable"`
+}
+
+// UpdatePrivacyRequest changes the settings that are present and leaves the
+// rest as they are
+type UpdatePrivacyRequest struct {
+	ShowInSuggestions *bool `json:"show_in_suggestions"`
+	Discoverable      *bool `json:"discoverable"`
+}
+
+// UserSearchQuery is a username prefix search. Results come in username
+// order; pass the last username seen as After for the next page.
+type UserSearchQuery struct {
+	Q     string `form:"q" binding:"required,max=32"`
+	After string `form:"after" binding:"max=32"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+const DefaultSearchLimit = 20
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
@@ -3,8 +3,14 @@ package blockchain
 import (
 	"context"
 	"crypto/ecdsa"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/google/uuid"
 	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/username"
 )
 
 // Backend implements repository.Backend for blockchain storage
@@ -25,13 +32,36 @@ type Backend struct {
 	privateKey      *ecdsa.PrivateKey
 	chainID         *big.Int
 
-	users    *UserRepository
-	friends  *FriendRepository
-	messages *MessageRepository
+	users         *UserRepository
+	friends       *FriendRepository
+	blocks        *BlockRepository
+	invites       *InviteRepository
+	messages      *MessageRepository
+	devices       *DeviceRepository
+	prekeys       *PreKeyRepository
+	notifications *NotificationRepository
+	profiles      *ProfileRepository
+	streaks       *StreakRepository
+	interactions  *InteractionRepository
+	reports       *ReportRepository
+	stats         *StatsRepository
+	audit         *AuditRepository
+
+	// Local views fed by contract events
+	friendGraph *friendGraph
+	userIndex   *userIndex
 
 	// In-memory refresh token storage (not stored on-chain)
 	refreshTokens     map[string]refreshTokenEntry
 	refreshTokenMutex sync.RWMutex
+
+	// In-memory notification storage (not stored on-chain)
+	notificationStore map[uuid.UUID][]models.Notification
+	notificationMutex sync.RWMutex
+
+	// Off-chain SQLite store for friend settings, streaks, interactions,
+	// reports and the audit log
+	offchain *sql.DB
 }
 
 type refreshTokenEntry struct {
@@ -44,6 +74,7 @@ type Config struct {
 	RPCURL          string // e.g., "http://localhost:8545" for Anvil
 	PrivateKey      string // hex-encoded private key (without 0x prefix)
 	ContractAddress string // deployed contract address
+	OffchainDBPath  string // SQLite database for state kept off-chain
 }
 
 // NewBackend creates a new blockchain backend
@@ -72,18 +103,39 @@ func NewBackend(cfg Config) (*Backend, error) {
 		return nil, fmt.Errorf("failed to bind contract: %w", err)
 	}
 
+	offchain, err := openOffchainStore(cfg.OffchainDBPath)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
 	backend := &Backend{
-		client:          client,
-		contract:        contract,
-		contractAddress: contractAddress,
-		privateKey:      privateKey,
-		chainID:         chainID,
-		refreshTokens:   make(map[string]refreshTokenEntry),
+		client:            client,
+		contract:          contract,
+		contractAddress:   contractAddress,
+		privateKey:        privateKey,
+		chainID:           chainID,
+		refreshTokens:     make(map[string]refreshTokenEntry),
+		notificationStore: make(map[uuid.UUID][]models.Notification),
+		offchain:          offchain,
+		friendGraph:       newFriendGraph(),
+		userIndex:         newUserIndex(),
 	}
 
 	backend.users = &UserRepository{backend: backend}
 	backend.friends = &FriendRepository{backend: backend}
+	backend.blocks = &BlockRepository{backend: backend}
+	backend.invites = &InviteRepository{backend: backend}
 	backend.messages = &MessageRepository{backend: backend}
+	backend.devices = &DeviceRepository{backend: backend}
+	backend.prekeys = &PreKeyRepository{backend: backend}
+	backend.notifications = &NotificationRepository{backend: backend}
+	backend.profiles = &ProfileRepository{backend: backend}
+	backend.streaks = &StreakRepository{backend: backend}
+	backend.interactions = &InteractionRepository{backend: backend}
+	backend.reports = &ReportRepository{backend: backend}
+	backend.stats = &StatsRepository{backend: backend}
+	backend.audit = &AuditRepository{backend: backend}
 
 	return backend, nil
 }
@@ -96,13 +148,57 @@ func (b *Backend) Friends() *FriendRepository {
 	return b.friends
 }
 
+func (b *Backend) Blocks() *BlockRepository {
+	return b.blocks
+}
+
+func (b *Backend) Invites() *InviteRepository {
+	return b.invites
+}
+
 func (b *Backend) Messages() *MessageRepository {
 	return b.messages
 }
 
+func (b *Backend) Devices() *DeviceRepository {
+	return b.devices
+}
+
+func (b *Backend) PreKeys() *PreKeyRepository {
+	return b.prekeys
+}
+
+func (b *Backend) Notifications() *NotificationRepository {
+	return b.notifications
+}
+
+func (b *Backend) Profiles() *ProfileRepository {
+	return b.profiles
+}
+
+func (b *Backend) Streaks() *StreakRepository {
+	return b.streaks
+}
+
+func (b *Backend) Interactions() *InteractionRepository {
+	return b.interactions
+}
+
+func (b *Backend) Reports() *ReportRepository {
+	return b.reports
+}
+
+func (b *Backend) Stats() *StatsRepository {
+	return b.stats
+}
+
+func (b *Backend) Audit() *AuditRepository {
+	return b.audit
+}
+
 func (b *Backend) Close() error {
 	b.client.Close()
-	return nil
+	return b.offchain.Close()
 }
 
 func (b *Backend) Name() string {
@@ -132,6 +228,30 @@ func bytes32ToUUID(b [32]byte) uuid.UUID {
 	return id
 }
 
+// usernameSkeletonHash is the key the contract indexes confusable usernames
+// by; the skeleton itself never needs to be read back
+func usernameSkeletonHash(name string) [32]byte {
+	return crypto.Keccak256Hash([]byte(username.Skeleton(name)))
+}
+
+// friendRequestStatuses maps the contract's FriendRequestStatus enum
+var friendRequestStatuses = []models.FriendRequestStatus{
+	models.FriendRequestPending,
+	models.FriendRequestAccepted,
+	models.FriendRequestRejected,
+	models.FriendRequestCancelled,
+	models.FriendRequestExpired,
+}
+
+// privacyFlags bits that keep a user out of friend suggestions and search
+const (
+	privacyHideFromSuggestions = 1 << iota
+	privacyHideFromSearch
+)
+
+// friendRequestExpiryBatch bounds how many requests one expiry transaction sweeps
+const friendRequestExpiryBatch = 100
+
 // ============ UserRepository ============
 
 type UserRepository struct {
@@ -143,6 +263,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	user.Username = strings.ToLower(user.Username)
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
+	user.KeyVersion = 1
 
 	auth, err := r.backend.getTransactOpts(ctx)
 	if err != nil {
@@ -153,6 +274,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		auth,
 		uuidToBytes32(user.ID),
 		user.Username,
+		usernameSkeletonHash(user.Username),
 		user.PasswordHash,
 		user.PublicKey,
 	)
@@ -160,6 +282,12 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		if strings.Contains(err.Error(), "Username already taken") {
 			return models.ErrUsernameExists
 		}
+		if strings.Contains(err.Error(), "Username too similar") {
+			return models.ErrUsernameConfusable
+		}
+		if strings.Contains(err.Error(), "Username is reserved") {
+			return models.ErrUsernameReserved
+		}
 		return err
 	}
 
@@ -191,12 +319,18 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 		return nil, err
 	}
 
+	keyVersion, err := r.backend.contract.GetKeyVersion(&bind.CallOpts{Context: ctx}, result.UserId)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.User{
 		ID:           bytes32ToUUID(result.UserId),
 		UserNumber:   result.UserNumber.Int64(),
 		Username:     result.Username,
 		PasswordHash: result.PasswordHash,
 		PublicKey:    result.PublicKey,
+		KeyVersion:   int(keyVersion.Int64()),
 		CreatedAt:    time.Unix(result.CreatedAt.Int64(), 0),
 		UpdatedAt:    time.Unix(result.UpdatedAt.Int64(), 0),
 	}, nil
@@ -211,101 +345,109 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 		return nil, err
 	}
 
+	keyVersion, err := r.backend.contract.GetKeyVersion(&bind.CallOpts{Context: ctx}, result.UserId)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.User{
 		ID:           bytes32ToUUID(result.UserId),
 		UserNumber:   result.UserNumber.Int64(),
 		Username:     result.UsernameOut,
 		PasswordHash: result.PasswordHash,
 		PublicKey:    result.PublicKey,
+		KeyVersion:   int(keyVersion.Int64()),
 		CreatedAt:    time.Unix(result.CreatedAt.Int64(), 0),
 		UpdatedAt:    time.Unix(result.UpdatedAt.Int64(), 0),
 	}, nil
 }
 
-// Refresh tokens are stored in-memory (not on-chain) for performance
-func (r *UserRepository) StoreRefreshToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
-	r.backend.refreshTokenMutex.Lock()
-	defer r.backend.refreshTokenMutex.Unlock()
-
-	r.backend.refreshTokens[tokenHash] = refreshTokenEntry{
-		userID:    userID,
-		expiresAt: expiresAt,
+func (r *UserRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
 	}
-	return nil
-}
 
-func (r *UserRepository) ValidateRefreshToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
-	r.backend.refreshTokenMutex.RLock()
-	defer r.backend.refreshTokenMutex.RUnlock()
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
 
-	entry, ok := r.backend.refreshTokens[tokenHash]
-	if !ok || time.Now().After(entry.expiresAt) {
-		return uuid.Nil, models.ErrInvalidToken
+	// updateUser overwrites both fields, so pass the current public key through
+	tx, err := r.backend.contract.UpdateUser(auth, uuidToBytes32(id), passwordHash, user.PublicKey)
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		return err
 	}
-	return entry.userID, nil
-}
 
-func (r *UserRepository) DeleteRefreshToken(ctx context.Context, tokenHash string) error {
-	r.backend.refreshTokenMutex.Lock()
-	defer r.backend.refreshTokenMutex.Unlock()
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
 
-	delete(r.backend.refreshTokens, tokenHash)
 	return nil
 }
 
-func (r *UserRepository) DeleteAllRefreshTokens(ctx context.Context, userID uuid.UUID) error {
-	r.backend.refreshTokenMutex.Lock()
-	defer r.backend.refreshTokenMutex.Unlock()
+func (r *UserRepository) ChangeUsername(ctx context.Context, id uuid.UUID, newUsername string, reservedUntil time.Time) error {
+	newUsername = strings.ToLower(newUsername)
 
-	for hash, entry := range r.backend.refreshTokens {
-		if entry.userID == userID {
-			delete(r.backend.refreshTokens, hash)
-		}
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
 	}
-	return nil
-}
-
-// ============ FriendRepository ============
 
-type FriendRepository struct {
-	backend *Backend
-}
+	tx, err := r.backend.contract.ChangeUsername(
+		auth,
+		uuidToBytes32(id),
+		newUsername,
+		usernameSkeletonHash(newUsername),
+		big.NewInt(reservedUntil.Unix()),
+	)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "User not found"):
+			return models.ErrUserNotFound
+		case strings.Contains(err.Error(), "Username already taken"):
+			return models.ErrUsernameExists
+		case strings.Contains(err.Error(), "Username too similar"):
+			return models.ErrUsernameConfusable
+		case strings.Contains(err.Error(), "Username is reserved"):
+			return models.ErrUsernameReserved
+		}
+		return err
+	}
 
-func (r *FriendRepository) CreateFriendRequest(ctx context.Context, fromUserID, toUserID uuid.UUID) (*models.FriendRequest, error) {
-	// Check if already friends
-	areFriends, err := r.AreFriends(ctx, fromUserID, toUserID)
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if areFriends {
-		return nil, models.ErrAlreadyFriends
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
 	}
 
-	requestID := uuid.New()
+	return nil
+}
 
+func (r *UserRepository) RotatePublicKey(ctx context.Context, id uuid.UUID, expectedVersion int, publicKey string) (*models.PublicKeyVersion, error) {
 	auth, err := r.backend.getTransactOpts(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := r.backend.contract.CreateFriendRequest(
-		auth,
-		uuidToBytes32(requestID),
-		uuidToBytes32(fromUserID),
-		uuidToBytes32(toUserID),
-	)
+	tx, err := r.backend.contract.RotatePublicKey(auth, uuidToBytes32(id), big.NewInt(int64(expectedVersion)), publicKey)
 	if err != nil {
-		errStr := strings.ToLower(err.Error())
-		if strings.Contains(errStr, "request already exists") {
-			return nil, models.ErrFriendRequestExists
-		}
-		if strings.Contains(errStr, "already friends") {
-			return nil, models.ErrAlreadyFriends
+		if strings.Contains(err.Error(), "User not found") {
+			return nil, models.ErrUserNotFound
 		}
-		if strings.Contains(errStr, "cannot send request to self") {
-			return nil, models.ErrCannotAddSelf
+		if strings.Contains(err.Error(), "Key version mismatch") {
+			return nil, models.ErrKeyVersionConflict
 		}
-		return nil, fmt.Errorf("blockchain error: %w", err)
+		return nil, err
 	}
 
 	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
@@ -316,92 +458,55 @@ func (r *FriendRepository) CreateFriendRequest(ctx context.Context, fromUserID,
 		return nil, fmt.Errorf("transaction failed")
 	}
 
-	return &models.FriendRequest{
-		ID:         requestID,
-		FromUserID: fromUserID,
-		ToUserID:   toUserID,
-		Status:     models.FriendRequestPending,
-		CreatedAt:  time.Now(),
-	}, nil
-}
-
-func (r *FriendRepository) GetPendingRequests(ctx context.Context, userID uuid.UUID) ([]models.FriendRequestWithUser, error) {
-	requestIds, err := r.backend.contract.GetPendingRequestsForUser(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
+	// Read back the entry so ValidFrom reflects the block timestamp
+	history, err := r.GetKeyHistory(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-
-	var requests []models.FriendRequestWithUser
-	for _, reqID := range requestIds {
-		result, err := r.backend.contract.GetFriendRequest(&bind.CallOpts{Context: ctx}, reqID)
-		if err != nil {
-			continue
-		}
-
-		fromUserID := bytes32ToUUID(result.FromUserId)
-		fromUser, err := r.backend.users.GetByID(ctx, fromUserID)
-		if err != nil {
-			continue
-		}
-
-		req := models.FriendRequestWithUser{
-			FriendRequest: models.FriendRequest{
-				ID:         bytes32ToUUID(result.RequestId),
-				FromUserID: fromUserID,
-				ToUserID:   bytes32ToUUID(result.ToUserId),
-				Status:     models.FriendRequestStatus([]string{"pending", "accepted", "rejected"}[result.Status]),
-				CreatedAt:  time.Unix(result.CreatedAt.Int64(), 0),
-			},
-			FromUser: models.UserPublic{
-				ID:        fromUser.ID,
-				Username:  fromUser.Username,
-				PublicKey: fromUser.PublicKey,
-			},
-		}
-		requests = append(requests, req)
+	if len(history) == 0 {
+		return nil, fmt.Errorf("key history is empty after rotation")
 	}
-
-	return requests, nil
+	return &history[len(history)-1], nil
 }
 
-func (r *FriendRepository) GetFriendRequest(ctx context.Context, requestID uuid.UUID) (*models.FriendRequest, error) {
-	result, err := r.backend.contract.GetFriendRequest(&bind.CallOpts{Context: ctx}, uuidToBytes32(requestID))
+func (r *UserRepository) GetKeyHistory(ctx context.Context, id uuid.UUID) ([]models.PublicKeyVersion, error) {
+	result, err := r.backend.contract.GetKeyHistory(&bind.CallOpts{Context: ctx}, uuidToBytes32(id))
 	if err != nil {
-		if strings.Contains(err.Error(), "Friend request not found") {
-			return nil, models.ErrFriendRequestNotFound
+		if strings.Contains(err.Error(), "User not found") {
+			return nil, models.ErrUserNotFound
 		}
 		return nil, err
 	}
 
-	return &models.FriendRequest{
-		ID:         bytes32ToUUID(result.RequestId),
-		FromUserID: bytes32ToUUID(result.FromUserId),
-		ToUserID:   bytes32ToUUID(result.ToUserId),
-		Status:     models.FriendRequestStatus([]string{"pending", "accepted", "rejected"}[result.Status]),
-		CreatedAt:  time.Unix(result.CreatedAt.Int64(), 0),
-	}, nil
+	history := make([]models.PublicKeyVersion, 0, len(result.PublicKeys))
+	for i, publicKey := range result.PublicKeys {
+		k := models.PublicKeyVersion{
+			Version:   i + 1,
+			PublicKey: publicKey,
+			ValidFrom: time.Unix(result.ValidFrom[i].Int64(), 0),
+		}
+		if until := result.ValidUntil[i].Int64(); until != 0 {
+			validUntil := time.Unix(until, 0)
+			k.ValidUntil = &validUntil
+		}
+		history = append(history, k)
+	}
+
+	return history, nil
 }
 
-func (r *FriendRepository) UpdateFriendRequestStatus(ctx context.Context, requestID uuid.UUID, status models.FriendRequestStatus) error {
+// Delete tombstones the user on-chain, which frees the username and removes
+// their friendships, friend requests either way and undelivered messages
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	auth, err := r.backend.getTransactOpts(ctx)
 	if err != nil {
 		return err
 	}
 
-	var statusVal uint8
-	switch status {
-	case models.FriendRequestPending:
-		statusVal = 0
-	case models.FriendRequestAccepted:
-		statusVal = 1
-	case models.FriendRequestRejected:
-		statusVal = 2
-	}
-
-	tx, err := r.backend.contract.UpdateFriendRequestStatus(auth, uuidToBytes32(requestID), statusVal)
+	tx, err := r.backend.contract.DeleteUser(auth, uuidToBytes32(id))
 	if err != nil {
-		if strings.Contains(err.Error(), "Friend request not found") {
-			return models.ErrFriendRequestNotFound
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
 		}
 		return err
 	}
@@ -414,113 +519,171 @@ func (r *FriendRepository) UpdateFriendRequestStatus(ctx context.Context, reques
 		return fmt.Errorf("transaction failed")
 	}
 
-	return nil
-}
-
-func (r *FriendRepository) CreateFriendship(ctx context.Context, userAID, userBID uuid.UUID) error {
-	friendshipID := uuid.New()
+	r.backend.notificationMutex.Lock()
+	delete(r.backend.notificationStore, id)
+	r.backend.notificationMutex.Unlock()
 
-	auth, err := r.backend.getTransactOpts(ctx)
-	if err != nil {
+	if err := r.backend.forgetUser(ctx, id); err != nil {
 		return err
 	}
 
-	tx, err := r.backend.contract.CreateFriendship(
-		auth,
-		uuidToBytes32(friendshipID),
-		uuidToBytes32(userAID),
-		uuidToBytes32(userBID),
-	)
-	if err != nil {
-		return err
-	}
+	return r.DeleteAllRefreshTokens(ctx, id)
+}
 
-	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
-	if err != nil {
-		return err
-	}
-	if receipt.Status == 0 {
-		return fmt.Errorf("transaction failed")
-	}
+// Refresh tokens are stored in-memory (not on-chain) for performance
+func (r *UserRepository) StoreRefreshToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	r.backend.refreshTokenMutex.Lock()
+	defer r.backend.refreshTokenMutex.Unlock()
 
+	r.backend.refreshTokens[tokenHash] = refreshTokenEntry{
+		userID:    userID,
+		expiresAt: expiresAt,
+	}
 	return nil
 }
 
-func (r *FriendRepository) AreFriends(ctx context.Context, userAID, userBID uuid.UUID) (bool, error) {
-	return r.backend.contract.AreFriends(&bind.CallOpts{Context: ctx}, uuidToBytes32(userAID), uuidToBytes32(userBID))
-}
+func (r *UserRepository) ValidateRefreshToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	r.backend.refreshTokenMutex.RLock()
+	defer r.backend.refreshTokenMutex.RUnlock()
 
-func (r *FriendRepository) GetFriends(ctx context.Context, userID uuid.UUID) ([]models.Friend, error) {
-	friendIds, err := r.backend.contract.GetFriendsOfUser(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
-	if err != nil {
-		return nil, err
+	entry, ok := r.backend.refreshTokens[tokenHash]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return uuid.Nil, models.ErrInvalidToken
 	}
+	return entry.userID, nil
+}
 
-	var friends []models.Friend
-	for _, friendID := range friendIds {
-		friendUser, err := r.backend.users.GetByID(ctx, bytes32ToUUID(friendID))
-		if err != nil {
+func (r *UserRepository) DeleteRefreshToken(ctx context.Context, tokenHash string) error {
+	r.backend.refreshTokenMutex.Lock()
+	defer r.backend.refreshTokenMutex.Unlock()
+
+	delete(r.backend.refreshTokens, tokenHash)
+	return nil
+}
+
+func (r *UserRepository) DeleteAllRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	r.backend.refreshTokenMutex.Lock()
+	defer r.backend.refreshTokenMutex.Unlock()
+
+	for hash, entry := range r.backend.refreshTokens {
+		if entry.userID == userID {
+			delete(r.backend.refreshTokens, hash)
+		}
+	}
+	return nil
+}
+
+// Search pages through the local username index, then leaves out the viewer
+// and users who have blocked them
+func (r *UserRepository) Search(ctx context.Context, viewerID uuid.UUID, prefix, after string, limit int) ([]models.UserPublic, error) {
+	ids, err := r.backend.userIndex.search(ctx, r.backend, prefix, after)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []models.UserPublic
+	for _, id := range ids {
+		if len(users) == limit {
+			break
+		}
+		if id == viewerID {
 			continue
 		}
 
-		// Get friendship creation time
-		friendships, _ := r.backend.contract.GetUserFriendships(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
-		var since time.Time
-		for _, fshipID := range friendships {
-			fship, err := r.backend.contract.GetFriendship(&bind.CallOpts{Context: ctx}, fshipID)
-			if err != nil {
-				continue
-			}
-			if bytes32ToUUID(fship.UserAId) == friendUser.ID || bytes32ToUUID(fship.UserBId) == friendUser.ID {
-				since = time.Unix(fship.CreatedAt.Int64(), 0)
-				break
-			}
+		blocked, err := r.backend.blocks.IsBlocked(ctx, id, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			continue
 		}
 
-		friends = append(friends, models.Friend{
-			UserID:    friendUser.ID,
-			Username:  friendUser.Username,
-			PublicKey: friendUser.PublicKey,
-			Since:     since,
-		})
+		user, err := r.GetByID(ctx, id)
+		if err != nil {
+			continue
+		}
+		users = append(users, user.ToPublic())
 	}
 
-	return friends, nil
+	return users, nil
 }
 
-// ============ MessageRepository ============
+func (r *UserRepository) GetPrivacySettings(ctx context.Context, id uuid.UUID) (*models.PrivacySettings, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
 
-type MessageRepository struct {
-	backend *Backend
+	flags, err := r.backend.contract.PrivacyFlags(&bind.CallOpts{Context: ctx}, uuidToBytes32(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PrivacySettings{
+		ShowInSuggestions: flags.Uint64()&privacyHideFromSuggestions == 0,
+		Discoverable:      flags.Uint64()&privacyHideFromSearch == 0,
+	}, nil
 }
 
-func (r *MessageRepository) Create(ctx context.Context, msg *models.Message) error {
-	msg.ID = uuid.New()
-	msg.CreatedAt = time.Now()
+func (r *UserRepository) UpdatePrivacySettings(ctx context.Context, id uuid.UUID, settings *models.PrivacySettings) error {
+	// Keep bits this server version doesn't know about
+	flags, err := r.backend.contract.PrivacyFlags(&bind.CallOpts{Context: ctx}, uuidToBytes32(id))
+	if err != nil {
+		return err
+	}
+	bits := flags.Uint64() &^ (privacyHideFromSuggestions | privacyHideFromSearch)
+	if !settings.ShowInSuggestions {
+		bits |= privacyHideFromSuggestions
+	}
+	if !settings.Discoverable {
+		bits |= privacyHideFromSearch
+	}
 
 	auth, err := r.backend.getTransactOpts(ctx)
 	if err != nil {
 		return err
 	}
 
-	var contentType uint8
-	switch msg.ContentType {
-	case models.ContentTypeText:
-		contentType = 0
-	case models.ContentTypeImage:
-		contentType = 1
+	tx, err := r.backend.contract.SetPrivacyFlags(auth, uuidToBytes32(id), new(big.Int).SetUint64(bits))
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		return err
 	}
 
-	tx, err := r.backend.contract.CreateMessage(
-		auth,
-		uuidToBytes32(msg.ID),
-		uuidToBytes32(msg.FromUserID),
-		uuidToBytes32(msg.ToUserID),
-		msg.EncryptedContent,
-		contentType,
-		msg.Signature,
-	)
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *UserRepository) GetByUserNumber(ctx context.Context, userNumber int64) (*models.User, error) {
+	id, ok, err := r.backend.userIndex.lookupNumber(ctx, r.backend, userNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, models.ErrUserNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *UserRepository) SetSuspended(ctx context.Context, id uuid.UUID, suspended bool) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.SetSuspended(auth, uuidToBytes32(id), suspended)
 	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
 		return err
 	}
 
@@ -535,73 +698,222 @@ func (r *MessageRepository) Create(ctx context.Context, msg *models.Message) err
 	return nil
 }
 
-func (r *MessageRepository) GetPendingMessages(ctx context.Context, userID uuid.UUID) ([]models.MessageWithSender, error) {
-	messageIds, err := r.backend.contract.GetMessagesForUser(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
+func (r *UserRepository) GetSuspension(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	result, err := r.backend.contract.GetSuspension(&bind.CallOpts{Context: ctx}, uuidToBytes32(id))
 	if err != nil {
 		return nil, err
 	}
+	if !result.Exists {
+		return nil, models.ErrUserNotFound
+	}
 
-	var messages []models.MessageWithSender
-	for _, msgID := range messageIds {
-		result, err := r.backend.contract.GetMessage(&bind.CallOpts{Context: ctx}, msgID)
+	if result.Since.Sign() == 0 {
+		return nil, nil
+	}
+	suspendedAt := time.Unix(result.Since.Int64(), 0)
+	return &suspendedAt, nil
+}
+
+// ============ FriendRepository ============
+
+type FriendRepository struct {
+	backend *Backend
+}
+
+func (r *FriendRepository) CreateFriendRequest(ctx context.Context, fromUserID, toUserID uuid.UUID) (*models.FriendRequest, error) {
+	// Check if already friends
+	areFriends, err := r.AreFriends(ctx, fromUserID, toUserID)
+	if err != nil {
+		return nil, err
+	}
+	if areFriends {
+		return nil, models.ErrAlreadyFriends
+	}
+
+	requestID := uuid.New()
+
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.backend.contract.CreateFriendRequest(
+		auth,
+		uuidToBytes32(requestID),
+		uuidToBytes32(fromUserID),
+		uuidToBytes32(toUserID),
+	)
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "request already exists") {
+			return nil, models.ErrFriendRequestExists
+		}
+		if strings.Contains(errStr, "already friends") {
+			return nil, models.ErrAlreadyFriends
+		}
+		if strings.Contains(errStr, "cannot send request to self") {
+			return nil, models.ErrCannotAddSelf
+		}
+		return nil, fmt.Errorf("blockchain error: %w", err)
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status == 0 {
+		return nil, fmt.Errorf("transaction failed")
+	}
+
+	return &models.FriendRequest{
+		ID:         requestID,
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Status:     models.FriendRequestPending,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func (r *FriendRepository) GetPendingRequests(ctx context.Context, userID uuid.UUID, maxAge time.Duration) ([]models.FriendRequestWithUser, error) {
+	createdAfter := big.NewInt(time.Now().Add(-maxAge).Unix())
+	requestIds, err := r.backend.contract.GetPendingRequestsForUser(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID), createdAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []models.FriendRequestWithUser
+	for _, reqID := range requestIds {
+		result, err := r.backend.contract.GetFriendRequest(&bind.CallOpts{Context: ctx}, reqID)
 		if err != nil {
 			continue
 		}
 
-		fromUser, err := r.backend.users.GetByID(ctx, bytes32ToUUID(result.FromUserId))
+		fromUserID := bytes32ToUUID(result.FromUserId)
+		fromUser, err := r.backend.users.GetByID(ctx, fromUserID)
 		if err != nil {
 			continue
 		}
 
-		msg := models.MessageWithSender{
-			Message: models.Message{
-				ID:               bytes32ToUUID(result.MessageId),
-				FromUserID:       bytes32ToUUID(result.FromUserId),
-				ToUserID:         bytes32ToUUID(result.ToUserId),
-				EncryptedContent: result.EncryptedContent,
-				ContentType:      models.ContentType([]string{"text", "image"}[result.ContentType]),
-				Signature:        result.Signature,
-				CreatedAt:        time.Unix(result.CreatedAt.Int64(), 0),
+		req := models.FriendRequestWithUser{
+			FriendRequest: models.FriendRequest{
+				ID:         bytes32ToUUID(result.RequestId),
+				FromUserID: fromUserID,
+				ToUserID:   bytes32ToUUID(result.ToUserId),
+				Status:     friendRequestStatuses[result.Status],
+				CreatedAt:  time.Unix(result.CreatedAt.Int64(), 0),
+			},
+			FromUser: models.UserPublic{
+				ID:         fromUser.ID,
+				Username:   fromUser.Username,
+				PublicKey:  fromUser.PublicKey,
+				KeyVersion: fromUser.KeyVersion,
 			},
-			FromUsername:  fromUser.Username,
-			FromPublicKey: fromUser.PublicKey,
 		}
-		messages = append(messages, msg)
+		requests = append(requests, req)
 	}
 
-	return messages, nil
+	return requests, nil
 }
 
-func (r *MessageRepository) GetByID(ctx context.Context, messageID uuid.UUID) (*models.Message, error) {
-	result, err := r.backend.contract.GetMessage(&bind.CallOpts{Context: ctx}, uuidToBytes32(messageID))
+func (r *FriendRepository) GetOutgoingRequests(ctx context.Context, userID uuid.UUID, maxAge time.Duration) ([]models.OutgoingFriendRequest, error) {
+	createdAfter := time.Now().Add(-maxAge)
+
+	requestIds, err := r.backend.contract.GetRequestsFromUser(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
 	if err != nil {
-		if strings.Contains(err.Error(), "Message not found") {
-			return nil, models.ErrMessageNotFound
+		return nil, err
+	}
+
+	var requests []models.OutgoingFriendRequest
+	for _, reqID := range requestIds {
+		request, err := r.GetFriendRequest(ctx, bytes32ToUUID(reqID))
+		if err != nil {
+			continue
+		}
+		if request.Status != models.FriendRequestPending && request.Status != models.FriendRequestRejected {
+			continue
+		}
+		if !request.CreatedAt.After(createdAfter) {
+			continue
+		}
+
+		toUser, err := r.backend.users.GetByID(ctx, request.ToUserID)
+		if err != nil {
+			continue
+		}
+
+		requests = append(requests, models.OutgoingFriendRequest{
+			FriendRequest: *request,
+			ToUser: models.UserPublic{
+				ID:         toUser.ID,
+				Username:   toUser.Username,
+				PublicKey:  toUser.PublicKey,
+				KeyVersion: toUser.KeyVersion,
+			},
+		})
+	}
+
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.After(requests[j].CreatedAt)
+	})
+
+	return requests, nil
+}
+
+func (r *FriendRepository) GetFriendRequest(ctx context.Context, requestID uuid.UUID) (*models.FriendRequest, error) {
+	result, err := r.backend.contract.GetFriendRequest(&bind.CallOpts{Context: ctx}, uuidToBytes32(requestID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Friend request not found") {
+			return nil, models.ErrFriendRequestNotFound
 		}
 		return nil, err
 	}
 
-	return &models.Message{
-		ID:               bytes32ToUUID(result.MessageId),
-		FromUserID:       bytes32ToUUID(result.FromUserId),
-		ToUserID:         bytes32ToUUID(result.ToUserId),
-		EncryptedContent: result.EncryptedContent,
-		ContentType:      models.ContentType([]string{"text", "image"}[result.ContentType]),
-		Signature:        result.Signature,
-		CreatedAt:        time.Unix(result.CreatedAt.Int64(), 0),
+	return &models.FriendRequest{
+		ID:         bytes32ToUUID(result.RequestId),
+		FromUserID: bytes32ToUUID(result.FromUserId),
+		ToUserID:   bytes32ToUUID(result.ToUserId),
+		Status:     friendRequestStatuses[result.Status],
+		CreatedAt:  time.Unix(result.CreatedAt.Int64(), 0),
+		UpdatedAt:  time.Unix(result.UpdatedAt.Int64(), 0),
 	}, nil
 }
 
-func (r *MessageRepository) Delete(ctx context.Context, messageID uuid.UUID) error {
+func (r *FriendRepository) GetFriendRequestBetween(ctx context.Context, fromUserID, toUserID uuid.UUID) (*models.FriendRequest, error) {
+	requestID, err := r.backend.contract.FriendRequestByUsers(&bind.CallOpts{Context: ctx}, uuidToBytes32(fromUserID), uuidToBytes32(toUserID))
+	if err != nil {
+		return nil, err
+	}
+	if requestID == [32]byte{} {
+		return nil, models.ErrFriendRequestNotFound
+	}
+
+	return r.GetFriendRequest(ctx, bytes32ToUUID(requestID))
+}
+
+func (r *FriendRepository) UpdateFriendRequestStatus(ctx context.Context, requestID uuid.UUID, status models.FriendRequestStatus) error {
 	auth, err := r.backend.getTransactOpts(ctx)
 	if err != nil {
 		return err
 	}
 
-	tx, err := r.backend.contract.DeleteMessage(auth, uuidToBytes32(messageID))
+	var statusVal uint8
+	switch status {
+	case models.FriendRequestPending:
+		statusVal = 0
+	case models.FriendRequestAccepted:
+		statusVal = 1
+	case models.FriendRequestRejected:
+		statusVal = 2
+	case models.FriendRequestCancelled:
+		statusVal = 3
+	case models.FriendRequestExpired:
+		statusVal = 4
+	}
+
+	tx, err := r.backend.contract.UpdateFriendRequestStatus(auth, uuidToBytes32(requestID), statusVal)
 	if err != nil {
-		if strings.Contains(err.Error(), "Message not found") {
-			return models.ErrMessageNotFound
+		if strings.Contains(err.Error(), "Friend request not found") || strings.Contains(err.Error(), "Friend request not open") {
+			return models.ErrFriendRequestNotFound
 		}
 		return err
 	}
@@ -617,9 +929,1695 @@ func (r *MessageRepository) Delete(ctx context.Context, messageID uuid.UUID) err
 	return nil
 }
 
-func (r *MessageRepository) DeleteOldMessages(ctx context.Context, olderThan time.Duration) (int64, error) {
-	// Note: This would require iterating through all messages on-chain,
-	// which is expensive. For now, we return 0 as this operation is
-	// better handled by off-chain indexing or a scheduled task.
-	return 0, nil
+func (r *FriendRepository) AcceptFriendRequest(ctx context.Context, requestID uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.AcceptFriendRequest(auth, uuidToBytes32(requestID), uuidToBytes32(uuid.New()))
+	if err != nil {
+		if strings.Contains(err.Error(), "Friend request not found") || strings.Contains(err.Error(), "Friend request not open") {
+			return models.ErrFriendRequestNotFound
+		}
+		return fmt.Errorf("blockchain error: %w", err)
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+// ExpireFriendRequests sweeps the contract's request list in batches, oldest
+// first. Swept requests also leave the per-user lists the read paths scan.
+func (r *FriendRepository) ExpireFriendRequests(ctx context.Context, olderThan time.Duration) (int64, error) {
+	createdBefore := big.NewInt(time.Now().Add(-olderThan).Unix())
+
+	var expired int64
+	for {
+		pending, err := r.backend.contract.HasExpiredFriendRequests(&bind.CallOpts{Context: ctx}, createdBefore)
+		if err != nil {
+			return expired, err
+		}
+		if !pending {
+			return expired, nil
+		}
+
+		auth, err := r.backend.getTransactOpts(ctx)
+		if err != nil {
+			return expired, err
+		}
+
+		tx, err := r.backend.contract.ExpireFriendRequests(auth, createdBefore, big.NewInt(friendRequestExpiryBatch))
+		if err != nil {
+			return expired, fmt.Errorf("blockchain error: %w", err)
+		}
+
+		receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+		if err != nil {
+			return expired, err
+		}
+		if receipt.Status == 0 {
+			return expired, fmt.Errorf("transaction failed")
+		}
+
+		for _, log := range receipt.Logs {
+			if log.Address != r.backend.contractAddress {
+				continue
+			}
+			event, err := r.backend.contract.ParseFriendRequestUpdated(*log)
+			if err != nil {
+				continue
+			}
+			if friendRequestStatuses[event.Status] == models.FriendRequestExpired {
+				expired++
+			}
+		}
+	}
+}
+
+func (r *FriendRepository) CreateFriendship(ctx context.Context, userAID, userBID uuid.UUID) error {
+	friendshipID := uuid.New()
+
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.CreateFriendship(
+		auth,
+		uuidToBytes32(friendshipID),
+		uuidToBytes32(userAID),
+		uuidToBytes32(userBID),
+	)
+	if err != nil {
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *FriendRepository) RemoveFriendship(ctx context.Context, userAID, userBID uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.RemoveFriendship(auth, uuidToBytes32(userAID), uuidToBytes32(userBID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Friendship not found") {
+			return models.ErrNotFriends
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return r.backend.forgetFriendship(ctx, userAID, userBID)
+}
+
+func (r *FriendRepository) AreFriends(ctx context.Context, userAID, userBID uuid.UUID) (bool, error) {
+	return r.backend.contract.AreFriends(&bind.CallOpts{Context: ctx}, uuidToBytes32(userAID), uuidToBytes32(userBID))
+}
+
+// GetFriends reads the friendships from the local friendship view, then
+// each friend's account, devices and profile from the contract
+func (r *FriendRepository) GetFriends(ctx context.Context, userID uuid.UUID) ([]models.Friend, error) {
+	friendships, err := r.backend.friendGraph.friendsOf(ctx, r.backend, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var friends []models.Friend
+	for friendID, since := range friendships {
+		friendUser, err := r.backend.users.GetByID(ctx, friendID)
+		if err != nil {
+			continue
+		}
+
+		verifiedVersion, err := r.GetVerifiedKeyVersion(ctx, userID, friendID)
+		if err != nil {
+			return nil, err
+		}
+
+		settings, err := r.GetFriendSettings(ctx, userID, friendID)
+		if err != nil {
+			return nil, err
+		}
+
+		devices, err := r.backend.devices.ListForUser(ctx, friendID)
+		if err != nil {
+			return nil, err
+		}
+
+		profile, err := r.backend.profiles.GetProfile(ctx, friendID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		streak, err := r.backend.streaks.GetStreak(ctx, userID, friendID)
+		if err != nil {
+			return nil, err
+		}
+
+		friends = append(friends, models.Friend{
+			UserID:         friendUser.ID,
+			Username:       friendUser.Username,
+			PublicKey:      friendUser.PublicKey,
+			KeyVersion:     friendUser.KeyVersion,
+			Devices:        devices,
+			Since:          since,
+			Verified:       verifiedVersion == friendUser.KeyVersion,
+			Profile:        *profile,
+			FriendSettings: *settings,
+			Streak:         *streak,
+		})
+	}
+
+	sort.Slice(friends, func(i, j int) bool {
+		return friends[i].Username < friends[j].Username
+	})
+
+	return friends, nil
+}
+
+// GetSuggestions ranks candidates from the local friendship view, then checks
+// the exclusions against the contract for the best ones only
+func (r *FriendRepository) GetSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]models.FriendSuggestion, error) {
+	counts, err := r.backend.friendGraph.mutualFriends(ctx, r.backend, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]uuid.UUID, 0, len(counts))
+	for candidateID := range counts {
+		candidates = append(candidates, candidateID)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if counts[candidates[i]] != counts[candidates[j]] {
+			return counts[candidates[i]] > counts[candidates[j]]
+		}
+		return candidates[i].String() < candidates[j].String()
+	})
+
+	var suggestions []models.FriendSuggestion
+	for _, candidateID := range candidates {
+		if len(suggestions) == limit {
+			break
+		}
+
+		excluded, err := r.excludedFromSuggestions(ctx, userID, candidateID)
+		if err != nil {
+			return nil, err
+		}
+		if excluded {
+			continue
+		}
+
+		user, err := r.backend.users.GetByID(ctx, candidateID)
+		if err != nil {
+			continue
+		}
+		suggestions = append(suggestions, models.FriendSuggestion{
+			UserPublic:    user.ToPublic(),
+			MutualFriends: counts[candidateID],
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].MutualFriends != suggestions[j].MutualFriends {
+			return suggestions[i].MutualFriends > suggestions[j].MutualFriends
+		}
+		return suggestions[i].Username < suggestions[j].Username
+	})
+
+	return suggestions, nil
+}
+
+func (r *FriendRepository) excludedFromSuggestions(ctx context.Context, userID, candidateID uuid.UUID) (bool, error) {
+	flags, err := r.backend.contract.PrivacyFlags(&bind.CallOpts{Context: ctx}, uuidToBytes32(candidateID))
+	if err != nil {
+		return false, err
+	}
+	if flags.Uint64()&privacyHideFromSuggestions != 0 {
+		return true, nil
+	}
+
+	for _, pair := range [][2]uuid.UUID{{userID, candidateID}, {candidateID, userID}} {
+		blocked, err := r.backend.blocks.IsBlocked(ctx, pair[0], pair[1])
+		if err != nil {
+			return false, err
+		}
+		if blocked {
+			return true, nil
+		}
+
+		request, err := r.GetFriendRequestBetween(ctx, pair[0], pair[1])
+		if errors.Is(err, models.ErrFriendRequestNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if request.Status == models.FriendRequestPending || request.Status == models.FriendRequestRejected {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *FriendRepository) SetVerifiedKeyVersion(ctx context.Context, userID, friendID uuid.UUID, keyVersion int) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.SetVerifiedKeyVersion(auth, uuidToBytes32(userID), uuidToBytes32(friendID), big.NewInt(int64(keyVersion)))
+	if err != nil {
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *FriendRepository) GetVerifiedKeyVersion(ctx context.Context, userID, friendID uuid.UUID) (int, error) {
+	version, err := r.backend.contract.VerifiedKeyVersion(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID), uuidToBytes32(friendID))
+	if err != nil {
+		return 0, err
+	}
+	return int(version.Int64()), nil
+}
+
+// Friend settings are stored off-chain so they stay private
+func (r *FriendRepository) GetFriendSettings(ctx context.Context, userID, friendID uuid.UUID) (*models.FriendSettings, error) {
+	query := `SELECT nickname, muted, pinned, message_ttl FROM friend_settings WHERE user_id = ? AND friend_id = ?`
+	var settings models.FriendSettings
+	err := r.backend.offchain.QueryRowContext(ctx, query, userID.String(), friendID.String()).Scan(
+		&settings.Nickname, &settings.Muted, &settings.Pinned, &settings.MessageTTL,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.FriendSettings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *FriendRepository) SetFriendSettings(ctx context.Context, userID, friendID uuid.UUID, settings *models.FriendSettings) error {
+	query := `
+		INSERT INTO friend_settings (user_id, friend_id, nickname, muted, pinned, message_ttl) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, friend_id) DO UPDATE SET
+			nickname = excluded.nickname, muted = excluded.muted,
+			pinned = excluded.pinned, message_ttl = excluded.message_ttl
+	`
+	_, err := r.backend.offchain.ExecContext(ctx, query, userID.String(), friendID.String(),
+		settings.Nickname, settings.Muted, settings.Pinned, settings.MessageTTL)
+	return err
+}
+
+// ============ BlockRepository ============
+
+type BlockRepository struct {
+	backend *Backend
+}
+
+func (r *BlockRepository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.BlockUser(auth, uuidToBytes32(blockerID), uuidToBytes32(blockedID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Already blocked") {
+			return models.ErrAlreadyBlocked
+		}
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		if strings.Contains(err.Error(), "Cannot block self") {
+			return models.ErrCannotBlockSelf
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return r.backend.forgetFriendship(ctx, blockerID, blockedID)
+}
+
+func (r *BlockRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.UnblockUser(auth, uuidToBytes32(blockerID), uuidToBytes32(blockedID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Not blocked") {
+			return models.ErrNotBlocked
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *BlockRepository) IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+	blockedAt, err := r.backend.contract.BlockedAt(&bind.CallOpts{Context: ctx}, uuidToBytes32(blockerID), uuidToBytes32(blockedID))
+	if err != nil {
+		return false, err
+	}
+	return blockedAt.Sign() > 0, nil
+}
+
+func (r *BlockRepository) ListBlocked(ctx context.Context, blockerID uuid.UUID) ([]models.BlockedUser, error) {
+	blockedIDs, err := r.backend.contract.GetBlockedUsers(&bind.CallOpts{Context: ctx}, uuidToBytes32(blockerID))
+	if err != nil {
+		return nil, err
+	}
+
+	var blocked []models.BlockedUser
+	for _, id := range blockedIDs {
+		user, err := r.backend.users.GetByID(ctx, bytes32ToUUID(id))
+		if err != nil {
+			continue
+		}
+
+		blockedAt, err := r.backend.contract.BlockedAt(&bind.CallOpts{Context: ctx}, uuidToBytes32(blockerID), id)
+		if err != nil {
+			continue
+		}
+
+		blocked = append(blocked, models.BlockedUser{
+			UserID:    user.ID,
+			Username:  user.Username,
+			BlockedAt: time.Unix(blockedAt.Int64(), 0),
+		})
+	}
+
+	return blocked, nil
+}
+
+// ============ InviteRepository ============
+
+type InviteRepository struct {
+	backend *Backend
+}
+
+func (r *InviteRepository) Create(ctx context.Context, invite *models.Invite) error {
+	invite.ID = uuid.New()
+	invite.CreatedAt = time.Now()
+
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.CreateInvite(
+		auth,
+		uuidToBytes32(invite.ID),
+		uuidToBytes32(invite.CreatorID),
+		big.NewInt(int64(invite.MaxUses)),
+		big.NewInt(invite.ExpiresAt.Unix()),
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *InviteRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Invite, error) {
+	result, err := r.backend.contract.Invites(&bind.CallOpts{Context: ctx}, uuidToBytes32(id))
+	if err != nil {
+		return nil, err
+	}
+	if !result.Exists {
+		return nil, models.ErrInviteNotFound
+	}
+
+	return &models.Invite{
+		ID:        bytes32ToUUID(result.Id),
+		CreatorID: bytes32ToUUID(result.CreatorId),
+		MaxUses:   int(result.MaxUses.Int64()),
+		Uses:      int(result.Uses.Int64()),
+		ExpiresAt: time.Unix(result.ExpiresAt.Int64(), 0),
+		Revoked:   result.Revoked,
+		CreatedAt: time.Unix(result.CreatedAt.Int64(), 0),
+	}, nil
+}
+
+func (r *InviteRepository) ListForUser(ctx context.Context, creatorID uuid.UUID) ([]models.Invite, error) {
+	inviteIDs, err := r.backend.contract.GetUserInvites(&bind.CallOpts{Context: ctx}, uuidToBytes32(creatorID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Newest first, as the contract appends
+	var invites []models.Invite
+	for i := len(inviteIDs) - 1; i >= 0; i-- {
+		invite, err := r.GetByID(ctx, bytes32ToUUID(inviteIDs[i]))
+		if err != nil {
+			continue
+		}
+		invites = append(invites, *invite)
+	}
+
+	return invites, nil
+}
+
+func (r *InviteRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.RevokeInvite(auth, uuidToBytes32(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "Invite not found") {
+			return models.ErrInviteNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *InviteRepository) Redeem(ctx context.Context, id, redeemerID uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.RedeemInvite(auth, uuidToBytes32(id), uuidToBytes32(redeemerID), uuidToBytes32(uuid.New()))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "Invite not found"):
+			return models.ErrInviteNotFound
+		case strings.Contains(err.Error(), "Invite expired"):
+			return models.ErrInviteExpired
+		case strings.Contains(err.Error(), "Cannot redeem own invite"):
+			return models.ErrCannotAddSelf
+		case strings.Contains(err.Error(), "Already friends"):
+			return models.ErrAlreadyFriends
+		case strings.Contains(err.Error(), "User not found"):
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+// ============ MessageRepository ============
+
+type MessageRepository struct {
+	backend *Backend
+}
+
+func (r *MessageRepository) Create(ctx context.Context, msg *models.Message) error {
+	msg.ID = uuid.New()
+	msg.CreatedAt = time.Now()
+
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	var contentType uint8
+	switch msg.ContentType {
+	case models.ContentTypeText:
+		contentType = 0
+	case models.ContentTypeImage:
+		contentType = 1
+	}
+
+	tx, err := r.backend.contract.CreateMessage(
+		auth,
+		uuidToBytes32(msg.ID),
+		uuidToBytes32(msg.FromUserID),
+		uuidToBytes32(msg.ToUserID),
+		msg.EncryptedContent,
+		contentType,
+		msg.Signature,
+	)
+	if err != nil {
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	// The contract records the recipient's key version when storing the message
+	keyVersion, err := r.backend.contract.MessageKeyVersion(&bind.CallOpts{Context: ctx}, uuidToBytes32(msg.ID))
+	if err != nil {
+		return err
+	}
+	msg.RecipientKeyVersion = int(keyVersion.Int64())
+
+	return nil
+}
+
+// CreateForDevices stores the message with one ciphertext per device. The
+// contract rejects the transaction unless every device of the recipient is
+// covered exactly once.
+func (r *MessageRepository) CreateForDevices(ctx context.Context, msg *models.Message, ciphertexts []models.DeviceCiphertext) error {
+	msg.ID = uuid.New()
+	msg.CreatedAt = time.Now()
+
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	var contentType uint8
+	switch msg.ContentType {
+	case models.ContentTypeText:
+		contentType = 0
+	case models.ContentTypeImage:
+		contentType = 1
+	}
+
+	deliveries := make([]QuickPicStorageDeviceCiphertext, len(ciphertexts))
+	for i, c := range ciphertexts {
+		deliveries[i] = QuickPicStorageDeviceCiphertext{
+			DeviceId:   uuidToBytes32(c.DeviceID),
+			Ciphertext: c.EncryptedContent,
+		}
+	}
+
+	tx, err := r.backend.contract.CreateDeviceMessage(
+		auth,
+		uuidToBytes32(msg.ID),
+		uuidToBytes32(msg.FromUserID),
+		uuidToBytes32(msg.ToUserID),
+		contentType,
+		msg.Signature,
+		deliveries,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "Device list mismatch") {
+			return models.ErrDeviceMismatch
+		}
+		if strings.Contains(err.Error(), "user not found") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	keyVersion, err := r.backend.contract.MessageKeyVersion(&bind.CallOpts{Context: ctx}, uuidToBytes32(msg.ID))
+	if err != nil {
+		return err
+	}
+	msg.RecipientKeyVersion = int(keyVersion.Int64())
+
+	return nil
+}
+
+func (r *MessageRepository) GetPendingMessages(ctx context.Context, userID uuid.UUID) ([]models.MessageWithSender, error) {
+	messageIds, err := r.backend.contract.GetMessagesForUser(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []models.MessageWithSender
+	for _, msgID := range messageIds {
+		// Per-device messages are only fetched through GetPendingForDevice
+		deviceIDs, err := r.backend.contract.GetMessageDevices(&bind.CallOpts{Context: ctx}, msgID)
+		if err != nil || len(deviceIDs) > 0 {
+			continue
+		}
+
+		msg, err := r.getWithSender(ctx, msgID)
+		if err != nil {
+			continue
+		}
+		messages = append(messages, *msg)
+	}
+
+	return messages, nil
+}
+
+func (r *MessageRepository) GetPendingForDevice(ctx context.Context, userID, deviceID uuid.UUID) ([]models.MessageWithSender, error) {
+	messageIds, err := r.backend.contract.GetMessagesForDevice(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID), uuidToBytes32(deviceID))
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []models.MessageWithSender
+	for _, msgID := range messageIds {
+		msg, err := r.getWithSender(ctx, msgID)
+		if err != nil {
+			continue
+		}
+
+		deviceIDs, err := r.backend.contract.GetMessageDevices(&bind.CallOpts{Context: ctx}, msgID)
+		if err != nil {
+			continue
+		}
+		if len(deviceIDs) > 0 {
+			ciphertext, err := r.backend.contract.GetDeliveryCiphertext(&bind.CallOpts{Context: ctx}, msgID, uuidToBytes32(deviceID))
+			if err != nil {
+				continue
+			}
+			msg.EncryptedContent = ciphertext
+			msg.DeviceID = &deviceID
+		}
+
+		messages = append(messages, *msg)
+	}
+
+	return messages, nil
+}
+
+func (r *MessageRepository) getWithSender(ctx context.Context, msgID [32]byte) (*models.MessageWithSender, error) {
+	result, err := r.backend.contract.GetMessage(&bind.CallOpts{Context: ctx}, msgID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromUser, err := r.backend.users.GetByID(ctx, bytes32ToUUID(result.FromUserId))
+	if err != nil {
+		return nil, err
+	}
+
+	keyVersion, err := r.backend.contract.MessageKeyVersion(&bind.CallOpts{Context: ctx}, msgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MessageWithSender{
+		Message: models.Message{
+			ID:                  bytes32ToUUID(result.MessageId),
+			FromUserID:          bytes32ToUUID(result.FromUserId),
+			ToUserID:            bytes32ToUUID(result.ToUserId),
+			EncryptedContent:    result.EncryptedContent,
+			ContentType:         models.ContentType([]string{"text", "image"}[result.ContentType]),
+			Signature:           result.Signature,
+			RecipientKeyVersion: int(keyVersion.Int64()),
+			CreatedAt:           time.Unix(result.CreatedAt.Int64(), 0),
+		},
+		FromUsername:  fromUser.Username,
+		FromPublicKey: fromUser.PublicKey,
+	}, nil
+}
+
+func (r *MessageRepository) GetByID(ctx context.Context, messageID uuid.UUID) (*models.Message, error) {
+	result, err := r.backend.contract.GetMessage(&bind.CallOpts{Context: ctx}, uuidToBytes32(messageID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Message not found") {
+			return nil, models.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	keyVersion, err := r.backend.contract.MessageKeyVersion(&bind.CallOpts{Context: ctx}, result.MessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceIDs, err := r.backend.contract.GetMessageDevices(&bind.CallOpts{Context: ctx}, result.MessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Message{
+		ID:                  bytes32ToUUID(result.MessageId),
+		FromUserID:          bytes32ToUUID(result.FromUserId),
+		ToUserID:            bytes32ToUUID(result.ToUserId),
+		EncryptedContent:    result.EncryptedContent,
+		ContentType:         models.ContentType([]string{"text", "image"}[result.ContentType]),
+		Signature:           result.Signature,
+		RecipientKeyVersion: int(keyVersion.Int64()),
+		CreatedAt:           time.Unix(result.CreatedAt.Int64(), 0),
+		FanOut:              len(deviceIDs) > 0,
+	}, nil
+}
+
+func (r *MessageRepository) Delete(ctx context.Context, messageID uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.DeleteMessage(auth, uuidToBytes32(messageID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Message not found") {
+			return models.ErrMessageNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *MessageRepository) AcknowledgeDelivery(ctx context.Context, messageID, deviceID uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.AcknowledgeDelivery(auth, uuidToBytes32(messageID), uuidToBytes32(deviceID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Message not found") || strings.Contains(err.Error(), "Delivery not found") {
+			return models.ErrMessageNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *MessageRepository) DeleteOldMessages(ctx context.Context, olderThan time.Duration) (int64, error) {
+	// Note: This would require iterating through all messages on-chain,
+	// which is expensive. For now, we return 0 as this operation is
+	// better handled by off-chain indexing or a scheduled task.
+	return 0, nil
+}
+
+// ============ DeviceRepository ============
+
+type DeviceRepository struct {
+	backend *Backend
+}
+
+func (r *DeviceRepository) Create(ctx context.Context, device *models.Device) error {
+	device.ID = uuid.New()
+	device.CreatedAt = time.Now()
+
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.AddDevice(
+		auth,
+		uuidToBytes32(device.ID),
+		uuidToBytes32(device.UserID),
+		device.Name,
+		device.PublicKey,
+		device.Signature,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		if strings.Contains(err.Error(), "Device key already registered") {
+			return models.ErrDeviceExists
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *DeviceRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Device, error) {
+	result, err := r.backend.contract.Devices(&bind.CallOpts{Context: ctx}, uuidToBytes32(id))
+	if err != nil {
+		return nil, err
+	}
+	if !result.Exists {
+		return nil, models.ErrDeviceNotFound
+	}
+
+	return &models.Device{
+		ID:        bytes32ToUUID(result.Id),
+		UserID:    bytes32ToUUID(result.UserId),
+		Name:      result.Name,
+		PublicKey: result.PublicKey,
+		Signature: result.Signature,
+		CreatedAt: time.Unix(result.CreatedAt.Int64(), 0),
+	}, nil
+}
+
+func (r *DeviceRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	deviceIDs, err := r.backend.contract.GetDevices(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []models.Device
+	for _, id := range deviceIDs {
+		device, err := r.GetByID(ctx, bytes32ToUUID(id))
+		if err != nil {
+			continue
+		}
+		devices = append(devices, *device)
+	}
+
+	return devices, nil
+}
+
+func (r *DeviceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.RemoveDevice(auth, uuidToBytes32(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "Device not found") {
+			return models.ErrDeviceNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+// ============ PreKeyRepository ============
+
+type PreKeyRepository struct {
+	backend *Backend
+}
+
+func (r *PreKeyRepository) SetSignedPreKey(ctx context.Context, userID uuid.UUID, key *models.SignedPreKey) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.SetSignedPreKey(
+		auth,
+		uuidToBytes32(userID),
+		big.NewInt(int64(key.KeyID)),
+		key.PublicKey,
+		key.Signature,
+		big.NewInt(int64(key.IdentityKeyVersion)),
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	key.CreatedAt = time.Now()
+	return nil
+}
+
+func (r *PreKeyRepository) GetSignedPreKey(ctx context.Context, userID uuid.UUID) (*models.SignedPreKey, error) {
+	result, err := r.backend.contract.GetSignedPreKey(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Signed prekey not found") {
+			return nil, models.ErrPreKeyBundleNotFound
+		}
+		return nil, err
+	}
+
+	return &models.SignedPreKey{
+		KeyID:              uint32(result.KeyId.Uint64()),
+		PublicKey:          result.PublicKey,
+		Signature:          result.Signature,
+		IdentityKeyVersion: int(result.IdentityKeyVersion.Int64()),
+		CreatedAt:          time.Unix(result.CreatedAt.Int64(), 0),
+	}, nil
+}
+
+func (r *PreKeyRepository) AddOneTimePreKeys(ctx context.Context, userID uuid.UUID, keys []models.OneTimePreKey, replace bool) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	keyIDs := make([]*big.Int, len(keys))
+	publicKeys := make([]string, len(keys))
+	for i, key := range keys {
+		keyIDs[i] = big.NewInt(int64(key.KeyID))
+		publicKeys[i] = key.PublicKey
+	}
+
+	tx, err := r.backend.contract.AddOneTimePreKeys(auth, uuidToBytes32(userID), keyIDs, publicKeys, replace)
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		if strings.Contains(err.Error(), "Prekey ID already used") {
+			return models.ErrPreKeyIDUsed
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *PreKeyRepository) CountOneTimePreKeys(ctx context.Context, userID uuid.UUID) (int, error) {
+	count, err := r.backend.contract.GetOneTimePreKeyCount(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
+	if err != nil {
+		return 0, err
+	}
+	return int(count.Int64()), nil
+}
+
+// ClaimOneTimePreKey pops a key in a transaction, so claims are serialized by
+// the chain. The claimed key is read back from the PreKeyClaimed event.
+func (r *PreKeyRepository) ClaimOneTimePreKey(ctx context.Context, userID uuid.UUID) (*models.OneTimePreKey, error) {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.backend.contract.ClaimOneTimePreKey(auth, uuidToBytes32(userID))
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return nil, models.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status == 0 {
+		return nil, fmt.Errorf("transaction failed")
+	}
+
+	for _, log := range receipt.Logs {
+		if log.Address != r.backend.contractAddress {
+			continue
+		}
+		event, err := r.backend.contract.ParsePreKeyClaimed(*log)
+		if err != nil {
+			continue
+		}
+		return &models.OneTimePreKey{
+			KeyID:     uint32(event.KeyId.Uint64()),
+			PublicKey: event.PublicKey,
+		}, nil
+	}
+
+	// No event means the user had no one-time prekeys left
+	return nil, nil
+}
+
+// ============ NotificationRepository ============
+
+// Notifications are stored in-memory (not on-chain) since they are
+// short-lived and only meaningful to the recipient
+type NotificationRepository struct {
+	backend *Backend
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	notification.ID = uuid.New()
+	notification.CreatedAt = time.Now()
+
+	r.backend.notificationMutex.Lock()
+	defer r.backend.notificationMutex.Unlock()
+
+	r.backend.notificationStore[notification.UserID] = append(r.backend.notificationStore[notification.UserID], *notification)
+	return nil
+}
+
+func (r *NotificationRepository) GetForUser(ctx context.Context, userID uuid.UUID) ([]models.Notification, error) {
+	r.backend.notificationMutex.RLock()
+	defer r.backend.notificationMutex.RUnlock()
+
+	stored := r.backend.notificationStore[userID]
+	notifications := make([]models.Notification, len(stored))
+	copy(notifications, stored)
+	return notifications, nil
+}
+
+func (r *NotificationRepository) Delete(ctx context.Context, userID, notificationID uuid.UUID) error {
+	r.backend.notificationMutex.Lock()
+	defer r.backend.notificationMutex.Unlock()
+
+	stored := r.backend.notificationStore[userID]
+	for i, n := range stored {
+		if n.ID == notificationID {
+			r.backend.notificationStore[userID] = append(stored[:i], stored[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrNotificationNotFound
+}
+
+// ============ ProfileRepository ============
+
+type ProfileRepository struct {
+	backend *Backend
+}
+
+// avatarIDToBytes32 converts the hex SHA-256 avatar id to the contract's key
+func avatarIDToBytes32(id string) ([32]byte, error) {
+	var result [32]byte
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != len(result) {
+		return result, models.ErrAvatarChanged
+	}
+	copy(result[:], b)
+	return result, nil
+}
+
+func (r *ProfileRepository) SetDisplayName(ctx context.Context, userID uuid.UUID, displayName string) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.SetDisplayName(auth, uuidToBytes32(userID), displayName)
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *ProfileRepository) GetProfile(ctx context.Context, userID, viewerID uuid.UUID) (*models.Profile, error) {
+	result, err := r.backend.contract.GetProfile(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID), uuidToBytes32(viewerID))
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return nil, models.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	profile := &models.Profile{DisplayName: result.DisplayName}
+	if result.AvatarId != ([32]byte{}) {
+		profile.Avatar = &models.AvatarRef{
+			ID:         hex.EncodeToString(result.AvatarId[:]),
+			WrappedKey: base64.StdEncoding.EncodeToString(result.WrappedKey),
+		}
+	}
+	return profile, nil
+}
+
+func (r *ProfileRepository) SetAvatar(ctx context.Context, userID uuid.UUID, avatar *models.Avatar) error {
+	id, err := avatarIDToBytes32(avatar.ID)
+	if err != nil {
+		return err
+	}
+
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.SetAvatar(auth, uuidToBytes32(userID), id, avatar.Data)
+	if err != nil {
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	// Read back so UpdatedAt reflects the block timestamp
+	stored, err := r.GetAvatar(ctx, userID)
+	if err != nil {
+		return err
+	}
+	avatar.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (r *ProfileRepository) GetAvatar(ctx context.Context, userID uuid.UUID) (*models.Avatar, error) {
+	result, err := r.backend.contract.GetAvatar(&bind.CallOpts{Context: ctx}, uuidToBytes32(userID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Avatar not found") {
+			return nil, models.ErrAvatarNotFound
+		}
+		return nil, err
+	}
+
+	return &models.Avatar{
+		ID:        hex.EncodeToString(result.Id[:]),
+		Data:      result.Data,
+		UpdatedAt: time.Unix(result.UpdatedAt.Int64(), 0),
+	}, nil
+}
+
+func (r *ProfileRepository) DeleteAvatar(ctx context.Context, userID uuid.UUID) error {
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.DeleteAvatar(auth, uuidToBytes32(userID))
+	if err != nil {
+		if strings.Contains(err.Error(), "Avatar not found") {
+			return models.ErrAvatarNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+func (r *ProfileRepository) SetAvatarKeys(ctx context.Context, userID uuid.UUID, avatarID string, keys []models.AvatarKey) error {
+	id, err := avatarIDToBytes32(avatarID)
+	if err != nil {
+		return err
+	}
+
+	viewerIDs := make([][32]byte, 0, len(keys))
+	wrappedKeys := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		wrapped, err := base64.StdEncoding.DecodeString(key.WrappedKey)
+		if err != nil {
+			return err
+		}
+		viewerIDs = append(viewerIDs, uuidToBytes32(key.UserID))
+		wrappedKeys = append(wrappedKeys, wrapped)
+	}
+
+	auth, err := r.backend.getTransactOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.backend.contract.SetAvatarKeys(auth, uuidToBytes32(userID), id, viewerIDs, wrappedKeys)
+	if err != nil {
+		if strings.Contains(err.Error(), "Avatar changed") {
+			return models.ErrAvatarChanged
+		}
+		if strings.Contains(err.Error(), "User not found") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	receipt, err := bind.WaitMined(ctx, r.backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("transaction failed")
+	}
+
+	return nil
+}
+
+// ============ StreakRepository ============
+
+// Streaks are kept off-chain; recording one on-chain would cost a transaction
+// per message
+type StreakRepository struct {
+	backend *Backend
+}
+
+func (r *StreakRepository) RecordMessage(ctx context.Context, fromUserID, toUserID uuid.UUID, day int64) error {
+	// Ensure user_a_id < user_b_id
+	a, b := fromUserID.String(), toUserID.String()
+	sentColumn := "a_sent_day"
+	if a > b {
+		a, b = b, a
+		sentColumn = "b_sent_day"
+	}
+
+	tx, err := r.backend.offchain.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO streaks (user_a_id, user_b_id, ` + sentColumn + `) VALUES (?, ?, ?)
+		ON CONFLICT(user_a_id, user_b_id) DO UPDATE SET ` + sentColumn + ` = excluded.` + sentColumn
+	if _, err := tx.ExecContext(ctx, query, a, b, day); err != nil {
+		return err
+	}
+
+	// Count the day once both sides have sent on it
+	query = `
+		UPDATE streaks
+		SET count = CASE WHEN last_day = ? - 1 THEN count + 1 ELSE 1 END, last_day = ?
+		WHERE user_a_id = ? AND user_b_id = ?
+		  AND a_sent_day = ? AND b_sent_day = ? AND (last_day IS NULL OR last_day < ?)
+	`
+	if _, err := tx.ExecContext(ctx, query, day, day, a, b, day, day, day); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *StreakRepository) GetStreak(ctx context.Context, userAID, userBID uuid.UUID) (*models.Streak, error) {
+	// Ensure user_a_id < user_b_id
+	if userAID.String() > userBID.String() {
+		userAID, userBID = userBID, userAID
+	}
+
+	query := `SELECT count, COALESCE(last_day, 0) FROM streaks WHERE user_a_id = ? AND user_b_id = ?`
+	var streak models.Streak
+	err := r.backend.offchain.QueryRowContext(ctx, query, userAID.String(), userBID.String()).Scan(&streak.Count, &streak.LastDay)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.Streak{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &streak, nil
+}
+
+func (r *StreakRepository) ResetBrokenStreaks(ctx context.Context, before int64) (int64, error) {
+	result, err := r.backend.offchain.ExecContext(ctx, `UPDATE streaks SET count = 0 WHERE count > 0 AND last_day < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ============ InteractionRepository ============
+
+// Interaction counters are kept off-chain; recording one on-chain would cost
+// a transaction per message
+type InteractionRepository struct {
+	backend *Backend
+}
+
+func (r *InteractionRepository) RecordInteraction(ctx context.Context, userID, friendID uuid.UUID, at time.Time) error {
+	tx, err := r.backend.offchain.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	interaction := models.Interaction{FriendID: friendID}
+	query := `SELECT score, scored_at, last_at FROM interactions WHERE user_id = ? AND friend_id = ?`
+	err = tx.QueryRowContext(ctx, query, userID.String(), friendID.String()).Scan(
+		&interaction.Score, &interaction.ScoredAt, &interaction.LastAt,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	interaction.Record(at)
+
+	query = `
+		INSERT INTO interactions (user_id, friend_id, score, scored_at, last_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, friend_id) DO UPDATE SET
+			score = excluded.score, scored_at = excluded.scored_at, last_at = excluded.last_at
+	`
+	if _, err := tx.ExecContext(ctx, query, userID.String(), friendID.String(),
+		interaction.Score, interaction.ScoredAt, interaction.LastAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *InteractionRepository) GetInteractions(ctx context.Context, userID uuid.UUID) ([]models.Interaction, error) {
+	query := `SELECT friend_id, score, scored_at, last_at FROM interactions WHERE user_id = ?`
+	rows, err := r.backend.offchain.QueryContext(ctx, query, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var interactions []models.Interaction
+	for rows.Next() {
+		var i models.Interaction
+		var friendIDStr string
+		if err := rows.Scan(&friendIDStr, &i.Score, &i.ScoredAt, &i.LastAt); err != nil {
+			return nil, err
+		}
+		i.FriendID, _ = uuid.Parse(friendIDStr)
+		interactions = append(interactions, i)
+	}
+
+	return interactions, rows.Err()
+}
+
+// ============ ReportRepository ============
+
+// Reports are kept off-chain; their content must not be published on-chain
+type ReportRepository struct {
+	backend *Backend
+}
+
+func (r *ReportRepository) Create(ctx context.Context, report *models.Report) error {
+	report.ID = uuid.New()
+	report.Status = models.ReportOpen
+	report.CreatedAt = time.Now()
+	report.UpdatedAt = report.CreatedAt
+
+	var messageID sql.NullString
+	if report.MessageID != nil {
+		messageID = sql.NullString{String: report.MessageID.String(), Valid: true}
+	}
+
+	tx, err := r.backend.offchain.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO reports (id, reporter_id, reported_user_id, message_id, reason, comment, content, content_type,
+		                     encrypted_content, signature, verified, sender_key_version, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		report.ID.String(), report.ReporterID.String(), report.ReportedUserID.String(), messageID,
+		report.Reason, report.Comment, report.Content, report.ContentType,
+		report.EncryptedContent, report.Signature, report.Verified, report.SenderKeyVersion,
+		report.Status, report.CreatedAt, report.UpdatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return models.ErrReportExists
+		}
+		return err
+	}
+
+	event := models.ReportEvent{ActorID: report.ReporterID, ToStatus: models.ReportOpen, CreatedAt: report.CreatedAt}
+	if err := r.addEvent(ctx, tx, report.ID, event); err != nil {
+		return err
+	}
+	report.History = []models.ReportEvent{event}
+
+	return tx.Commit()
+}
+
+func (r *ReportRepository) addEvent(ctx context.Context, tx *sql.Tx, reportID uuid.UUID, event models.ReportEvent) error {
+	query := `
+		INSERT INTO report_events (report_id, actor_id, from_status, to_status, note, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := tx.ExecContext(ctx, query, reportID.String(), event.ActorID.String(),
+		event.FromStatus, event.ToStatus, event.Note, event.CreatedAt)
+	return err
+}
+
+const reportColumns = `id, reporter_id, reported_user_id, message_id, reason, comment, content, content_type,
+	encrypted_content, signature, verified, sender_key_version, status, created_at, updated_at`
+
+func scanReport(scan func(dest ...interface{}) error) (*models.Report, error) {
+	var report models.Report
+	var id, reporterID, reportedUserID string
+	var messageID sql.NullString
+	err := scan(&id, &reporterID, &reportedUserID, &messageID, &report.Reason, &report.Comment,
+		&report.Content, &report.ContentType, &report.EncryptedContent, &report.Signature,
+		&report.Verified, &report.SenderKeyVersion, &report.Status, &report.CreatedAt, &report.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	report.ID, _ = uuid.Parse(id)
+	report.ReporterID, _ = uuid.Parse(reporterID)
+	report.ReportedUserID, _ = uuid.Parse(reportedUserID)
+	if messageID.Valid {
+		parsed, _ := uuid.Parse(messageID.String)
+		report.MessageID = &parsed
+	}
+	return &report, nil
+}
+
+func (r *ReportRepository) Get(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+	row := r.backend.offchain.QueryRowContext(ctx, `SELECT `+reportColumns+` FROM reports WHERE id = ?`, id.String())
+	report, err := scanReport(row.Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT actor_id, from_status, to_status, note, created_at
+		FROM report_events WHERE report_id = ? ORDER BY created_at, rowid
+	`
+	rows, err := r.backend.offchain.QueryContext(ctx, query, id.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var event models.ReportEvent
+		var actorID string
+		if err := rows.Scan(&actorID, &event.FromStatus, &event.ToStatus, &event.Note, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.ActorID, _ = uuid.Parse(actorID)
+		report.History = append(report.History, event)
+	}
+
+	return report, rows.Err()
+}
+
+func (r *ReportRepository) List(ctx context.Context, status models.ReportStatus, limit int) ([]models.Report, error) {
+	query := `SELECT ` + reportColumns + ` FROM reports WHERE status = ? ORDER BY created_at, id LIMIT ?`
+	rows, err := r.backend.offchain.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reports []models.Report
+	for rows.Next() {
+		report, err := scanReport(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *report)
+	}
+
+	return reports, rows.Err()
+}
+
+func (r *ReportRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.ReportStatus, actorID uuid.UUID, note string) error {
+	tx, err := r.backend.offchain.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var from models.ReportStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM reports WHERE id = ?`, id.String()).Scan(&from)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrReportNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(status.PreviousStatuses(), from) {
+		return models.ErrReportStatusConflict
+	}
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `UPDATE reports SET status = ?, updated_at = ? WHERE id = ?`, status, now, id.String()); err != nil {
+		return err
+	}
+
+	event := models.ReportEvent{ActorID: actorID, FromStatus: from, ToStatus: status, Note: note, CreatedAt: now}
+	if err := r.addEvent(ctx, tx, id, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ============ StatsRepository ============
+
+type StatsRepository struct {
+	backend *Backend
+}
+
+func (r *StatsRepository) GetStats(ctx context.Context) (*models.AdminStats, error) {
+	result, err := r.backend.contract.GetStats(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.AdminStats{
+		Users:                 result.UserCount.Int64(),
+		SuspendedUsers:        result.SuspendedCount.Int64(),
+		PendingMessages:       result.PendingMessages.Int64(),
+		PendingMessageBytes:   result.PendingMessageBytes.Int64(),
+		PendingFriendRequests: result.PendingRequests.Int64(),
+	}
+
+	// Reports are kept off-chain
+	query := `SELECT COUNT(*) FROM reports WHERE status = ?`
+	if err := r.backend.offchain.QueryRowContext(ctx, query, models.ReportOpen).Scan(&stats.OpenReports); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// ============ AuditRepository ============
+
+// The audit log is kept off-chain; contract events (e.g. UserDeleted) remain
+// the public on-chain record
+type AuditRepository struct {
+	backend *Backend
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO audit_log (id, actor_id, action, target_id, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.backend.offchain.ExecContext(ctx, query,
+		entry.ID.String(), entry.ActorID.String(), entry.Action, entry.TargetID.String(), entry.Details, entry.CreatedAt)
+
+	return err
 }
@@ -29,9 +29,15 @@ var (
 	_ = abi.ConvertType
 )
 
+// QuickPicStorageDeviceCiphertext is an auto generated low-level Go binding around an user-defined struct.
+type QuickPicStorageDeviceCiphertext struct {
+	DeviceId   [32]byte
+	Ciphertext []byte
+}
+
 // QuickPicStorageMetaData contains all meta data concerning the QuickPicStorage contract.
 var QuickPicStorageMetaData = &bind.MetaData{
-	ABI: "[{\"type\":\"constructor\",\"inputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"areFriends\",\"inputs\":[{\"name\":\"userId1\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userId2\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"createFriendRequest\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createFriendship\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userAId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userBId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createMessage\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"encryptedContent\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"contentType\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.ContentType\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createUser\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"userNumber\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"deleteMessage\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"friendRequestByUsers\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendRequestIds\",\"inputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendRequests\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"status\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.FriendRequestStatus\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendshipByUsers\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendshipIds\",\"inputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendships\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userAId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userBId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getFriendRequest\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"requestId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"status\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.FriendRequestStatus\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getFriendsOfUser\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"friendIds\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getFriendship\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"friendshipId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userAId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userBId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMessage\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"messageId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"encryptedContent\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"contentType\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.ContentType\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMessagesForUser\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMessagesSentByUser\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getPendingRequestsForUser\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getUser\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userNumber\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"updatedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getUserByUsername\",\"inputs\":[{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userNumber\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"usernameOut\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"updatedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getUserCount\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getUserFriendships\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"messageIds\",\"inputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"messages\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"encryptedContent\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"contentType\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.ContentType\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"messagesFromUser\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"messagesToUser\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"nextUserNumber\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"owner\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pendingRequestsTo\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"transferOwnership\",\"inputs\":[{\"name\":\"newOwner\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"updateFriendRequestStatus\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"status\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.FriendRequestStatus\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"updateUser\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"userExists\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"userFriendships\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"userIds\",\"inputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"usernameExists\",\"inputs\":[{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"usernameToId\",\"inputs\":[{\"name\":\"\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"users\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userNumber\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"updatedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"event\",\"name\":\"FriendRequestCreated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"FriendRequestUpdated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"status\",\"type\":\"uint8\",\"indexed\":false,\"internalType\":\"enumQuickPicStorage.FriendRequestStatus\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"FriendshipCreated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userAId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userBId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"MessageCreated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"MessageDeleted\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"UserCreated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userNumber\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"},{\"name\":\"username\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"UserUpdated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false}]",
+	ABI: "[{\"type\":\"constructor\",\"inputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"acceptFriendRequest\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"friendshipId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"acknowledgeDelivery\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"deviceId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"addDevice\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"name\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"addOneTimePreKeys\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"keyIds\",\"type\":\"uint256[]\",\"internalType\":\"uint256[]\"},{\"name\":\"publicKeys\",\"type\":\"string[]\",\"internalType\":\"string[]\"},{\"name\":\"replace\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"areFriends\",\"inputs\":[{\"name\":\"userId1\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userId2\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"blockUser\",\"inputs\":[{\"name\":\"blockerId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"blockedId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"blockedAt\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"changeUsername\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"newUsername\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"newSkeleton\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"reservedUntil\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"claimOneTimePreKey\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"claimed\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"keyId\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createDeviceMessage\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"contentType\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.ContentType\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"deliveries\",\"type\":\"tuple[]\",\"internalType\":\"structQuickPicStorage.DeviceCiphertext[]\",\"components\":[{\"name\":\"deviceId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"ciphertext\",\"type\":\"bytes\",\"internalType\":\"bytes\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createFriendRequest\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createFriendship\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userAId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userBId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createInvite\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"creatorId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"maxUses\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"expiresAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createMessage\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"encryptedContent\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"contentType\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.ContentType\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createUser\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"usernameSkeleton\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"userNumber\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"deleteAvatar\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"deleteMessage\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"deleteUser\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"deletedUsers\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"devices\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"name\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"displayNames\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"string\",\"internalType\":\"string\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"expireFriendRequests\",\"inputs\":[{\"name\":\"createdBefore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"limit\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"expiryCursor\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendRequestByUsers\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendRequestIds\",\"inputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendRequests\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"status\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.FriendRequestStatus\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"updatedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendshipByUsers\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendshipIds\",\"inputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"friendships\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userAId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userBId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getAvatar\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"data\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"updatedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getBlockedUsers\",\"inputs\":[{\"name\":\"blockerId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getDeliveryCiphertext\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"deviceId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getDevices\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getFriendRequest\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"requestId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"status\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.FriendRequestStatus\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"updatedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getFriendsOfUser\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"friendIds\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getFriendship\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"friendshipId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userAId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userBId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getKeyHistory\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"publicKeys\",\"type\":\"string[]\",\"internalType\":\"string[]\"},{\"name\":\"validFrom\",\"type\":\"uint256[]\",\"internalType\":\"uint256[]\"},{\"name\":\"validUntil\",\"type\":\"uint256[]\",\"internalType\":\"uint256[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getKeyVersion\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMessage\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"messageId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"encryptedContent\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"contentType\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.ContentType\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMessageDevices\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMessagesForDevice\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"deviceId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMessagesForUser\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMessagesSentByUser\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getOneTimePreKeyCount\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getPendingRequestsForUser\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"createdAfter\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getProfile\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"viewerId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"displayName\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"avatarId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"wrappedKey\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getRequestsFromUser\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getSignedPreKey\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"keyId\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"identityKeyVersion\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getStats\",\"inputs\":[],\"outputs\":[{\"name\":\"userCount\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"suspendedCount\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"pendingMessages\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"pendingMessageBytes\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"pendingRequests\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getSuspension\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"since\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getUser\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userNumber\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"updatedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getUserByUsername\",\"inputs\":[{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userNumber\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"usernameOut\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"updatedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getUserCount\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getUserFriendships\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getUserInvites\",\"inputs\":[{\"name\":\"creatorId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"hasExpiredFriendRequests\",\"inputs\":[{\"name\":\"createdBefore\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"invites\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"creatorId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"maxUses\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"uses\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"expiresAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"revoked\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"messageIds\",\"inputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"messageKeyVersion\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"messages\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"encryptedContent\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"contentType\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.ContentType\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"messagesFromUser\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"messagesToUser\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"nextUserNumber\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"owner\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pendingRequestsTo\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"privacyFlags\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"redeemInvite\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"redeemerId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"friendshipId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"removeDevice\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"removeFriendship\",\"inputs\":[{\"name\":\"userId1\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userId2\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"revokeInvite\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"rotatePublicKey\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"expectedVersion\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"version\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setAvatar\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"avatarId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"data\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setAvatarKeys\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"avatarId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"viewerIds\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"},{\"name\":\"wrappedKeys\",\"type\":\"bytes[]\",\"internalType\":\"bytes[]\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setDisplayName\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"displayName\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setPrivacyFlags\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"flags\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setSignedPreKey\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"keyId\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"signature\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"identityKeyVersion\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setSuspended\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"suspended\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setVerifiedKeyVersion\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"friendId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"keyVersion\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"skeletonToId\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"transferOwnership\",\"inputs\":[{\"name\":\"newOwner\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"unblockUser\",\"inputs\":[{\"name\":\"blockerId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"blockedId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"updateFriendRequestStatus\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"status\",\"type\":\"uint8\",\"internalType\":\"enumQuickPicStorage.FriendRequestStatus\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"updateUser\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"userExists\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"userFriendships\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"userIds\",\"inputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"usernameExists\",\"inputs\":[{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"usernameReservations\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"reservedUntil\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"usernameToId\",\"inputs\":[{\"name\":\"\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"users\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"userNumber\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"username\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"passwordHash\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"publicKey\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"createdAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"updatedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"exists\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"verifiedKeyVersion\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"event\",\"name\":\"DeliveryAcknowledged\",\"inputs\":[{\"name\":\"messageId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"deviceId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"DeviceAdded\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"DeviceRemoved\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"FriendRequestCreated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"FriendRequestUpdated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"status\",\"type\":\"uint8\",\"indexed\":false,\"internalType\":\"enumQuickPicStorage.FriendRequestStatus\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"FriendVerified\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"friendId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"keyVersion\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"FriendshipCreated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userAId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userBId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"FriendshipRemoved\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userAId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userBId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"InviteCreated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"creatorId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"InviteRedeemed\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"redeemerId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"InviteRevoked\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"MessageCreated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"fromUserId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"toUserId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"MessageDeleted\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"OneTimePreKeysAdded\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"count\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"PreKeyClaimed\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"keyId\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"},{\"name\":\"publicKey\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"PrivacyFlagsUpdated\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"flags\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"ProfileUpdated\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"PublicKeyRotated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"version\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"SignedPreKeyUpdated\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"keyId\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"UserBlocked\",\"inputs\":[{\"name\":\"blockerId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"blockedId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"UserCreated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"userNumber\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"},{\"name\":\"username\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"UserDeleted\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"UserSuspended\",\"inputs\":[{\"name\":\"userId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"suspended\",\"type\":\"bool\",\"indexed\":false,\"internalType\":\"bool\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"UserUnblocked\",\"inputs\":[{\"name\":\"blockerId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"blockedId\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"UserUpdated\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"UsernameChanged\",\"inputs\":[{\"name\":\"id\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"oldUsername\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"},{\"name\":\"newUsername\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"}],\"anonymous\":false}]",
 }
 
 // QuickPicStorageABI is the input ABI used to generate the binding from.
@@ -211,6 +217,200 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) AreFriends(userId1 [32]byt
 	return _QuickPicStorage.Contract.AreFriends(&_QuickPicStorage.CallOpts, userId1, userId2)
 }
 
+// BlockedAt is a free data retrieval call binding the contract method 0x972e14c3.
+//
+// Solidity: function blockedAt(bytes32 , bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCaller) BlockedAt(opts *bind.CallOpts, arg0 [32]byte, arg1 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "blockedAt", arg0, arg1)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// BlockedAt is a free data retrieval call binding the contract method 0x972e14c3.
+//
+// Solidity: function blockedAt(bytes32 , bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageSession) BlockedAt(arg0 [32]byte, arg1 [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.BlockedAt(&_QuickPicStorage.CallOpts, arg0, arg1)
+}
+
+// BlockedAt is a free data retrieval call binding the contract method 0x972e14c3.
+//
+// Solidity: function blockedAt(bytes32 , bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCallerSession) BlockedAt(arg0 [32]byte, arg1 [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.BlockedAt(&_QuickPicStorage.CallOpts, arg0, arg1)
+}
+
+// DeletedUsers is a free data retrieval call binding the contract method 0x6791f0e7.
+//
+// Solidity: function deletedUsers(bytes32 ) view returns(bool)
+func (_QuickPicStorage *QuickPicStorageCaller) DeletedUsers(opts *bind.CallOpts, arg0 [32]byte) (bool, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "deletedUsers", arg0)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// DeletedUsers is a free data retrieval call binding the contract method 0x6791f0e7.
+//
+// Solidity: function deletedUsers(bytes32 ) view returns(bool)
+func (_QuickPicStorage *QuickPicStorageSession) DeletedUsers(arg0 [32]byte) (bool, error) {
+	return _QuickPicStorage.Contract.DeletedUsers(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// DeletedUsers is a free data retrieval call binding the contract method 0x6791f0e7.
+//
+// Solidity: function deletedUsers(bytes32 ) view returns(bool)
+func (_QuickPicStorage *QuickPicStorageCallerSession) DeletedUsers(arg0 [32]byte) (bool, error) {
+	return _QuickPicStorage.Contract.DeletedUsers(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// Devices is a free data retrieval call binding the contract method 0xc5c400de.
+//
+// Solidity: function devices(bytes32 ) view returns(bytes32 id, bytes32 userId, string name, string publicKey, string signature, uint256 createdAt, bool exists)
+func (_QuickPicStorage *QuickPicStorageCaller) Devices(opts *bind.CallOpts, arg0 [32]byte) (struct {
+	Id        [32]byte
+	UserId    [32]byte
+	Name      string
+	PublicKey string
+	Signature string
+	CreatedAt *big.Int
+	Exists    bool
+}, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "devices", arg0)
+
+	outstruct := new(struct {
+		Id        [32]byte
+		UserId    [32]byte
+		Name      string
+		PublicKey string
+		Signature string
+		CreatedAt *big.Int
+		Exists    bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Id = *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	outstruct.UserId = *abi.ConvertType(out[1], new([32]byte)).(*[32]byte)
+	outstruct.Name = *abi.ConvertType(out[2], new(string)).(*string)
+	outstruct.PublicKey = *abi.ConvertType(out[3], new(string)).(*string)
+	outstruct.Signature = *abi.ConvertType(out[4], new(string)).(*string)
+	outstruct.CreatedAt = *abi.ConvertType(out[5], new(*big.Int)).(**big.Int)
+	outstruct.Exists = *abi.ConvertType(out[6], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Devices is a free data retrieval call binding the contract method 0xc5c400de.
+//
+// Solidity: function devices(bytes32 ) view returns(bytes32 id, bytes32 userId, string name, string publicKey, string signature, uint256 createdAt, bool exists)
+func (_QuickPicStorage *QuickPicStorageSession) Devices(arg0 [32]byte) (struct {
+	Id        [32]byte
+	UserId    [32]byte
+	Name      string
+	PublicKey string
+	Signature string
+	CreatedAt *big.Int
+	Exists    bool
+}, error) {
+	return _QuickPicStorage.Contract.Devices(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// Devices is a free data retrieval call binding the contract method 0xc5c400de.
+//
+// Solidity: function devices(bytes32 ) view returns(bytes32 id, bytes32 userId, string name, string publicKey, string signature, uint256 createdAt, bool exists)
+func (_QuickPicStorage *QuickPicStorageCallerSession) Devices(arg0 [32]byte) (struct {
+	Id        [32]byte
+	UserId    [32]byte
+	Name      string
+	PublicKey string
+	Signature string
+	CreatedAt *big.Int
+	Exists    bool
+}, error) {
+	return _QuickPicStorage.Contract.Devices(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// DisplayNames is a free data retrieval call binding the contract method 0xb49942dd.
+//
+// Solidity: function displayNames(bytes32 ) view returns(string)
+func (_QuickPicStorage *QuickPicStorageCaller) DisplayNames(opts *bind.CallOpts, arg0 [32]byte) (string, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "displayNames", arg0)
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// DisplayNames is a free data retrieval call binding the contract method 0xb49942dd.
+//
+// Solidity: function displayNames(bytes32 ) view returns(string)
+func (_QuickPicStorage *QuickPicStorageSession) DisplayNames(arg0 [32]byte) (string, error) {
+	return _QuickPicStorage.Contract.DisplayNames(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// DisplayNames is a free data retrieval call binding the contract method 0xb49942dd.
+//
+// Solidity: function displayNames(bytes32 ) view returns(string)
+func (_QuickPicStorage *QuickPicStorageCallerSession) DisplayNames(arg0 [32]byte) (string, error) {
+	return _QuickPicStorage.Contract.DisplayNames(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// ExpiryCursor is a free data retrieval call binding the contract method 0x24fe4c1b.
+//
+// Solidity: function expiryCursor() view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCaller) ExpiryCursor(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "expiryCursor")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ExpiryCursor is a free data retrieval call binding the contract method 0x24fe4c1b.
+//
+// Solidity: function expiryCursor() view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageSession) ExpiryCursor() (*big.Int, error) {
+	return _QuickPicStorage.Contract.ExpiryCursor(&_QuickPicStorage.CallOpts)
+}
+
+// ExpiryCursor is a free data retrieval call binding the contract method 0x24fe4c1b.
+//
+// Solidity: function expiryCursor() view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCallerSession) ExpiryCursor() (*big.Int, error) {
+	return _QuickPicStorage.Contract.ExpiryCursor(&_QuickPicStorage.CallOpts)
+}
+
 // FriendRequestByUsers is a free data retrieval call binding the contract method 0xc6b50640.
 //
 // Solidity: function friendRequestByUsers(bytes32 , bytes32 ) view returns(bytes32)
@@ -275,13 +475,14 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) FriendRequestIds(arg0 *big
 
 // FriendRequests is a free data retrieval call binding the contract method 0xe44834eb.
 //
-// Solidity: function friendRequests(bytes32 ) view returns(bytes32 id, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt, bool exists)
+// Solidity: function friendRequests(bytes32 ) view returns(bytes32 id, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt, uint256 updatedAt, bool exists)
 func (_QuickPicStorage *QuickPicStorageCaller) FriendRequests(opts *bind.CallOpts, arg0 [32]byte) (struct {
 	Id         [32]byte
 	FromUserId [32]byte
 	ToUserId   [32]byte
 	Status     uint8
 	CreatedAt  *big.Int
+	UpdatedAt  *big.Int
 	Exists     bool
 }, error) {
 	var out []interface{}
@@ -293,6 +494,7 @@ func (_QuickPicStorage *QuickPicStorageCaller) FriendRequests(opts *bind.CallOpt
 		ToUserId   [32]byte
 		Status     uint8
 		CreatedAt  *big.Int
+		UpdatedAt  *big.Int
 		Exists     bool
 	})
 	if err != nil {
@@ -304,7 +506,8 @@ func (_QuickPicStorage *QuickPicStorageCaller) FriendRequests(opts *bind.CallOpt
 	outstruct.ToUserId = *abi.ConvertType(out[2], new([32]byte)).(*[32]byte)
 	outstruct.Status = *abi.ConvertType(out[3], new(uint8)).(*uint8)
 	outstruct.CreatedAt = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
-	outstruct.Exists = *abi.ConvertType(out[5], new(bool)).(*bool)
+	outstruct.UpdatedAt = *abi.ConvertType(out[5], new(*big.Int)).(**big.Int)
+	outstruct.Exists = *abi.ConvertType(out[6], new(bool)).(*bool)
 
 	return *outstruct, err
 
@@ -312,13 +515,14 @@ func (_QuickPicStorage *QuickPicStorageCaller) FriendRequests(opts *bind.CallOpt
 
 // FriendRequests is a free data retrieval call binding the contract method 0xe44834eb.
 //
-// Solidity: function friendRequests(bytes32 ) view returns(bytes32 id, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt, bool exists)
+// Solidity: function friendRequests(bytes32 ) view returns(bytes32 id, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt, uint256 updatedAt, bool exists)
 func (_QuickPicStorage *QuickPicStorageSession) FriendRequests(arg0 [32]byte) (struct {
 	Id         [32]byte
 	FromUserId [32]byte
 	ToUserId   [32]byte
 	Status     uint8
 	CreatedAt  *big.Int
+	UpdatedAt  *big.Int
 	Exists     bool
 }, error) {
 	return _QuickPicStorage.Contract.FriendRequests(&_QuickPicStorage.CallOpts, arg0)
@@ -326,13 +530,14 @@ func (_QuickPicStorage *QuickPicStorageSession) FriendRequests(arg0 [32]byte) (s
 
 // FriendRequests is a free data retrieval call binding the contract method 0xe44834eb.
 //
-// Solidity: function friendRequests(bytes32 ) view returns(bytes32 id, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt, bool exists)
+// Solidity: function friendRequests(bytes32 ) view returns(bytes32 id, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt, uint256 updatedAt, bool exists)
 func (_QuickPicStorage *QuickPicStorageCallerSession) FriendRequests(arg0 [32]byte) (struct {
 	Id         [32]byte
 	FromUserId [32]byte
 	ToUserId   [32]byte
 	Status     uint8
 	CreatedAt  *big.Int
+	UpdatedAt  *big.Int
 	Exists     bool
 }, error) {
 	return _QuickPicStorage.Contract.FriendRequests(&_QuickPicStorage.CallOpts, arg0)
@@ -460,15 +665,159 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) Friendships(arg0 [32]byte)
 	return _QuickPicStorage.Contract.Friendships(&_QuickPicStorage.CallOpts, arg0)
 }
 
+// GetAvatar is a free data retrieval call binding the contract method 0xc43eb0e0.
+//
+// Solidity: function getAvatar(bytes32 userId) view returns(bytes32 id, bytes data, uint256 updatedAt)
+func (_QuickPicStorage *QuickPicStorageCaller) GetAvatar(opts *bind.CallOpts, userId [32]byte) (struct {
+	Id        [32]byte
+	Data      []byte
+	UpdatedAt *big.Int
+}, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getAvatar", userId)
+
+	outstruct := new(struct {
+		Id        [32]byte
+		Data      []byte
+		UpdatedAt *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Id = *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	outstruct.Data = *abi.ConvertType(out[1], new([]byte)).(*[]byte)
+	outstruct.UpdatedAt = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetAvatar is a free data retrieval call binding the contract method 0xc43eb0e0.
+//
+// Solidity: function getAvatar(bytes32 userId) view returns(bytes32 id, bytes data, uint256 updatedAt)
+func (_QuickPicStorage *QuickPicStorageSession) GetAvatar(userId [32]byte) (struct {
+	Id        [32]byte
+	Data      []byte
+	UpdatedAt *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetAvatar(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetAvatar is a free data retrieval call binding the contract method 0xc43eb0e0.
+//
+// Solidity: function getAvatar(bytes32 userId) view returns(bytes32 id, bytes data, uint256 updatedAt)
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetAvatar(userId [32]byte) (struct {
+	Id        [32]byte
+	Data      []byte
+	UpdatedAt *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetAvatar(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetBlockedUsers is a free data retrieval call binding the contract method 0x9556996a.
+//
+// Solidity: function getBlockedUsers(bytes32 blockerId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCaller) GetBlockedUsers(opts *bind.CallOpts, blockerId [32]byte) ([][32]byte, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getBlockedUsers", blockerId)
+
+	if err != nil {
+		return *new([][32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
+
+	return out0, err
+
+}
+
+// GetBlockedUsers is a free data retrieval call binding the contract method 0x9556996a.
+//
+// Solidity: function getBlockedUsers(bytes32 blockerId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageSession) GetBlockedUsers(blockerId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetBlockedUsers(&_QuickPicStorage.CallOpts, blockerId)
+}
+
+// GetBlockedUsers is a free data retrieval call binding the contract method 0x9556996a.
+//
+// Solidity: function getBlockedUsers(bytes32 blockerId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetBlockedUsers(blockerId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetBlockedUsers(&_QuickPicStorage.CallOpts, blockerId)
+}
+
+// GetDeliveryCiphertext is a free data retrieval call binding the contract method 0x6bc2dbda.
+//
+// Solidity: function getDeliveryCiphertext(bytes32 id, bytes32 deviceId) view returns(bytes)
+func (_QuickPicStorage *QuickPicStorageCaller) GetDeliveryCiphertext(opts *bind.CallOpts, id [32]byte, deviceId [32]byte) ([]byte, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getDeliveryCiphertext", id, deviceId)
+
+	if err != nil {
+		return *new([]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]byte)).(*[]byte)
+
+	return out0, err
+
+}
+
+// GetDeliveryCiphertext is a free data retrieval call binding the contract method 0x6bc2dbda.
+//
+// Solidity: function getDeliveryCiphertext(bytes32 id, bytes32 deviceId) view returns(bytes)
+func (_QuickPicStorage *QuickPicStorageSession) GetDeliveryCiphertext(id [32]byte, deviceId [32]byte) ([]byte, error) {
+	return _QuickPicStorage.Contract.GetDeliveryCiphertext(&_QuickPicStorage.CallOpts, id, deviceId)
+}
+
+// GetDeliveryCiphertext is a free data retrieval call binding the contract method 0x6bc2dbda.
+//
+// Solidity: function getDeliveryCiphertext(bytes32 id, bytes32 deviceId) view returns(bytes)
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetDeliveryCiphertext(id [32]byte, deviceId [32]byte) ([]byte, error) {
+	return _QuickPicStorage.Contract.GetDeliveryCiphertext(&_QuickPicStorage.CallOpts, id, deviceId)
+}
+
+// GetDevices is a free data retrieval call binding the contract method 0x1feb7951.
+//
+// Solidity: function getDevices(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCaller) GetDevices(opts *bind.CallOpts, userId [32]byte) ([][32]byte, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getDevices", userId)
+
+	if err != nil {
+		return *new([][32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
+
+	return out0, err
+
+}
+
+// GetDevices is a free data retrieval call binding the contract method 0x1feb7951.
+//
+// Solidity: function getDevices(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageSession) GetDevices(userId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetDevices(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetDevices is a free data retrieval call binding the contract method 0x1feb7951.
+//
+// Solidity: function getDevices(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetDevices(userId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetDevices(&_QuickPicStorage.CallOpts, userId)
+}
+
 // GetFriendRequest is a free data retrieval call binding the contract method 0xea65a758.
 //
-// Solidity: function getFriendRequest(bytes32 id) view returns(bytes32 requestId, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt)
+// Solidity: function getFriendRequest(bytes32 id) view returns(bytes32 requestId, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt, uint256 updatedAt)
 func (_QuickPicStorage *QuickPicStorageCaller) GetFriendRequest(opts *bind.CallOpts, id [32]byte) (struct {
 	RequestId  [32]byte
 	FromUserId [32]byte
 	ToUserId   [32]byte
 	Status     uint8
 	CreatedAt  *big.Int
+	UpdatedAt  *big.Int
 }, error) {
 	var out []interface{}
 	err := _QuickPicStorage.contract.Call(opts, &out, "getFriendRequest", id)
@@ -479,6 +828,7 @@ func (_QuickPicStorage *QuickPicStorageCaller) GetFriendRequest(opts *bind.CallO
 		ToUserId   [32]byte
 		Status     uint8
 		CreatedAt  *big.Int
+		UpdatedAt  *big.Int
 	})
 	if err != nil {
 		return *outstruct, err
@@ -489,6 +839,7 @@ func (_QuickPicStorage *QuickPicStorageCaller) GetFriendRequest(opts *bind.CallO
 	outstruct.ToUserId = *abi.ConvertType(out[2], new([32]byte)).(*[32]byte)
 	outstruct.Status = *abi.ConvertType(out[3], new(uint8)).(*uint8)
 	outstruct.CreatedAt = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+	outstruct.UpdatedAt = *abi.ConvertType(out[5], new(*big.Int)).(**big.Int)
 
 	return *outstruct, err
 
@@ -496,26 +847,28 @@ func (_QuickPicStorage *QuickPicStorageCaller) GetFriendRequest(opts *bind.CallO
 
 // GetFriendRequest is a free data retrieval call binding the contract method 0xea65a758.
 //
-// Solidity: function getFriendRequest(bytes32 id) view returns(bytes32 requestId, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt)
+// Solidity: function getFriendRequest(bytes32 id) view returns(bytes32 requestId, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt, uint256 updatedAt)
 func (_QuickPicStorage *QuickPicStorageSession) GetFriendRequest(id [32]byte) (struct {
 	RequestId  [32]byte
 	FromUserId [32]byte
 	ToUserId   [32]byte
 	Status     uint8
 	CreatedAt  *big.Int
+	UpdatedAt  *big.Int
 }, error) {
 	return _QuickPicStorage.Contract.GetFriendRequest(&_QuickPicStorage.CallOpts, id)
 }
 
 // GetFriendRequest is a free data retrieval call binding the contract method 0xea65a758.
 //
-// Solidity: function getFriendRequest(bytes32 id) view returns(bytes32 requestId, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt)
+// Solidity: function getFriendRequest(bytes32 id) view returns(bytes32 requestId, bytes32 fromUserId, bytes32 toUserId, uint8 status, uint256 createdAt, uint256 updatedAt)
 func (_QuickPicStorage *QuickPicStorageCallerSession) GetFriendRequest(id [32]byte) (struct {
 	RequestId  [32]byte
 	FromUserId [32]byte
 	ToUserId   [32]byte
 	Status     uint8
 	CreatedAt  *big.Int
+	UpdatedAt  *big.Int
 }, error) {
 	return _QuickPicStorage.Contract.GetFriendRequest(&_QuickPicStorage.CallOpts, id)
 }
@@ -606,6 +959,87 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) GetFriendship(id [32]byte)
 	return _QuickPicStorage.Contract.GetFriendship(&_QuickPicStorage.CallOpts, id)
 }
 
+// GetKeyHistory is a free data retrieval call binding the contract method 0x91754027.
+//
+// Solidity: function getKeyHistory(bytes32 id) view returns(string[] publicKeys, uint256[] validFrom, uint256[] validUntil)
+func (_QuickPicStorage *QuickPicStorageCaller) GetKeyHistory(opts *bind.CallOpts, id [32]byte) (struct {
+	PublicKeys []string
+	ValidFrom  []*big.Int
+	ValidUntil []*big.Int
+}, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getKeyHistory", id)
+
+	outstruct := new(struct {
+		PublicKeys []string
+		ValidFrom  []*big.Int
+		ValidUntil []*big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.PublicKeys = *abi.ConvertType(out[0], new([]string)).(*[]string)
+	outstruct.ValidFrom = *abi.ConvertType(out[1], new([]*big.Int)).(*[]*big.Int)
+	outstruct.ValidUntil = *abi.ConvertType(out[2], new([]*big.Int)).(*[]*big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetKeyHistory is a free data retrieval call binding the contract method 0x91754027.
+//
+// Solidity: function getKeyHistory(bytes32 id) view returns(string[] publicKeys, uint256[] validFrom, uint256[] validUntil)
+func (_QuickPicStorage *QuickPicStorageSession) GetKeyHistory(id [32]byte) (struct {
+	PublicKeys []string
+	ValidFrom  []*big.Int
+	ValidUntil []*big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetKeyHistory(&_QuickPicStorage.CallOpts, id)
+}
+
+// GetKeyHistory is a free data retrieval call binding the contract method 0x91754027.
+//
+// Solidity: function getKeyHistory(bytes32 id) view returns(string[] publicKeys, uint256[] validFrom, uint256[] validUntil)
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetKeyHistory(id [32]byte) (struct {
+	PublicKeys []string
+	ValidFrom  []*big.Int
+	ValidUntil []*big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetKeyHistory(&_QuickPicStorage.CallOpts, id)
+}
+
+// GetKeyVersion is a free data retrieval call binding the contract method 0x6e347f14.
+//
+// Solidity: function getKeyVersion(bytes32 id) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCaller) GetKeyVersion(opts *bind.CallOpts, id [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getKeyVersion", id)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetKeyVersion is a free data retrieval call binding the contract method 0x6e347f14.
+//
+// Solidity: function getKeyVersion(bytes32 id) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageSession) GetKeyVersion(id [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.GetKeyVersion(&_QuickPicStorage.CallOpts, id)
+}
+
+// GetKeyVersion is a free data retrieval call binding the contract method 0x6e347f14.
+//
+// Solidity: function getKeyVersion(bytes32 id) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetKeyVersion(id [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.GetKeyVersion(&_QuickPicStorage.CallOpts, id)
+}
+
 // GetMessage is a free data retrieval call binding the contract method 0x0139a221.
 //
 // Solidity: function getMessage(bytes32 id) view returns(bytes32 messageId, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature, uint256 createdAt)
@@ -676,12 +1110,12 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) GetMessage(id [32]byte) (s
 	return _QuickPicStorage.Contract.GetMessage(&_QuickPicStorage.CallOpts, id)
 }
 
-// GetMessagesForUser is a free data retrieval call binding the contract method 0x9e2243ef.
+// GetMessageDevices is a free data retrieval call binding the contract method 0x55bed7a9.
 //
-// Solidity: function getMessagesForUser(bytes32 userId) view returns(bytes32[])
-func (_QuickPicStorage *QuickPicStorageCaller) GetMessagesForUser(opts *bind.CallOpts, userId [32]byte) ([][32]byte, error) {
+// Solidity: function getMessageDevices(bytes32 id) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCaller) GetMessageDevices(opts *bind.CallOpts, id [32]byte) ([][32]byte, error) {
 	var out []interface{}
-	err := _QuickPicStorage.contract.Call(opts, &out, "getMessagesForUser", userId)
+	err := _QuickPicStorage.contract.Call(opts, &out, "getMessageDevices", id)
 
 	if err != nil {
 		return *new([][32]byte), err
@@ -693,26 +1127,26 @@ func (_QuickPicStorage *QuickPicStorageCaller) GetMessagesForUser(opts *bind.Cal
 
 }
 
-// GetMessagesForUser is a free data retrieval call binding the contract method 0x9e2243ef.
+// GetMessageDevices is a free data retrieval call binding the contract method 0x55bed7a9.
 //
-// Solidity: function getMessagesForUser(bytes32 userId) view returns(bytes32[])
-func (_QuickPicStorage *QuickPicStorageSession) GetMessagesForUser(userId [32]byte) ([][32]byte, error) {
-	return _QuickPicStorage.Contract.GetMessagesForUser(&_QuickPicStorage.CallOpts, userId)
+// Solidity: function getMessageDevices(bytes32 id) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageSession) GetMessageDevices(id [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetMessageDevices(&_QuickPicStorage.CallOpts, id)
 }
 
-// GetMessagesForUser is a free data retrieval call binding the contract method 0x9e2243ef.
+// GetMessageDevices is a free data retrieval call binding the contract method 0x55bed7a9.
 //
-// Solidity: function getMessagesForUser(bytes32 userId) view returns(bytes32[])
-func (_QuickPicStorage *QuickPicStorageCallerSession) GetMessagesForUser(userId [32]byte) ([][32]byte, error) {
-	return _QuickPicStorage.Contract.GetMessagesForUser(&_QuickPicStorage.CallOpts, userId)
+// Solidity: function getMessageDevices(bytes32 id) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetMessageDevices(id [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetMessageDevices(&_QuickPicStorage.CallOpts, id)
 }
 
-// GetMessagesSentByUser is a free data retrieval call binding the contract method 0x563485ed.
+// GetMessagesForDevice is a free data retrieval call binding the contract method 0xdc8f0eab.
 //
-// Solidity: function getMessagesSentByUser(bytes32 userId) view returns(bytes32[])
-func (_QuickPicStorage *QuickPicStorageCaller) GetMessagesSentByUser(opts *bind.CallOpts, userId [32]byte) ([][32]byte, error) {
+// Solidity: function getMessagesForDevice(bytes32 userId, bytes32 deviceId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCaller) GetMessagesForDevice(opts *bind.CallOpts, userId [32]byte, deviceId [32]byte) ([][32]byte, error) {
 	var out []interface{}
-	err := _QuickPicStorage.contract.Call(opts, &out, "getMessagesSentByUser", userId)
+	err := _QuickPicStorage.contract.Call(opts, &out, "getMessagesForDevice", userId, deviceId)
 
 	if err != nil {
 		return *new([][32]byte), err
@@ -724,9 +1158,71 @@ func (_QuickPicStorage *QuickPicStorageCaller) GetMessagesSentByUser(opts *bind.
 
 }
 
-// GetMessagesSentByUser is a free data retrieval call binding the contract method 0x563485ed.
+// GetMessagesForDevice is a free data retrieval call binding the contract method 0xdc8f0eab.
 //
-// Solidity: function getMessagesSentByUser(bytes32 userId) view returns(bytes32[])
+// Solidity: function getMessagesForDevice(bytes32 userId, bytes32 deviceId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageSession) GetMessagesForDevice(userId [32]byte, deviceId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetMessagesForDevice(&_QuickPicStorage.CallOpts, userId, deviceId)
+}
+
+// GetMessagesForDevice is a free data retrieval call binding the contract method 0xdc8f0eab.
+//
+// Solidity: function getMessagesForDevice(bytes32 userId, bytes32 deviceId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetMessagesForDevice(userId [32]byte, deviceId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetMessagesForDevice(&_QuickPicStorage.CallOpts, userId, deviceId)
+}
+
+// GetMessagesForUser is a free data retrieval call binding the contract method 0x9e2243ef.
+//
+// Solidity: function getMessagesForUser(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCaller) GetMessagesForUser(opts *bind.CallOpts, userId [32]byte) ([][32]byte, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getMessagesForUser", userId)
+
+	if err != nil {
+		return *new([][32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
+
+	return out0, err
+
+}
+
+// GetMessagesForUser is a free data retrieval call binding the contract method 0x9e2243ef.
+//
+// Solidity: function getMessagesForUser(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageSession) GetMessagesForUser(userId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetMessagesForUser(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetMessagesForUser is a free data retrieval call binding the contract method 0x9e2243ef.
+//
+// Solidity: function getMessagesForUser(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetMessagesForUser(userId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetMessagesForUser(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetMessagesSentByUser is a free data retrieval call binding the contract method 0x563485ed.
+//
+// Solidity: function getMessagesSentByUser(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCaller) GetMessagesSentByUser(opts *bind.CallOpts, userId [32]byte) ([][32]byte, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getMessagesSentByUser", userId)
+
+	if err != nil {
+		return *new([][32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
+
+	return out0, err
+
+}
+
+// GetMessagesSentByUser is a free data retrieval call binding the contract method 0x563485ed.
+//
+// Solidity: function getMessagesSentByUser(bytes32 userId) view returns(bytes32[])
 func (_QuickPicStorage *QuickPicStorageSession) GetMessagesSentByUser(userId [32]byte) ([][32]byte, error) {
 	return _QuickPicStorage.Contract.GetMessagesSentByUser(&_QuickPicStorage.CallOpts, userId)
 }
@@ -738,12 +1234,124 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) GetMessagesSentByUser(user
 	return _QuickPicStorage.Contract.GetMessagesSentByUser(&_QuickPicStorage.CallOpts, userId)
 }
 
-// GetPendingRequestsForUser is a free data retrieval call binding the contract method 0xdb0088ac.
+// GetOneTimePreKeyCount is a free data retrieval call binding the contract method 0xef3cb976.
+//
+// Solidity: function getOneTimePreKeyCount(bytes32 userId) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCaller) GetOneTimePreKeyCount(opts *bind.CallOpts, userId [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getOneTimePreKeyCount", userId)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetOneTimePreKeyCount is a free data retrieval call binding the contract method 0xef3cb976.
+//
+// Solidity: function getOneTimePreKeyCount(bytes32 userId) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageSession) GetOneTimePreKeyCount(userId [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.GetOneTimePreKeyCount(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetOneTimePreKeyCount is a free data retrieval call binding the contract method 0xef3cb976.
+//
+// Solidity: function getOneTimePreKeyCount(bytes32 userId) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetOneTimePreKeyCount(userId [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.GetOneTimePreKeyCount(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetPendingRequestsForUser is a free data retrieval call binding the contract method 0x8d823478.
+//
+// Solidity: function getPendingRequestsForUser(bytes32 userId, uint256 createdAfter) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCaller) GetPendingRequestsForUser(opts *bind.CallOpts, userId [32]byte, createdAfter *big.Int) ([][32]byte, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getPendingRequestsForUser", userId, createdAfter)
+
+	if err != nil {
+		return *new([][32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
+
+	return out0, err
+
+}
+
+// GetPendingRequestsForUser is a free data retrieval call binding the contract method 0x8d823478.
+//
+// Solidity: function getPendingRequestsForUser(bytes32 userId, uint256 createdAfter) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageSession) GetPendingRequestsForUser(userId [32]byte, createdAfter *big.Int) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetPendingRequestsForUser(&_QuickPicStorage.CallOpts, userId, createdAfter)
+}
+
+// GetPendingRequestsForUser is a free data retrieval call binding the contract method 0x8d823478.
+//
+// Solidity: function getPendingRequestsForUser(bytes32 userId, uint256 createdAfter) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetPendingRequestsForUser(userId [32]byte, createdAfter *big.Int) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetPendingRequestsForUser(&_QuickPicStorage.CallOpts, userId, createdAfter)
+}
+
+// GetProfile is a free data retrieval call binding the contract method 0xc0664466.
+//
+// Solidity: function getProfile(bytes32 userId, bytes32 viewerId) view returns(string displayName, bytes32 avatarId, bytes wrappedKey)
+func (_QuickPicStorage *QuickPicStorageCaller) GetProfile(opts *bind.CallOpts, userId [32]byte, viewerId [32]byte) (struct {
+	DisplayName string
+	AvatarId    [32]byte
+	WrappedKey  []byte
+}, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getProfile", userId, viewerId)
+
+	outstruct := new(struct {
+		DisplayName string
+		AvatarId    [32]byte
+		WrappedKey  []byte
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.DisplayName = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.AvatarId = *abi.ConvertType(out[1], new([32]byte)).(*[32]byte)
+	outstruct.WrappedKey = *abi.ConvertType(out[2], new([]byte)).(*[]byte)
+
+	return *outstruct, err
+
+}
+
+// GetProfile is a free data retrieval call binding the contract method 0xc0664466.
+//
+// Solidity: function getProfile(bytes32 userId, bytes32 viewerId) view returns(string displayName, bytes32 avatarId, bytes wrappedKey)
+func (_QuickPicStorage *QuickPicStorageSession) GetProfile(userId [32]byte, viewerId [32]byte) (struct {
+	DisplayName string
+	AvatarId    [32]byte
+	WrappedKey  []byte
+}, error) {
+	return _QuickPicStorage.Contract.GetProfile(&_QuickPicStorage.CallOpts, userId, viewerId)
+}
+
+// GetProfile is a free data retrieval call binding the contract method 0xc0664466.
+//
+// Solidity: function getProfile(bytes32 userId, bytes32 viewerId) view returns(string displayName, bytes32 avatarId, bytes wrappedKey)
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetProfile(userId [32]byte, viewerId [32]byte) (struct {
+	DisplayName string
+	AvatarId    [32]byte
+	WrappedKey  []byte
+}, error) {
+	return _QuickPicStorage.Contract.GetProfile(&_QuickPicStorage.CallOpts, userId, viewerId)
+}
+
+// GetRequestsFromUser is a free data retrieval call binding the contract method 0xfa8e6759.
 //
-// Solidity: function getPendingRequestsForUser(bytes32 userId) view returns(bytes32[])
-func (_QuickPicStorage *QuickPicStorageCaller) GetPendingRequestsForUser(opts *bind.CallOpts, userId [32]byte) ([][32]byte, error) {
+// Solidity: function getRequestsFromUser(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCaller) GetRequestsFromUser(opts *bind.CallOpts, userId [32]byte) ([][32]byte, error) {
 	var out []interface{}
-	err := _QuickPicStorage.contract.Call(opts, &out, "getPendingRequestsForUser", userId)
+	err := _QuickPicStorage.contract.Call(opts, &out, "getRequestsFromUser", userId)
 
 	if err != nil {
 		return *new([][32]byte), err
@@ -755,18 +1363,183 @@ func (_QuickPicStorage *QuickPicStorageCaller) GetPendingRequestsForUser(opts *b
 
 }
 
-// GetPendingRequestsForUser is a free data retrieval call binding the contract method 0xdb0088ac.
+// GetRequestsFromUser is a free data retrieval call binding the contract method 0xfa8e6759.
+//
+// Solidity: function getRequestsFromUser(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageSession) GetRequestsFromUser(userId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetRequestsFromUser(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetRequestsFromUser is a free data retrieval call binding the contract method 0xfa8e6759.
+//
+// Solidity: function getRequestsFromUser(bytes32 userId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetRequestsFromUser(userId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetRequestsFromUser(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetSignedPreKey is a free data retrieval call binding the contract method 0x106423aa.
+//
+// Solidity: function getSignedPreKey(bytes32 userId) view returns(uint256 keyId, string publicKey, string signature, uint256 identityKeyVersion, uint256 createdAt)
+func (_QuickPicStorage *QuickPicStorageCaller) GetSignedPreKey(opts *bind.CallOpts, userId [32]byte) (struct {
+	KeyId              *big.Int
+	PublicKey          string
+	Signature          string
+	IdentityKeyVersion *big.Int
+	CreatedAt          *big.Int
+}, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getSignedPreKey", userId)
+
+	outstruct := new(struct {
+		KeyId              *big.Int
+		PublicKey          string
+		Signature          string
+		IdentityKeyVersion *big.Int
+		CreatedAt          *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.KeyId = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.PublicKey = *abi.ConvertType(out[1], new(string)).(*string)
+	outstruct.Signature = *abi.ConvertType(out[2], new(string)).(*string)
+	outstruct.IdentityKeyVersion = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.CreatedAt = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetSignedPreKey is a free data retrieval call binding the contract method 0x106423aa.
+//
+// Solidity: function getSignedPreKey(bytes32 userId) view returns(uint256 keyId, string publicKey, string signature, uint256 identityKeyVersion, uint256 createdAt)
+func (_QuickPicStorage *QuickPicStorageSession) GetSignedPreKey(userId [32]byte) (struct {
+	KeyId              *big.Int
+	PublicKey          string
+	Signature          string
+	IdentityKeyVersion *big.Int
+	CreatedAt          *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetSignedPreKey(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetSignedPreKey is a free data retrieval call binding the contract method 0x106423aa.
+//
+// Solidity: function getSignedPreKey(bytes32 userId) view returns(uint256 keyId, string publicKey, string signature, uint256 identityKeyVersion, uint256 createdAt)
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetSignedPreKey(userId [32]byte) (struct {
+	KeyId              *big.Int
+	PublicKey          string
+	Signature          string
+	IdentityKeyVersion *big.Int
+	CreatedAt          *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetSignedPreKey(&_QuickPicStorage.CallOpts, userId)
+}
+
+// GetStats is a free data retrieval call binding the contract method 0xc59d4847.
+//
+// Solidity: function getStats() view returns(uint256 userCount, uint256 suspendedCount, uint256 pendingMessages, uint256 pendingMessageBytes, uint256 pendingRequests)
+func (_QuickPicStorage *QuickPicStorageCaller) GetStats(opts *bind.CallOpts) (struct {
+	UserCount           *big.Int
+	SuspendedCount      *big.Int
+	PendingMessages     *big.Int
+	PendingMessageBytes *big.Int
+	PendingRequests     *big.Int
+}, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getStats")
+
+	outstruct := new(struct {
+		UserCount           *big.Int
+		SuspendedCount      *big.Int
+		PendingMessages     *big.Int
+		PendingMessageBytes *big.Int
+		PendingRequests     *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.UserCount = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.SuspendedCount = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.PendingMessages = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.PendingMessageBytes = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.PendingRequests = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetStats is a free data retrieval call binding the contract method 0xc59d4847.
+//
+// Solidity: function getStats() view returns(uint256 userCount, uint256 suspendedCount, uint256 pendingMessages, uint256 pendingMessageBytes, uint256 pendingRequests)
+func (_QuickPicStorage *QuickPicStorageSession) GetStats() (struct {
+	UserCount           *big.Int
+	SuspendedCount      *big.Int
+	PendingMessages     *big.Int
+	PendingMessageBytes *big.Int
+	PendingRequests     *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetStats(&_QuickPicStorage.CallOpts)
+}
+
+// GetStats is a free data retrieval call binding the contract method 0xc59d4847.
+//
+// Solidity: function getStats() view returns(uint256 userCount, uint256 suspendedCount, uint256 pendingMessages, uint256 pendingMessageBytes, uint256 pendingRequests)
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetStats() (struct {
+	UserCount           *big.Int
+	SuspendedCount      *big.Int
+	PendingMessages     *big.Int
+	PendingMessageBytes *big.Int
+	PendingRequests     *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetStats(&_QuickPicStorage.CallOpts)
+}
+
+// GetSuspension is a free data retrieval call binding the contract method 0x8cea3044.
+//
+// Solidity: function getSuspension(bytes32 userId) view returns(bool exists, uint256 since)
+func (_QuickPicStorage *QuickPicStorageCaller) GetSuspension(opts *bind.CallOpts, userId [32]byte) (struct {
+	Exists bool
+	Since  *big.Int
+}, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "getSuspension", userId)
+
+	outstruct := new(struct {
+		Exists bool
+		Since  *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Exists = *abi.ConvertType(out[0], new(bool)).(*bool)
+	outstruct.Since = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetSuspension is a free data retrieval call binding the contract method 0x8cea3044.
 //
-// Solidity: function getPendingRequestsForUser(bytes32 userId) view returns(bytes32[])
-func (_QuickPicStorage *QuickPicStorageSession) GetPendingRequestsForUser(userId [32]byte) ([][32]byte, error) {
-	return _QuickPicStorage.Contract.GetPendingRequestsForUser(&_QuickPicStorage.CallOpts, userId)
+// Solidity: function getSuspension(bytes32 userId) view returns(bool exists, uint256 since)
+func (_QuickPicStorage *QuickPicStorageSession) GetSuspension(userId [32]byte) (struct {
+	Exists bool
+	Since  *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetSuspension(&_QuickPicStorage.CallOpts, userId)
 }
 
-// GetPendingRequestsForUser is a free data retrieval call binding the contract method 0xdb0088ac.
+// GetSuspension is a free data retrieval call binding the contract method 0x8cea3044.
 //
-// Solidity: function getPendingRequestsForUser(bytes32 userId) view returns(bytes32[])
-func (_QuickPicStorage *QuickPicStorageCallerSession) GetPendingRequestsForUser(userId [32]byte) ([][32]byte, error) {
-	return _QuickPicStorage.Contract.GetPendingRequestsForUser(&_QuickPicStorage.CallOpts, userId)
+// Solidity: function getSuspension(bytes32 userId) view returns(bool exists, uint256 since)
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetSuspension(userId [32]byte) (struct {
+	Exists bool
+	Since  *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.GetSuspension(&_QuickPicStorage.CallOpts, userId)
 }
 
 // GetUser is a free data retrieval call binding the contract method 0x6517579c.
@@ -971,81 +1744,249 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) GetUserFriendships(userId
 	return _QuickPicStorage.Contract.GetUserFriendships(&_QuickPicStorage.CallOpts, userId)
 }
 
-// MessageIds is a free data retrieval call binding the contract method 0x9d17b9c5.
+// GetUserInvites is a free data retrieval call binding the contract method 0xa476a8d4.
 //
-// Solidity: function messageIds(uint256 ) view returns(bytes32)
-func (_QuickPicStorage *QuickPicStorageCaller) MessageIds(opts *bind.CallOpts, arg0 *big.Int) ([32]byte, error) {
+// Solidity: function getUserInvites(bytes32 creatorId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCaller) GetUserInvites(opts *bind.CallOpts, creatorId [32]byte) ([][32]byte, error) {
 	var out []interface{}
-	err := _QuickPicStorage.contract.Call(opts, &out, "messageIds", arg0)
+	err := _QuickPicStorage.contract.Call(opts, &out, "getUserInvites", creatorId)
 
 	if err != nil {
-		return *new([32]byte), err
+		return *new([][32]byte), err
 	}
 
-	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	out0 := *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
 
 	return out0, err
 
 }
 
-// MessageIds is a free data retrieval call binding the contract method 0x9d17b9c5.
+// GetUserInvites is a free data retrieval call binding the contract method 0xa476a8d4.
 //
-// Solidity: function messageIds(uint256 ) view returns(bytes32)
-func (_QuickPicStorage *QuickPicStorageSession) MessageIds(arg0 *big.Int) ([32]byte, error) {
-	return _QuickPicStorage.Contract.MessageIds(&_QuickPicStorage.CallOpts, arg0)
+// Solidity: function getUserInvites(bytes32 creatorId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageSession) GetUserInvites(creatorId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetUserInvites(&_QuickPicStorage.CallOpts, creatorId)
 }
 
-// MessageIds is a free data retrieval call binding the contract method 0x9d17b9c5.
+// GetUserInvites is a free data retrieval call binding the contract method 0xa476a8d4.
 //
-// Solidity: function messageIds(uint256 ) view returns(bytes32)
-func (_QuickPicStorage *QuickPicStorageCallerSession) MessageIds(arg0 *big.Int) ([32]byte, error) {
-	return _QuickPicStorage.Contract.MessageIds(&_QuickPicStorage.CallOpts, arg0)
+// Solidity: function getUserInvites(bytes32 creatorId) view returns(bytes32[])
+func (_QuickPicStorage *QuickPicStorageCallerSession) GetUserInvites(creatorId [32]byte) ([][32]byte, error) {
+	return _QuickPicStorage.Contract.GetUserInvites(&_QuickPicStorage.CallOpts, creatorId)
 }
 
-// Messages is a free data retrieval call binding the contract method 0x2bbd59ca.
+// HasExpiredFriendRequests is a free data retrieval call binding the contract method 0x33ec11bf.
 //
-// Solidity: function messages(bytes32 ) view returns(bytes32 id, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature, uint256 createdAt, bool exists)
-func (_QuickPicStorage *QuickPicStorageCaller) Messages(opts *bind.CallOpts, arg0 [32]byte) (struct {
-	Id               [32]byte
-	FromUserId       [32]byte
-	ToUserId         [32]byte
-	EncryptedContent []byte
-	ContentType      uint8
-	Signature        string
-	CreatedAt        *big.Int
-	Exists           bool
+// Solidity: function hasExpiredFriendRequests(uint256 createdBefore) view returns(bool)
+func (_QuickPicStorage *QuickPicStorageCaller) HasExpiredFriendRequests(opts *bind.CallOpts, createdBefore *big.Int) (bool, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "hasExpiredFriendRequests", createdBefore)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// HasExpiredFriendRequests is a free data retrieval call binding the contract method 0x33ec11bf.
+//
+// Solidity: function hasExpiredFriendRequests(uint256 createdBefore) view returns(bool)
+func (_QuickPicStorage *QuickPicStorageSession) HasExpiredFriendRequests(createdBefore *big.Int) (bool, error) {
+	return _QuickPicStorage.Contract.HasExpiredFriendRequests(&_QuickPicStorage.CallOpts, createdBefore)
+}
+
+// HasExpiredFriendRequests is a free data retrieval call binding the contract method 0x33ec11bf.
+//
+// Solidity: function hasExpiredFriendRequests(uint256 createdBefore) view returns(bool)
+func (_QuickPicStorage *QuickPicStorageCallerSession) HasExpiredFriendRequests(createdBefore *big.Int) (bool, error) {
+	return _QuickPicStorage.Contract.HasExpiredFriendRequests(&_QuickPicStorage.CallOpts, createdBefore)
+}
+
+// Invites is a free data retrieval call binding the contract method 0xa5aa4aa4.
+//
+// Solidity: function invites(bytes32 ) view returns(bytes32 id, bytes32 creatorId, uint256 maxUses, uint256 uses, uint256 expiresAt, uint256 createdAt, bool revoked, bool exists)
+func (_QuickPicStorage *QuickPicStorageCaller) Invites(opts *bind.CallOpts, arg0 [32]byte) (struct {
+	Id        [32]byte
+	CreatorId [32]byte
+	MaxUses   *big.Int
+	Uses      *big.Int
+	ExpiresAt *big.Int
+	CreatedAt *big.Int
+	Revoked   bool
+	Exists    bool
 }, error) {
 	var out []interface{}
-	err := _QuickPicStorage.contract.Call(opts, &out, "messages", arg0)
+	err := _QuickPicStorage.contract.Call(opts, &out, "invites", arg0)
 
 	outstruct := new(struct {
-		Id               [32]byte
-		FromUserId       [32]byte
-		ToUserId         [32]byte
-		EncryptedContent []byte
-		ContentType      uint8
-		Signature        string
-		CreatedAt        *big.Int
-		Exists           bool
+		Id        [32]byte
+		CreatorId [32]byte
+		MaxUses   *big.Int
+		Uses      *big.Int
+		ExpiresAt *big.Int
+		CreatedAt *big.Int
+		Revoked   bool
+		Exists    bool
 	})
 	if err != nil {
 		return *outstruct, err
 	}
 
 	outstruct.Id = *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
-	outstruct.FromUserId = *abi.ConvertType(out[1], new([32]byte)).(*[32]byte)
-	outstruct.ToUserId = *abi.ConvertType(out[2], new([32]byte)).(*[32]byte)
-	outstruct.EncryptedContent = *abi.ConvertType(out[3], new([]byte)).(*[]byte)
-	outstruct.ContentType = *abi.ConvertType(out[4], new(uint8)).(*uint8)
-	outstruct.Signature = *abi.ConvertType(out[5], new(string)).(*string)
-	outstruct.CreatedAt = *abi.ConvertType(out[6], new(*big.Int)).(**big.Int)
+	outstruct.CreatorId = *abi.ConvertType(out[1], new([32]byte)).(*[32]byte)
+	outstruct.MaxUses = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.Uses = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.ExpiresAt = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+	outstruct.CreatedAt = *abi.ConvertType(out[5], new(*big.Int)).(**big.Int)
+	outstruct.Revoked = *abi.ConvertType(out[6], new(bool)).(*bool)
 	outstruct.Exists = *abi.ConvertType(out[7], new(bool)).(*bool)
 
 	return *outstruct, err
 
 }
 
-// Messages is a free data retrieval call binding the contract method 0x2bbd59ca.
+// Invites is a free data retrieval call binding the contract method 0xa5aa4aa4.
+//
+// Solidity: function invites(bytes32 ) view returns(bytes32 id, bytes32 creatorId, uint256 maxUses, uint256 uses, uint256 expiresAt, uint256 createdAt, bool revoked, bool exists)
+func (_QuickPicStorage *QuickPicStorageSession) Invites(arg0 [32]byte) (struct {
+	Id        [32]byte
+	CreatorId [32]byte
+	MaxUses   *big.Int
+	Uses      *big.Int
+	ExpiresAt *big.Int
+	CreatedAt *big.Int
+	Revoked   bool
+	Exists    bool
+}, error) {
+	return _QuickPicStorage.Contract.Invites(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// Invites is a free data retrieval call binding the contract method 0xa5aa4aa4.
+//
+// Solidity: function invites(bytes32 ) view returns(bytes32 id, bytes32 creatorId, uint256 maxUses, uint256 uses, uint256 expiresAt, uint256 createdAt, bool revoked, bool exists)
+func (_QuickPicStorage *QuickPicStorageCallerSession) Invites(arg0 [32]byte) (struct {
+	Id        [32]byte
+	CreatorId [32]byte
+	MaxUses   *big.Int
+	Uses      *big.Int
+	ExpiresAt *big.Int
+	CreatedAt *big.Int
+	Revoked   bool
+	Exists    bool
+}, error) {
+	return _QuickPicStorage.Contract.Invites(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// MessageIds is a free data retrieval call binding the contract method 0x9d17b9c5.
+//
+// Solidity: function messageIds(uint256 ) view returns(bytes32)
+func (_QuickPicStorage *QuickPicStorageCaller) MessageIds(opts *bind.CallOpts, arg0 *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "messageIds", arg0)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// MessageIds is a free data retrieval call binding the contract method 0x9d17b9c5.
+//
+// Solidity: function messageIds(uint256 ) view returns(bytes32)
+func (_QuickPicStorage *QuickPicStorageSession) MessageIds(arg0 *big.Int) ([32]byte, error) {
+	return _QuickPicStorage.Contract.MessageIds(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// MessageIds is a free data retrieval call binding the contract method 0x9d17b9c5.
+//
+// Solidity: function messageIds(uint256 ) view returns(bytes32)
+func (_QuickPicStorage *QuickPicStorageCallerSession) MessageIds(arg0 *big.Int) ([32]byte, error) {
+	return _QuickPicStorage.Contract.MessageIds(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// MessageKeyVersion is a free data retrieval call binding the contract method 0xda743092.
+//
+// Solidity: function messageKeyVersion(bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCaller) MessageKeyVersion(opts *bind.CallOpts, arg0 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "messageKeyVersion", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MessageKeyVersion is a free data retrieval call binding the contract method 0xda743092.
+//
+// Solidity: function messageKeyVersion(bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageSession) MessageKeyVersion(arg0 [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.MessageKeyVersion(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// MessageKeyVersion is a free data retrieval call binding the contract method 0xda743092.
+//
+// Solidity: function messageKeyVersion(bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCallerSession) MessageKeyVersion(arg0 [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.MessageKeyVersion(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// Messages is a free data retrieval call binding the contract method 0x2bbd59ca.
+//
+// Solidity: function messages(bytes32 ) view returns(bytes32 id, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature, uint256 createdAt, bool exists)
+func (_QuickPicStorage *QuickPicStorageCaller) Messages(opts *bind.CallOpts, arg0 [32]byte) (struct {
+	Id               [32]byte
+	FromUserId       [32]byte
+	ToUserId         [32]byte
+	EncryptedContent []byte
+	ContentType      uint8
+	Signature        string
+	CreatedAt        *big.Int
+	Exists           bool
+}, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "messages", arg0)
+
+	outstruct := new(struct {
+		Id               [32]byte
+		FromUserId       [32]byte
+		ToUserId         [32]byte
+		EncryptedContent []byte
+		ContentType      uint8
+		Signature        string
+		CreatedAt        *big.Int
+		Exists           bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Id = *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	outstruct.FromUserId = *abi.ConvertType(out[1], new([32]byte)).(*[32]byte)
+	outstruct.ToUserId = *abi.ConvertType(out[2], new([32]byte)).(*[32]byte)
+	outstruct.EncryptedContent = *abi.ConvertType(out[3], new([]byte)).(*[]byte)
+	outstruct.ContentType = *abi.ConvertType(out[4], new(uint8)).(*uint8)
+	outstruct.Signature = *abi.ConvertType(out[5], new(string)).(*string)
+	outstruct.CreatedAt = *abi.ConvertType(out[6], new(*big.Int)).(**big.Int)
+	outstruct.Exists = *abi.ConvertType(out[7], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Messages is a free data retrieval call binding the contract method 0x2bbd59ca.
 //
 // Solidity: function messages(bytes32 ) view returns(bytes32 id, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature, uint256 createdAt, bool exists)
 func (_QuickPicStorage *QuickPicStorageSession) Messages(arg0 [32]byte) (struct {
@@ -1232,6 +2173,68 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) PendingRequestsTo(arg0 [32
 	return _QuickPicStorage.Contract.PendingRequestsTo(&_QuickPicStorage.CallOpts, arg0, arg1)
 }
 
+// PrivacyFlags is a free data retrieval call binding the contract method 0x0bcea9ee.
+//
+// Solidity: function privacyFlags(bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCaller) PrivacyFlags(opts *bind.CallOpts, arg0 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "privacyFlags", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// PrivacyFlags is a free data retrieval call binding the contract method 0x0bcea9ee.
+//
+// Solidity: function privacyFlags(bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageSession) PrivacyFlags(arg0 [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.PrivacyFlags(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// PrivacyFlags is a free data retrieval call binding the contract method 0x0bcea9ee.
+//
+// Solidity: function privacyFlags(bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCallerSession) PrivacyFlags(arg0 [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.PrivacyFlags(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// SkeletonToId is a free data retrieval call binding the contract method 0x58001e1c.
+//
+// Solidity: function skeletonToId(bytes32 ) view returns(bytes32)
+func (_QuickPicStorage *QuickPicStorageCaller) SkeletonToId(opts *bind.CallOpts, arg0 [32]byte) ([32]byte, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "skeletonToId", arg0)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// SkeletonToId is a free data retrieval call binding the contract method 0x58001e1c.
+//
+// Solidity: function skeletonToId(bytes32 ) view returns(bytes32)
+func (_QuickPicStorage *QuickPicStorageSession) SkeletonToId(arg0 [32]byte) ([32]byte, error) {
+	return _QuickPicStorage.Contract.SkeletonToId(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// SkeletonToId is a free data retrieval call binding the contract method 0x58001e1c.
+//
+// Solidity: function skeletonToId(bytes32 ) view returns(bytes32)
+func (_QuickPicStorage *QuickPicStorageCallerSession) SkeletonToId(arg0 [32]byte) ([32]byte, error) {
+	return _QuickPicStorage.Contract.SkeletonToId(&_QuickPicStorage.CallOpts, arg0)
+}
+
 // UserExists is a free data retrieval call binding the contract method 0xa2e8452c.
 //
 // Solidity: function userExists(bytes32 id) view returns(bool)
@@ -1356,6 +2359,51 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) UsernameExists(username st
 	return _QuickPicStorage.Contract.UsernameExists(&_QuickPicStorage.CallOpts, username)
 }
 
+// UsernameReservations is a free data retrieval call binding the contract method 0x97c6ada7.
+//
+// Solidity: function usernameReservations(bytes32 ) view returns(bytes32 userId, uint256 reservedUntil)
+func (_QuickPicStorage *QuickPicStorageCaller) UsernameReservations(opts *bind.CallOpts, arg0 [32]byte) (struct {
+	UserId        [32]byte
+	ReservedUntil *big.Int
+}, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "usernameReservations", arg0)
+
+	outstruct := new(struct {
+		UserId        [32]byte
+		ReservedUntil *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.UserId = *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	outstruct.ReservedUntil = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// UsernameReservations is a free data retrieval call binding the contract method 0x97c6ada7.
+//
+// Solidity: function usernameReservations(bytes32 ) view returns(bytes32 userId, uint256 reservedUntil)
+func (_QuickPicStorage *QuickPicStorageSession) UsernameReservations(arg0 [32]byte) (struct {
+	UserId        [32]byte
+	ReservedUntil *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.UsernameReservations(&_QuickPicStorage.CallOpts, arg0)
+}
+
+// UsernameReservations is a free data retrieval call binding the contract method 0x97c6ada7.
+//
+// Solidity: function usernameReservations(bytes32 ) view returns(bytes32 userId, uint256 reservedUntil)
+func (_QuickPicStorage *QuickPicStorageCallerSession) UsernameReservations(arg0 [32]byte) (struct {
+	UserId        [32]byte
+	ReservedUntil *big.Int
+}, error) {
+	return _QuickPicStorage.Contract.UsernameReservations(&_QuickPicStorage.CallOpts, arg0)
+}
+
 // UsernameToId is a free data retrieval call binding the contract method 0x5e1fe1db.
 //
 // Solidity: function usernameToId(string ) view returns(bytes32)
@@ -1462,177 +2510,3458 @@ func (_QuickPicStorage *QuickPicStorageCallerSession) Users(arg0 [32]byte) (stru
 	return _QuickPicStorage.Contract.Users(&_QuickPicStorage.CallOpts, arg0)
 }
 
-// CreateFriendRequest is a paid mutator transaction binding the contract method 0xe6924351.
+// VerifiedKeyVersion is a free data retrieval call binding the contract method 0x30ed7596.
 //
-// Solidity: function createFriendRequest(bytes32 id, bytes32 fromUserId, bytes32 toUserId) returns()
-func (_QuickPicStorage *QuickPicStorageTransactor) CreateFriendRequest(opts *bind.TransactOpts, id [32]byte, fromUserId [32]byte, toUserId [32]byte) (*types.Transaction, error) {
-	return _QuickPicStorage.contract.Transact(opts, "createFriendRequest", id, fromUserId, toUserId)
+// Solidity: function verifiedKeyVersion(bytes32 , bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCaller) VerifiedKeyVersion(opts *bind.CallOpts, arg0 [32]byte, arg1 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _QuickPicStorage.contract.Call(opts, &out, "verifiedKeyVersion", arg0, arg1)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
 }
 
-// CreateFriendRequest is a paid mutator transaction binding the contract method 0xe6924351.
+// VerifiedKeyVersion is a free data retrieval call binding the contract method 0x30ed7596.
 //
-// Solidity: function createFriendRequest(bytes32 id, bytes32 fromUserId, bytes32 toUserId) returns()
-func (_QuickPicStorage *QuickPicStorageSession) CreateFriendRequest(id [32]byte, fromUserId [32]byte, toUserId [32]byte) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.CreateFriendRequest(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId)
+// Solidity: function verifiedKeyVersion(bytes32 , bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageSession) VerifiedKeyVersion(arg0 [32]byte, arg1 [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.VerifiedKeyVersion(&_QuickPicStorage.CallOpts, arg0, arg1)
 }
 
-// CreateFriendRequest is a paid mutator transaction binding the contract method 0xe6924351.
+// VerifiedKeyVersion is a free data retrieval call binding the contract method 0x30ed7596.
 //
-// Solidity: function createFriendRequest(bytes32 id, bytes32 fromUserId, bytes32 toUserId) returns()
-func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateFriendRequest(id [32]byte, fromUserId [32]byte, toUserId [32]byte) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.CreateFriendRequest(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId)
+// Solidity: function verifiedKeyVersion(bytes32 , bytes32 ) view returns(uint256)
+func (_QuickPicStorage *QuickPicStorageCallerSession) VerifiedKeyVersion(arg0 [32]byte, arg1 [32]byte) (*big.Int, error) {
+	return _QuickPicStorage.Contract.VerifiedKeyVersion(&_QuickPicStorage.CallOpts, arg0, arg1)
 }
 
-// CreateFriendship is a paid mutator transaction binding the contract method 0x60b7ef48.
+// AcceptFriendRequest is a paid mutator transaction binding the contract method 0x1a66c2fd.
 //
-// Solidity: function createFriendship(bytes32 id, bytes32 userAId, bytes32 userBId) returns()
-func (_QuickPicStorage *QuickPicStorageTransactor) CreateFriendship(opts *bind.TransactOpts, id [32]byte, userAId [32]byte, userBId [32]byte) (*types.Transaction, error) {
-	return _QuickPicStorage.contract.Transact(opts, "createFriendship", id, userAId, userBId)
+// Solidity: function acceptFriendRequest(bytes32 id, bytes32 friendshipId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) AcceptFriendRequest(opts *bind.TransactOpts, id [32]byte, friendshipId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "acceptFriendRequest", id, friendshipId)
 }
 
-// CreateFriendship is a paid mutator transaction binding the contract method 0x60b7ef48.
+// AcceptFriendRequest is a paid mutator transaction binding the contract method 0x1a66c2fd.
 //
-// Solidity: function createFriendship(bytes32 id, bytes32 userAId, bytes32 userBId) returns()
-func (_QuickPicStorage *QuickPicStorageSession) CreateFriendship(id [32]byte, userAId [32]byte, userBId [32]byte) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.CreateFriendship(&_QuickPicStorage.TransactOpts, id, userAId, userBId)
+// Solidity: function acceptFriendRequest(bytes32 id, bytes32 friendshipId) returns()
+func (_QuickPicStorage *QuickPicStorageSession) AcceptFriendRequest(id [32]byte, friendshipId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.AcceptFriendRequest(&_QuickPicStorage.TransactOpts, id, friendshipId)
 }
 
-// CreateFriendship is a paid mutator transaction binding the contract method 0x60b7ef48.
+// AcceptFriendRequest is a paid mutator transaction binding the contract method 0x1a66c2fd.
 //
-// Solidity: function createFriendship(bytes32 id, bytes32 userAId, bytes32 userBId) returns()
-func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateFriendship(id [32]byte, userAId [32]byte, userBId [32]byte) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.CreateFriendship(&_QuickPicStorage.TransactOpts, id, userAId, userBId)
+// Solidity: function acceptFriendRequest(bytes32 id, bytes32 friendshipId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) AcceptFriendRequest(id [32]byte, friendshipId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.AcceptFriendRequest(&_QuickPicStorage.TransactOpts, id, friendshipId)
 }
 
-// CreateMessage is a paid mutator transaction binding the contract method 0x14782c61.
+// AcknowledgeDelivery is a paid mutator transaction binding the contract method 0x107ae02a.
 //
-// Solidity: function createMessage(bytes32 id, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature) returns()
-func (_QuickPicStorage *QuickPicStorageTransactor) CreateMessage(opts *bind.TransactOpts, id [32]byte, fromUserId [32]byte, toUserId [32]byte, encryptedContent []byte, contentType uint8, signature string) (*types.Transaction, error) {
-	return _QuickPicStorage.contract.Transact(opts, "createMessage", id, fromUserId, toUserId, encryptedContent, contentType, signature)
+// Solidity: function acknowledgeDelivery(bytes32 id, bytes32 deviceId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) AcknowledgeDelivery(opts *bind.TransactOpts, id [32]byte, deviceId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "acknowledgeDelivery", id, deviceId)
 }
 
-// CreateMessage is a paid mutator transaction binding the contract method 0x14782c61.
+// AcknowledgeDelivery is a paid mutator transaction binding the contract method 0x107ae02a.
 //
-// Solidity: function createMessage(bytes32 id, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature) returns()
-func (_QuickPicStorage *QuickPicStorageSession) CreateMessage(id [32]byte, fromUserId [32]byte, toUserId [32]byte, encryptedContent []byte, contentType uint8, signature string) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.CreateMessage(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId, encryptedContent, contentType, signature)
+// Solidity: function acknowledgeDelivery(bytes32 id, bytes32 deviceId) returns()
+func (_QuickPicStorage *QuickPicStorageSession) AcknowledgeDelivery(id [32]byte, deviceId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.AcknowledgeDelivery(&_QuickPicStorage.TransactOpts, id, deviceId)
 }
 
-// CreateMessage is a paid mutator transaction binding the contract method 0x14782c61.
+// AcknowledgeDelivery is a paid mutator transaction binding the contract method 0x107ae02a.
 //
-// Solidity: function createMessage(bytes32 id, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature) returns()
-func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateMessage(id [32]byte, fromUserId [32]byte, toUserId [32]byte, encryptedContent []byte, contentType uint8, signature string) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.CreateMessage(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId, encryptedContent, contentType, signature)
+// Solidity: function acknowledgeDelivery(bytes32 id, bytes32 deviceId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) AcknowledgeDelivery(id [32]byte, deviceId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.AcknowledgeDelivery(&_QuickPicStorage.TransactOpts, id, deviceId)
 }
 
-// CreateUser is a paid mutator transaction binding the contract method 0x765f5d35.
+// AddDevice is a paid mutator transaction binding the contract method 0x3fb1174d.
 //
-// Solidity: function createUser(bytes32 id, string username, string passwordHash, string publicKey) returns(uint256 userNumber)
-func (_QuickPicStorage *QuickPicStorageTransactor) CreateUser(opts *bind.TransactOpts, id [32]byte, username string, passwordHash string, publicKey string) (*types.Transaction, error) {
-	return _QuickPicStorage.contract.Transact(opts, "createUser", id, username, passwordHash, publicKey)
+// Solidity: function addDevice(bytes32 id, bytes32 userId, string name, string publicKey, string signature) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) AddDevice(opts *bind.TransactOpts, id [32]byte, userId [32]byte, name string, publicKey string, signature string) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "addDevice", id, userId, name, publicKey, signature)
 }
 
-// CreateUser is a paid mutator transaction binding the contract method 0x765f5d35.
+// AddDevice is a paid mutator transaction binding the contract method 0x3fb1174d.
 //
-// Solidity: function createUser(bytes32 id, string username, string passwordHash, string publicKey) returns(uint256 userNumber)
-func (_QuickPicStorage *QuickPicStorageSession) CreateUser(id [32]byte, username string, passwordHash string, publicKey string) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.CreateUser(&_QuickPicStorage.TransactOpts, id, username, passwordHash, publicKey)
+// Solidity: function addDevice(bytes32 id, bytes32 userId, string name, string publicKey, string signature) returns()
+func (_QuickPicStorage *QuickPicStorageSession) AddDevice(id [32]byte, userId [32]byte, name string, publicKey string, signature string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.AddDevice(&_QuickPicStorage.TransactOpts, id, userId, name, publicKey, signature)
 }
 
-// CreateUser is a paid mutator transaction binding the contract method 0x765f5d35.
+// AddDevice is a paid mutator transaction binding the contract method 0x3fb1174d.
 //
-// Solidity: function createUser(bytes32 id, string username, string passwordHash, string publicKey) returns(uint256 userNumber)
-func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateUser(id [32]byte, username string, passwordHash string, publicKey string) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.CreateUser(&_QuickPicStorage.TransactOpts, id, username, passwordHash, publicKey)
+// Solidity: function addDevice(bytes32 id, bytes32 userId, string name, string publicKey, string signature) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) AddDevice(id [32]byte, userId [32]byte, name string, publicKey string, signature string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.AddDevice(&_QuickPicStorage.TransactOpts, id, userId, name, publicKey, signature)
 }
 
-// DeleteMessage is a paid mutator transaction binding the contract method 0xfe1e3eca.
+// AddOneTimePreKeys is a paid mutator transaction binding the contract method 0xa9fa1185.
 //
-// Solidity: function deleteMessage(bytes32 id) returns()
-func (_QuickPicStorage *QuickPicStorageTransactor) DeleteMessage(opts *bind.TransactOpts, id [32]byte) (*types.Transaction, error) {
-	return _QuickPicStorage.contract.Transact(opts, "deleteMessage", id)
+// Solidity: function addOneTimePreKeys(bytes32 userId, uint256[] keyIds, string[] publicKeys, bool replace) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) AddOneTimePreKeys(opts *bind.TransactOpts, userId [32]byte, keyIds []*big.Int, publicKeys []string, replace bool) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "addOneTimePreKeys", userId, keyIds, publicKeys, replace)
 }
 
-// DeleteMessage is a paid mutator transaction binding the contract method 0xfe1e3eca.
+// AddOneTimePreKeys is a paid mutator transaction binding the contract method 0xa9fa1185.
 //
-// Solidity: function deleteMessage(bytes32 id) returns()
-func (_QuickPicStorage *QuickPicStorageSession) DeleteMessage(id [32]byte) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.DeleteMessage(&_QuickPicStorage.TransactOpts, id)
+// Solidity: function addOneTimePreKeys(bytes32 userId, uint256[] keyIds, string[] publicKeys, bool replace) returns()
+func (_QuickPicStorage *QuickPicStorageSession) AddOneTimePreKeys(userId [32]byte, keyIds []*big.Int, publicKeys []string, replace bool) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.AddOneTimePreKeys(&_QuickPicStorage.TransactOpts, userId, keyIds, publicKeys, replace)
 }
 
-// DeleteMessage is a paid mutator transaction binding the contract method 0xfe1e3eca.
+// AddOneTimePreKeys is a paid mutator transaction binding the contract method 0xa9fa1185.
 //
-// Solidity: function deleteMessage(bytes32 id) returns()
-func (_QuickPicStorage *QuickPicStorageTransactorSession) DeleteMessage(id [32]byte) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.DeleteMessage(&_QuickPicStorage.TransactOpts, id)
+// Solidity: function addOneTimePreKeys(bytes32 userId, uint256[] keyIds, string[] publicKeys, bool replace) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) AddOneTimePreKeys(userId [32]byte, keyIds []*big.Int, publicKeys []string, replace bool) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.AddOneTimePreKeys(&_QuickPicStorage.TransactOpts, userId, keyIds, publicKeys, replace)
 }
 
-// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+// BlockUser is a paid mutator transaction binding the contract method 0xaad6f71c.
 //
-// Solidity: function transferOwnership(address newOwner) returns()
-func (_QuickPicStorage *QuickPicStorageTransactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
-	return _QuickPicStorage.contract.Transact(opts, "transferOwnership", newOwner)
+// Solidity: function blockUser(bytes32 blockerId, bytes32 blockedId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) BlockUser(opts *bind.TransactOpts, blockerId [32]byte, blockedId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "blockUser", blockerId, blockedId)
 }
 
-// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+// BlockUser is a paid mutator transaction binding the contract method 0xaad6f71c.
 //
-// Solidity: function transferOwnership(address newOwner) returns()
-func (_QuickPicStorage *QuickPicStorageSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.TransferOwnership(&_QuickPicStorage.TransactOpts, newOwner)
+// Solidity: function blockUser(bytes32 blockerId, bytes32 blockedId) returns()
+func (_QuickPicStorage *QuickPicStorageSession) BlockUser(blockerId [32]byte, blockedId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.BlockUser(&_QuickPicStorage.TransactOpts, blockerId, blockedId)
 }
 
-// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+// BlockUser is a paid mutator transaction binding the contract method 0xaad6f71c.
 //
-// Solidity: function transferOwnership(address newOwner) returns()
-func (_QuickPicStorage *QuickPicStorageTransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.TransferOwnership(&_QuickPicStorage.TransactOpts, newOwner)
+// Solidity: function blockUser(bytes32 blockerId, bytes32 blockedId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) BlockUser(blockerId [32]byte, blockedId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.BlockUser(&_QuickPicStorage.TransactOpts, blockerId, blockedId)
 }
 
-// UpdateFriendRequestStatus is a paid mutator transaction binding the contract method 0x4018c162.
+// ChangeUsername is a paid mutator transaction binding the contract method 0x41643b80.
 //
-// Solidity: function updateFriendRequestStatus(bytes32 id, uint8 status) returns()
-func (_QuickPicStorage *QuickPicStorageTransactor) UpdateFriendRequestStatus(opts *bind.TransactOpts, id [32]byte, status uint8) (*types.Transaction, error) {
-	return _QuickPicStorage.contract.Transact(opts, "updateFriendRequestStatus", id, status)
+// Solidity: function changeUsername(bytes32 id, string newUsername, bytes32 newSkeleton, uint256 reservedUntil) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) ChangeUsername(opts *bind.TransactOpts, id [32]byte, newUsername string, newSkeleton [32]byte, reservedUntil *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "changeUsername", id, newUsername, newSkeleton, reservedUntil)
 }
 
-// UpdateFriendRequestStatus is a paid mutator transaction binding the contract method 0x4018c162.
+// ChangeUsername is a paid mutator transaction binding the contract method 0x41643b80.
 //
-// Solidity: function updateFriendRequestStatus(bytes32 id, uint8 status) returns()
-func (_QuickPicStorage *QuickPicStorageSession) UpdateFriendRequestStatus(id [32]byte, status uint8) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.UpdateFriendRequestStatus(&_QuickPicStorage.TransactOpts, id, status)
+// Solidity: function changeUsername(bytes32 id, string newUsername, bytes32 newSkeleton, uint256 reservedUntil) returns()
+func (_QuickPicStorage *QuickPicStorageSession) ChangeUsername(id [32]byte, newUsername string, newSkeleton [32]byte, reservedUntil *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.ChangeUsername(&_QuickPicStorage.TransactOpts, id, newUsername, newSkeleton, reservedUntil)
 }
 
-// UpdateFriendRequestStatus is a paid mutator transaction binding the contract method 0x4018c162.
+// ChangeUsername is a paid mutator transaction binding the contract method 0x41643b80.
 //
-// Solidity: function updateFriendRequestStatus(bytes32 id, uint8 status) returns()
-func (_QuickPicStorage *QuickPicStorageTransactorSession) UpdateFriendRequestStatus(id [32]byte, status uint8) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.UpdateFriendRequestStatus(&_QuickPicStorage.TransactOpts, id, status)
+// Solidity: function changeUsername(bytes32 id, string newUsername, bytes32 newSkeleton, uint256 reservedUntil) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) ChangeUsername(id [32]byte, newUsername string, newSkeleton [32]byte, reservedUntil *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.ChangeUsername(&_QuickPicStorage.TransactOpts, id, newUsername, newSkeleton, reservedUntil)
 }
 
-// UpdateUser is a paid mutator transaction binding the contract method 0x494fb8e7.
+// ClaimOneTimePreKey is a paid mutator transaction binding the contract method 0x971743ac.
 //
-// Solidity: function updateUser(bytes32 id, string passwordHash, string publicKey) returns()
-func (_QuickPicStorage *QuickPicStorageTransactor) UpdateUser(opts *bind.TransactOpts, id [32]byte, passwordHash string, publicKey string) (*types.Transaction, error) {
-	return _QuickPicStorage.contract.Transact(opts, "updateUser", id, passwordHash, publicKey)
+// Solidity: function claimOneTimePreKey(bytes32 userId) returns(bool claimed, uint256 keyId, string publicKey)
+func (_QuickPicStorage *QuickPicStorageTransactor) ClaimOneTimePreKey(opts *bind.TransactOpts, userId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "claimOneTimePreKey", userId)
+}
+
+// ClaimOneTimePreKey is a paid mutator transaction binding the contract method 0x971743ac.
+//
+// Solidity: function claimOneTimePreKey(bytes32 userId) returns(bool claimed, uint256 keyId, string publicKey)
+func (_QuickPicStorage *QuickPicStorageSession) ClaimOneTimePreKey(userId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.ClaimOneTimePreKey(&_QuickPicStorage.TransactOpts, userId)
+}
+
+// ClaimOneTimePreKey is a paid mutator transaction binding the contract method 0x971743ac.
+//
+// Solidity: function claimOneTimePreKey(bytes32 userId) returns(bool claimed, uint256 keyId, string publicKey)
+func (_QuickPicStorage *QuickPicStorageTransactorSession) ClaimOneTimePreKey(userId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.ClaimOneTimePreKey(&_QuickPicStorage.TransactOpts, userId)
+}
+
+// CreateDeviceMessage is a paid mutator transaction binding the contract method 0xa7b2f7e3.
+//
+// Solidity: function createDeviceMessage(bytes32 id, bytes32 fromUserId, bytes32 toUserId, uint8 contentType, string signature, (bytes32,bytes)[] deliveries) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) CreateDeviceMessage(opts *bind.TransactOpts, id [32]byte, fromUserId [32]byte, toUserId [32]byte, contentType uint8, signature string, deliveries []QuickPicStorageDeviceCiphertext) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "createDeviceMessage", id, fromUserId, toUserId, contentType, signature, deliveries)
+}
+
+// CreateDeviceMessage is a paid mutator transaction binding the contract method 0xa7b2f7e3.
+//
+// Solidity: function createDeviceMessage(bytes32 id, bytes32 fromUserId, bytes32 toUserId, uint8 contentType, string signature, (bytes32,bytes)[] deliveries) returns()
+func (_QuickPicStorage *QuickPicStorageSession) CreateDeviceMessage(id [32]byte, fromUserId [32]byte, toUserId [32]byte, contentType uint8, signature string, deliveries []QuickPicStorageDeviceCiphertext) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateDeviceMessage(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId, contentType, signature, deliveries)
+}
+
+// CreateDeviceMessage is a paid mutator transaction binding the contract method 0xa7b2f7e3.
+//
+// Solidity: function createDeviceMessage(bytes32 id, bytes32 fromUserId, bytes32 toUserId, uint8 contentType, string signature, (bytes32,bytes)[] deliveries) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateDeviceMessage(id [32]byte, fromUserId [32]byte, toUserId [32]byte, contentType uint8, signature string, deliveries []QuickPicStorageDeviceCiphertext) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateDeviceMessage(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId, contentType, signature, deliveries)
+}
+
+// CreateFriendRequest is a paid mutator transaction binding the contract method 0xe6924351.
+//
+// Solidity: function createFriendRequest(bytes32 id, bytes32 fromUserId, bytes32 toUserId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) CreateFriendRequest(opts *bind.TransactOpts, id [32]byte, fromUserId [32]byte, toUserId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "createFriendRequest", id, fromUserId, toUserId)
+}
+
+// CreateFriendRequest is a paid mutator transaction binding the contract method 0xe6924351.
+//
+// Solidity: function createFriendRequest(bytes32 id, bytes32 fromUserId, bytes32 toUserId) returns()
+func (_QuickPicStorage *QuickPicStorageSession) CreateFriendRequest(id [32]byte, fromUserId [32]byte, toUserId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateFriendRequest(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId)
+}
+
+// CreateFriendRequest is a paid mutator transaction binding the contract method 0xe6924351.
+//
+// Solidity: function createFriendRequest(bytes32 id, bytes32 fromUserId, bytes32 toUserId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateFriendRequest(id [32]byte, fromUserId [32]byte, toUserId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateFriendRequest(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId)
+}
+
+// CreateFriendship is a paid mutator transaction binding the contract method 0x60b7ef48.
+//
+// Solidity: function createFriendship(bytes32 id, bytes32 userAId, bytes32 userBId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) CreateFriendship(opts *bind.TransactOpts, id [32]byte, userAId [32]byte, userBId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "createFriendship", id, userAId, userBId)
+}
+
+// CreateFriendship is a paid mutator transaction binding the contract method 0x60b7ef48.
+//
+// Solidity: function createFriendship(bytes32 id, bytes32 userAId, bytes32 userBId) returns()
+func (_QuickPicStorage *QuickPicStorageSession) CreateFriendship(id [32]byte, userAId [32]byte, userBId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateFriendship(&_QuickPicStorage.TransactOpts, id, userAId, userBId)
+}
+
+// CreateFriendship is a paid mutator transaction binding the contract method 0x60b7ef48.
+//
+// Solidity: function createFriendship(bytes32 id, bytes32 userAId, bytes32 userBId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateFriendship(id [32]byte, userAId [32]byte, userBId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateFriendship(&_QuickPicStorage.TransactOpts, id, userAId, userBId)
+}
+
+// CreateInvite is a paid mutator transaction binding the contract method 0x319b9247.
+//
+// Solidity: function createInvite(bytes32 id, bytes32 creatorId, uint256 maxUses, uint256 expiresAt) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) CreateInvite(opts *bind.TransactOpts, id [32]byte, creatorId [32]byte, maxUses *big.Int, expiresAt *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "createInvite", id, creatorId, maxUses, expiresAt)
+}
+
+// CreateInvite is a paid mutator transaction binding the contract method 0x319b9247.
+//
+// Solidity: function createInvite(bytes32 id, bytes32 creatorId, uint256 maxUses, uint256 expiresAt) returns()
+func (_QuickPicStorage *QuickPicStorageSession) CreateInvite(id [32]byte, creatorId [32]byte, maxUses *big.Int, expiresAt *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateInvite(&_QuickPicStorage.TransactOpts, id, creatorId, maxUses, expiresAt)
+}
+
+// CreateInvite is a paid mutator transaction binding the contract method 0x319b9247.
+//
+// Solidity: function createInvite(bytes32 id, bytes32 creatorId, uint256 maxUses, uint256 expiresAt) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateInvite(id [32]byte, creatorId [32]byte, maxUses *big.Int, expiresAt *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateInvite(&_QuickPicStorage.TransactOpts, id, creatorId, maxUses, expiresAt)
+}
+
+// CreateMessage is a paid mutator transaction binding the contract method 0x14782c61.
+//
+// Solidity: function createMessage(bytes32 id, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) CreateMessage(opts *bind.TransactOpts, id [32]byte, fromUserId [32]byte, toUserId [32]byte, encryptedContent []byte, contentType uint8, signature string) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "createMessage", id, fromUserId, toUserId, encryptedContent, contentType, signature)
+}
+
+// CreateMessage is a paid mutator transaction binding the contract method 0x14782c61.
+//
+// Solidity: function createMessage(bytes32 id, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature) returns()
+func (_QuickPicStorage *QuickPicStorageSession) CreateMessage(id [32]byte, fromUserId [32]byte, toUserId [32]byte, encryptedContent []byte, contentType uint8, signature string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateMessage(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId, encryptedContent, contentType, signature)
+}
+
+// CreateMessage is a paid mutator transaction binding the contract method 0x14782c61.
+//
+// Solidity: function createMessage(bytes32 id, bytes32 fromUserId, bytes32 toUserId, bytes encryptedContent, uint8 contentType, string signature) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateMessage(id [32]byte, fromUserId [32]byte, toUserId [32]byte, encryptedContent []byte, contentType uint8, signature string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateMessage(&_QuickPicStorage.TransactOpts, id, fromUserId, toUserId, encryptedContent, contentType, signature)
+}
+
+// CreateUser is a paid mutator transaction binding the contract method 0x1fd4cbd2.
+//
+// Solidity: function createUser(bytes32 id, string username, bytes32 usernameSkeleton, string passwordHash, string publicKey) returns(uint256 userNumber)
+func (_QuickPicStorage *QuickPicStorageTransactor) CreateUser(opts *bind.TransactOpts, id [32]byte, username string, usernameSkeleton [32]byte, passwordHash string, publicKey string) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "createUser", id, username, usernameSkeleton, passwordHash, publicKey)
+}
+
+// CreateUser is a paid mutator transaction binding the contract method 0x1fd4cbd2.
+//
+// Solidity: function createUser(bytes32 id, string username, bytes32 usernameSkeleton, string passwordHash, string publicKey) returns(uint256 userNumber)
+func (_QuickPicStorage *QuickPicStorageSession) CreateUser(id [32]byte, username string, usernameSkeleton [32]byte, passwordHash string, publicKey string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateUser(&_QuickPicStorage.TransactOpts, id, username, usernameSkeleton, passwordHash, publicKey)
+}
+
+// CreateUser is a paid mutator transaction binding the contract method 0x1fd4cbd2.
+//
+// Solidity: function createUser(bytes32 id, string username, bytes32 usernameSkeleton, string passwordHash, string publicKey) returns(uint256 userNumber)
+func (_QuickPicStorage *QuickPicStorageTransactorSession) CreateUser(id [32]byte, username string, usernameSkeleton [32]byte, passwordHash string, publicKey string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.CreateUser(&_QuickPicStorage.TransactOpts, id, username, usernameSkeleton, passwordHash, publicKey)
+}
+
+// DeleteAvatar is a paid mutator transaction binding the contract method 0xe1e3b7b9.
+//
+// Solidity: function deleteAvatar(bytes32 userId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) DeleteAvatar(opts *bind.TransactOpts, userId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "deleteAvatar", userId)
+}
+
+// DeleteAvatar is a paid mutator transaction binding the contract method 0xe1e3b7b9.
+//
+// Solidity: function deleteAvatar(bytes32 userId) returns()
+func (_QuickPicStorage *QuickPicStorageSession) DeleteAvatar(userId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.DeleteAvatar(&_QuickPicStorage.TransactOpts, userId)
+}
+
+// DeleteAvatar is a paid mutator transaction binding the contract method 0xe1e3b7b9.
+//
+// Solidity: function deleteAvatar(bytes32 userId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) DeleteAvatar(userId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.DeleteAvatar(&_QuickPicStorage.TransactOpts, userId)
+}
+
+// DeleteMessage is a paid mutator transaction binding the contract method 0xfe1e3eca.
+//
+// Solidity: function deleteMessage(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) DeleteMessage(opts *bind.TransactOpts, id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "deleteMessage", id)
+}
+
+// DeleteMessage is a paid mutator transaction binding the contract method 0xfe1e3eca.
+//
+// Solidity: function deleteMessage(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageSession) DeleteMessage(id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.DeleteMessage(&_QuickPicStorage.TransactOpts, id)
+}
+
+// DeleteMessage is a paid mutator transaction binding the contract method 0xfe1e3eca.
+//
+// Solidity: function deleteMessage(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) DeleteMessage(id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.DeleteMessage(&_QuickPicStorage.TransactOpts, id)
+}
+
+// DeleteUser is a paid mutator transaction binding the contract method 0x5e5d4320.
+//
+// Solidity: function deleteUser(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) DeleteUser(opts *bind.TransactOpts, id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "deleteUser", id)
+}
+
+// DeleteUser is a paid mutator transaction binding the contract method 0x5e5d4320.
+//
+// Solidity: function deleteUser(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageSession) DeleteUser(id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.DeleteUser(&_QuickPicStorage.TransactOpts, id)
+}
+
+// DeleteUser is a paid mutator transaction binding the contract method 0x5e5d4320.
+//
+// Solidity: function deleteUser(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) DeleteUser(id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.DeleteUser(&_QuickPicStorage.TransactOpts, id)
+}
+
+// ExpireFriendRequests is a paid mutator transaction binding the contract method 0xc1ba929e.
+//
+// Solidity: function expireFriendRequests(uint256 createdBefore, uint256 limit) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) ExpireFriendRequests(opts *bind.TransactOpts, createdBefore *big.Int, limit *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "expireFriendRequests", createdBefore, limit)
+}
+
+// ExpireFriendRequests is a paid mutator transaction binding the contract method 0xc1ba929e.
+//
+// Solidity: function expireFriendRequests(uint256 createdBefore, uint256 limit) returns()
+func (_QuickPicStorage *QuickPicStorageSession) ExpireFriendRequests(createdBefore *big.Int, limit *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.ExpireFriendRequests(&_QuickPicStorage.TransactOpts, createdBefore, limit)
+}
+
+// ExpireFriendRequests is a paid mutator transaction binding the contract method 0xc1ba929e.
+//
+// Solidity: function expireFriendRequests(uint256 createdBefore, uint256 limit) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) ExpireFriendRequests(createdBefore *big.Int, limit *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.ExpireFriendRequests(&_QuickPicStorage.TransactOpts, createdBefore, limit)
+}
+
+// RedeemInvite is a paid mutator transaction binding the contract method 0xab897e4f.
+//
+// Solidity: function redeemInvite(bytes32 id, bytes32 redeemerId, bytes32 friendshipId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) RedeemInvite(opts *bind.TransactOpts, id [32]byte, redeemerId [32]byte, friendshipId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "redeemInvite", id, redeemerId, friendshipId)
+}
+
+// RedeemInvite is a paid mutator transaction binding the contract method 0xab897e4f.
+//
+// Solidity: function redeemInvite(bytes32 id, bytes32 redeemerId, bytes32 friendshipId) returns()
+func (_QuickPicStorage *QuickPicStorageSession) RedeemInvite(id [32]byte, redeemerId [32]byte, friendshipId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RedeemInvite(&_QuickPicStorage.TransactOpts, id, redeemerId, friendshipId)
+}
+
+// RedeemInvite is a paid mutator transaction binding the contract method 0xab897e4f.
+//
+// Solidity: function redeemInvite(bytes32 id, bytes32 redeemerId, bytes32 friendshipId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) RedeemInvite(id [32]byte, redeemerId [32]byte, friendshipId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RedeemInvite(&_QuickPicStorage.TransactOpts, id, redeemerId, friendshipId)
+}
+
+// RemoveDevice is a paid mutator transaction binding the contract method 0x1d266200.
+//
+// Solidity: function removeDevice(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) RemoveDevice(opts *bind.TransactOpts, id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "removeDevice", id)
+}
+
+// RemoveDevice is a paid mutator transaction binding the contract method 0x1d266200.
+//
+// Solidity: function removeDevice(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageSession) RemoveDevice(id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RemoveDevice(&_QuickPicStorage.TransactOpts, id)
+}
+
+// RemoveDevice is a paid mutator transaction binding the contract method 0x1d266200.
+//
+// Solidity: function removeDevice(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) RemoveDevice(id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RemoveDevice(&_QuickPicStorage.TransactOpts, id)
+}
+
+// RemoveFriendship is a paid mutator transaction binding the contract method 0xfdfadb3b.
+//
+// Solidity: function removeFriendship(bytes32 userId1, bytes32 userId2) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) RemoveFriendship(opts *bind.TransactOpts, userId1 [32]byte, userId2 [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "removeFriendship", userId1, userId2)
+}
+
+// RemoveFriendship is a paid mutator transaction binding the contract method 0xfdfadb3b.
+//
+// Solidity: function removeFriendship(bytes32 userId1, bytes32 userId2) returns()
+func (_QuickPicStorage *QuickPicStorageSession) RemoveFriendship(userId1 [32]byte, userId2 [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RemoveFriendship(&_QuickPicStorage.TransactOpts, userId1, userId2)
+}
+
+// RemoveFriendship is a paid mutator transaction binding the contract method 0xfdfadb3b.
+//
+// Solidity: function removeFriendship(bytes32 userId1, bytes32 userId2) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) RemoveFriendship(userId1 [32]byte, userId2 [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RemoveFriendship(&_QuickPicStorage.TransactOpts, userId1, userId2)
+}
+
+// RevokeInvite is a paid mutator transaction binding the contract method 0xf2f0e5e1.
+//
+// Solidity: function revokeInvite(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) RevokeInvite(opts *bind.TransactOpts, id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "revokeInvite", id)
+}
+
+// RevokeInvite is a paid mutator transaction binding the contract method 0xf2f0e5e1.
+//
+// Solidity: function revokeInvite(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageSession) RevokeInvite(id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RevokeInvite(&_QuickPicStorage.TransactOpts, id)
+}
+
+// RevokeInvite is a paid mutator transaction binding the contract method 0xf2f0e5e1.
+//
+// Solidity: function revokeInvite(bytes32 id) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) RevokeInvite(id [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RevokeInvite(&_QuickPicStorage.TransactOpts, id)
+}
+
+// RotatePublicKey is a paid mutator transaction binding the contract method 0x0c0da766.
+//
+// Solidity: function rotatePublicKey(bytes32 id, uint256 expectedVersion, string publicKey) returns(uint256 version)
+func (_QuickPicStorage *QuickPicStorageTransactor) RotatePublicKey(opts *bind.TransactOpts, id [32]byte, expectedVersion *big.Int, publicKey string) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "rotatePublicKey", id, expectedVersion, publicKey)
+}
+
+// RotatePublicKey is a paid mutator transaction binding the contract method 0x0c0da766.
+//
+// Solidity: function rotatePublicKey(bytes32 id, uint256 expectedVersion, string publicKey) returns(uint256 version)
+func (_QuickPicStorage *QuickPicStorageSession) RotatePublicKey(id [32]byte, expectedVersion *big.Int, publicKey string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RotatePublicKey(&_QuickPicStorage.TransactOpts, id, expectedVersion, publicKey)
+}
+
+// RotatePublicKey is a paid mutator transaction binding the contract method 0x0c0da766.
+//
+// Solidity: function rotatePublicKey(bytes32 id, uint256 expectedVersion, string publicKey) returns(uint256 version)
+func (_QuickPicStorage *QuickPicStorageTransactorSession) RotatePublicKey(id [32]byte, expectedVersion *big.Int, publicKey string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.RotatePublicKey(&_QuickPicStorage.TransactOpts, id, expectedVersion, publicKey)
+}
+
+// SetAvatar is a paid mutator transaction binding the contract method 0xfbb5c64a.
+//
+// Solidity: function setAvatar(bytes32 userId, bytes32 avatarId, bytes data) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) SetAvatar(opts *bind.TransactOpts, userId [32]byte, avatarId [32]byte, data []byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "setAvatar", userId, avatarId, data)
+}
+
+// SetAvatar is a paid mutator transaction binding the contract method 0xfbb5c64a.
+//
+// Solidity: function setAvatar(bytes32 userId, bytes32 avatarId, bytes data) returns()
+func (_QuickPicStorage *QuickPicStorageSession) SetAvatar(userId [32]byte, avatarId [32]byte, data []byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetAvatar(&_QuickPicStorage.TransactOpts, userId, avatarId, data)
+}
+
+// SetAvatar is a paid mutator transaction binding the contract method 0xfbb5c64a.
+//
+// Solidity: function setAvatar(bytes32 userId, bytes32 avatarId, bytes data) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) SetAvatar(userId [32]byte, avatarId [32]byte, data []byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetAvatar(&_QuickPicStorage.TransactOpts, userId, avatarId, data)
+}
+
+// SetAvatarKeys is a paid mutator transaction binding the contract method 0x40731685.
+//
+// Solidity: function setAvatarKeys(bytes32 userId, bytes32 avatarId, bytes32[] viewerIds, bytes[] wrappedKeys) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) SetAvatarKeys(opts *bind.TransactOpts, userId [32]byte, avatarId [32]byte, viewerIds [][32]byte, wrappedKeys [][]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "setAvatarKeys", userId, avatarId, viewerIds, wrappedKeys)
+}
+
+// SetAvatarKeys is a paid mutator transaction binding the contract method 0x40731685.
+//
+// Solidity: function setAvatarKeys(bytes32 userId, bytes32 avatarId, bytes32[] viewerIds, bytes[] wrappedKeys) returns()
+func (_QuickPicStorage *QuickPicStorageSession) SetAvatarKeys(userId [32]byte, avatarId [32]byte, viewerIds [][32]byte, wrappedKeys [][]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetAvatarKeys(&_QuickPicStorage.TransactOpts, userId, avatarId, viewerIds, wrappedKeys)
+}
+
+// SetAvatarKeys is a paid mutator transaction binding the contract method 0x40731685.
+//
+// Solidity: function setAvatarKeys(bytes32 userId, bytes32 avatarId, bytes32[] viewerIds, bytes[] wrappedKeys) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) SetAvatarKeys(userId [32]byte, avatarId [32]byte, viewerIds [][32]byte, wrappedKeys [][]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetAvatarKeys(&_QuickPicStorage.TransactOpts, userId, avatarId, viewerIds, wrappedKeys)
+}
+
+// SetDisplayName is a paid mutator transaction binding the contract method 0x8f5f3b26.
+//
+// Solidity: function setDisplayName(bytes32 userId, string displayName) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) SetDisplayName(opts *bind.TransactOpts, userId [32]byte, displayName string) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "setDisplayName", userId, displayName)
+}
+
+// SetDisplayName is a paid mutator transaction binding the contract method 0x8f5f3b26.
+//
+// Solidity: function setDisplayName(bytes32 userId, string displayName) returns()
+func (_QuickPicStorage *QuickPicStorageSession) SetDisplayName(userId [32]byte, displayName string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetDisplayName(&_QuickPicStorage.TransactOpts, userId, displayName)
+}
+
+// SetDisplayName is a paid mutator transaction binding the contract method 0x8f5f3b26.
+//
+// Solidity: function setDisplayName(bytes32 userId, string displayName) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) SetDisplayName(userId [32]byte, displayName string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetDisplayName(&_QuickPicStorage.TransactOpts, userId, displayName)
+}
+
+// SetPrivacyFlags is a paid mutator transaction binding the contract method 0xbbec0ce2.
+//
+// Solidity: function setPrivacyFlags(bytes32 userId, uint256 flags) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) SetPrivacyFlags(opts *bind.TransactOpts, userId [32]byte, flags *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "setPrivacyFlags", userId, flags)
+}
+
+// SetPrivacyFlags is a paid mutator transaction binding the contract method 0xbbec0ce2.
+//
+// Solidity: function setPrivacyFlags(bytes32 userId, uint256 flags) returns()
+func (_QuickPicStorage *QuickPicStorageSession) SetPrivacyFlags(userId [32]byte, flags *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetPrivacyFlags(&_QuickPicStorage.TransactOpts, userId, flags)
+}
+
+// SetPrivacyFlags is a paid mutator transaction binding the contract method 0xbbec0ce2.
+//
+// Solidity: function setPrivacyFlags(bytes32 userId, uint256 flags) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) SetPrivacyFlags(userId [32]byte, flags *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetPrivacyFlags(&_QuickPicStorage.TransactOpts, userId, flags)
+}
+
+// SetSignedPreKey is a paid mutator transaction binding the contract method 0x712986e4.
+//
+// Solidity: function setSignedPreKey(bytes32 userId, uint256 keyId, string publicKey, string signature, uint256 identityKeyVersion) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) SetSignedPreKey(opts *bind.TransactOpts, userId [32]byte, keyId *big.Int, publicKey string, signature string, identityKeyVersion *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "setSignedPreKey", userId, keyId, publicKey, signature, identityKeyVersion)
+}
+
+// SetSignedPreKey is a paid mutator transaction binding the contract method 0x712986e4.
+//
+// Solidity: function setSignedPreKey(bytes32 userId, uint256 keyId, string publicKey, string signature, uint256 identityKeyVersion) returns()
+func (_QuickPicStorage *QuickPicStorageSession) SetSignedPreKey(userId [32]byte, keyId *big.Int, publicKey string, signature string, identityKeyVersion *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetSignedPreKey(&_QuickPicStorage.TransactOpts, userId, keyId, publicKey, signature, identityKeyVersion)
+}
+
+// SetSignedPreKey is a paid mutator transaction binding the contract method 0x712986e4.
+//
+// Solidity: function setSignedPreKey(bytes32 userId, uint256 keyId, string publicKey, string signature, uint256 identityKeyVersion) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) SetSignedPreKey(userId [32]byte, keyId *big.Int, publicKey string, signature string, identityKeyVersion *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetSignedPreKey(&_QuickPicStorage.TransactOpts, userId, keyId, publicKey, signature, identityKeyVersion)
+}
+
+// SetSuspended is a paid mutator transaction binding the contract method 0x29f72845.
+//
+// Solidity: function setSuspended(bytes32 userId, bool suspended) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) SetSuspended(opts *bind.TransactOpts, userId [32]byte, suspended bool) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "setSuspended", userId, suspended)
+}
+
+// SetSuspended is a paid mutator transaction binding the contract method 0x29f72845.
+//
+// Solidity: function setSuspended(bytes32 userId, bool suspended) returns()
+func (_QuickPicStorage *QuickPicStorageSession) SetSuspended(userId [32]byte, suspended bool) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetSuspended(&_QuickPicStorage.TransactOpts, userId, suspended)
+}
+
+// SetSuspended is a paid mutator transaction binding the contract method 0x29f72845.
+//
+// Solidity: function setSuspended(bytes32 userId, bool suspended) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) SetSuspended(userId [32]byte, suspended bool) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetSuspended(&_QuickPicStorage.TransactOpts, userId, suspended)
+}
+
+// SetVerifiedKeyVersion is a paid mutator transaction binding the contract method 0x85738067.
+//
+// Solidity: function setVerifiedKeyVersion(bytes32 userId, bytes32 friendId, uint256 keyVersion) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) SetVerifiedKeyVersion(opts *bind.TransactOpts, userId [32]byte, friendId [32]byte, keyVersion *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "setVerifiedKeyVersion", userId, friendId, keyVersion)
+}
+
+// SetVerifiedKeyVersion is a paid mutator transaction binding the contract method 0x85738067.
+//
+// Solidity: function setVerifiedKeyVersion(bytes32 userId, bytes32 friendId, uint256 keyVersion) returns()
+func (_QuickPicStorage *QuickPicStorageSession) SetVerifiedKeyVersion(userId [32]byte, friendId [32]byte, keyVersion *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetVerifiedKeyVersion(&_QuickPicStorage.TransactOpts, userId, friendId, keyVersion)
+}
+
+// SetVerifiedKeyVersion is a paid mutator transaction binding the contract method 0x85738067.
+//
+// Solidity: function setVerifiedKeyVersion(bytes32 userId, bytes32 friendId, uint256 keyVersion) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) SetVerifiedKeyVersion(userId [32]byte, friendId [32]byte, keyVersion *big.Int) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.SetVerifiedKeyVersion(&_QuickPicStorage.TransactOpts, userId, friendId, keyVersion)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "transferOwnership", newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_QuickPicStorage *QuickPicStorageSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.TransferOwnership(&_QuickPicStorage.TransactOpts, newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.TransferOwnership(&_QuickPicStorage.TransactOpts, newOwner)
+}
+
+// UnblockUser is a paid mutator transaction binding the contract method 0xcc5073cd.
+//
+// Solidity: function unblockUser(bytes32 blockerId, bytes32 blockedId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) UnblockUser(opts *bind.TransactOpts, blockerId [32]byte, blockedId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "unblockUser", blockerId, blockedId)
+}
+
+// UnblockUser is a paid mutator transaction binding the contract method 0xcc5073cd.
+//
+// Solidity: function unblockUser(bytes32 blockerId, bytes32 blockedId) returns()
+func (_QuickPicStorage *QuickPicStorageSession) UnblockUser(blockerId [32]byte, blockedId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.UnblockUser(&_QuickPicStorage.TransactOpts, blockerId, blockedId)
+}
+
+// UnblockUser is a paid mutator transaction binding the contract method 0xcc5073cd.
+//
+// Solidity: function unblockUser(bytes32 blockerId, bytes32 blockedId) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) UnblockUser(blockerId [32]byte, blockedId [32]byte) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.UnblockUser(&_QuickPicStorage.TransactOpts, blockerId, blockedId)
+}
+
+// UpdateFriendRequestStatus is a paid mutator transaction binding the contract method 0x4018c162.
+//
+// Solidity: function updateFriendRequestStatus(bytes32 id, uint8 status) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) UpdateFriendRequestStatus(opts *bind.TransactOpts, id [32]byte, status uint8) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "updateFriendRequestStatus", id, status)
+}
+
+// UpdateFriendRequestStatus is a paid mutator transaction binding the contract method 0x4018c162.
+//
+// Solidity: function updateFriendRequestStatus(bytes32 id, uint8 status) returns()
+func (_QuickPicStorage *QuickPicStorageSession) UpdateFriendRequestStatus(id [32]byte, status uint8) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.UpdateFriendRequestStatus(&_QuickPicStorage.TransactOpts, id, status)
+}
+
+// UpdateFriendRequestStatus is a paid mutator transaction binding the contract method 0x4018c162.
+//
+// Solidity: function updateFriendRequestStatus(bytes32 id, uint8 status) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) UpdateFriendRequestStatus(id [32]byte, status uint8) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.UpdateFriendRequestStatus(&_QuickPicStorage.TransactOpts, id, status)
+}
+
+// UpdateUser is a paid mutator transaction binding the contract method 0x494fb8e7.
+//
+// Solidity: function updateUser(bytes32 id, string passwordHash, string publicKey) returns()
+func (_QuickPicStorage *QuickPicStorageTransactor) UpdateUser(opts *bind.TransactOpts, id [32]byte, passwordHash string, publicKey string) (*types.Transaction, error) {
+	return _QuickPicStorage.contract.Transact(opts, "updateUser", id, passwordHash, publicKey)
 }
 
 // UpdateUser is a paid mutator transaction binding the contract method 0x494fb8e7.
 //
-// Solidity: function updateUser(bytes32 id, string passwordHash, string publicKey) returns()
-func (_QuickPicStorage *QuickPicStorageSession) UpdateUser(id [32]byte, passwordHash string, publicKey string) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.UpdateUser(&_QuickPicStorage.TransactOpts, id, passwordHash, publicKey)
+// Solidity: function updateUser(bytes32 id, string passwordHash, string publicKey) returns()
+func (_QuickPicStorage *QuickPicStorageSession) UpdateUser(id [32]byte, passwordHash string, publicKey string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.UpdateUser(&_QuickPicStorage.TransactOpts, id, passwordHash, publicKey)
+}
+
+// UpdateUser is a paid mutator transaction binding the contract method 0x494fb8e7.
+//
+// Solidity: function updateUser(bytes32 id, string passwordHash, string publicKey) returns()
+func (_QuickPicStorage *QuickPicStorageTransactorSession) UpdateUser(id [32]byte, passwordHash string, publicKey string) (*types.Transaction, error) {
+	return _QuickPicStorage.Contract.UpdateUser(&_QuickPicStorage.TransactOpts, id, passwordHash, publicKey)
+}
+
+// QuickPicStorageDeliveryAcknowledgedIterator is returned from FilterDeliveryAcknowledged and is used to iterate over the raw logs and unpacked data for DeliveryAcknowledged events raised by the QuickPicStorage contract.
+type QuickPicStorageDeliveryAcknowledgedIterator struct {
+	Event *QuickPicStorageDeliveryAcknowledged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageDeliveryAcknowledgedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageDeliveryAcknowledged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageDeliveryAcknowledged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageDeliveryAcknowledgedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageDeliveryAcknowledgedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageDeliveryAcknowledged represents a DeliveryAcknowledged event raised by the QuickPicStorage contract.
+type QuickPicStorageDeliveryAcknowledged struct {
+	MessageId [32]byte
+	DeviceId  [32]byte
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterDeliveryAcknowledged is a free log retrieval operation binding the contract event 0x7e23a352fbfb09b968f825386e3e8d523ef805b0bcf4fedc10cceb9b68c89743.
+//
+// Solidity: event DeliveryAcknowledged(bytes32 indexed messageId, bytes32 indexed deviceId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterDeliveryAcknowledged(opts *bind.FilterOpts, messageId [][32]byte, deviceId [][32]byte) (*QuickPicStorageDeliveryAcknowledgedIterator, error) {
+
+	var messageIdRule []interface{}
+	for _, messageIdItem := range messageId {
+		messageIdRule = append(messageIdRule, messageIdItem)
+	}
+	var deviceIdRule []interface{}
+	for _, deviceIdItem := range deviceId {
+		deviceIdRule = append(deviceIdRule, deviceIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "DeliveryAcknowledged", messageIdRule, deviceIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageDeliveryAcknowledgedIterator{contract: _QuickPicStorage.contract, event: "DeliveryAcknowledged", logs: logs, sub: sub}, nil
+}
+
+// WatchDeliveryAcknowledged is a free log subscription operation binding the contract event 0x7e23a352fbfb09b968f825386e3e8d523ef805b0bcf4fedc10cceb9b68c89743.
+//
+// Solidity: event DeliveryAcknowledged(bytes32 indexed messageId, bytes32 indexed deviceId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchDeliveryAcknowledged(opts *bind.WatchOpts, sink chan<- *QuickPicStorageDeliveryAcknowledged, messageId [][32]byte, deviceId [][32]byte) (event.Subscription, error) {
+
+	var messageIdRule []interface{}
+	for _, messageIdItem := range messageId {
+		messageIdRule = append(messageIdRule, messageIdItem)
+	}
+	var deviceIdRule []interface{}
+	for _, deviceIdItem := range deviceId {
+		deviceIdRule = append(deviceIdRule, deviceIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "DeliveryAcknowledged", messageIdRule, deviceIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageDeliveryAcknowledged)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "DeliveryAcknowledged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseDeliveryAcknowledged is a log parse operation binding the contract event 0x7e23a352fbfb09b968f825386e3e8d523ef805b0bcf4fedc10cceb9b68c89743.
+//
+// Solidity: event DeliveryAcknowledged(bytes32 indexed messageId, bytes32 indexed deviceId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseDeliveryAcknowledged(log types.Log) (*QuickPicStorageDeliveryAcknowledged, error) {
+	event := new(QuickPicStorageDeliveryAcknowledged)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "DeliveryAcknowledged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageDeviceAddedIterator is returned from FilterDeviceAdded and is used to iterate over the raw logs and unpacked data for DeviceAdded events raised by the QuickPicStorage contract.
+type QuickPicStorageDeviceAddedIterator struct {
+	Event *QuickPicStorageDeviceAdded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageDeviceAddedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageDeviceAdded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageDeviceAdded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageDeviceAddedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageDeviceAddedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageDeviceAdded represents a DeviceAdded event raised by the QuickPicStorage contract.
+type QuickPicStorageDeviceAdded struct {
+	Id     [32]byte
+	UserId [32]byte
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterDeviceAdded is a free log retrieval operation binding the contract event 0x39f4898a4166bdcdbfe322442fafc310e514734c7c4e0426bb4608ba5ce10ca6.
+//
+// Solidity: event DeviceAdded(bytes32 indexed id, bytes32 indexed userId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterDeviceAdded(opts *bind.FilterOpts, id [][32]byte, userId [][32]byte) (*QuickPicStorageDeviceAddedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "DeviceAdded", idRule, userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageDeviceAddedIterator{contract: _QuickPicStorage.contract, event: "DeviceAdded", logs: logs, sub: sub}, nil
+}
+
+// WatchDeviceAdded is a free log subscription operation binding the contract event 0x39f4898a4166bdcdbfe322442fafc310e514734c7c4e0426bb4608ba5ce10ca6.
+//
+// Solidity: event DeviceAdded(bytes32 indexed id, bytes32 indexed userId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchDeviceAdded(opts *bind.WatchOpts, sink chan<- *QuickPicStorageDeviceAdded, id [][32]byte, userId [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "DeviceAdded", idRule, userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageDeviceAdded)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "DeviceAdded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseDeviceAdded is a log parse operation binding the contract event 0x39f4898a4166bdcdbfe322442fafc310e514734c7c4e0426bb4608ba5ce10ca6.
+//
+// Solidity: event DeviceAdded(bytes32 indexed id, bytes32 indexed userId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseDeviceAdded(log types.Log) (*QuickPicStorageDeviceAdded, error) {
+	event := new(QuickPicStorageDeviceAdded)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "DeviceAdded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageDeviceRemovedIterator is returned from FilterDeviceRemoved and is used to iterate over the raw logs and unpacked data for DeviceRemoved events raised by the QuickPicStorage contract.
+type QuickPicStorageDeviceRemovedIterator struct {
+	Event *QuickPicStorageDeviceRemoved // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageDeviceRemovedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageDeviceRemoved)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageDeviceRemoved)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageDeviceRemovedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageDeviceRemovedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageDeviceRemoved represents a DeviceRemoved event raised by the QuickPicStorage contract.
+type QuickPicStorageDeviceRemoved struct {
+	Id     [32]byte
+	UserId [32]byte
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterDeviceRemoved is a free log retrieval operation binding the contract event 0x91ba44af1310c640162542eb1818f20b2dc0ef7cd0e7add4661efe9334a768cb.
+//
+// Solidity: event DeviceRemoved(bytes32 indexed id, bytes32 indexed userId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterDeviceRemoved(opts *bind.FilterOpts, id [][32]byte, userId [][32]byte) (*QuickPicStorageDeviceRemovedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "DeviceRemoved", idRule, userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageDeviceRemovedIterator{contract: _QuickPicStorage.contract, event: "DeviceRemoved", logs: logs, sub: sub}, nil
+}
+
+// WatchDeviceRemoved is a free log subscription operation binding the contract event 0x91ba44af1310c640162542eb1818f20b2dc0ef7cd0e7add4661efe9334a768cb.
+//
+// Solidity: event DeviceRemoved(bytes32 indexed id, bytes32 indexed userId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchDeviceRemoved(opts *bind.WatchOpts, sink chan<- *QuickPicStorageDeviceRemoved, id [][32]byte, userId [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "DeviceRemoved", idRule, userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageDeviceRemoved)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "DeviceRemoved", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseDeviceRemoved is a log parse operation binding the contract event 0x91ba44af1310c640162542eb1818f20b2dc0ef7cd0e7add4661efe9334a768cb.
+//
+// Solidity: event DeviceRemoved(bytes32 indexed id, bytes32 indexed userId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseDeviceRemoved(log types.Log) (*QuickPicStorageDeviceRemoved, error) {
+	event := new(QuickPicStorageDeviceRemoved)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "DeviceRemoved", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageFriendRequestCreatedIterator is returned from FilterFriendRequestCreated and is used to iterate over the raw logs and unpacked data for FriendRequestCreated events raised by the QuickPicStorage contract.
+type QuickPicStorageFriendRequestCreatedIterator struct {
+	Event *QuickPicStorageFriendRequestCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageFriendRequestCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageFriendRequestCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageFriendRequestCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageFriendRequestCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageFriendRequestCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageFriendRequestCreated represents a FriendRequestCreated event raised by the QuickPicStorage contract.
+type QuickPicStorageFriendRequestCreated struct {
+	Id         [32]byte
+	FromUserId [32]byte
+	ToUserId   [32]byte
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterFriendRequestCreated is a free log retrieval operation binding the contract event 0x9ac14503d7f332c5dbec9975c7e73dc5a8473298dbc7abc78c5997f410d2777c.
+//
+// Solidity: event FriendRequestCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterFriendRequestCreated(opts *bind.FilterOpts, id [][32]byte, fromUserId [][32]byte, toUserId [][32]byte) (*QuickPicStorageFriendRequestCreatedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var fromUserIdRule []interface{}
+	for _, fromUserIdItem := range fromUserId {
+		fromUserIdRule = append(fromUserIdRule, fromUserIdItem)
+	}
+	var toUserIdRule []interface{}
+	for _, toUserIdItem := range toUserId {
+		toUserIdRule = append(toUserIdRule, toUserIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "FriendRequestCreated", idRule, fromUserIdRule, toUserIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageFriendRequestCreatedIterator{contract: _QuickPicStorage.contract, event: "FriendRequestCreated", logs: logs, sub: sub}, nil
+}
+
+// WatchFriendRequestCreated is a free log subscription operation binding the contract event 0x9ac14503d7f332c5dbec9975c7e73dc5a8473298dbc7abc78c5997f410d2777c.
+//
+// Solidity: event FriendRequestCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendRequestCreated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageFriendRequestCreated, id [][32]byte, fromUserId [][32]byte, toUserId [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var fromUserIdRule []interface{}
+	for _, fromUserIdItem := range fromUserId {
+		fromUserIdRule = append(fromUserIdRule, fromUserIdItem)
+	}
+	var toUserIdRule []interface{}
+	for _, toUserIdItem := range toUserId {
+		toUserIdRule = append(toUserIdRule, toUserIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "FriendRequestCreated", idRule, fromUserIdRule, toUserIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageFriendRequestCreated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "FriendRequestCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseFriendRequestCreated is a log parse operation binding the contract event 0x9ac14503d7f332c5dbec9975c7e73dc5a8473298dbc7abc78c5997f410d2777c.
+//
+// Solidity: event FriendRequestCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseFriendRequestCreated(log types.Log) (*QuickPicStorageFriendRequestCreated, error) {
+	event := new(QuickPicStorageFriendRequestCreated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "FriendRequestCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageFriendRequestUpdatedIterator is returned from FilterFriendRequestUpdated and is used to iterate over the raw logs and unpacked data for FriendRequestUpdated events raised by the QuickPicStorage contract.
+type QuickPicStorageFriendRequestUpdatedIterator struct {
+	Event *QuickPicStorageFriendRequestUpdated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageFriendRequestUpdatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageFriendRequestUpdated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageFriendRequestUpdated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageFriendRequestUpdatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageFriendRequestUpdatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageFriendRequestUpdated represents a FriendRequestUpdated event raised by the QuickPicStorage contract.
+type QuickPicStorageFriendRequestUpdated struct {
+	Id     [32]byte
+	Status uint8
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterFriendRequestUpdated is a free log retrieval operation binding the contract event 0x8a2bf39e9087c33765bcca9a2c61caaa3e82956b669d0f3914a1d95e50f19ff2.
+//
+// Solidity: event FriendRequestUpdated(bytes32 indexed id, uint8 status)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterFriendRequestUpdated(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageFriendRequestUpdatedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "FriendRequestUpdated", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageFriendRequestUpdatedIterator{contract: _QuickPicStorage.contract, event: "FriendRequestUpdated", logs: logs, sub: sub}, nil
+}
+
+// WatchFriendRequestUpdated is a free log subscription operation binding the contract event 0x8a2bf39e9087c33765bcca9a2c61caaa3e82956b669d0f3914a1d95e50f19ff2.
+//
+// Solidity: event FriendRequestUpdated(bytes32 indexed id, uint8 status)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendRequestUpdated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageFriendRequestUpdated, id [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "FriendRequestUpdated", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageFriendRequestUpdated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "FriendRequestUpdated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseFriendRequestUpdated is a log parse operation binding the contract event 0x8a2bf39e9087c33765bcca9a2c61caaa3e82956b669d0f3914a1d95e50f19ff2.
+//
+// Solidity: event FriendRequestUpdated(bytes32 indexed id, uint8 status)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseFriendRequestUpdated(log types.Log) (*QuickPicStorageFriendRequestUpdated, error) {
+	event := new(QuickPicStorageFriendRequestUpdated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "FriendRequestUpdated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageFriendVerifiedIterator is returned from FilterFriendVerified and is used to iterate over the raw logs and unpacked data for FriendVerified events raised by the QuickPicStorage contract.
+type QuickPicStorageFriendVerifiedIterator struct {
+	Event *QuickPicStorageFriendVerified // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageFriendVerifiedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageFriendVerified)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageFriendVerified)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageFriendVerifiedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageFriendVerifiedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageFriendVerified represents a FriendVerified event raised by the QuickPicStorage contract.
+type QuickPicStorageFriendVerified struct {
+	UserId     [32]byte
+	FriendId   [32]byte
+	KeyVersion *big.Int
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterFriendVerified is a free log retrieval operation binding the contract event 0xe78b7c064d561281a5236cb01304b1253bec57b86df71c6594fb1a3523d65f0d.
+//
+// Solidity: event FriendVerified(bytes32 indexed userId, bytes32 indexed friendId, uint256 keyVersion)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterFriendVerified(opts *bind.FilterOpts, userId [][32]byte, friendId [][32]byte) (*QuickPicStorageFriendVerifiedIterator, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+	var friendIdRule []interface{}
+	for _, friendIdItem := range friendId {
+		friendIdRule = append(friendIdRule, friendIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "FriendVerified", userIdRule, friendIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageFriendVerifiedIterator{contract: _QuickPicStorage.contract, event: "FriendVerified", logs: logs, sub: sub}, nil
+}
+
+// WatchFriendVerified is a free log subscription operation binding the contract event 0xe78b7c064d561281a5236cb01304b1253bec57b86df71c6594fb1a3523d65f0d.
+//
+// Solidity: event FriendVerified(bytes32 indexed userId, bytes32 indexed friendId, uint256 keyVersion)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendVerified(opts *bind.WatchOpts, sink chan<- *QuickPicStorageFriendVerified, userId [][32]byte, friendId [][32]byte) (event.Subscription, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+	var friendIdRule []interface{}
+	for _, friendIdItem := range friendId {
+		friendIdRule = append(friendIdRule, friendIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "FriendVerified", userIdRule, friendIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageFriendVerified)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "FriendVerified", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseFriendVerified is a log parse operation binding the contract event 0xe78b7c064d561281a5236cb01304b1253bec57b86df71c6594fb1a3523d65f0d.
+//
+// Solidity: event FriendVerified(bytes32 indexed userId, bytes32 indexed friendId, uint256 keyVersion)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseFriendVerified(log types.Log) (*QuickPicStorageFriendVerified, error) {
+	event := new(QuickPicStorageFriendVerified)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "FriendVerified", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageFriendshipCreatedIterator is returned from FilterFriendshipCreated and is used to iterate over the raw logs and unpacked data for FriendshipCreated events raised by the QuickPicStorage contract.
+type QuickPicStorageFriendshipCreatedIterator struct {
+	Event *QuickPicStorageFriendshipCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageFriendshipCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageFriendshipCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageFriendshipCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageFriendshipCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageFriendshipCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageFriendshipCreated represents a FriendshipCreated event raised by the QuickPicStorage contract.
+type QuickPicStorageFriendshipCreated struct {
+	Id      [32]byte
+	UserAId [32]byte
+	UserBId [32]byte
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterFriendshipCreated is a free log retrieval operation binding the contract event 0x63bb48a2197f36fe8120ecc4f504ccefdd2d712a3bf2d5645c0e6dac33e0301b.
+//
+// Solidity: event FriendshipCreated(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterFriendshipCreated(opts *bind.FilterOpts, id [][32]byte, userAId [][32]byte, userBId [][32]byte) (*QuickPicStorageFriendshipCreatedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userAIdRule []interface{}
+	for _, userAIdItem := range userAId {
+		userAIdRule = append(userAIdRule, userAIdItem)
+	}
+	var userBIdRule []interface{}
+	for _, userBIdItem := range userBId {
+		userBIdRule = append(userBIdRule, userBIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "FriendshipCreated", idRule, userAIdRule, userBIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageFriendshipCreatedIterator{contract: _QuickPicStorage.contract, event: "FriendshipCreated", logs: logs, sub: sub}, nil
+}
+
+// WatchFriendshipCreated is a free log subscription operation binding the contract event 0x63bb48a2197f36fe8120ecc4f504ccefdd2d712a3bf2d5645c0e6dac33e0301b.
+//
+// Solidity: event FriendshipCreated(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendshipCreated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageFriendshipCreated, id [][32]byte, userAId [][32]byte, userBId [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userAIdRule []interface{}
+	for _, userAIdItem := range userAId {
+		userAIdRule = append(userAIdRule, userAIdItem)
+	}
+	var userBIdRule []interface{}
+	for _, userBIdItem := range userBId {
+		userBIdRule = append(userBIdRule, userBIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "FriendshipCreated", idRule, userAIdRule, userBIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageFriendshipCreated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "FriendshipCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseFriendshipCreated is a log parse operation binding the contract event 0x63bb48a2197f36fe8120ecc4f504ccefdd2d712a3bf2d5645c0e6dac33e0301b.
+//
+// Solidity: event FriendshipCreated(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseFriendshipCreated(log types.Log) (*QuickPicStorageFriendshipCreated, error) {
+	event := new(QuickPicStorageFriendshipCreated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "FriendshipCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageFriendshipRemovedIterator is returned from FilterFriendshipRemoved and is used to iterate over the raw logs and unpacked data for FriendshipRemoved events raised by the QuickPicStorage contract.
+type QuickPicStorageFriendshipRemovedIterator struct {
+	Event *QuickPicStorageFriendshipRemoved // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageFriendshipRemovedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageFriendshipRemoved)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageFriendshipRemoved)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageFriendshipRemovedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageFriendshipRemovedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageFriendshipRemoved represents a FriendshipRemoved event raised by the QuickPicStorage contract.
+type QuickPicStorageFriendshipRemoved struct {
+	Id      [32]byte
+	UserAId [32]byte
+	UserBId [32]byte
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterFriendshipRemoved is a free log retrieval operation binding the contract event 0x481e949fd5b88c689045a55e3b0f9fc865757f3e9bd307737db1913177098e24.
+//
+// Solidity: event FriendshipRemoved(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterFriendshipRemoved(opts *bind.FilterOpts, id [][32]byte, userAId [][32]byte, userBId [][32]byte) (*QuickPicStorageFriendshipRemovedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userAIdRule []interface{}
+	for _, userAIdItem := range userAId {
+		userAIdRule = append(userAIdRule, userAIdItem)
+	}
+	var userBIdRule []interface{}
+	for _, userBIdItem := range userBId {
+		userBIdRule = append(userBIdRule, userBIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "FriendshipRemoved", idRule, userAIdRule, userBIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageFriendshipRemovedIterator{contract: _QuickPicStorage.contract, event: "FriendshipRemoved", logs: logs, sub: sub}, nil
+}
+
+// WatchFriendshipRemoved is a free log subscription operation binding the contract event 0x481e949fd5b88c689045a55e3b0f9fc865757f3e9bd307737db1913177098e24.
+//
+// Solidity: event FriendshipRemoved(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendshipRemoved(opts *bind.WatchOpts, sink chan<- *QuickPicStorageFriendshipRemoved, id [][32]byte, userAId [][32]byte, userBId [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userAIdRule []interface{}
+	for _, userAIdItem := range userAId {
+		userAIdRule = append(userAIdRule, userAIdItem)
+	}
+	var userBIdRule []interface{}
+	for _, userBIdItem := range userBId {
+		userBIdRule = append(userBIdRule, userBIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "FriendshipRemoved", idRule, userAIdRule, userBIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageFriendshipRemoved)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "FriendshipRemoved", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseFriendshipRemoved is a log parse operation binding the contract event 0x481e949fd5b88c689045a55e3b0f9fc865757f3e9bd307737db1913177098e24.
+//
+// Solidity: event FriendshipRemoved(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseFriendshipRemoved(log types.Log) (*QuickPicStorageFriendshipRemoved, error) {
+	event := new(QuickPicStorageFriendshipRemoved)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "FriendshipRemoved", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageInviteCreatedIterator is returned from FilterInviteCreated and is used to iterate over the raw logs and unpacked data for InviteCreated events raised by the QuickPicStorage contract.
+type QuickPicStorageInviteCreatedIterator struct {
+	Event *QuickPicStorageInviteCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageInviteCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageInviteCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageInviteCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageInviteCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageInviteCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageInviteCreated represents a InviteCreated event raised by the QuickPicStorage contract.
+type QuickPicStorageInviteCreated struct {
+	Id        [32]byte
+	CreatorId [32]byte
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterInviteCreated is a free log retrieval operation binding the contract event 0xe78e6e0f37c4e63aeb5e4876eee8639d6a4ebf88f7e2d014597582a18a748b4b.
+//
+// Solidity: event InviteCreated(bytes32 indexed id, bytes32 indexed creatorId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterInviteCreated(opts *bind.FilterOpts, id [][32]byte, creatorId [][32]byte) (*QuickPicStorageInviteCreatedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var creatorIdRule []interface{}
+	for _, creatorIdItem := range creatorId {
+		creatorIdRule = append(creatorIdRule, creatorIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "InviteCreated", idRule, creatorIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageInviteCreatedIterator{contract: _QuickPicStorage.contract, event: "InviteCreated", logs: logs, sub: sub}, nil
+}
+
+// WatchInviteCreated is a free log subscription operation binding the contract event 0xe78e6e0f37c4e63aeb5e4876eee8639d6a4ebf88f7e2d014597582a18a748b4b.
+//
+// Solidity: event InviteCreated(bytes32 indexed id, bytes32 indexed creatorId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchInviteCreated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageInviteCreated, id [][32]byte, creatorId [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var creatorIdRule []interface{}
+	for _, creatorIdItem := range creatorId {
+		creatorIdRule = append(creatorIdRule, creatorIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "InviteCreated", idRule, creatorIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageInviteCreated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "InviteCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseInviteCreated is a log parse operation binding the contract event 0xe78e6e0f37c4e63aeb5e4876eee8639d6a4ebf88f7e2d014597582a18a748b4b.
+//
+// Solidity: event InviteCreated(bytes32 indexed id, bytes32 indexed creatorId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseInviteCreated(log types.Log) (*QuickPicStorageInviteCreated, error) {
+	event := new(QuickPicStorageInviteCreated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "InviteCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageInviteRedeemedIterator is returned from FilterInviteRedeemed and is used to iterate over the raw logs and unpacked data for InviteRedeemed events raised by the QuickPicStorage contract.
+type QuickPicStorageInviteRedeemedIterator struct {
+	Event *QuickPicStorageInviteRedeemed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageInviteRedeemedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageInviteRedeemed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageInviteRedeemed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageInviteRedeemedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageInviteRedeemedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageInviteRedeemed represents a InviteRedeemed event raised by the QuickPicStorage contract.
+type QuickPicStorageInviteRedeemed struct {
+	Id         [32]byte
+	RedeemerId [32]byte
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterInviteRedeemed is a free log retrieval operation binding the contract event 0x2a5abe6d76fab0d7715ec1dcdf1003a7387f8fdeab079a766ae4a79eb8999ebb.
+//
+// Solidity: event InviteRedeemed(bytes32 indexed id, bytes32 indexed redeemerId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterInviteRedeemed(opts *bind.FilterOpts, id [][32]byte, redeemerId [][32]byte) (*QuickPicStorageInviteRedeemedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var redeemerIdRule []interface{}
+	for _, redeemerIdItem := range redeemerId {
+		redeemerIdRule = append(redeemerIdRule, redeemerIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "InviteRedeemed", idRule, redeemerIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageInviteRedeemedIterator{contract: _QuickPicStorage.contract, event: "InviteRedeemed", logs: logs, sub: sub}, nil
+}
+
+// WatchInviteRedeemed is a free log subscription operation binding the contract event 0x2a5abe6d76fab0d7715ec1dcdf1003a7387f8fdeab079a766ae4a79eb8999ebb.
+//
+// Solidity: event InviteRedeemed(bytes32 indexed id, bytes32 indexed redeemerId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchInviteRedeemed(opts *bind.WatchOpts, sink chan<- *QuickPicStorageInviteRedeemed, id [][32]byte, redeemerId [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var redeemerIdRule []interface{}
+	for _, redeemerIdItem := range redeemerId {
+		redeemerIdRule = append(redeemerIdRule, redeemerIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "InviteRedeemed", idRule, redeemerIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageInviteRedeemed)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "InviteRedeemed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseInviteRedeemed is a log parse operation binding the contract event 0x2a5abe6d76fab0d7715ec1dcdf1003a7387f8fdeab079a766ae4a79eb8999ebb.
+//
+// Solidity: event InviteRedeemed(bytes32 indexed id, bytes32 indexed redeemerId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseInviteRedeemed(log types.Log) (*QuickPicStorageInviteRedeemed, error) {
+	event := new(QuickPicStorageInviteRedeemed)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "InviteRedeemed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageInviteRevokedIterator is returned from FilterInviteRevoked and is used to iterate over the raw logs and unpacked data for InviteRevoked events raised by the QuickPicStorage contract.
+type QuickPicStorageInviteRevokedIterator struct {
+	Event *QuickPicStorageInviteRevoked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageInviteRevokedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageInviteRevoked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageInviteRevoked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageInviteRevokedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageInviteRevokedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageInviteRevoked represents a InviteRevoked event raised by the QuickPicStorage contract.
+type QuickPicStorageInviteRevoked struct {
+	Id  [32]byte
+	Raw types.Log // Blockchain specific contextual infos
+}
+
+// FilterInviteRevoked is a free log retrieval operation binding the contract event 0x0374a7f71ac7015ff8ac3b5436f6fb93ce7cc8942724cf8c1d9fe7a97eacf423.
+//
+// Solidity: event InviteRevoked(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterInviteRevoked(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageInviteRevokedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "InviteRevoked", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageInviteRevokedIterator{contract: _QuickPicStorage.contract, event: "InviteRevoked", logs: logs, sub: sub}, nil
+}
+
+// WatchInviteRevoked is a free log subscription operation binding the contract event 0x0374a7f71ac7015ff8ac3b5436f6fb93ce7cc8942724cf8c1d9fe7a97eacf423.
+//
+// Solidity: event InviteRevoked(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchInviteRevoked(opts *bind.WatchOpts, sink chan<- *QuickPicStorageInviteRevoked, id [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "InviteRevoked", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageInviteRevoked)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "InviteRevoked", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseInviteRevoked is a log parse operation binding the contract event 0x0374a7f71ac7015ff8ac3b5436f6fb93ce7cc8942724cf8c1d9fe7a97eacf423.
+//
+// Solidity: event InviteRevoked(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseInviteRevoked(log types.Log) (*QuickPicStorageInviteRevoked, error) {
+	event := new(QuickPicStorageInviteRevoked)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "InviteRevoked", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageMessageCreatedIterator is returned from FilterMessageCreated and is used to iterate over the raw logs and unpacked data for MessageCreated events raised by the QuickPicStorage contract.
+type QuickPicStorageMessageCreatedIterator struct {
+	Event *QuickPicStorageMessageCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageMessageCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageMessageCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageMessageCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageMessageCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageMessageCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageMessageCreated represents a MessageCreated event raised by the QuickPicStorage contract.
+type QuickPicStorageMessageCreated struct {
+	Id         [32]byte
+	FromUserId [32]byte
+	ToUserId   [32]byte
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterMessageCreated is a free log retrieval operation binding the contract event 0x4f2ba766b8c822d461311c87d079178a55231792db9f142af3ecd797a5cc5d28.
+//
+// Solidity: event MessageCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterMessageCreated(opts *bind.FilterOpts, id [][32]byte, fromUserId [][32]byte, toUserId [][32]byte) (*QuickPicStorageMessageCreatedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var fromUserIdRule []interface{}
+	for _, fromUserIdItem := range fromUserId {
+		fromUserIdRule = append(fromUserIdRule, fromUserIdItem)
+	}
+	var toUserIdRule []interface{}
+	for _, toUserIdItem := range toUserId {
+		toUserIdRule = append(toUserIdRule, toUserIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "MessageCreated", idRule, fromUserIdRule, toUserIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageMessageCreatedIterator{contract: _QuickPicStorage.contract, event: "MessageCreated", logs: logs, sub: sub}, nil
+}
+
+// WatchMessageCreated is a free log subscription operation binding the contract event 0x4f2ba766b8c822d461311c87d079178a55231792db9f142af3ecd797a5cc5d28.
+//
+// Solidity: event MessageCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchMessageCreated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageMessageCreated, id [][32]byte, fromUserId [][32]byte, toUserId [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var fromUserIdRule []interface{}
+	for _, fromUserIdItem := range fromUserId {
+		fromUserIdRule = append(fromUserIdRule, fromUserIdItem)
+	}
+	var toUserIdRule []interface{}
+	for _, toUserIdItem := range toUserId {
+		toUserIdRule = append(toUserIdRule, toUserIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "MessageCreated", idRule, fromUserIdRule, toUserIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageMessageCreated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "MessageCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseMessageCreated is a log parse operation binding the contract event 0x4f2ba766b8c822d461311c87d079178a55231792db9f142af3ecd797a5cc5d28.
+//
+// Solidity: event MessageCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseMessageCreated(log types.Log) (*QuickPicStorageMessageCreated, error) {
+	event := new(QuickPicStorageMessageCreated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "MessageCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageMessageDeletedIterator is returned from FilterMessageDeleted and is used to iterate over the raw logs and unpacked data for MessageDeleted events raised by the QuickPicStorage contract.
+type QuickPicStorageMessageDeletedIterator struct {
+	Event *QuickPicStorageMessageDeleted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageMessageDeletedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageMessageDeleted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageMessageDeleted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageMessageDeletedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageMessageDeletedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageMessageDeleted represents a MessageDeleted event raised by the QuickPicStorage contract.
+type QuickPicStorageMessageDeleted struct {
+	Id  [32]byte
+	Raw types.Log // Blockchain specific contextual infos
+}
+
+// FilterMessageDeleted is a free log retrieval operation binding the contract event 0xf4ef3cbd1d2cff45998b1fb60a13a6505834c570a5a9a44f55c3a3c2b5c852fd.
+//
+// Solidity: event MessageDeleted(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterMessageDeleted(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageMessageDeletedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "MessageDeleted", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageMessageDeletedIterator{contract: _QuickPicStorage.contract, event: "MessageDeleted", logs: logs, sub: sub}, nil
+}
+
+// WatchMessageDeleted is a free log subscription operation binding the contract event 0xf4ef3cbd1d2cff45998b1fb60a13a6505834c570a5a9a44f55c3a3c2b5c852fd.
+//
+// Solidity: event MessageDeleted(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchMessageDeleted(opts *bind.WatchOpts, sink chan<- *QuickPicStorageMessageDeleted, id [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "MessageDeleted", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageMessageDeleted)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "MessageDeleted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseMessageDeleted is a log parse operation binding the contract event 0xf4ef3cbd1d2cff45998b1fb60a13a6505834c570a5a9a44f55c3a3c2b5c852fd.
+//
+// Solidity: event MessageDeleted(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseMessageDeleted(log types.Log) (*QuickPicStorageMessageDeleted, error) {
+	event := new(QuickPicStorageMessageDeleted)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "MessageDeleted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageOneTimePreKeysAddedIterator is returned from FilterOneTimePreKeysAdded and is used to iterate over the raw logs and unpacked data for OneTimePreKeysAdded events raised by the QuickPicStorage contract.
+type QuickPicStorageOneTimePreKeysAddedIterator struct {
+	Event *QuickPicStorageOneTimePreKeysAdded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageOneTimePreKeysAddedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageOneTimePreKeysAdded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageOneTimePreKeysAdded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageOneTimePreKeysAddedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageOneTimePreKeysAddedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageOneTimePreKeysAdded represents a OneTimePreKeysAdded event raised by the QuickPicStorage contract.
+type QuickPicStorageOneTimePreKeysAdded struct {
+	UserId [32]byte
+	Count  *big.Int
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterOneTimePreKeysAdded is a free log retrieval operation binding the contract event 0xbdb98fc5d343c9b63d7d0a7d5942a7e55e4f2f8bfae0576a3b9947a5252fa388.
+//
+// Solidity: event OneTimePreKeysAdded(bytes32 indexed userId, uint256 count)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterOneTimePreKeysAdded(opts *bind.FilterOpts, userId [][32]byte) (*QuickPicStorageOneTimePreKeysAddedIterator, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "OneTimePreKeysAdded", userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageOneTimePreKeysAddedIterator{contract: _QuickPicStorage.contract, event: "OneTimePreKeysAdded", logs: logs, sub: sub}, nil
+}
+
+// WatchOneTimePreKeysAdded is a free log subscription operation binding the contract event 0xbdb98fc5d343c9b63d7d0a7d5942a7e55e4f2f8bfae0576a3b9947a5252fa388.
+//
+// Solidity: event OneTimePreKeysAdded(bytes32 indexed userId, uint256 count)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchOneTimePreKeysAdded(opts *bind.WatchOpts, sink chan<- *QuickPicStorageOneTimePreKeysAdded, userId [][32]byte) (event.Subscription, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "OneTimePreKeysAdded", userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageOneTimePreKeysAdded)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "OneTimePreKeysAdded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOneTimePreKeysAdded is a log parse operation binding the contract event 0xbdb98fc5d343c9b63d7d0a7d5942a7e55e4f2f8bfae0576a3b9947a5252fa388.
+//
+// Solidity: event OneTimePreKeysAdded(bytes32 indexed userId, uint256 count)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseOneTimePreKeysAdded(log types.Log) (*QuickPicStorageOneTimePreKeysAdded, error) {
+	event := new(QuickPicStorageOneTimePreKeysAdded)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "OneTimePreKeysAdded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStoragePreKeyClaimedIterator is returned from FilterPreKeyClaimed and is used to iterate over the raw logs and unpacked data for PreKeyClaimed events raised by the QuickPicStorage contract.
+type QuickPicStoragePreKeyClaimedIterator struct {
+	Event *QuickPicStoragePreKeyClaimed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStoragePreKeyClaimedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStoragePreKeyClaimed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStoragePreKeyClaimed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStoragePreKeyClaimedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStoragePreKeyClaimedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStoragePreKeyClaimed represents a PreKeyClaimed event raised by the QuickPicStorage contract.
+type QuickPicStoragePreKeyClaimed struct {
+	UserId    [32]byte
+	KeyId     *big.Int
+	PublicKey string
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterPreKeyClaimed is a free log retrieval operation binding the contract event 0x6d6add29961a596eef3512ec5211e4a08843ebd124f05ffc0331f1995d48854e.
+//
+// Solidity: event PreKeyClaimed(bytes32 indexed userId, uint256 keyId, string publicKey)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterPreKeyClaimed(opts *bind.FilterOpts, userId [][32]byte) (*QuickPicStoragePreKeyClaimedIterator, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "PreKeyClaimed", userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStoragePreKeyClaimedIterator{contract: _QuickPicStorage.contract, event: "PreKeyClaimed", logs: logs, sub: sub}, nil
+}
+
+// WatchPreKeyClaimed is a free log subscription operation binding the contract event 0x6d6add29961a596eef3512ec5211e4a08843ebd124f05ffc0331f1995d48854e.
+//
+// Solidity: event PreKeyClaimed(bytes32 indexed userId, uint256 keyId, string publicKey)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchPreKeyClaimed(opts *bind.WatchOpts, sink chan<- *QuickPicStoragePreKeyClaimed, userId [][32]byte) (event.Subscription, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "PreKeyClaimed", userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStoragePreKeyClaimed)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "PreKeyClaimed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePreKeyClaimed is a log parse operation binding the contract event 0x6d6add29961a596eef3512ec5211e4a08843ebd124f05ffc0331f1995d48854e.
+//
+// Solidity: event PreKeyClaimed(bytes32 indexed userId, uint256 keyId, string publicKey)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParsePreKeyClaimed(log types.Log) (*QuickPicStoragePreKeyClaimed, error) {
+	event := new(QuickPicStoragePreKeyClaimed)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "PreKeyClaimed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStoragePrivacyFlagsUpdatedIterator is returned from FilterPrivacyFlagsUpdated and is used to iterate over the raw logs and unpacked data for PrivacyFlagsUpdated events raised by the QuickPicStorage contract.
+type QuickPicStoragePrivacyFlagsUpdatedIterator struct {
+	Event *QuickPicStoragePrivacyFlagsUpdated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStoragePrivacyFlagsUpdatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStoragePrivacyFlagsUpdated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStoragePrivacyFlagsUpdated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStoragePrivacyFlagsUpdatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStoragePrivacyFlagsUpdatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStoragePrivacyFlagsUpdated represents a PrivacyFlagsUpdated event raised by the QuickPicStorage contract.
+type QuickPicStoragePrivacyFlagsUpdated struct {
+	UserId [32]byte
+	Flags  *big.Int
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterPrivacyFlagsUpdated is a free log retrieval operation binding the contract event 0xf283aa319b9ecfd4fb2576be155c905a3fa786ba8ac93aa5b50b0294029b5f61.
+//
+// Solidity: event PrivacyFlagsUpdated(bytes32 indexed userId, uint256 flags)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterPrivacyFlagsUpdated(opts *bind.FilterOpts, userId [][32]byte) (*QuickPicStoragePrivacyFlagsUpdatedIterator, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "PrivacyFlagsUpdated", userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStoragePrivacyFlagsUpdatedIterator{contract: _QuickPicStorage.contract, event: "PrivacyFlagsUpdated", logs: logs, sub: sub}, nil
+}
+
+// WatchPrivacyFlagsUpdated is a free log subscription operation binding the contract event 0xf283aa319b9ecfd4fb2576be155c905a3fa786ba8ac93aa5b50b0294029b5f61.
+//
+// Solidity: event PrivacyFlagsUpdated(bytes32 indexed userId, uint256 flags)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchPrivacyFlagsUpdated(opts *bind.WatchOpts, sink chan<- *QuickPicStoragePrivacyFlagsUpdated, userId [][32]byte) (event.Subscription, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "PrivacyFlagsUpdated", userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStoragePrivacyFlagsUpdated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "PrivacyFlagsUpdated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePrivacyFlagsUpdated is a log parse operation binding the contract event 0xf283aa319b9ecfd4fb2576be155c905a3fa786ba8ac93aa5b50b0294029b5f61.
+//
+// Solidity: event PrivacyFlagsUpdated(bytes32 indexed userId, uint256 flags)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParsePrivacyFlagsUpdated(log types.Log) (*QuickPicStoragePrivacyFlagsUpdated, error) {
+	event := new(QuickPicStoragePrivacyFlagsUpdated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "PrivacyFlagsUpdated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageProfileUpdatedIterator is returned from FilterProfileUpdated and is used to iterate over the raw logs and unpacked data for ProfileUpdated events raised by the QuickPicStorage contract.
+type QuickPicStorageProfileUpdatedIterator struct {
+	Event *QuickPicStorageProfileUpdated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageProfileUpdatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageProfileUpdated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageProfileUpdated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageProfileUpdatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageProfileUpdatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageProfileUpdated represents a ProfileUpdated event raised by the QuickPicStorage contract.
+type QuickPicStorageProfileUpdated struct {
+	UserId [32]byte
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterProfileUpdated is a free log retrieval operation binding the contract event 0xbcc8547d07ccdb27400e2524da86bf5bcc4f0ef2adce334b30a704fd45f6b253.
+//
+// Solidity: event ProfileUpdated(bytes32 indexed userId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterProfileUpdated(opts *bind.FilterOpts, userId [][32]byte) (*QuickPicStorageProfileUpdatedIterator, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "ProfileUpdated", userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageProfileUpdatedIterator{contract: _QuickPicStorage.contract, event: "ProfileUpdated", logs: logs, sub: sub}, nil
+}
+
+// WatchProfileUpdated is a free log subscription operation binding the contract event 0xbcc8547d07ccdb27400e2524da86bf5bcc4f0ef2adce334b30a704fd45f6b253.
+//
+// Solidity: event ProfileUpdated(bytes32 indexed userId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchProfileUpdated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageProfileUpdated, userId [][32]byte) (event.Subscription, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "ProfileUpdated", userIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageProfileUpdated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "ProfileUpdated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseProfileUpdated is a log parse operation binding the contract event 0xbcc8547d07ccdb27400e2524da86bf5bcc4f0ef2adce334b30a704fd45f6b253.
+//
+// Solidity: event ProfileUpdated(bytes32 indexed userId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseProfileUpdated(log types.Log) (*QuickPicStorageProfileUpdated, error) {
+	event := new(QuickPicStorageProfileUpdated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "ProfileUpdated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStoragePublicKeyRotatedIterator is returned from FilterPublicKeyRotated and is used to iterate over the raw logs and unpacked data for PublicKeyRotated events raised by the QuickPicStorage contract.
+type QuickPicStoragePublicKeyRotatedIterator struct {
+	Event *QuickPicStoragePublicKeyRotated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStoragePublicKeyRotatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStoragePublicKeyRotated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStoragePublicKeyRotated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStoragePublicKeyRotatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStoragePublicKeyRotatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStoragePublicKeyRotated represents a PublicKeyRotated event raised by the QuickPicStorage contract.
+type QuickPicStoragePublicKeyRotated struct {
+	Id      [32]byte
+	Version *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterPublicKeyRotated is a free log retrieval operation binding the contract event 0x92c9ff29c101f9a32028c7088521c8263cc42075be46d4159d186fe4f00441dc.
+//
+// Solidity: event PublicKeyRotated(bytes32 indexed id, uint256 version)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterPublicKeyRotated(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStoragePublicKeyRotatedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "PublicKeyRotated", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStoragePublicKeyRotatedIterator{contract: _QuickPicStorage.contract, event: "PublicKeyRotated", logs: logs, sub: sub}, nil
 }
 
-// UpdateUser is a paid mutator transaction binding the contract method 0x494fb8e7.
+// WatchPublicKeyRotated is a free log subscription operation binding the contract event 0x92c9ff29c101f9a32028c7088521c8263cc42075be46d4159d186fe4f00441dc.
 //
-// Solidity: function updateUser(bytes32 id, string passwordHash, string publicKey) returns()
-func (_QuickPicStorage *QuickPicStorageTransactorSession) UpdateUser(id [32]byte, passwordHash string, publicKey string) (*types.Transaction, error) {
-	return _QuickPicStorage.Contract.UpdateUser(&_QuickPicStorage.TransactOpts, id, passwordHash, publicKey)
+// Solidity: event PublicKeyRotated(bytes32 indexed id, uint256 version)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchPublicKeyRotated(opts *bind.WatchOpts, sink chan<- *QuickPicStoragePublicKeyRotated, id [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "PublicKeyRotated", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStoragePublicKeyRotated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "PublicKeyRotated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
 }
 
-// QuickPicStorageFriendRequestCreatedIterator is returned from FilterFriendRequestCreated and is used to iterate over the raw logs and unpacked data for FriendRequestCreated events raised by the QuickPicStorage contract.
-type QuickPicStorageFriendRequestCreatedIterator struct {
-	Event *QuickPicStorageFriendRequestCreated // Event containing the contract specifics and raw log
+// ParsePublicKeyRotated is a log parse operation binding the contract event 0x92c9ff29c101f9a32028c7088521c8263cc42075be46d4159d186fe4f00441dc.
+//
+// Solidity: event PublicKeyRotated(bytes32 indexed id, uint256 version)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParsePublicKeyRotated(log types.Log) (*QuickPicStoragePublicKeyRotated, error) {
+	event := new(QuickPicStoragePublicKeyRotated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "PublicKeyRotated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// QuickPicStorageSignedPreKeyUpdatedIterator is returned from FilterSignedPreKeyUpdated and is used to iterate over the raw logs and unpacked data for SignedPreKeyUpdated events raised by the QuickPicStorage contract.
+type QuickPicStorageSignedPreKeyUpdatedIterator struct {
+	Event *QuickPicStorageSignedPreKeyUpdated // Event containing the contract specifics and raw log
 
 	contract *bind.BoundContract // Generic contract to use for unpacking event data
 	event    string              // Event name to use for unpacking event data
@@ -1646,7 +5975,7 @@ type QuickPicStorageFriendRequestCreatedIterator struct {
 // Next advances the iterator to the subsequent event, returning whether there
 // are any more events found. In case of a retrieval or parsing error, false is
 // returned and Error() can be queried for the exact failure.
-func (it *QuickPicStorageFriendRequestCreatedIterator) Next() bool {
+func (it *QuickPicStorageSignedPreKeyUpdatedIterator) Next() bool {
 	// If the iterator failed, stop iterating
 	if it.fail != nil {
 		return false
@@ -1655,7 +5984,7 @@ func (it *QuickPicStorageFriendRequestCreatedIterator) Next() bool {
 	if it.done {
 		select {
 		case log := <-it.logs:
-			it.Event = new(QuickPicStorageFriendRequestCreated)
+			it.Event = new(QuickPicStorageSignedPreKeyUpdated)
 			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 				it.fail = err
 				return false
@@ -1670,7 +5999,7 @@ func (it *QuickPicStorageFriendRequestCreatedIterator) Next() bool {
 	// Iterator still in progress, wait for either a data or an error event
 	select {
 	case log := <-it.logs:
-		it.Event = new(QuickPicStorageFriendRequestCreated)
+		it.Event = new(QuickPicStorageSignedPreKeyUpdated)
 		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 			it.fail = err
 			return false
@@ -1686,69 +6015,205 @@ func (it *QuickPicStorageFriendRequestCreatedIterator) Next() bool {
 }
 
 // Error returns any retrieval or parsing error occurred during filtering.
-func (it *QuickPicStorageFriendRequestCreatedIterator) Error() error {
+func (it *QuickPicStorageSignedPreKeyUpdatedIterator) Error() error {
 	return it.fail
 }
 
 // Close terminates the iteration process, releasing any pending underlying
 // resources.
-func (it *QuickPicStorageFriendRequestCreatedIterator) Close() error {
+func (it *QuickPicStorageSignedPreKeyUpdatedIterator) Close() error {
 	it.sub.Unsubscribe()
 	return nil
 }
 
-// QuickPicStorageFriendRequestCreated represents a FriendRequestCreated event raised by the QuickPicStorage contract.
-type QuickPicStorageFriendRequestCreated struct {
-	Id         [32]byte
-	FromUserId [32]byte
-	ToUserId   [32]byte
-	Raw        types.Log // Blockchain specific contextual infos
+// QuickPicStorageSignedPreKeyUpdated represents a SignedPreKeyUpdated event raised by the QuickPicStorage contract.
+type QuickPicStorageSignedPreKeyUpdated struct {
+	UserId [32]byte
+	KeyId  *big.Int
+	Raw    types.Log // Blockchain specific contextual infos
 }
 
-// FilterFriendRequestCreated is a free log retrieval operation binding the contract event 0x9ac14503d7f332c5dbec9975c7e73dc5a8473298dbc7abc78c5997f410d2777c.
+// FilterSignedPreKeyUpdated is a free log retrieval operation binding the contract event 0x4199305d1a000ae12da7a27651abf22b2292f38d23007c41ac99804ed7192b1f.
 //
-// Solidity: event FriendRequestCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
-func (_QuickPicStorage *QuickPicStorageFilterer) FilterFriendRequestCreated(opts *bind.FilterOpts, id [][32]byte, fromUserId [][32]byte, toUserId [][32]byte) (*QuickPicStorageFriendRequestCreatedIterator, error) {
+// Solidity: event SignedPreKeyUpdated(bytes32 indexed userId, uint256 keyId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterSignedPreKeyUpdated(opts *bind.FilterOpts, userId [][32]byte) (*QuickPicStorageSignedPreKeyUpdatedIterator, error) {
 
-	var idRule []interface{}
-	for _, idItem := range id {
-		idRule = append(idRule, idItem)
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
 	}
-	var fromUserIdRule []interface{}
-	for _, fromUserIdItem := range fromUserId {
-		fromUserIdRule = append(fromUserIdRule, fromUserIdItem)
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "SignedPreKeyUpdated", userIdRule)
+	if err != nil {
+		return nil, err
 	}
-	var toUserIdRule []interface{}
-	for _, toUserIdItem := range toUserId {
-		toUserIdRule = append(toUserIdRule, toUserIdItem)
+	return &QuickPicStorageSignedPreKeyUpdatedIterator{contract: _QuickPicStorage.contract, event: "SignedPreKeyUpdated", logs: logs, sub: sub}, nil
+}
+
+// WatchSignedPreKeyUpdated is a free log subscription operation binding the contract event 0x4199305d1a000ae12da7a27651abf22b2292f38d23007c41ac99804ed7192b1f.
+//
+// Solidity: event SignedPreKeyUpdated(bytes32 indexed userId, uint256 keyId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchSignedPreKeyUpdated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageSignedPreKeyUpdated, userId [][32]byte) (event.Subscription, error) {
+
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "FriendRequestCreated", idRule, fromUserIdRule, toUserIdRule)
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "SignedPreKeyUpdated", userIdRule)
 	if err != nil {
 		return nil, err
 	}
-	return &QuickPicStorageFriendRequestCreatedIterator{contract: _QuickPicStorage.contract, event: "FriendRequestCreated", logs: logs, sub: sub}, nil
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(QuickPicStorageSignedPreKeyUpdated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "SignedPreKeyUpdated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
 }
 
-// WatchFriendRequestCreated is a free log subscription operation binding the contract event 0x9ac14503d7f332c5dbec9975c7e73dc5a8473298dbc7abc78c5997f410d2777c.
+// ParseSignedPreKeyUpdated is a log parse operation binding the contract event 0x4199305d1a000ae12da7a27651abf22b2292f38d23007c41ac99804ed7192b1f.
 //
-// Solidity: event FriendRequestCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
-func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendRequestCreated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageFriendRequestCreated, id [][32]byte, fromUserId [][32]byte, toUserId [][32]byte) (event.Subscription, error) {
+// Solidity: event SignedPreKeyUpdated(bytes32 indexed userId, uint256 keyId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseSignedPreKeyUpdated(log types.Log) (*QuickPicStorageSignedPreKeyUpdated, error) {
+	event := new(QuickPicStorageSignedPreKeyUpdated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "SignedPreKeyUpdated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
 
-	var idRule []interface{}
-	for _, idItem := range id {
-		idRule = append(idRule, idItem)
+// QuickPicStorageUserBlockedIterator is returned from FilterUserBlocked and is used to iterate over the raw logs and unpacked data for UserBlocked events raised by the QuickPicStorage contract.
+type QuickPicStorageUserBlockedIterator struct {
+	Event *QuickPicStorageUserBlocked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *QuickPicStorageUserBlockedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
 	}
-	var fromUserIdRule []interface{}
-	for _, fromUserIdItem := range fromUserId {
-		fromUserIdRule = append(fromUserIdRule, fromUserIdItem)
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(QuickPicStorageUserBlocked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
 	}
-	var toUserIdRule []interface{}
-	for _, toUserIdItem := range toUserId {
-		toUserIdRule = append(toUserIdRule, toUserIdItem)
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(QuickPicStorageUserBlocked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
 	}
+}
 
-	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "FriendRequestCreated", idRule, fromUserIdRule, toUserIdRule)
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *QuickPicStorageUserBlockedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *QuickPicStorageUserBlockedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// QuickPicStorageUserBlocked represents a UserBlocked event raised by the QuickPicStorage contract.
+type QuickPicStorageUserBlocked struct {
+	BlockerId [32]byte
+	BlockedId [32]byte
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterUserBlocked is a free log retrieval operation binding the contract event 0xf1ffb655ae899fdaaa1495d3622ab61d4dcfd2cec231aa3775dd1f4c83c463f9.
+//
+// Solidity: event UserBlocked(bytes32 indexed blockerId, bytes32 indexed blockedId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterUserBlocked(opts *bind.FilterOpts, blockerId [][32]byte, blockedId [][32]byte) (*QuickPicStorageUserBlockedIterator, error) {
+
+	var blockerIdRule []interface{}
+	for _, blockerIdItem := range blockerId {
+		blockerIdRule = append(blockerIdRule, blockerIdItem)
+	}
+	var blockedIdRule []interface{}
+	for _, blockedIdItem := range blockedId {
+		blockedIdRule = append(blockedIdRule, blockedIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "UserBlocked", blockerIdRule, blockedIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickPicStorageUserBlockedIterator{contract: _QuickPicStorage.contract, event: "UserBlocked", logs: logs, sub: sub}, nil
+}
+
+// WatchUserBlocked is a free log subscription operation binding the contract event 0xf1ffb655ae899fdaaa1495d3622ab61d4dcfd2cec231aa3775dd1f4c83c463f9.
+//
+// Solidity: event UserBlocked(bytes32 indexed blockerId, bytes32 indexed blockedId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserBlocked(opts *bind.WatchOpts, sink chan<- *QuickPicStorageUserBlocked, blockerId [][32]byte, blockedId [][32]byte) (event.Subscription, error) {
+
+	var blockerIdRule []interface{}
+	for _, blockerIdItem := range blockerId {
+		blockerIdRule = append(blockerIdRule, blockerIdItem)
+	}
+	var blockedIdRule []interface{}
+	for _, blockedIdItem := range blockedId {
+		blockedIdRule = append(blockedIdRule, blockedIdItem)
+	}
+
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "UserBlocked", blockerIdRule, blockedIdRule)
 	if err != nil {
 		return nil, err
 	}
@@ -1758,8 +6223,8 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendRequestCreated(opts
 			select {
 			case log := <-logs:
 				// New log arrived, parse the event and forward to the user
-				event := new(QuickPicStorageFriendRequestCreated)
-				if err := _QuickPicStorage.contract.UnpackLog(event, "FriendRequestCreated", log); err != nil {
+				event := new(QuickPicStorageUserBlocked)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "UserBlocked", log); err != nil {
 					return err
 				}
 				event.Raw = log
@@ -1780,21 +6245,21 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendRequestCreated(opts
 	}), nil
 }
 
-// ParseFriendRequestCreated is a log parse operation binding the contract event 0x9ac14503d7f332c5dbec9975c7e73dc5a8473298dbc7abc78c5997f410d2777c.
+// ParseUserBlocked is a log parse operation binding the contract event 0xf1ffb655ae899fdaaa1495d3622ab61d4dcfd2cec231aa3775dd1f4c83c463f9.
 //
-// Solidity: event FriendRequestCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
-func (_QuickPicStorage *QuickPicStorageFilterer) ParseFriendRequestCreated(log types.Log) (*QuickPicStorageFriendRequestCreated, error) {
-	event := new(QuickPicStorageFriendRequestCreated)
-	if err := _QuickPicStorage.contract.UnpackLog(event, "FriendRequestCreated", log); err != nil {
+// Solidity: event UserBlocked(bytes32 indexed blockerId, bytes32 indexed blockedId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseUserBlocked(log types.Log) (*QuickPicStorageUserBlocked, error) {
+	event := new(QuickPicStorageUserBlocked)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "UserBlocked", log); err != nil {
 		return nil, err
 	}
 	event.Raw = log
 	return event, nil
 }
 
-// QuickPicStorageFriendRequestUpdatedIterator is returned from FilterFriendRequestUpdated and is used to iterate over the raw logs and unpacked data for FriendRequestUpdated events raised by the QuickPicStorage contract.
-type QuickPicStorageFriendRequestUpdatedIterator struct {
-	Event *QuickPicStorageFriendRequestUpdated // Event containing the contract specifics and raw log
+// QuickPicStorageUserCreatedIterator is returned from FilterUserCreated and is used to iterate over the raw logs and unpacked data for UserCreated events raised by the QuickPicStorage contract.
+type QuickPicStorageUserCreatedIterator struct {
+	Event *QuickPicStorageUserCreated // Event containing the contract specifics and raw log
 
 	contract *bind.BoundContract // Generic contract to use for unpacking event data
 	event    string              // Event name to use for unpacking event data
@@ -1808,7 +6273,7 @@ type QuickPicStorageFriendRequestUpdatedIterator struct {
 // Next advances the iterator to the subsequent event, returning whether there
 // are any more events found. In case of a retrieval or parsing error, false is
 // returned and Error() can be queried for the exact failure.
-func (it *QuickPicStorageFriendRequestUpdatedIterator) Next() bool {
+func (it *QuickPicStorageUserCreatedIterator) Next() bool {
 	// If the iterator failed, stop iterating
 	if it.fail != nil {
 		return false
@@ -1817,7 +6282,7 @@ func (it *QuickPicStorageFriendRequestUpdatedIterator) Next() bool {
 	if it.done {
 		select {
 		case log := <-it.logs:
-			it.Event = new(QuickPicStorageFriendRequestUpdated)
+			it.Event = new(QuickPicStorageUserCreated)
 			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 				it.fail = err
 				return false
@@ -1832,7 +6297,7 @@ func (it *QuickPicStorageFriendRequestUpdatedIterator) Next() bool {
 	// Iterator still in progress, wait for either a data or an error event
 	select {
 	case log := <-it.logs:
-		it.Event = new(QuickPicStorageFriendRequestUpdated)
+		it.Event = new(QuickPicStorageUserCreated)
 		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 			it.fail = err
 			return false
@@ -1848,52 +6313,53 @@ func (it *QuickPicStorageFriendRequestUpdatedIterator) Next() bool {
 }
 
 // Error returns any retrieval or parsing error occurred during filtering.
-func (it *QuickPicStorageFriendRequestUpdatedIterator) Error() error {
+func (it *QuickPicStorageUserCreatedIterator) Error() error {
 	return it.fail
 }
 
 // Close terminates the iteration process, releasing any pending underlying
 // resources.
-func (it *QuickPicStorageFriendRequestUpdatedIterator) Close() error {
+func (it *QuickPicStorageUserCreatedIterator) Close() error {
 	it.sub.Unsubscribe()
 	return nil
 }
 
-// QuickPicStorageFriendRequestUpdated represents a FriendRequestUpdated event raised by the QuickPicStorage contract.
-type QuickPicStorageFriendRequestUpdated struct {
-	Id     [32]byte
-	Status uint8
-	Raw    types.Log // Blockchain specific contextual infos
+// QuickPicStorageUserCreated represents a UserCreated event raised by the QuickPicStorage contract.
+type QuickPicStorageUserCreated struct {
+	Id         [32]byte
+	UserNumber *big.Int
+	Username   string
+	Raw        types.Log // Blockchain specific contextual infos
 }
 
-// FilterFriendRequestUpdated is a free log retrieval operation binding the contract event 0x8a2bf39e9087c33765bcca9a2c61caaa3e82956b669d0f3914a1d95e50f19ff2.
+// FilterUserCreated is a free log retrieval operation binding the contract event 0xfa1f973aa4e6a4cd973d33775be141ea493df90f6cba4b68b4fcc7fe352522f0.
 //
-// Solidity: event FriendRequestUpdated(bytes32 indexed id, uint8 status)
-func (_QuickPicStorage *QuickPicStorageFilterer) FilterFriendRequestUpdated(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageFriendRequestUpdatedIterator, error) {
+// Solidity: event UserCreated(bytes32 indexed id, uint256 userNumber, string username)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterUserCreated(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageUserCreatedIterator, error) {
 
 	var idRule []interface{}
 	for _, idItem := range id {
 		idRule = append(idRule, idItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "FriendRequestUpdated", idRule)
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "UserCreated", idRule)
 	if err != nil {
 		return nil, err
 	}
-	return &QuickPicStorageFriendRequestUpdatedIterator{contract: _QuickPicStorage.contract, event: "FriendRequestUpdated", logs: logs, sub: sub}, nil
+	return &QuickPicStorageUserCreatedIterator{contract: _QuickPicStorage.contract, event: "UserCreated", logs: logs, sub: sub}, nil
 }
 
-// WatchFriendRequestUpdated is a free log subscription operation binding the contract event 0x8a2bf39e9087c33765bcca9a2c61caaa3e82956b669d0f3914a1d95e50f19ff2.
+// WatchUserCreated is a free log subscription operation binding the contract event 0xfa1f973aa4e6a4cd973d33775be141ea493df90f6cba4b68b4fcc7fe352522f0.
 //
-// Solidity: event FriendRequestUpdated(bytes32 indexed id, uint8 status)
-func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendRequestUpdated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageFriendRequestUpdated, id [][32]byte) (event.Subscription, error) {
+// Solidity: event UserCreated(bytes32 indexed id, uint256 userNumber, string username)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserCreated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageUserCreated, id [][32]byte) (event.Subscription, error) {
 
 	var idRule []interface{}
 	for _, idItem := range id {
 		idRule = append(idRule, idItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "FriendRequestUpdated", idRule)
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "UserCreated", idRule)
 	if err != nil {
 		return nil, err
 	}
@@ -1903,8 +6369,8 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendRequestUpdated(opts
 			select {
 			case log := <-logs:
 				// New log arrived, parse the event and forward to the user
-				event := new(QuickPicStorageFriendRequestUpdated)
-				if err := _QuickPicStorage.contract.UnpackLog(event, "FriendRequestUpdated", log); err != nil {
+				event := new(QuickPicStorageUserCreated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "UserCreated", log); err != nil {
 					return err
 				}
 				event.Raw = log
@@ -1925,21 +6391,21 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendRequestUpdated(opts
 	}), nil
 }
 
-// ParseFriendRequestUpdated is a log parse operation binding the contract event 0x8a2bf39e9087c33765bcca9a2c61caaa3e82956b669d0f3914a1d95e50f19ff2.
+// ParseUserCreated is a log parse operation binding the contract event 0xfa1f973aa4e6a4cd973d33775be141ea493df90f6cba4b68b4fcc7fe352522f0.
 //
-// Solidity: event FriendRequestUpdated(bytes32 indexed id, uint8 status)
-func (_QuickPicStorage *QuickPicStorageFilterer) ParseFriendRequestUpdated(log types.Log) (*QuickPicStorageFriendRequestUpdated, error) {
-	event := new(QuickPicStorageFriendRequestUpdated)
-	if err := _QuickPicStorage.contract.UnpackLog(event, "FriendRequestUpdated", log); err != nil {
+// Solidity: event UserCreated(bytes32 indexed id, uint256 userNumber, string username)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseUserCreated(log types.Log) (*QuickPicStorageUserCreated, error) {
+	event := new(QuickPicStorageUserCreated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "UserCreated", log); err != nil {
 		return nil, err
 	}
 	event.Raw = log
 	return event, nil
 }
 
-// QuickPicStorageFriendshipCreatedIterator is returned from FilterFriendshipCreated and is used to iterate over the raw logs and unpacked data for FriendshipCreated events raised by the QuickPicStorage contract.
-type QuickPicStorageFriendshipCreatedIterator struct {
-	Event *QuickPicStorageFriendshipCreated // Event containing the contract specifics and raw log
+// QuickPicStorageUserDeletedIterator is returned from FilterUserDeleted and is used to iterate over the raw logs and unpacked data for UserDeleted events raised by the QuickPicStorage contract.
+type QuickPicStorageUserDeletedIterator struct {
+	Event *QuickPicStorageUserDeleted // Event containing the contract specifics and raw log
 
 	contract *bind.BoundContract // Generic contract to use for unpacking event data
 	event    string              // Event name to use for unpacking event data
@@ -1953,7 +6419,7 @@ type QuickPicStorageFriendshipCreatedIterator struct {
 // Next advances the iterator to the subsequent event, returning whether there
 // are any more events found. In case of a retrieval or parsing error, false is
 // returned and Error() can be queried for the exact failure.
-func (it *QuickPicStorageFriendshipCreatedIterator) Next() bool {
+func (it *QuickPicStorageUserDeletedIterator) Next() bool {
 	// If the iterator failed, stop iterating
 	if it.fail != nil {
 		return false
@@ -1962,7 +6428,7 @@ func (it *QuickPicStorageFriendshipCreatedIterator) Next() bool {
 	if it.done {
 		select {
 		case log := <-it.logs:
-			it.Event = new(QuickPicStorageFriendshipCreated)
+			it.Event = new(QuickPicStorageUserDeleted)
 			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 				it.fail = err
 				return false
@@ -1977,7 +6443,7 @@ func (it *QuickPicStorageFriendshipCreatedIterator) Next() bool {
 	// Iterator still in progress, wait for either a data or an error event
 	select {
 	case log := <-it.logs:
-		it.Event = new(QuickPicStorageFriendshipCreated)
+		it.Event = new(QuickPicStorageUserDeleted)
 		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 			it.fail = err
 			return false
@@ -1993,69 +6459,51 @@ func (it *QuickPicStorageFriendshipCreatedIterator) Next() bool {
 }
 
 // Error returns any retrieval or parsing error occurred during filtering.
-func (it *QuickPicStorageFriendshipCreatedIterator) Error() error {
+func (it *QuickPicStorageUserDeletedIterator) Error() error {
 	return it.fail
 }
 
 // Close terminates the iteration process, releasing any pending underlying
 // resources.
-func (it *QuickPicStorageFriendshipCreatedIterator) Close() error {
+func (it *QuickPicStorageUserDeletedIterator) Close() error {
 	it.sub.Unsubscribe()
 	return nil
 }
 
-// QuickPicStorageFriendshipCreated represents a FriendshipCreated event raised by the QuickPicStorage contract.
-type QuickPicStorageFriendshipCreated struct {
-	Id      [32]byte
-	UserAId [32]byte
-	UserBId [32]byte
-	Raw     types.Log // Blockchain specific contextual infos
+// QuickPicStorageUserDeleted represents a UserDeleted event raised by the QuickPicStorage contract.
+type QuickPicStorageUserDeleted struct {
+	Id  [32]byte
+	Raw types.Log // Blockchain specific contextual infos
 }
 
-// FilterFriendshipCreated is a free log retrieval operation binding the contract event 0x63bb48a2197f36fe8120ecc4f504ccefdd2d712a3bf2d5645c0e6dac33e0301b.
+// FilterUserDeleted is a free log retrieval operation binding the contract event 0xc26eb30613e535870aeacfc24e58a8fb60e992f0d8bfe04ec9c98db26b53c5e5.
 //
-// Solidity: event FriendshipCreated(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId)
-func (_QuickPicStorage *QuickPicStorageFilterer) FilterFriendshipCreated(opts *bind.FilterOpts, id [][32]byte, userAId [][32]byte, userBId [][32]byte) (*QuickPicStorageFriendshipCreatedIterator, error) {
+// Solidity: event UserDeleted(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterUserDeleted(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageUserDeletedIterator, error) {
 
 	var idRule []interface{}
 	for _, idItem := range id {
 		idRule = append(idRule, idItem)
 	}
-	var userAIdRule []interface{}
-	for _, userAIdItem := range userAId {
-		userAIdRule = append(userAIdRule, userAIdItem)
-	}
-	var userBIdRule []interface{}
-	for _, userBIdItem := range userBId {
-		userBIdRule = append(userBIdRule, userBIdItem)
-	}
 
-	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "FriendshipCreated", idRule, userAIdRule, userBIdRule)
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "UserDeleted", idRule)
 	if err != nil {
 		return nil, err
 	}
-	return &QuickPicStorageFriendshipCreatedIterator{contract: _QuickPicStorage.contract, event: "FriendshipCreated", logs: logs, sub: sub}, nil
+	return &QuickPicStorageUserDeletedIterator{contract: _QuickPicStorage.contract, event: "UserDeleted", logs: logs, sub: sub}, nil
 }
 
-// WatchFriendshipCreated is a free log subscription operation binding the contract event 0x63bb48a2197f36fe8120ecc4f504ccefdd2d712a3bf2d5645c0e6dac33e0301b.
+// WatchUserDeleted is a free log subscription operation binding the contract event 0xc26eb30613e535870aeacfc24e58a8fb60e992f0d8bfe04ec9c98db26b53c5e5.
 //
-// Solidity: event FriendshipCreated(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId)
-func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendshipCreated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageFriendshipCreated, id [][32]byte, userAId [][32]byte, userBId [][32]byte) (event.Subscription, error) {
+// Solidity: event UserDeleted(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserDeleted(opts *bind.WatchOpts, sink chan<- *QuickPicStorageUserDeleted, id [][32]byte) (event.Subscription, error) {
 
 	var idRule []interface{}
 	for _, idItem := range id {
 		idRule = append(idRule, idItem)
 	}
-	var userAIdRule []interface{}
-	for _, userAIdItem := range userAId {
-		userAIdRule = append(userAIdRule, userAIdItem)
-	}
-	var userBIdRule []interface{}
-	for _, userBIdItem := range userBId {
-		userBIdRule = append(userBIdRule, userBIdItem)
-	}
 
-	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "FriendshipCreated", idRule, userAIdRule, userBIdRule)
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "UserDeleted", idRule)
 	if err != nil {
 		return nil, err
 	}
@@ -2065,8 +6513,8 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendshipCreated(opts *bi
 			select {
 			case log := <-logs:
 				// New log arrived, parse the event and forward to the user
-				event := new(QuickPicStorageFriendshipCreated)
-				if err := _QuickPicStorage.contract.UnpackLog(event, "FriendshipCreated", log); err != nil {
+				event := new(QuickPicStorageUserDeleted)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "UserDeleted", log); err != nil {
 					return err
 				}
 				event.Raw = log
@@ -2087,21 +6535,21 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchFriendshipCreated(opts *bi
 	}), nil
 }
 
-// ParseFriendshipCreated is a log parse operation binding the contract event 0x63bb48a2197f36fe8120ecc4f504ccefdd2d712a3bf2d5645c0e6dac33e0301b.
+// ParseUserDeleted is a log parse operation binding the contract event 0xc26eb30613e535870aeacfc24e58a8fb60e992f0d8bfe04ec9c98db26b53c5e5.
 //
-// Solidity: event FriendshipCreated(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId)
-func (_QuickPicStorage *QuickPicStorageFilterer) ParseFriendshipCreated(log types.Log) (*QuickPicStorageFriendshipCreated, error) {
-	event := new(QuickPicStorageFriendshipCreated)
-	if err := _QuickPicStorage.contract.UnpackLog(event, "FriendshipCreated", log); err != nil {
+// Solidity: event UserDeleted(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseUserDeleted(log types.Log) (*QuickPicStorageUserDeleted, error) {
+	event := new(QuickPicStorageUserDeleted)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "UserDeleted", log); err != nil {
 		return nil, err
 	}
 	event.Raw = log
 	return event, nil
 }
 
-// QuickPicStorageMessageCreatedIterator is returned from FilterMessageCreated and is used to iterate over the raw logs and unpacked data for MessageCreated events raised by the QuickPicStorage contract.
-type QuickPicStorageMessageCreatedIterator struct {
-	Event *QuickPicStorageMessageCreated // Event containing the contract specifics and raw log
+// QuickPicStorageUserSuspendedIterator is returned from FilterUserSuspended and is used to iterate over the raw logs and unpacked data for UserSuspended events raised by the QuickPicStorage contract.
+type QuickPicStorageUserSuspendedIterator struct {
+	Event *QuickPicStorageUserSuspended // Event containing the contract specifics and raw log
 
 	contract *bind.BoundContract // Generic contract to use for unpacking event data
 	event    string              // Event name to use for unpacking event data
@@ -2115,7 +6563,7 @@ type QuickPicStorageMessageCreatedIterator struct {
 // Next advances the iterator to the subsequent event, returning whether there
 // are any more events found. In case of a retrieval or parsing error, false is
 // returned and Error() can be queried for the exact failure.
-func (it *QuickPicStorageMessageCreatedIterator) Next() bool {
+func (it *QuickPicStorageUserSuspendedIterator) Next() bool {
 	// If the iterator failed, stop iterating
 	if it.fail != nil {
 		return false
@@ -2124,7 +6572,7 @@ func (it *QuickPicStorageMessageCreatedIterator) Next() bool {
 	if it.done {
 		select {
 		case log := <-it.logs:
-			it.Event = new(QuickPicStorageMessageCreated)
+			it.Event = new(QuickPicStorageUserSuspended)
 			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 				it.fail = err
 				return false
@@ -2139,7 +6587,7 @@ func (it *QuickPicStorageMessageCreatedIterator) Next() bool {
 	// Iterator still in progress, wait for either a data or an error event
 	select {
 	case log := <-it.logs:
-		it.Event = new(QuickPicStorageMessageCreated)
+		it.Event = new(QuickPicStorageUserSuspended)
 		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 			it.fail = err
 			return false
@@ -2155,69 +6603,52 @@ func (it *QuickPicStorageMessageCreatedIterator) Next() bool {
 }
 
 // Error returns any retrieval or parsing error occurred during filtering.
-func (it *QuickPicStorageMessageCreatedIterator) Error() error {
+func (it *QuickPicStorageUserSuspendedIterator) Error() error {
 	return it.fail
 }
 
 // Close terminates the iteration process, releasing any pending underlying
 // resources.
-func (it *QuickPicStorageMessageCreatedIterator) Close() error {
+func (it *QuickPicStorageUserSuspendedIterator) Close() error {
 	it.sub.Unsubscribe()
 	return nil
 }
 
-// QuickPicStorageMessageCreated represents a MessageCreated event raised by the QuickPicStorage contract.
-type QuickPicStorageMessageCreated struct {
-	Id         [32]byte
-	FromUserId [32]byte
-	ToUserId   [32]byte
-	Raw        types.Log // Blockchain specific contextual infos
+// QuickPicStorageUserSuspended represents a UserSuspended event raised by the QuickPicStorage contract.
+type QuickPicStorageUserSuspended struct {
+	UserId    [32]byte
+	Suspended bool
+	Raw       types.Log // Blockchain specific contextual infos
 }
 
-// FilterMessageCreated is a free log retrieval operation binding the contract event 0x4f2ba766b8c822d461311c87d079178a55231792db9f142af3ecd797a5cc5d28.
+// FilterUserSuspended is a free log retrieval operation binding the contract event 0x4ae0b98a5aa401efc5d28816c9cd0ab818ee22ffe961d6fc8e5bc187e84c83c1.
 //
-// Solidity: event MessageCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
-func (_QuickPicStorage *QuickPicStorageFilterer) FilterMessageCreated(opts *bind.FilterOpts, id [][32]byte, fromUserId [][32]byte, toUserId [][32]byte) (*QuickPicStorageMessageCreatedIterator, error) {
+// Solidity: event UserSuspended(bytes32 indexed userId, bool suspended)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterUserSuspended(opts *bind.FilterOpts, userId [][32]byte) (*QuickPicStorageUserSuspendedIterator, error) {
 
-	var idRule []interface{}
-	for _, idItem := range id {
-		idRule = append(idRule, idItem)
-	}
-	var fromUserIdRule []interface{}
-	for _, fromUserIdItem := range fromUserId {
-		fromUserIdRule = append(fromUserIdRule, fromUserIdItem)
-	}
-	var toUserIdRule []interface{}
-	for _, toUserIdItem := range toUserId {
-		toUserIdRule = append(toUserIdRule, toUserIdItem)
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "MessageCreated", idRule, fromUserIdRule, toUserIdRule)
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "UserSuspended", userIdRule)
 	if err != nil {
 		return nil, err
 	}
-	return &QuickPicStorageMessageCreatedIterator{contract: _QuickPicStorage.contract, event: "MessageCreated", logs: logs, sub: sub}, nil
+	return &QuickPicStorageUserSuspendedIterator{contract: _QuickPicStorage.contract, event: "UserSuspended", logs: logs, sub: sub}, nil
 }
 
-// WatchMessageCreated is a free log subscription operation binding the contract event 0x4f2ba766b8c822d461311c87d079178a55231792db9f142af3ecd797a5cc5d28.
+// WatchUserSuspended is a free log subscription operation binding the contract event 0x4ae0b98a5aa401efc5d28816c9cd0ab818ee22ffe961d6fc8e5bc187e84c83c1.
 //
-// Solidity: event MessageCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
-func (_QuickPicStorage *QuickPicStorageFilterer) WatchMessageCreated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageMessageCreated, id [][32]byte, fromUserId [][32]byte, toUserId [][32]byte) (event.Subscription, error) {
+// Solidity: event UserSuspended(bytes32 indexed userId, bool suspended)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserSuspended(opts *bind.WatchOpts, sink chan<- *QuickPicStorageUserSuspended, userId [][32]byte) (event.Subscription, error) {
 
-	var idRule []interface{}
-	for _, idItem := range id {
-		idRule = append(idRule, idItem)
-	}
-	var fromUserIdRule []interface{}
-	for _, fromUserIdItem := range fromUserId {
-		fromUserIdRule = append(fromUserIdRule, fromUserIdItem)
-	}
-	var toUserIdRule []interface{}
-	for _, toUserIdItem := range toUserId {
-		toUserIdRule = append(toUserIdRule, toUserIdItem)
+	var userIdRule []interface{}
+	for _, userIdItem := range userId {
+		userIdRule = append(userIdRule, userIdItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "MessageCreated", idRule, fromUserIdRule, toUserIdRule)
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "UserSuspended", userIdRule)
 	if err != nil {
 		return nil, err
 	}
@@ -2227,8 +6658,8 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchMessageCreated(opts *bind.
 			select {
 			case log := <-logs:
 				// New log arrived, parse the event and forward to the user
-				event := new(QuickPicStorageMessageCreated)
-				if err := _QuickPicStorage.contract.UnpackLog(event, "MessageCreated", log); err != nil {
+				event := new(QuickPicStorageUserSuspended)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "UserSuspended", log); err != nil {
 					return err
 				}
 				event.Raw = log
@@ -2249,21 +6680,21 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchMessageCreated(opts *bind.
 	}), nil
 }
 
-// ParseMessageCreated is a log parse operation binding the contract event 0x4f2ba766b8c822d461311c87d079178a55231792db9f142af3ecd797a5cc5d28.
+// ParseUserSuspended is a log parse operation binding the contract event 0x4ae0b98a5aa401efc5d28816c9cd0ab818ee22ffe961d6fc8e5bc187e84c83c1.
 //
-// Solidity: event MessageCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId)
-func (_QuickPicStorage *QuickPicStorageFilterer) ParseMessageCreated(log types.Log) (*QuickPicStorageMessageCreated, error) {
-	event := new(QuickPicStorageMessageCreated)
-	if err := _QuickPicStorage.contract.UnpackLog(event, "MessageCreated", log); err != nil {
+// Solidity: event UserSuspended(bytes32 indexed userId, bool suspended)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseUserSuspended(log types.Log) (*QuickPicStorageUserSuspended, error) {
+	event := new(QuickPicStorageUserSuspended)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "UserSuspended", log); err != nil {
 		return nil, err
 	}
 	event.Raw = log
 	return event, nil
 }
 
-// QuickPicStorageMessageDeletedIterator is returned from FilterMessageDeleted and is used to iterate over the raw logs and unpacked data for MessageDeleted events raised by the QuickPicStorage contract.
-type QuickPicStorageMessageDeletedIterator struct {
-	Event *QuickPicStorageMessageDeleted // Event containing the contract specifics and raw log
+// QuickPicStorageUserUnblockedIterator is returned from FilterUserUnblocked and is used to iterate over the raw logs and unpacked data for UserUnblocked events raised by the QuickPicStorage contract.
+type QuickPicStorageUserUnblockedIterator struct {
+	Event *QuickPicStorageUserUnblocked // Event containing the contract specifics and raw log
 
 	contract *bind.BoundContract // Generic contract to use for unpacking event data
 	event    string              // Event name to use for unpacking event data
@@ -2277,7 +6708,7 @@ type QuickPicStorageMessageDeletedIterator struct {
 // Next advances the iterator to the subsequent event, returning whether there
 // are any more events found. In case of a retrieval or parsing error, false is
 // returned and Error() can be queried for the exact failure.
-func (it *QuickPicStorageMessageDeletedIterator) Next() bool {
+func (it *QuickPicStorageUserUnblockedIterator) Next() bool {
 	// If the iterator failed, stop iterating
 	if it.fail != nil {
 		return false
@@ -2286,7 +6717,7 @@ func (it *QuickPicStorageMessageDeletedIterator) Next() bool {
 	if it.done {
 		select {
 		case log := <-it.logs:
-			it.Event = new(QuickPicStorageMessageDeleted)
+			it.Event = new(QuickPicStorageUserUnblocked)
 			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 				it.fail = err
 				return false
@@ -2301,7 +6732,7 @@ func (it *QuickPicStorageMessageDeletedIterator) Next() bool {
 	// Iterator still in progress, wait for either a data or an error event
 	select {
 	case log := <-it.logs:
-		it.Event = new(QuickPicStorageMessageDeleted)
+		it.Event = new(QuickPicStorageUserUnblocked)
 		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 			it.fail = err
 			return false
@@ -2317,51 +6748,60 @@ func (it *QuickPicStorageMessageDeletedIterator) Next() bool {
 }
 
 // Error returns any retrieval or parsing error occurred during filtering.
-func (it *QuickPicStorageMessageDeletedIterator) Error() error {
+func (it *QuickPicStorageUserUnblockedIterator) Error() error {
 	return it.fail
 }
 
 // Close terminates the iteration process, releasing any pending underlying
 // resources.
-func (it *QuickPicStorageMessageDeletedIterator) Close() error {
+func (it *QuickPicStorageUserUnblockedIterator) Close() error {
 	it.sub.Unsubscribe()
 	return nil
 }
 
-// QuickPicStorageMessageDeleted represents a MessageDeleted event raised by the QuickPicStorage contract.
-type QuickPicStorageMessageDeleted struct {
-	Id  [32]byte
-	Raw types.Log // Blockchain specific contextual infos
+// QuickPicStorageUserUnblocked represents a UserUnblocked event raised by the QuickPicStorage contract.
+type QuickPicStorageUserUnblocked struct {
+	BlockerId [32]byte
+	BlockedId [32]byte
+	Raw       types.Log // Blockchain specific contextual infos
 }
 
-// FilterMessageDeleted is a free log retrieval operation binding the contract event 0xf4ef3cbd1d2cff45998b1fb60a13a6505834c570a5a9a44f55c3a3c2b5c852fd.
+// FilterUserUnblocked is a free log retrieval operation binding the contract event 0xb42d4c6d254093fc5b24a8a2f06893a910ad4d1e92b36666179b8041b9a36017.
 //
-// Solidity: event MessageDeleted(bytes32 indexed id)
-func (_QuickPicStorage *QuickPicStorageFilterer) FilterMessageDeleted(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageMessageDeletedIterator, error) {
+// Solidity: event UserUnblocked(bytes32 indexed blockerId, bytes32 indexed blockedId)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterUserUnblocked(opts *bind.FilterOpts, blockerId [][32]byte, blockedId [][32]byte) (*QuickPicStorageUserUnblockedIterator, error) {
 
-	var idRule []interface{}
-	for _, idItem := range id {
-		idRule = append(idRule, idItem)
+	var blockerIdRule []interface{}
+	for _, blockerIdItem := range blockerId {
+		blockerIdRule = append(blockerIdRule, blockerIdItem)
+	}
+	var blockedIdRule []interface{}
+	for _, blockedIdItem := range blockedId {
+		blockedIdRule = append(blockedIdRule, blockedIdItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "MessageDeleted", idRule)
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "UserUnblocked", blockerIdRule, blockedIdRule)
 	if err != nil {
 		return nil, err
 	}
-	return &QuickPicStorageMessageDeletedIterator{contract: _QuickPicStorage.contract, event: "MessageDeleted", logs: logs, sub: sub}, nil
+	return &QuickPicStorageUserUnblockedIterator{contract: _QuickPicStorage.contract, event: "UserUnblocked", logs: logs, sub: sub}, nil
 }
 
-// WatchMessageDeleted is a free log subscription operation binding the contract event 0xf4ef3cbd1d2cff45998b1fb60a13a6505834c570a5a9a44f55c3a3c2b5c852fd.
+// WatchUserUnblocked is a free log subscription operation binding the contract event 0xb42d4c6d254093fc5b24a8a2f06893a910ad4d1e92b36666179b8041b9a36017.
 //
-// Solidity: event MessageDeleted(bytes32 indexed id)
-func (_QuickPicStorage *QuickPicStorageFilterer) WatchMessageDeleted(opts *bind.WatchOpts, sink chan<- *QuickPicStorageMessageDeleted, id [][32]byte) (event.Subscription, error) {
+// Solidity: event UserUnblocked(bytes32 indexed blockerId, bytes32 indexed blockedId)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserUnblocked(opts *bind.WatchOpts, sink chan<- *QuickPicStorageUserUnblocked, blockerId [][32]byte, blockedId [][32]byte) (event.Subscription, error) {
 
-	var idRule []interface{}
-	for _, idItem := range id {
-		idRule = append(idRule, idItem)
+	var blockerIdRule []interface{}
+	for _, blockerIdItem := range blockerId {
+		blockerIdRule = append(blockerIdRule, blockerIdItem)
+	}
+	var blockedIdRule []interface{}
+	for _, blockedIdItem := range blockedId {
+		blockedIdRule = append(blockedIdRule, blockedIdItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "MessageDeleted", idRule)
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "UserUnblocked", blockerIdRule, blockedIdRule)
 	if err != nil {
 		return nil, err
 	}
@@ -2371,8 +6811,8 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchMessageDeleted(opts *bind.
 			select {
 			case log := <-logs:
 				// New log arrived, parse the event and forward to the user
-				event := new(QuickPicStorageMessageDeleted)
-				if err := _QuickPicStorage.contract.UnpackLog(event, "MessageDeleted", log); err != nil {
+				event := new(QuickPicStorageUserUnblocked)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "UserUnblocked", log); err != nil {
 					return err
 				}
 				event.Raw = log
@@ -2393,21 +6833,21 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchMessageDeleted(opts *bind.
 	}), nil
 }
 
-// ParseMessageDeleted is a log parse operation binding the contract event 0xf4ef3cbd1d2cff45998b1fb60a13a6505834c570a5a9a44f55c3a3c2b5c852fd.
+// ParseUserUnblocked is a log parse operation binding the contract event 0xb42d4c6d254093fc5b24a8a2f06893a910ad4d1e92b36666179b8041b9a36017.
 //
-// Solidity: event MessageDeleted(bytes32 indexed id)
-func (_QuickPicStorage *QuickPicStorageFilterer) ParseMessageDeleted(log types.Log) (*QuickPicStorageMessageDeleted, error) {
-	event := new(QuickPicStorageMessageDeleted)
-	if err := _QuickPicStorage.contract.UnpackLog(event, "MessageDeleted", log); err != nil {
+// Solidity: event UserUnblocked(bytes32 indexed blockerId, bytes32 indexed blockedId)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseUserUnblocked(log types.Log) (*QuickPicStorageUserUnblocked, error) {
+	event := new(QuickPicStorageUserUnblocked)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "UserUnblocked", log); err != nil {
 		return nil, err
 	}
 	event.Raw = log
 	return event, nil
 }
 
-// QuickPicStorageUserCreatedIterator is returned from FilterUserCreated and is used to iterate over the raw logs and unpacked data for UserCreated events raised by the QuickPicStorage contract.
-type QuickPicStorageUserCreatedIterator struct {
-	Event *QuickPicStorageUserCreated // Event containing the contract specifics and raw log
+// QuickPicStorageUserUpdatedIterator is returned from FilterUserUpdated and is used to iterate over the raw logs and unpacked data for UserUpdated events raised by the QuickPicStorage contract.
+type QuickPicStorageUserUpdatedIterator struct {
+	Event *QuickPicStorageUserUpdated // Event containing the contract specifics and raw log
 
 	contract *bind.BoundContract // Generic contract to use for unpacking event data
 	event    string              // Event name to use for unpacking event data
@@ -2421,7 +6861,7 @@ type QuickPicStorageUserCreatedIterator struct {
 // Next advances the iterator to the subsequent event, returning whether there
 // are any more events found. In case of a retrieval or parsing error, false is
 // returned and Error() can be queried for the exact failure.
-func (it *QuickPicStorageUserCreatedIterator) Next() bool {
+func (it *QuickPicStorageUserUpdatedIterator) Next() bool {
 	// If the iterator failed, stop iterating
 	if it.fail != nil {
 		return false
@@ -2430,7 +6870,7 @@ func (it *QuickPicStorageUserCreatedIterator) Next() bool {
 	if it.done {
 		select {
 		case log := <-it.logs:
-			it.Event = new(QuickPicStorageUserCreated)
+			it.Event = new(QuickPicStorageUserUpdated)
 			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 				it.fail = err
 				return false
@@ -2445,7 +6885,7 @@ func (it *QuickPicStorageUserCreatedIterator) Next() bool {
 	// Iterator still in progress, wait for either a data or an error event
 	select {
 	case log := <-it.logs:
-		it.Event = new(QuickPicStorageUserCreated)
+		it.Event = new(QuickPicStorageUserUpdated)
 		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 			it.fail = err
 			return false
@@ -2461,53 +6901,51 @@ func (it *QuickPicStorageUserCreatedIterator) Next() bool {
 }
 
 // Error returns any retrieval or parsing error occurred during filtering.
-func (it *QuickPicStorageUserCreatedIterator) Error() error {
+func (it *QuickPicStorageUserUpdatedIterator) Error() error {
 	return it.fail
 }
 
 // Close terminates the iteration process, releasing any pending underlying
 // resources.
-func (it *QuickPicStorageUserCreatedIterator) Close() error {
+func (it *QuickPicStorageUserUpdatedIterator) Close() error {
 	it.sub.Unsubscribe()
 	return nil
 }
 
-// QuickPicStorageUserCreated represents a UserCreated event raised by the QuickPicStorage contract.
-type QuickPicStorageUserCreated struct {
-	Id         [32]byte
-	UserNumber *big.Int
-	Username   string
-	Raw        types.Log // Blockchain specific contextual infos
+// QuickPicStorageUserUpdated represents a UserUpdated event raised by the QuickPicStorage contract.
+type QuickPicStorageUserUpdated struct {
+	Id  [32]byte
+	Raw types.Log // Blockchain specific contextual infos
 }
 
-// FilterUserCreated is a free log retrieval operation binding the contract event 0xfa1f973aa4e6a4cd973d33775be141ea493df90f6cba4b68b4fcc7fe352522f0.
+// FilterUserUpdated is a free log retrieval operation binding the contract event 0xdf3a752736081f0b857124cd76b543d42ea93c426e4ae00b28927d4de82ee102.
 //
-// Solidity: event UserCreated(bytes32 indexed id, uint256 userNumber, string username)
-func (_QuickPicStorage *QuickPicStorageFilterer) FilterUserCreated(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageUserCreatedIterator, error) {
+// Solidity: event UserUpdated(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterUserUpdated(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageUserUpdatedIterator, error) {
 
 	var idRule []interface{}
 	for _, idItem := range id {
 		idRule = append(idRule, idItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "UserCreated", idRule)
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "UserUpdated", idRule)
 	if err != nil {
 		return nil, err
 	}
-	return &QuickPicStorageUserCreatedIterator{contract: _QuickPicStorage.contract, event: "UserCreated", logs: logs, sub: sub}, nil
+	return &QuickPicStorageUserUpdatedIterator{contract: _QuickPicStorage.contract, event: "UserUpdated", logs: logs, sub: sub}, nil
 }
 
-// WatchUserCreated is a free log subscription operation binding the contract event 0xfa1f973aa4e6a4cd973d33775be141ea493df90f6cba4b68b4fcc7fe352522f0.
+// WatchUserUpdated is a free log subscription operation binding the contract event 0xdf3a752736081f0b857124cd76b543d42ea93c426e4ae00b28927d4de82ee102.
 //
-// Solidity: event UserCreated(bytes32 indexed id, uint256 userNumber, string username)
-func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserCreated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageUserCreated, id [][32]byte) (event.Subscription, error) {
+// Solidity: event UserUpdated(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserUpdated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageUserUpdated, id [][32]byte) (event.Subscription, error) {
 
 	var idRule []interface{}
 	for _, idItem := range id {
 		idRule = append(idRule, idItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "UserCreated", idRule)
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "UserUpdated", idRule)
 	if err != nil {
 		return nil, err
 	}
@@ -2517,8 +6955,8 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserCreated(opts *bind.Wat
 			select {
 			case log := <-logs:
 				// New log arrived, parse the event and forward to the user
-				event := new(QuickPicStorageUserCreated)
-				if err := _QuickPicStorage.contract.UnpackLog(event, "UserCreated", log); err != nil {
+				event := new(QuickPicStorageUserUpdated)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "UserUpdated", log); err != nil {
 					return err
 				}
 				event.Raw = log
@@ -2539,21 +6977,21 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserCreated(opts *bind.Wat
 	}), nil
 }
 
-// ParseUserCreated is a log parse operation binding the contract event 0xfa1f973aa4e6a4cd973d33775be141ea493df90f6cba4b68b4fcc7fe352522f0.
+// ParseUserUpdated is a log parse operation binding the contract event 0xdf3a752736081f0b857124cd76b543d42ea93c426e4ae00b28927d4de82ee102.
 //
-// Solidity: event UserCreated(bytes32 indexed id, uint256 userNumber, string username)
-func (_QuickPicStorage *QuickPicStorageFilterer) ParseUserCreated(log types.Log) (*QuickPicStorageUserCreated, error) {
-	event := new(QuickPicStorageUserCreated)
-	if err := _QuickPicStorage.contract.UnpackLog(event, "UserCreated", log); err != nil {
+// Solidity: event UserUpdated(bytes32 indexed id)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseUserUpdated(log types.Log) (*QuickPicStorageUserUpdated, error) {
+	event := new(QuickPicStorageUserUpdated)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "UserUpdated", log); err != nil {
 		return nil, err
 	}
 	event.Raw = log
 	return event, nil
 }
 
-// QuickPicStorageUserUpdatedIterator is returned from FilterUserUpdated and is used to iterate over the raw logs and unpacked data for UserUpdated events raised by the QuickPicStorage contract.
-type QuickPicStorageUserUpdatedIterator struct {
-	Event *QuickPicStorageUserUpdated // Event containing the contract specifics and raw log
+// QuickPicStorageUsernameChangedIterator is returned from FilterUsernameChanged and is used to iterate over the raw logs and unpacked data for UsernameChanged events raised by the QuickPicStorage contract.
+type QuickPicStorageUsernameChangedIterator struct {
+	Event *QuickPicStorageUsernameChanged // Event containing the contract specifics and raw log
 
 	contract *bind.BoundContract // Generic contract to use for unpacking event data
 	event    string              // Event name to use for unpacking event data
@@ -2567,7 +7005,7 @@ type QuickPicStorageUserUpdatedIterator struct {
 // Next advances the iterator to the subsequent event, returning whether there
 // are any more events found. In case of a retrieval or parsing error, false is
 // returned and Error() can be queried for the exact failure.
-func (it *QuickPicStorageUserUpdatedIterator) Next() bool {
+func (it *QuickPicStorageUsernameChangedIterator) Next() bool {
 	// If the iterator failed, stop iterating
 	if it.fail != nil {
 		return false
@@ -2576,7 +7014,7 @@ func (it *QuickPicStorageUserUpdatedIterator) Next() bool {
 	if it.done {
 		select {
 		case log := <-it.logs:
-			it.Event = new(QuickPicStorageUserUpdated)
+			it.Event = new(QuickPicStorageUsernameChanged)
 			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 				it.fail = err
 				return false
@@ -2591,7 +7029,7 @@ func (it *QuickPicStorageUserUpdatedIterator) Next() bool {
 	// Iterator still in progress, wait for either a data or an error event
 	select {
 	case log := <-it.logs:
-		it.Event = new(QuickPicStorageUserUpdated)
+		it.Event = new(QuickPicStorageUsernameChanged)
 		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 			it.fail = err
 			return false
@@ -2607,51 +7045,53 @@ func (it *QuickPicStorageUserUpdatedIterator) Next() bool {
 }
 
 // Error returns any retrieval or parsing error occurred during filtering.
-func (it *QuickPicStorageUserUpdatedIterator) Error() error {
+func (it *QuickPicStorageUsernameChangedIterator) Error() error {
 	return it.fail
 }
 
 // Close terminates the iteration process, releasing any pending underlying
 // resources.
-func (it *QuickPicStorageUserUpdatedIterator) Close() error {
+func (it *QuickPicStorageUsernameChangedIterator) Close() error {
 	it.sub.Unsubscribe()
 	return nil
 }
 
-// QuickPicStorageUserUpdated represents a UserUpdated event raised by the QuickPicStorage contract.
-type QuickPicStorageUserUpdated struct {
-	Id  [32]byte
-	Raw types.Log // Blockchain specific contextual infos
+// QuickPicStorageUsernameChanged represents a UsernameChanged event raised by the QuickPicStorage contract.
+type QuickPicStorageUsernameChanged struct {
+	Id          [32]byte
+	OldUsername string
+	NewUsername string
+	Raw         types.Log // Blockchain specific contextual infos
 }
 
-// FilterUserUpdated is a free log retrieval operation binding the contract event 0xdf3a752736081f0b857124cd76b543d42ea93c426e4ae00b28927d4de82ee102.
+// FilterUsernameChanged is a free log retrieval operation binding the contract event 0x53831ff22b6a07cac4b8d8b43b3a236654b3d3ff444eeb2acc53555e814ae9b6.
 //
-// Solidity: event UserUpdated(bytes32 indexed id)
-func (_QuickPicStorage *QuickPicStorageFilterer) FilterUserUpdated(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageUserUpdatedIterator, error) {
+// Solidity: event UsernameChanged(bytes32 indexed id, string oldUsername, string newUsername)
+func (_QuickPicStorage *QuickPicStorageFilterer) FilterUsernameChanged(opts *bind.FilterOpts, id [][32]byte) (*QuickPicStorageUsernameChangedIterator, error) {
 
 	var idRule []interface{}
 	for _, idItem := range id {
 		idRule = append(idRule, idItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "UserUpdated", idRule)
+	logs, sub, err := _QuickPicStorage.contract.FilterLogs(opts, "UsernameChanged", idRule)
 	if err != nil {
 		return nil, err
 	}
-	return &QuickPicStorageUserUpdatedIterator{contract: _QuickPicStorage.contract, event: "UserUpdated", logs: logs, sub: sub}, nil
+	return &QuickPicStorageUsernameChangedIterator{contract: _QuickPicStorage.contract, event: "UsernameChanged", logs: logs, sub: sub}, nil
 }
 
-// WatchUserUpdated is a free log subscription operation binding the contract event 0xdf3a752736081f0b857124cd76b543d42ea93c426e4ae00b28927d4de82ee102.
+// WatchUsernameChanged is a free log subscription operation binding the contract event 0x53831ff22b6a07cac4b8d8b43b3a236654b3d3ff444eeb2acc53555e814ae9b6.
 //
-// Solidity: event UserUpdated(bytes32 indexed id)
-func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserUpdated(opts *bind.WatchOpts, sink chan<- *QuickPicStorageUserUpdated, id [][32]byte) (event.Subscription, error) {
+// Solidity: event UsernameChanged(bytes32 indexed id, string oldUsername, string newUsername)
+func (_QuickPicStorage *QuickPicStorageFilterer) WatchUsernameChanged(opts *bind.WatchOpts, sink chan<- *QuickPicStorageUsernameChanged, id [][32]byte) (event.Subscription, error) {
 
 	var idRule []interface{}
 	for _, idItem := range id {
 		idRule = append(idRule, idItem)
 	}
 
-	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "UserUpdated", idRule)
+	logs, sub, err := _QuickPicStorage.contract.WatchLogs(opts, "UsernameChanged", idRule)
 	if err != nil {
 		return nil, err
 	}
@@ -2661,8 +7101,8 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserUpdated(opts *bind.Wat
 			select {
 			case log := <-logs:
 				// New log arrived, parse the event and forward to the user
-				event := new(QuickPicStorageUserUpdated)
-				if err := _QuickPicStorage.contract.UnpackLog(event, "UserUpdated", log); err != nil {
+				event := new(QuickPicStorageUsernameChanged)
+				if err := _QuickPicStorage.contract.UnpackLog(event, "UsernameChanged", log); err != nil {
 					return err
 				}
 				event.Raw = log
@@ -2683,12 +7123,12 @@ func (_QuickPicStorage *QuickPicStorageFilterer) WatchUserUpdated(opts *bind.Wat
 	}), nil
 }
 
-// ParseUserUpdated is a log parse operation binding the contract event 0xdf3a752736081f0b857124cd76b543d42ea93c426e4ae00b28927d4de82ee102.
+// ParseUsernameChanged is a log parse operation binding the contract event 0x53831ff22b6a07cac4b8d8b43b3a236654b3d3ff444eeb2acc53555e814ae9b6.
 //
-// Solidity: event UserUpdated(bytes32 indexed id)
-func (_QuickPicStorage *QuickPicStorageFilterer) ParseUserUpdated(log types.Log) (*QuickPicStorageUserUpdated, error) {
-	event := new(QuickPicStorageUserUpdated)
-	if err := _QuickPicStorage.contract.UnpackLog(event, "UserUpdated", log); err != nil {
+// Solidity: event UsernameChanged(bytes32 indexed id, string oldUsername, string newUsername)
+func (_QuickPicStorage *QuickPicStorageFilterer) ParseUsernameChanged(log types.Log) (*QuickPicStorageUsernameChanged, error) {
+	event := new(QuickPicStorageUsernameChanged)
+	if err := _QuickPicStorage.contract.UnpackLog(event, "UsernameChanged", log); err != nil {
 		return nil, err
 	}
 	event.Raw = log
@@ -0,0 +1,164 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/google/uuid"
+)
+
+// friendGraph is a local index of the contract's friendships. It is built
+// from FriendshipCreated and FriendshipRemoved events and caught up before
+// each read, so graph queries don't need a contract call per friend. Each
+// friendship maps to when it was created, the time of its event's block.
+type friendGraph struct {
+	mu        sync.Mutex
+	nextBlock uint64
+	friends   map[uuid.UUID]map[uuid.UUID]time.Time
+}
+
+type friendshipEvent struct {
+	userA, userB uuid.UUID
+	created      bool
+	block        uint64
+	index        uint
+}
+
+func newFriendGraph() *friendGraph {
+	return &friendGraph{friends: make(map[uuid.UUID]map[uuid.UUID]time.Time)}
+}
+
+// friendsOf returns the user's friends and when each friendship was created
+func (g *friendGraph) friendsOf(ctx context.Context, b *Backend, userID uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.sync(ctx, b); err != nil {
+		return nil, err
+	}
+
+	friends := make(map[uuid.UUID]time.Time, len(g.friends[userID]))
+	for friendID, since := range g.friends[userID] {
+		friends[friendID] = since
+	}
+	return friends, nil
+}
+
+// mutualFriends counts, for each friend of a friend of userID, how many
+// friends they share with userID. userID and their friends are left out.
+func (g *friendGraph) mutualFriends(ctx context.Context, b *Backend, userID uuid.UUID) (map[uuid.UUID]int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.sync(ctx, b); err != nil {
+		return nil, err
+	}
+
+	mine := g.friends[userID]
+	counts := make(map[uuid.UUID]int)
+	for friendID := range mine {
+		for candidateID := range g.friends[friendID] {
+			if candidateID == userID {
+				continue
+			}
+			if _, isFriend := mine[candidateID]; isFriend {
+				continue
+			}
+			counts[candidateID]++
+		}
+	}
+
+	return counts, nil
+}
+
+// sync applies the friendship events since the last call, in chain order.
+// The caller holds g.mu.
+func (g *friendGraph) sync(ctx context.Context, b *Backend) error {
+	head, err := b.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head < g.nextBlock {
+		return nil
+	}
+
+	opts := &bind.FilterOpts{Start: g.nextBlock, End: &head, Context: ctx}
+	var events []friendshipEvent
+
+	created, err := b.contract.FilterFriendshipCreated(opts, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	for created.Next() {
+		e := created.Event
+		events = append(events, friendshipEvent{
+			userA: bytes32ToUUID(e.UserAId), userB: bytes32ToUUID(e.UserBId), created: true,
+			block: e.Raw.BlockNumber, index: e.Raw.Index,
+		})
+	}
+	if err := created.Error(); err != nil {
+		return err
+	}
+	_ = created.Close()
+
+	removed, err := b.contract.FilterFriendshipRemoved(opts, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	for removed.Next() {
+		e := removed.Event
+		events = append(events, friendshipEvent{
+			userA: bytes32ToUUID(e.UserAId), userB: bytes32ToUUID(e.UserBId),
+			block: e.Raw.BlockNumber, index: e.Raw.Index,
+		})
+	}
+	if err := removed.Error(); err != nil {
+		return err
+	}
+	_ = removed.Close()
+
+	// A pair can be befriended again after an unfriend, so order matters
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].block != events[j].block {
+			return events[i].block < events[j].block
+		}
+		return events[i].index < events[j].index
+	})
+
+	// Friendships are stamped with their block's time, as the contract does
+	blockTimes := make(map[uint64]time.Time)
+	for _, e := range events {
+		if _, ok := blockTimes[e.block]; !e.created || ok {
+			continue
+		}
+		header, err := b.client.HeaderByNumber(ctx, new(big.Int).SetUint64(e.block))
+		if err != nil {
+			return err
+		}
+		blockTimes[e.block] = time.Unix(int64(header.Time), 0)
+	}
+
+	for _, e := range events {
+		if e.created {
+			g.link(e.userA, e.userB, blockTimes[e.block])
+			g.link(e.userB, e.userA, blockTimes[e.block])
+		} else {
+			delete(g.friends[e.userA], e.userB)
+			delete(g.friends[e.userB], e.userA)
+		}
+	}
+
+	g.nextBlock = head + 1
+	return nil
+}
+
+func (g *friendGraph) link(userID, friendID uuid.UUID, since time.Time) {
+	if g.friends[userID] == nil {
+		g.friends[userID] = make(map[uuid.UUID]time.Time)
+	}
+	g.friends[userID][friendID] = since
+}
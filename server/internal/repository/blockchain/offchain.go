@@ -0,0 +1,186 @@
+package blockchain
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openOffchainStore opens the SQLite database that holds what the contract
+// doesn't: friend settings, which are private to each side, streaks and
+// interaction counters, which change on every message, and reports and the
+// audit log, which may hold decrypted content. Users live on-chain, so rows
+// refer to them by plain ID without foreign keys.
+func openOffchainStore(path string) (*sql.DB, error) {
+	// As in the SQLite backend, wait for the write lock and take it when a
+	// transaction begins
+	dataSourceName := path
+	if !strings.Contains(dataSourceName, "?") {
+		dataSourceName += "?"
+	} else {
+		dataSourceName += "&"
+	}
+	dataSourceName += "_busy_timeout=5000&_txlock=immediate"
+
+	db, err := sql.Open("sqlite3", dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open off-chain database: %w", err)
+	}
+
+	// Every connection to ":memory:" gets its own empty database
+	if strings.HasPrefix(path, ":memory:") {
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := migrateOffchainStore(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to migrate off-chain database: %w", err)
+	}
+
+	return db, nil
+}
+
+func migrateOffchainStore(db *sql.DB) error {
+	migrations := []string{
+		`CREATE TABLE IF NOT EXISTS friend_settings (
+			user_id TEXT NOT NULL,
+			friend_id TEXT NOT NULL,
+			nickname TEXT NOT NULL DEFAULT '',
+			muted INTEGER NOT NULL DEFAULT 0,
+			pinned INTEGER NOT NULL DEFAULT 0,
+			message_ttl INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (user_id, friend_id)
+		)`,
+		// a_sent_day and b_sent_day are the last days each side sent a message
+		`CREATE TABLE IF NOT EXISTS streaks (
+			user_a_id TEXT NOT NULL,
+			user_b_id TEXT NOT NULL,
+			a_sent_day INTEGER,
+			b_sent_day INTEGER,
+			count INTEGER NOT NULL DEFAULT 0,
+			last_day INTEGER,
+			PRIMARY KEY (user_a_id, user_b_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS interactions (
+			user_id TEXT NOT NULL,
+			friend_id TEXT NOT NULL,
+			score REAL NOT NULL,
+			scored_at DATETIME NOT NULL,
+			last_at DATETIME NOT NULL,
+			PRIMARY KEY (user_id, friend_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS reports (
+			id TEXT PRIMARY KEY,
+			reporter_id TEXT NOT NULL,
+			reported_user_id TEXT NOT NULL,
+			message_id TEXT,
+			reason TEXT NOT NULL,
+			comment TEXT NOT NULL DEFAULT '',
+			content BLOB,
+			content_type TEXT NOT NULL DEFAULT '',
+			encrypted_content BLOB,
+			signature TEXT NOT NULL DEFAULT '',
+			verified INTEGER NOT NULL DEFAULT 0,
+			sender_key_version INTEGER NOT NULL DEFAULT 0,
+			status TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			UNIQUE(reporter_id, message_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status, created_at)`,
+		`CREATE TABLE IF NOT EXISTS report_events (
+			report_id TEXT NOT NULL,
+			actor_id TEXT NOT NULL,
+			from_status TEXT NOT NULL DEFAULT '',
+			to_status TEXT NOT NULL,
+			note TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_report_events_report ON report_events(report_id)`,
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id TEXT PRIMARY KEY,
+			actor_id TEXT NOT NULL,
+			action TEXT NOT NULL,
+			target_id TEXT NOT NULL,
+			details TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target_id, created_at)`,
+	}
+
+	for _, migration := range migrations {
+		if _, err := db.Exec(migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// forgetFriendship drops both sides' settings and interactions and the
+// streak for a pair. The contract clears the pair's verifications and
+// avatar keys when it removes the friendship.
+func (b *Backend) forgetFriendship(ctx context.Context, userAID, userBID uuid.UUID) error {
+	// Ensure user_a_id < user_b_id
+	if userAID.String() > userBID.String() {
+		userAID, userBID = userBID, userAID
+	}
+	userA, userB := userAID.String(), userBID.String()
+
+	tx, err := b.offchain.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	settingsQuery := `
+		DELETE FROM friend_settings
+		WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, settingsQuery, userA, userB, userB, userA); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM streaks WHERE user_a_id = ? AND user_b_id = ?`, userA, userB); err != nil {
+		return err
+	}
+
+	interactionQuery := `
+		DELETE FROM interactions
+		WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, interactionQuery, userA, userB, userB, userA); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// forgetUser drops the off-chain state of a deleted user's friendships.
+// Reports and the audit log keep plain user IDs so they outlive the account.
+func (b *Backend) forgetUser(ctx context.Context, userID uuid.UUID) error {
+	id := userID.String()
+
+	tx, err := b.offchain.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	queries := []string{
+		`DELETE FROM friend_settings WHERE user_id = ? OR friend_id = ?`,
+		`DELETE FROM streaks WHERE user_a_id = ? OR user_b_id = ?`,
+		`DELETE FROM interactions WHERE user_id = ? OR friend_id = ?`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, id, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,217 @@
+package blockchain
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/google/uuid"
+)
+
+// userIndex is a local, sorted index of usernames for prefix search, plus a
+// map of user numbers for lookups. Like friendGraph it is built from contract
+// events, renames included, and caught up before each read.
+type userIndex struct {
+	mu        sync.Mutex
+	nextBlock uint64
+	usernames []string // sorted
+	ids       map[string]uuid.UUID
+	names     map[uuid.UUID]string
+	hidden    map[uuid.UUID]bool // not discoverable in search
+	byNumber  map[int64]uuid.UUID
+	numbers   map[uuid.UUID]int64
+}
+
+type userEvent struct {
+	id       uuid.UUID
+	username string // set for UserCreated and UsernameChanged
+	number   int64  // set for UserCreated
+	renamed  bool
+	deleted  bool
+	flags    uint64 // set for PrivacyFlagsUpdated
+	isFlags  bool
+	block    uint64
+	index    uint
+}
+
+func newUserIndex() *userIndex {
+	return &userIndex{
+		ids:      make(map[string]uuid.UUID),
+		names:    make(map[uuid.UUID]string),
+		hidden:   make(map[uuid.UUID]bool),
+		byNumber: make(map[int64]uuid.UUID),
+		numbers:  make(map[uuid.UUID]int64),
+	}
+}
+
+// search returns the discoverable users whose username starts with prefix and
+// sorts after the given username, in username order
+func (x *userIndex) search(ctx context.Context, b *Backend, prefix, after string) ([]uuid.UUID, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if err := x.sync(ctx, b); err != nil {
+		return nil, err
+	}
+
+	start := sort.SearchStrings(x.usernames, prefix)
+	if after != "" {
+		start = max(start, sort.Search(len(x.usernames), func(i int) bool { return x.usernames[i] > after }))
+	}
+
+	var ids []uuid.UUID
+	for _, username := range x.usernames[start:] {
+		if !strings.HasPrefix(username, prefix) {
+			break
+		}
+		id := x.ids[username]
+		if !x.hidden[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// lookupNumber returns the ID of the user with the given user number
+func (x *userIndex) lookupNumber(ctx context.Context, b *Backend, userNumber int64) (uuid.UUID, bool, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if err := x.sync(ctx, b); err != nil {
+		return uuid.Nil, false, err
+	}
+
+	id, ok := x.byNumber[userNumber]
+	return id, ok, nil
+}
+
+// sync applies the user events since the last call, in chain order. The
+// caller holds x.mu.
+func (x *userIndex) sync(ctx context.Context, b *Backend) error {
+	head, err := b.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head < x.nextBlock {
+		return nil
+	}
+
+	opts := &bind.FilterOpts{Start: x.nextBlock, End: &head, Context: ctx}
+	var events []userEvent
+
+	created, err := b.contract.FilterUserCreated(opts, nil)
+	if err != nil {
+		return err
+	}
+	for created.Next() {
+		e := created.Event
+		events = append(events, userEvent{
+			id: bytes32ToUUID(e.Id), username: e.Username, number: e.UserNumber.Int64(),
+			block: e.Raw.BlockNumber, index: e.Raw.Index,
+		})
+	}
+	if err := created.Error(); err != nil {
+		return err
+	}
+	_ = created.Close()
+
+	deleted, err := b.contract.FilterUserDeleted(opts, nil)
+	if err != nil {
+		return err
+	}
+	for deleted.Next() {
+		e := deleted.Event
+		events = append(events, userEvent{
+			id: bytes32ToUUID(e.Id), deleted: true,
+			block: e.Raw.BlockNumber, index: e.Raw.Index,
+		})
+	}
+	if err := deleted.Error(); err != nil {
+		return err
+	}
+	_ = deleted.Close()
+
+	renamed, err := b.contract.FilterUsernameChanged(opts, nil)
+	if err != nil {
+		return err
+	}
+	for renamed.Next() {
+		e := renamed.Event
+		events = append(events, userEvent{
+			id: bytes32ToUUID(e.Id), username: e.NewUsername, renamed: true,
+			block: e.Raw.BlockNumber, index: e.Raw.Index,
+		})
+	}
+	if err := renamed.Error(); err != nil {
+		return err
+	}
+	_ = renamed.Close()
+
+	flags, err := b.contract.FilterPrivacyFlagsUpdated(opts, nil)
+	if err != nil {
+		return err
+	}
+	for flags.Next() {
+		e := flags.Event
+		events = append(events, userEvent{
+			id: bytes32ToUUID(e.UserId), flags: e.Flags.Uint64(), isFlags: true,
+			block: e.Raw.BlockNumber, index: e.Raw.Index,
+		})
+	}
+	if err := flags.Error(); err != nil {
+		return err
+	}
+	_ = flags.Close()
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].block != events[j].block {
+			return events[i].block < events[j].block
+		}
+		return events[i].index < events[j].index
+	})
+	for _, e := range events {
+		switch {
+		case e.isFlags:
+			x.hidden[e.id] = e.flags&privacyHideFromSearch != 0
+		case e.deleted:
+			x.remove(e.id)
+			delete(x.hidden, e.id)
+			delete(x.byNumber, x.numbers[e.id])
+			delete(x.numbers, e.id)
+		case e.renamed:
+			x.remove(e.id)
+			x.add(e.id, e.username)
+		default:
+			x.add(e.id, e.username)
+			x.byNumber[e.number] = e.id
+			x.numbers[e.id] = e.number
+		}
+	}
+
+	x.nextBlock = head + 1
+	return nil
+}
+
+func (x *userIndex) add(id uuid.UUID, username string) {
+	i := sort.SearchStrings(x.usernames, username)
+	x.usernames = append(x.usernames, "")
+	copy(x.usernames[i+1:], x.usernames[i:])
+	x.usernames[i] = username
+	x.ids[username] = id
+	x.names[id] = username
+}
+
+func (x *userIndex) remove(id uuid.UUID) {
+	username, ok := x.names[id]
+	if !ok {
+		return
+	}
+	if i := sort.SearchStrings(x.usernames, username); i < len(x.usernames) && x.usernames[i] == username {
+		x.usernames = append(x.usernames[:i], x.usernames[i+1:]...)
+	}
+	delete(x.ids, username)
+	delete(x.names, id)
+}
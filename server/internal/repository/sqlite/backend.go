@@ -11,25 +11,55 @@ import (
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/username"
 )
 
 // Backend implements repository.Backend for SQLite storage
 type Backend struct {
-	db       *sql.DB
-	users    *UserRepository
-	friends  *FriendRepository
-	messages *MessageRepository
+	db            *sql.DB
+	users         *UserRepository
+	friends       *FriendRepository
+	blocks        *BlockRepository
+	invites       *InviteRepository
+	messages      *MessageRepository
+	devices       *DeviceRepository
+	prekeys       *PreKeyRepository
+	notifications *NotificationRepository
+	profiles      *ProfileRepository
+	streaks       *StreakRepository
+	interactions  *InteractionRepository
+	reports       *ReportRepository
+	stats         *StatsRepository
+	audit         *AuditRepository
 }
 
 // NewBackend creates a new SQLite backend
 // Use ":memory:" for in-memory database (testing)
 // Use a file path like "./quickpic.db" for persistent storage
 func NewBackend(dataSourceName string) (*Backend, error) {
+	// PRAGMA foreign_keys is per-connection, so also request it in the DSN to
+	// make sure every pooled connection enforces ON DELETE CASCADE
+	dataSourceName = withDSNParam(dataSourceName, "_foreign_keys=on", "_foreign_keys", "_fk")
+
+	// Pooled connections to a database file contend for its write lock. Wait
+	// for it rather than failing with "database is locked", and take it when
+	// a transaction begins: a transaction that reads before writing could
+	// otherwise deadlock with another writer, which SQLite breaks by failing
+	// one of them without waiting.
+	dataSourceName = withDSNParam(dataSourceName, "_busy_timeout=5000", "_busy_timeout", "_timeout")
+	dataSourceName = withDSNParam(dataSourceName, "_txlock=immediate", "_txlock")
+
 	db, err := sql.Open("sqlite3", dataSourceName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	// Every connection to ":memory:" gets its own empty database, so keep the
+	// pool to a single connection for concurrent requests to see the same data
+	if strings.HasPrefix(dataSourceName, ":memory:") {
+		db.SetMaxOpenConns(1)
+	}
+
 	// Enable foreign keys
 	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
 		_ = db.Close()
@@ -46,7 +76,18 @@ func NewBackend(dataSourceName string) (*Backend, error) {
 	}
 	backend.users = &UserRepository{db: db}
 	backend.friends = &FriendRepository{db: db}
+	backend.blocks = &BlockRepository{db: db}
+	backend.invites = &InviteRepository{db: db}
 	backend.messages = &MessageRepository{db: db}
+	backend.devices = &DeviceRepository{db: db}
+	backend.prekeys = &PreKeyRepository{db: db}
+	backend.notifications = &NotificationRepository{db: db}
+	backend.profiles = &ProfileRepository{db: db}
+	backend.streaks = &StreakRepository{db: db}
+	backend.interactions = &InteractionRepository{db: db}
+	backend.reports = &ReportRepository{db: db}
+	backend.stats = &StatsRepository{db: db}
+	backend.audit = &AuditRepository{db: db}
 
 	// Run migrations
 	if err := backend.migrate(); err != nil {
@@ -57,6 +98,21 @@ func NewBackend(dataSourceName string) (*Backend, error) {
 	return backend, nil
 }
 
+// withDSNParam appends param to the DSN unless one of keys is already set
+func withDSNParam(dataSourceName, param string, keys ...string) string {
+	for _, key := range keys {
+		if strings.Contains(dataSourceName, key+"=") {
+			return dataSourceName
+		}
+	}
+
+	separator := "?"
+	if strings.Contains(dataSourceName, "?") {
+		separator = "&"
+	}
+	return dataSourceName + separator + param
+}
+
 func (b *Backend) migrate() error {
 	migrations := []string{
 		`CREATE TABLE IF NOT EXISTS users (
@@ -86,6 +142,7 @@ func (b *Backend) migrate() error {
 			UNIQUE(from_user_id, to_user_id)
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_friend_requests_to_user ON friend_requests(to_user_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_friend_requests_status ON friend_requests(status, created_at)`,
 		`CREATE TABLE IF NOT EXISTS friendships (
 			id TEXT PRIMARY KEY,
 			user_a_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
@@ -96,6 +153,84 @@ func (b *Backend) migrate() error {
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_friendships_user_a ON friendships(user_a_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_friendships_user_b ON friendships(user_b_id)`,
+		`CREATE TABLE IF NOT EXISTS blocks (
+			blocker_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			blocked_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			created_at DATETIME DEFAULT (datetime('now')),
+			PRIMARY KEY (blocker_id, blocked_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS friend_verifications (
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			friend_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			key_version INTEGER NOT NULL,
+			PRIMARY KEY (user_id, friend_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS friend_settings (
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			friend_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			nickname TEXT NOT NULL DEFAULT '',
+			muted INTEGER NOT NULL DEFAULT 0,
+			pinned INTEGER NOT NULL DEFAULT 0,
+			message_ttl INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (user_id, friend_id)
+		)`,
+		// a_sent_day and b_sent_day are the last days each side sent a message
+		`CREATE TABLE IF NOT EXISTS streaks (
+			user_a_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			user_b_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			a_sent_day INTEGER,
+			b_sent_day INTEGER,
+			count INTEGER NOT NULL DEFAULT 0,
+			last_day INTEGER,
+			PRIMARY KEY (user_a_id, user_b_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS interactions (
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			friend_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			score REAL NOT NULL,
+			scored_at DATETIME NOT NULL,
+			last_at DATETIME NOT NULL,
+			PRIMARY KEY (user_id, friend_id)
+		)`,
+		// Reports keep plain user IDs, like audit_log, to outlive the accounts
+		`CREATE TABLE IF NOT EXISTS reports (
+			id TEXT PRIMARY KEY,
+			reporter_id TEXT NOT NULL,
+			reported_user_id TEXT NOT NULL,
+			message_id TEXT,
+			reason TEXT NOT NULL,
+			comment TEXT NOT NULL DEFAULT '',
+			content BLOB,
+			content_type TEXT NOT NULL DEFAULT '',
+			encrypted_content BLOB,
+			signature TEXT NOT NULL DEFAULT '',
+			verified INTEGER NOT NULL DEFAULT 0,
+			sender_key_version INTEGER NOT NULL DEFAULT 0,
+			status TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			UNIQUE(reporter_id, message_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status, created_at)`,
+		`CREATE TABLE IF NOT EXISTS report_events (
+			report_id TEXT NOT NULL REFERENCES reports(id) ON DELETE CASCADE,
+			actor_id TEXT NOT NULL,
+			from_status TEXT NOT NULL DEFAULT '',
+			to_status TEXT NOT NULL,
+			note TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_report_events_report ON report_events(report_id)`,
+		`CREATE TABLE IF NOT EXISTS invites (
+			id TEXT PRIMARY KEY,
+			creator_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			max_uses INTEGER NOT NULL,
+			uses INTEGER NOT NULL DEFAULT 0,
+			expires_at DATETIME NOT NULL,
+			revoked INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME DEFAULT (datetime('now'))
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_invites_creator ON invites(creator_id)`,
 		`CREATE TABLE IF NOT EXISTS messages (
 			id TEXT PRIMARY KEY,
 			from_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
@@ -106,6 +241,87 @@ func (b *Backend) migrate() error {
 			created_at DATETIME DEFAULT (datetime('now'))
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_messages_to_user ON messages(to_user_id, created_at)`,
+		`CREATE TABLE IF NOT EXISTS notifications (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			type TEXT NOT NULL,
+			actor_id TEXT NOT NULL,
+			actor_username TEXT NOT NULL,
+			created_at DATETIME DEFAULT (datetime('now'))
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, created_at)`,
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id TEXT PRIMARY KEY,
+			actor_id TEXT NOT NULL,
+			action TEXT NOT NULL,
+			target_id TEXT NOT NULL,
+			details TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT (datetime('now'))
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target_id, created_at)`,
+		`CREATE TABLE IF NOT EXISTS public_key_history (
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			version INTEGER NOT NULL,
+			public_key TEXT NOT NULL,
+			valid_from DATETIME NOT NULL,
+			valid_until DATETIME,
+			PRIMARY KEY (user_id, version)
+		)`,
+		`CREATE TABLE IF NOT EXISTS devices (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			public_key TEXT NOT NULL,
+			signature TEXT NOT NULL,
+			created_at DATETIME DEFAULT (datetime('now')),
+			UNIQUE(user_id, public_key)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_devices_user ON devices(user_id)`,
+		`CREATE TABLE IF NOT EXISTS message_deliveries (
+			message_id TEXT NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+			device_id TEXT NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+			encrypted_content BLOB NOT NULL,
+			PRIMARY KEY (message_id, device_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_message_deliveries_device ON message_deliveries(device_id)`,
+		`CREATE TABLE IF NOT EXISTS signed_prekeys (
+			user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			key_id INTEGER NOT NULL,
+			public_key TEXT NOT NULL,
+			signature TEXT NOT NULL,
+			identity_key_version INTEGER NOT NULL,
+			created_at DATETIME DEFAULT (datetime('now'))
+		)`,
+		`CREATE TABLE IF NOT EXISTS one_time_prekeys (
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			key_id INTEGER NOT NULL,
+			public_key TEXT NOT NULL,
+			created_at DATETIME DEFAULT (datetime('now')),
+			PRIMARY KEY (user_id, key_id)
+		)`,
+		// Old usernames held for the user who renamed away from them, keyed
+		// by skeleton so lookalikes are held too. user_id is not a foreign
+		// key: the hold outlasts an account deleted right after a rename.
+		`CREATE TABLE IF NOT EXISTS username_reservations (
+			skeleton TEXT PRIMARY KEY,
+			username TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			reserved_until DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS avatars (
+			user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			id TEXT NOT NULL,
+			data BLOB NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		// avatar_id ties each wrapped key to the avatar it was made for
+		`CREATE TABLE IF NOT EXISTS avatar_keys (
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			viewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			avatar_id TEXT NOT NULL,
+			wrapped_key TEXT NOT NULL,
+			PRIMARY KEY (user_id, viewer_id)
+		)`,
 	}
 
 	for _, migration := range migrations {
@@ -114,6 +330,80 @@ func (b *Backend) migrate() error {
 		}
 	}
 
+	// Columns added after the initial schema. SQLite has no ADD COLUMN IF NOT
+	// EXISTS, so a duplicate column error means the column is already there.
+	columns := []string{
+		`ALTER TABLE users ADD COLUMN key_version INTEGER NOT NULL DEFAULT 1`,
+		`ALTER TABLE messages ADD COLUMN recipient_key_version INTEGER NOT NULL DEFAULT 1`,
+		// fan_out marks messages stored as per-device ciphertexts in message_deliveries
+		`ALTER TABLE messages ADD COLUMN fan_out INTEGER NOT NULL DEFAULT 0`,
+		// updated_at is when a friend request was last answered; NULL means never
+		`ALTER TABLE friend_requests ADD COLUMN updated_at DATETIME`,
+		`ALTER TABLE users ADD COLUMN hide_from_suggestions INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE users ADD COLUMN hide_from_search INTEGER NOT NULL DEFAULT 0`,
+		// username_skeleton is the confusable skeleton, unique so lookalikes can't register
+		`ALTER TABLE users ADD COLUMN username_skeleton TEXT`,
+		`ALTER TABLE users ADD COLUMN display_name TEXT NOT NULL DEFAULT ''`,
+		// suspended_at is when an operator suspended the user; NULL means active
+		`ALTER TABLE users ADD COLUMN suspended_at DATETIME`,
+	}
+
+	for _, column := range columns {
+		if _, err := b.db.Exec(column); err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+	}
+
+	// Give users created before key history existed their initial entry
+	backfill := `
+		INSERT OR IGNORE INTO public_key_history (user_id, version, public_key, valid_from)
+		SELECT id, key_version, public_key, created_at FROM users
+	`
+	if _, err := b.db.Exec(backfill); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	if _, err := b.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_skeleton ON users(username_skeleton)`); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+	if err := b.backfillUsernameSkeletons(); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	return nil
+}
+
+// backfillUsernameSkeletons fills in skeletons for users created before they
+// were stored. Where existing users already collide, the later one is left
+// without a skeleton rather than renamed.
+func (b *Backend) backfillUsernameSkeletons() error {
+	rows, err := b.db.Query(`SELECT id, username FROM users WHERE username_skeleton IS NULL ORDER BY user_number`)
+	if err != nil {
+		return err
+	}
+
+	type pending struct{ id, username string }
+	var users []pending
+	for rows.Next() {
+		var u pending
+		if err := rows.Scan(&u.id, &u.username); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		users = append(users, u)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, u := range users {
+		_, err := b.db.Exec(`UPDATE users SET username_skeleton = ? WHERE id = ?`, username.Skeleton(u.username), u.id)
+		if err != nil && !strings.Contains(err.Error(), "UNIQUE constraint") {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -125,10 +415,54 @@ func (b *Backend) Friends() *FriendRepository {
 	return b.friends
 }
 
+func (b *Backend) Blocks() *BlockRepository {
+	return b.blocks
+}
+
+func (b *Backend) Invites() *InviteRepository {
+	return b.invites
+}
+
 func (b *Backend) Messages() *MessageRepository {
 	return b.messages
 }
 
+func (b *Backend) Devices() *DeviceRepository {
+	return b.devices
+}
+
+func (b *Backend) PreKeys() *PreKeyRepository {
+	return b.prekeys
+}
+
+func (b *Backend) Notifications() *NotificationRepository {
+	return b.notifications
+}
+
+func (b *Backend) Profiles() *ProfileRepository {
+	return b.profiles
+}
+
+func (b *Backend) Streaks() *StreakRepository {
+	return b.streaks
+}
+
+func (b *Backend) Interactions() *InteractionRepository {
+	return b.interactions
+}
+
+func (b *Backend) Reports() *ReportRepository {
+	return b.reports
+}
+
+func (b *Backend) Stats() *StatsRepository {
+	return b.stats
+}
+
+func (b *Backend) Audit() *AuditRepository {
+	return b.audit
+}
+
 func (b *Backend) Close() error {
 	return b.db.Close()
 }
@@ -139,7 +473,7 @@ func (b *Backend) Name() string {
 
 // Reset clears all data (for testing)
 func (b *Backend) Reset() error {
-	tables := []string{"messages", "friendships", "friend_requests", "refresh_tokens", "users"}
+	tables := []string{"report_events", "reports", "avatar_keys", "avatars", "username_reservations", "one_time_prekeys", "signed_prekeys", "public_key_history", "audit_log", "notifications", "message_deliveries", "messages", "devices", "invites", "blocks", "interactions", "streaks", "friend_settings", "friend_verifications", "friendships", "friend_requests", "refresh_tokens", "users"}
 	for _, table := range tables {
 		if _, err := b.db.Exec("DELETE FROM " + table); err != nil {
 			return err
@@ -172,34 +506,131 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		user.UserNumber = 1
 	}
 
+	user.KeyVersion = 1
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// The insert is skipped while the name, or a lookalike, is held for
+	// someone who renamed away from it
+	skeleton := username.Skeleton(user.Username)
 	query := `
-		INSERT INTO users (id, user_number, username, password_hash, public_key, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO users (id, user_number, username, username_skeleton, password_hash, public_key, key_version, created_at, updated_at)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM username_reservations WHERE skeleton = ? AND reserved_until > ?)
 	`
 
-	_, err = r.db.ExecContext(ctx, query,
-		user.ID.String(), user.UserNumber, user.Username, user.PasswordHash, user.PublicKey, user.CreatedAt, user.UpdatedAt)
-
+	result, err := tx.ExecContext(ctx, query,
+		user.ID.String(), user.UserNumber, user.Username, skeleton, user.PasswordHash, user.PublicKey, user.KeyVersion, user.CreatedAt, user.UpdatedAt,
+		skeleton, user.CreatedAt)
 	if err != nil {
-		if strings.Contains(err.Error(), "UNIQUE constraint") || strings.Contains(err.Error(), "unique constraint") {
+		return usernameConflict(ctx, tx, user.Username, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return models.ErrUsernameReserved
+	}
+
+	historyQuery := `
+		INSERT INTO public_key_history (user_id, version, public_key, valid_from)
+		VALUES (?, ?, ?, ?)
+	`
+	if _, err := tx.ExecContext(ctx, historyQuery, user.ID.String(), user.KeyVersion, user.PublicKey, user.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// usernameConflict maps a unique violation on the username columns to the
+// matching error. An exact duplicate also shares a skeleton, so the username
+// is checked first.
+func usernameConflict(ctx context.Context, tx *sql.Tx, name string, err error) error {
+	if strings.Contains(err.Error(), "users.username_skeleton") {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE username = ?)`, name).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
 			return models.ErrUsernameExists
 		}
+		return models.ErrUsernameConfusable
+	}
+	if strings.Contains(err.Error(), "UNIQUE constraint") || strings.Contains(err.Error(), "unique constraint") {
+		return models.ErrUsernameExists
+	}
+	return err
+}
+
+func (r *UserRepository) ChangeUsername(ctx context.Context, id uuid.UUID, newUsername string, reservedUntil time.Time) error {
+	newUsername = strings.ToLower(newUsername)
+	skeleton := username.Skeleton(newUsername)
+	now := time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
 		return err
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	return nil
+	var oldUsername string
+	var oldSkeleton sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT username, username_skeleton FROM users WHERE id = ?`, id.String()).Scan(&oldUsername, &oldSkeleton)
+	if err == sql.ErrNoRows {
+		return models.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// A user may take back a name they are holding, but not one held for someone else
+	query := `
+		UPDATE users SET username = ?, username_skeleton = ?, updated_at = ?
+		WHERE id = ? AND NOT EXISTS (
+			SELECT 1 FROM username_reservations WHERE skeleton = ? AND user_id != ? AND reserved_until > ?
+		)
+	`
+	result, err := tx.ExecContext(ctx, query, newUsername, skeleton, now, id.String(), skeleton, id.String(), now)
+	if err != nil {
+		return usernameConflict(ctx, tx, newUsername, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return models.ErrUsernameReserved
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM username_reservations WHERE skeleton = ?`, skeleton); err != nil {
+		return err
+	}
+
+	// Users from before skeletons were stored may not have one
+	if !oldSkeleton.Valid {
+		oldSkeleton.String = username.Skeleton(oldUsername)
+	}
+	reserveQuery := `
+		INSERT OR REPLACE INTO username_reservations (skeleton, username, user_id, reserved_until)
+		VALUES (?, ?, ?, ?)
+	`
+	if oldSkeleton.String != skeleton {
+		if _, err := tx.ExecContext(ctx, reserveQuery, oldSkeleton.String, oldUsername, id.String(), reservedUntil); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := `
-		SELECT id, user_number, username, password_hash, public_key, created_at, updated_at
+		SELECT id, user_number, username, password_hash, public_key, key_version, created_at, updated_at
 		FROM users WHERE id = ?
 	`
 
 	var user models.User
 	var idStr string
 	err := r.db.QueryRowContext(ctx, query, id.String()).Scan(
-		&idStr, &user.UserNumber, &user.Username, &user.PasswordHash, &user.PublicKey, &user.CreatedAt, &user.UpdatedAt)
+		&idStr, &user.UserNumber, &user.Username, &user.PasswordHash, &user.PublicKey, &user.KeyVersion, &user.CreatedAt, &user.UpdatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, models.ErrUserNotFound
@@ -214,14 +645,36 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	query := `
-		SELECT id, user_number, username, password_hash, public_key, created_at, updated_at
+		SELECT id, user_number, username, password_hash, public_key, key_version, created_at, updated_at
 		FROM users WHERE username = ?
 	`
 
 	var user models.User
 	var idStr string
 	err := r.db.QueryRowContext(ctx, query, strings.ToLower(username)).Scan(
-		&idStr, &user.UserNumber, &user.Username, &user.PasswordHash, &user.PublicKey, &user.CreatedAt, &user.UpdatedAt)
+		&idStr, &user.UserNumber, &user.Username, &user.PasswordHash, &user.PublicKey, &user.KeyVersion, &user.CreatedAt, &user.UpdatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user.ID, _ = uuid.Parse(idStr)
+	return &user, nil
+}
+
+func (r *UserRepository) GetByUserNumber(ctx context.Context, userNumber int64) (*models.User, error) {
+	query := `
+		SELECT id, user_number, username, password_hash, public_key, key_version, created_at, updated_at
+		FROM users WHERE user_number = ?
+	`
+
+	var user models.User
+	var idStr string
+	err := r.db.QueryRowContext(ctx, query, userNumber).Scan(
+		&idStr, &user.UserNumber, &user.Username, &user.PasswordHash, &user.PublicKey, &user.KeyVersion, &user.CreatedAt, &user.UpdatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, models.ErrUserNotFound
@@ -234,6 +687,124 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 	return &user, nil
 }
 
+func (r *UserRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	query := `UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, passwordHash, time.Now(), id.String())
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// RotatePublicKey makes publicKey the user's current key, closing the
+// validity period of the previous one. It fails with ErrKeyVersionConflict if
+// the current version is no longer expectedVersion.
+func (r *UserRepository) RotatePublicKey(ctx context.Context, id uuid.UUID, expectedVersion int, publicKey string) (*models.PublicKeyVersion, error) {
+	now := time.Now()
+	record := &models.PublicKeyVersion{
+		Version:   expectedVersion + 1,
+		PublicKey: publicKey,
+		ValidFrom: now,
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `UPDATE users SET public_key = ?, key_version = ?, updated_at = ? WHERE id = ? AND key_version = ?`
+	result, err := tx.ExecContext(ctx, query, publicKey, record.Version, now, id.String(), expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		var exists int
+		err := tx.QueryRowContext(ctx, `SELECT 1 FROM users WHERE id = ?`, id.String()).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, models.ErrKeyVersionConflict
+	}
+
+	closeQuery := `UPDATE public_key_history SET valid_until = ? WHERE user_id = ? AND version = ?`
+	if _, err := tx.ExecContext(ctx, closeQuery, now, id.String(), expectedVersion); err != nil {
+		return nil, err
+	}
+
+	insertQuery := `
+		INSERT INTO public_key_history (user_id, version, public_key, valid_from)
+		VALUES (?, ?, ?, ?)
+	`
+	if _, err := tx.ExecContext(ctx, insertQuery, id.String(), record.Version, publicKey, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *UserRepository) GetKeyHistory(ctx context.Context, id uuid.UUID) ([]models.PublicKeyVersion, error) {
+	query := `
+		SELECT version, public_key, valid_from, valid_until
+		FROM public_key_history
+		WHERE user_id = ?
+		ORDER BY version ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, id.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var history []models.PublicKeyVersion
+	for rows.Next() {
+		var k models.PublicKeyVersion
+		var validUntil sql.NullTime
+		if err := rows.Scan(&k.Version, &k.PublicKey, &k.ValidFrom, &validUntil); err != nil {
+			return nil, err
+		}
+		if validUntil.Valid {
+			k.ValidUntil = &validUntil.Time
+		}
+		history = append(history, k)
+	}
+
+	return history, rows.Err()
+}
+
+// Delete removes the user. Refresh tokens, friend requests, friendships,
+// messages and notifications are removed by their ON DELETE CASCADE keys.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM users WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, id.String())
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepository) StoreRefreshToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
 	query := `
 		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at)
@@ -275,17 +846,117 @@ func (r *UserRepository) DeleteAllRefreshTokens(ctx context.Context, userID uuid
 	return err
 }
 
-// ============ FriendRepository ============
-
-type FriendRepository struct {
-	db *sql.DB
-}
-
-func (r *FriendRepository) CreateFriendRequest(ctx context.Context, fromUserID, toUserID uuid.UUID) (*models.FriendRequest, error) {
-	// Check if request already exists
-	existingQuery := `
-		SELECT id FROM friend_requests
-		WHERE from_user_id = ? AND to_user_id = ? AND status = 'pending'
+// Search walks the username index as a range, so a prefix costs no more than
+// the page it returns
+func (r *UserRepository) Search(ctx context.Context, viewerID uuid.UUID, prefix, after string, limit int) ([]models.UserPublic, error) {
+	// Every username starting with prefix sorts below prefix + U+10FFFF
+	query := `
+		SELECT u.id, u.user_number, u.username, u.public_key, u.key_version
+		FROM users u
+		WHERE u.username >= ? AND u.username < ? AND u.username > ?
+		  AND u.hide_from_search = 0 AND u.id != ?
+		  AND NOT EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = u.id AND b.blocked_id = ?)
+		ORDER BY u.username
+		LIMIT ?
+	`
+	viewer := viewerID.String()
+	rows, err := r.db.QueryContext(ctx, query, prefix, prefix+"\U0010FFFF", after, viewer, viewer, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var users []models.UserPublic
+	for rows.Next() {
+		var u models.UserPublic
+		var idStr string
+		if err := rows.Scan(&idStr, &u.UserNumber, &u.Username, &u.PublicKey, &u.KeyVersion); err != nil {
+			return nil, err
+		}
+		u.ID, _ = uuid.Parse(idStr)
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *UserRepository) GetPrivacySettings(ctx context.Context, id uuid.UUID) (*models.PrivacySettings, error) {
+	var hideFromSuggestions, hideFromSearch bool
+	err := r.db.QueryRowContext(ctx, `SELECT hide_from_suggestions, hide_from_search FROM users WHERE id = ?`, id.String()).
+		Scan(&hideFromSuggestions, &hideFromSearch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PrivacySettings{ShowInSuggestions: !hideFromSuggestions, Discoverable: !hideFromSearch}, nil
+}
+
+func (r *UserRepository) UpdatePrivacySettings(ctx context.Context, id uuid.UUID, settings *models.PrivacySettings) error {
+	query := `UPDATE users SET hide_from_suggestions = ?, hide_from_search = ?, updated_at = ? WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, !settings.ShowInSuggestions, !settings.Discoverable, time.Now(), id.String())
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) SetSuspended(ctx context.Context, id uuid.UUID, suspended bool) error {
+	query := `UPDATE users SET suspended_at = NULL WHERE id = ?`
+	args := []interface{}{id.String()}
+	if suspended {
+		query = `UPDATE users SET suspended_at = COALESCE(suspended_at, ?) WHERE id = ?`
+		args = []interface{}{time.Now(), id.String()}
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) GetSuspension(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	var suspendedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `SELECT suspended_at FROM users WHERE id = ?`, id.String()).Scan(&suspendedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !suspendedAt.Valid {
+		return nil, nil
+	}
+	return &suspendedAt.Time, nil
+}
+
+// ============ FriendRepository ============
+
+type FriendRepository struct {
+	db *sql.DB
+}
+
+func (r *FriendRepository) CreateFriendRequest(ctx context.Context, fromUserID, toUserID uuid.UUID) (*models.FriendRequest, error) {
+	// Check if request already exists
+	existingQuery := `
+		SELECT id FROM friend_requests
+		WHERE from_user_id = ? AND to_user_id = ? AND status = 'pending'
 	`
 	var existingID string
 	err := r.db.QueryRowContext(ctx, existingQuery, fromUserID.String(), toUserID.String()).Scan(&existingID)
@@ -317,31 +988,42 @@ func (r *FriendRepository) CreateFriendRequest(ctx context.Context, fromUserID,
 		Status:     models.FriendRequestPending,
 		CreatedAt:  time.Now(),
 	}
+	request.UpdatedAt = request.CreatedAt
 
+	// An answered request takes the UNIQUE(from_user_id, to_user_id) slot, so
+	// it is replaced in place. A pending one is left alone.
 	query := `
-		INSERT INTO friend_requests (id, from_user_id, to_user_id, status, created_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO friend_requests (id, from_user_id, to_user_id, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, NULL)
+		ON CONFLICT(from_user_id, to_user_id) DO UPDATE SET
+			id = excluded.id, status = excluded.status, created_at = excluded.created_at, updated_at = NULL
+		WHERE friend_requests.status != 'pending'
 	`
-	_, err = r.db.ExecContext(ctx, query,
+	result, err := r.db.ExecContext(ctx, query,
 		request.ID.String(), request.FromUserID.String(), request.ToUserID.String(), request.Status, request.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return nil, models.ErrFriendRequestExists
+	}
+
 	return request, nil
 }
 
-func (r *FriendRepository) GetPendingRequests(ctx context.Context, userID uuid.UUID) ([]models.FriendRequestWithUser, error) {
+func (r *FriendRepository) GetPendingRequests(ctx context.Context, userID uuid.UUID, maxAge time.Duration) ([]models.FriendRequestWithUser, error) {
 	query := `
 		SELECT fr.id, fr.from_user_id, fr.to_user_id, fr.status, fr.created_at,
-		       u.id, u.username, u.public_key
+		       u.id, u.username, u.public_key, u.key_version
 		FROM friend_requests fr
 		JOIN users u ON u.id = fr.from_user_id
-		WHERE fr.to_user_id = ? AND fr.status = 'pending'
+		WHERE fr.to_user_id = ? AND fr.status = 'pending' AND fr.created_at > ?
 		ORDER BY fr.created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID.String())
+	rows, err := r.db.QueryContext(ctx, query, userID.String(), time.Now().Add(-maxAge))
 	if err != nil {
 		return nil, err
 	}
@@ -353,7 +1035,7 @@ func (r *FriendRepository) GetPendingRequests(ctx context.Context, userID uuid.U
 		var idStr, fromUserIDStr, toUserIDStr, fromUserIDStr2 string
 		err := rows.Scan(
 			&idStr, &fromUserIDStr, &toUserIDStr, &req.Status, &req.CreatedAt,
-			&fromUserIDStr2, &req.FromUser.Username, &req.FromUser.PublicKey)
+			&fromUserIDStr2, &req.FromUser.Username, &req.FromUser.PublicKey, &req.FromUser.KeyVersion)
 		if err != nil {
 			return nil, err
 		}
@@ -367,16 +1049,63 @@ func (r *FriendRepository) GetPendingRequests(ctx context.Context, userID uuid.U
 	return requests, rows.Err()
 }
 
+func (r *FriendRepository) GetOutgoingRequests(ctx context.Context, userID uuid.UUID, maxAge time.Duration) ([]models.OutgoingFriendRequest, error) {
+	query := `
+		SELECT fr.id, fr.from_user_id, fr.to_user_id, fr.status, fr.created_at,
+		       u.username, u.public_key, u.key_version
+		FROM friend_requests fr
+		JOIN users u ON u.id = fr.to_user_id
+		WHERE fr.from_user_id = ? AND fr.status IN ('pending', 'rejected') AND fr.created_at > ?
+		ORDER BY fr.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID.String(), time.Now().Add(-maxAge))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var requests []models.OutgoingFriendRequest
+	for rows.Next() {
+		var req models.OutgoingFriendRequest
+		var idStr, fromUserIDStr, toUserIDStr string
+		err := rows.Scan(
+			&idStr, &fromUserIDStr, &toUserIDStr, &req.Status, &req.CreatedAt,
+			&req.ToUser.Username, &req.ToUser.PublicKey, &req.ToUser.KeyVersion)
+		if err != nil {
+			return nil, err
+		}
+		req.ID, _ = uuid.Parse(idStr)
+		req.FromUserID, _ = uuid.Parse(fromUserIDStr)
+		req.ToUserID, _ = uuid.Parse(toUserIDStr)
+		req.ToUser.ID = req.ToUserID
+		requests = append(requests, req)
+	}
+
+	return requests, rows.Err()
+}
+
 func (r *FriendRepository) GetFriendRequest(ctx context.Context, requestID uuid.UUID) (*models.FriendRequest, error) {
 	query := `
-		SELECT id, from_user_id, to_user_id, status, created_at
+		SELECT id, from_user_id, to_user_id, status, created_at, updated_at
 		FROM friend_requests WHERE id = ?
 	`
+	return r.scanFriendRequest(r.db.QueryRowContext(ctx, query, requestID.String()))
+}
+
+func (r *FriendRepository) GetFriendRequestBetween(ctx context.Context, fromUserID, toUserID uuid.UUID) (*models.FriendRequest, error) {
+	query := `
+		SELECT id, from_user_id, to_user_id, status, created_at, updated_at
+		FROM friend_requests WHERE from_user_id = ? AND to_user_id = ?
+	`
+	return r.scanFriendRequest(r.db.QueryRowContext(ctx, query, fromUserID.String(), toUserID.String()))
+}
 
+func (r *FriendRepository) scanFriendRequest(row *sql.Row) (*models.FriendRequest, error) {
 	var req models.FriendRequest
 	var idStr, fromUserIDStr, toUserIDStr string
-	err := r.db.QueryRowContext(ctx, query, requestID.String()).Scan(
-		&idStr, &fromUserIDStr, &toUserIDStr, &req.Status, &req.CreatedAt)
+	var updatedAt sql.NullTime
+	err := row.Scan(&idStr, &fromUserIDStr, &toUserIDStr, &req.Status, &req.CreatedAt, &updatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, models.ErrFriendRequestNotFound
@@ -388,12 +1117,23 @@ func (r *FriendRepository) GetFriendRequest(ctx context.Context, requestID uuid.
 	req.ID, _ = uuid.Parse(idStr)
 	req.FromUserID, _ = uuid.Parse(fromUserIDStr)
 	req.ToUserID, _ = uuid.Parse(toUserIDStr)
+	req.UpdatedAt = req.CreatedAt
+	if updatedAt.Valid {
+		req.UpdatedAt = updatedAt.Time
+	}
 	return &req, nil
 }
 
 func (r *FriendRepository) UpdateFriendRequestStatus(ctx context.Context, requestID uuid.UUID, status models.FriendRequestStatus) error {
-	query := `UPDATE friend_requests SET status = ? WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, status, requestID.String())
+	previous := status.PreviousStatuses()
+	query := `UPDATE friend_requests SET status = ?, updated_at = ? WHERE id = ? AND status IN (?` +
+		strings.Repeat(", ?", len(previous)-1) + `)`
+	args := []interface{}{status, time.Now(), requestID.String()}
+	for _, from := range previous {
+		args = append(args, from)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -406,6 +1146,61 @@ func (r *FriendRepository) UpdateFriendRequestStatus(ctx context.Context, reques
 	return nil
 }
 
+func (r *FriendRepository) AcceptFriendRequest(ctx context.Context, requestID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// The status check and the update are one statement, so a concurrent
+	// reject, cancel or second accept either lands first or finds nothing
+	result, err := tx.ExecContext(ctx,
+		`UPDATE friend_requests SET status = 'accepted', updated_at = ? WHERE id = ? AND status = 'pending'`,
+		time.Now(), requestID.String())
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return models.ErrFriendRequestNotFound
+	}
+
+	var fromUserID, toUserID string
+	err = tx.QueryRowContext(ctx, `SELECT from_user_id, to_user_id FROM friend_requests WHERE id = ?`, requestID.String()).
+		Scan(&fromUserID, &toUserID)
+	if err != nil {
+		return err
+	}
+
+	// Ensure user_a_id < user_b_id for the unique constraint
+	if fromUserID > toUserID {
+		fromUserID, toUserID = toUserID, fromUserID
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT OR IGNORE INTO friendships (id, user_a_id, user_b_id, created_at) VALUES (?, ?, ?, ?)`,
+		uuid.New().String(), fromUserID, toUserID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *FriendRepository) ExpireFriendRequests(ctx context.Context, olderThan time.Duration) (int64, error) {
+	query := `
+		UPDATE friend_requests SET status = 'expired', updated_at = ?
+		WHERE status IN ('pending', 'rejected') AND created_at < ?
+	`
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query, now, now.Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
 func (r *FriendRepository) CreateFriendship(ctx context.Context, userAID, userBID uuid.UUID) error {
 	// Ensure user_a_id < user_b_id for the unique constraint
 	if userAID.String() > userBID.String() {
@@ -420,6 +1215,84 @@ func (r *FriendRepository) CreateFriendship(ctx context.Context, userAID, userBI
 	return err
 }
 
+func (r *FriendRepository) RemoveFriendship(ctx context.Context, userAID, userBID uuid.UUID) error {
+	// Ensure user_a_id < user_b_id
+	if userAID.String() > userBID.String() {
+		userAID, userBID = userBID, userAID
+	}
+	a, b := userAID.String(), userBID.String()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM friendships WHERE user_a_id = ? AND user_b_id = ?`, a, b)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return models.ErrNotFriends
+	}
+
+	// The UNIQUE(from_user_id, to_user_id) constraint would otherwise block a new request
+	requestQuery := `
+		DELETE FROM friend_requests
+		WHERE (from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, requestQuery, a, b, b, a); err != nil {
+		return err
+	}
+
+	messageQuery := `
+		DELETE FROM messages
+		WHERE (from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, messageQuery, a, b, b, a); err != nil {
+		return err
+	}
+
+	settingsQuery := `
+		DELETE FROM friend_settings
+		WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, settingsQuery, a, b, b, a); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM streaks WHERE user_a_id = ? AND user_b_id = ?`, a, b); err != nil {
+		return err
+	}
+
+	interactionQuery := `
+		DELETE FROM interactions
+		WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, interactionQuery, a, b, b, a); err != nil {
+		return err
+	}
+
+	// Wrapped avatar keys and verifications must not carry over to a later friendship
+	avatarKeyQuery := `
+		DELETE FROM avatar_keys
+		WHERE (user_id = ? AND viewer_id = ?) OR (user_id = ? AND viewer_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, avatarKeyQuery, a, b, b, a); err != nil {
+		return err
+	}
+
+	verificationQuery := `
+		DELETE FROM friend_verifications
+		WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, verificationQuery, a, b, b, a); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *FriendRepository) AreFriends(ctx context.Context, userAID, userBID uuid.UUID) (bool, error) {
 	// Ensure user_a_id < user_b_id
 	if userAID.String() > userBID.String() {
@@ -440,20 +1313,31 @@ func (r *FriendRepository) AreFriends(ctx context.Context, userAID, userBID uuid
 	return true, nil
 }
 
+// GetFriends reads the friends with their profiles, settings and streaks in
+// one query and all of their devices in a second
 func (r *FriendRepository) GetFriends(ctx context.Context, userID uuid.UUID) ([]models.Friend, error) {
+	// Streaks are keyed by the ordered pair, like friendships
 	query := `
-		SELECT u.id, u.username, u.public_key, f.created_at
+		SELECT u.id, u.username, u.public_key, u.key_version, f.created_at,
+		       COALESCE(v.key_version = u.key_version, 0),
+		       COALESCE(s.nickname, ''), COALESCE(s.muted, 0), COALESCE(s.pinned, 0), COALESCE(s.message_ttl, 0),
+		       u.display_name, a.id, k.wrapped_key,
+		       COALESCE(st.count, 0), COALESCE(st.last_day, 0)
 		FROM friendships f
 		JOIN users u ON (
-			(f.user_a_id = ? AND u.id = f.user_b_id) OR
-			(f.user_b_id = ? AND u.id = f.user_a_id)
+			(f.user_a_id = :me AND u.id = f.user_b_id) OR
+			(f.user_b_id = :me AND u.id = f.user_a_id)
 		)
-		WHERE f.user_a_id = ? OR f.user_b_id = ?
+		LEFT JOIN friend_verifications v ON v.user_id = :me AND v.friend_id = u.id
+		LEFT JOIN friend_settings s ON s.user_id = :me AND s.friend_id = u.id
+		LEFT JOIN avatars a ON a.user_id = u.id
+		LEFT JOIN avatar_keys k ON k.user_id = u.id AND k.viewer_id = :me AND k.avatar_id = a.id
+		LEFT JOIN streaks st ON st.user_a_id = f.user_a_id AND st.user_b_id = f.user_b_id
+		WHERE f.user_a_id = :me OR f.user_b_id = :me
 		ORDER BY u.username
 	`
 
-	userIDStr := userID.String()
-	rows, err := r.db.QueryContext(ctx, query, userIDStr, userIDStr, userIDStr, userIDStr)
+	rows, err := r.db.QueryContext(ctx, query, sql.Named("me", userID.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -463,119 +1347,1499 @@ func (r *FriendRepository) GetFriends(ctx context.Context, userID uuid.UUID) ([]
 	for rows.Next() {
 		var f models.Friend
 		var userIDStr string
-		if err := rows.Scan(&userIDStr, &f.Username, &f.PublicKey, &f.Since); err != nil {
+		var avatarID, wrappedKey sql.NullString
+		if err := rows.Scan(&userIDStr, &f.Username, &f.PublicKey, &f.KeyVersion, &f.Since, &f.Verified,
+			&f.Nickname, &f.Muted, &f.Pinned, &f.MessageTTL,
+			&f.DisplayName, &avatarID, &wrappedKey,
+			&f.Count, &f.LastDay); err != nil {
 			return nil, err
 		}
 		f.UserID, _ = uuid.Parse(userIDStr)
+		if avatarID.Valid && wrappedKey.Valid {
+			f.Avatar = &models.AvatarRef{ID: avatarID.String, WrappedKey: wrappedKey.String}
+		}
 		friends = append(friends, f)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return friends, rows.Err()
-}
-
-// ============ MessageRepository ============
+	devices, err := r.friendDevices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range friends {
+		friends[i].Devices = devices[friends[i].UserID]
+	}
 
-type MessageRepository struct {
-	db *sql.DB
+	return friends, nil
 }
 
-func (r *MessageRepository) Create(ctx context.Context, msg *models.Message) error {
-	msg.ID = uuid.New()
-	msg.CreatedAt = time.Now()
-
+// friendDevices returns the devices of each of the user's friends, oldest first
+func (r *FriendRepository) friendDevices(ctx context.Context, userID uuid.UUID) (map[uuid.UUID][]models.Device, error) {
 	query := `
-		INSERT INTO messages (id, from_user_id, to_user_id, encrypted_content, content_type, signature, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		SELECT d.id, d.user_id, d.name, d.public_key, d.signature, d.created_at
+		FROM friendships f
+		JOIN devices d ON (
+			(f.user_a_id = :me AND d.user_id = f.user_b_id) OR
+			(f.user_b_id = :me AND d.user_id = f.user_a_id)
+		)
+		WHERE f.user_a_id = :me OR f.user_b_id = :me
+		ORDER BY d.created_at ASC
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		msg.ID.String(), msg.FromUserID.String(), msg.ToUserID.String(),
-		msg.EncryptedContent, msg.ContentType, msg.Signature, msg.CreatedAt)
 
-	return err
+	rows, err := r.db.QueryContext(ctx, query, sql.Named("me", userID.String()))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	devices := make(map[uuid.UUID][]models.Device)
+	for rows.Next() {
+		var device models.Device
+		var idStr, userIDStr string
+		if err := rows.Scan(&idStr, &userIDStr, &device.Name, &device.PublicKey, &device.Signature, &device.CreatedAt); err != nil {
+			return nil, err
+		}
+		device.ID, _ = uuid.Parse(idStr)
+		device.UserID, _ = uuid.Parse(userIDStr)
+		devices[device.UserID] = append(devices[device.UserID], device)
+	}
+
+	return devices, rows.Err()
 }
 
-func (r *MessageRepository) GetPendingMessages(ctx context.Context, userID uuid.UUID) ([]models.MessageWithSender, error) {
+func (r *FriendRepository) GetSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]models.FriendSuggestion, error) {
 	query := `
-		SELECT m.id, m.from_user_id, m.to_user_id, m.encrypted_content, m.content_type, m.signature, m.created_at,
-		       u.username, u.public_key
-		FROM messages m
-		JOIN users u ON u.id = m.from_user_id
-		WHERE m.to_user_id = ?
-		ORDER BY m.created_at ASC
+		WITH mine AS (
+			SELECT CASE WHEN user_a_id = :me THEN user_b_id ELSE user_a_id END AS friend_id
+			FROM friendships WHERE user_a_id = :me OR user_b_id = :me
+		),
+		candidates AS (
+			SELECT CASE WHEN f.user_a_id = m.friend_id THEN f.user_b_id ELSE f.user_a_id END AS user_id
+			FROM mine m
+			JOIN friendships f ON f.user_a_id = m.friend_id OR f.user_b_id = m.friend_id
+		)
+		SELECT u.id, u.user_number, u.username, u.public_key, u.key_version, COUNT(*) AS mutual
+		FROM candidates c
+		JOIN users u ON u.id = c.user_id
+		WHERE u.id != :me
+		  AND u.hide_from_suggestions = 0
+		  AND u.id NOT IN (SELECT friend_id FROM mine)
+		  AND NOT EXISTS (
+			SELECT 1 FROM blocks b
+			WHERE (b.blocker_id = :me AND b.blocked_id = u.id) OR (b.blocker_id = u.id AND b.blocked_id = :me)
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM friend_requests fr
+			WHERE ((fr.from_user_id = :me AND fr.to_user_id = u.id) OR (fr.from_user_id = u.id AND fr.to_user_id = :me))
+			  AND fr.status IN ('pending', 'rejected')
+		  )
+		GROUP BY u.id
+		ORDER BY mutual DESC, u.username
+		LIMIT :limit
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID.String())
+	rows, err := r.db.QueryContext(ctx, query, sql.Named("me", userID.String()), sql.Named("limit", limit))
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = rows.Close() }()
 
-	var messages []models.MessageWithSender
+	var suggestions []models.FriendSuggestion
 	for rows.Next() {
-		var msg models.MessageWithSender
-		var idStr, fromUserIDStr, toUserIDStr string
-		err := rows.Scan(
-			&idStr, &fromUserIDStr, &toUserIDStr,
-			&msg.EncryptedContent, &msg.ContentType, &msg.Signature, &msg.CreatedAt,
-			&msg.FromUsername, &msg.FromPublicKey)
-		if err != nil {
+		var s models.FriendSuggestion
+		var idStr string
+		if err := rows.Scan(&idStr, &s.UserNumber, &s.Username, &s.PublicKey, &s.KeyVersion, &s.MutualFriends); err != nil {
 			return nil, err
 		}
-		msg.ID, _ = uuid.Parse(idStr)
-		msg.FromUserID, _ = uuid.Parse(fromUserIDStr)
-		msg.ToUserID, _ = uuid.Parse(toUserIDStr)
-		messages = append(messages, msg)
+		s.ID, _ = uuid.Parse(idStr)
+		suggestions = append(suggestions, s)
 	}
 
-	return messages, rows.Err()
+	return suggestions, rows.Err()
 }
 
-func (r *MessageRepository) GetByID(ctx context.Context, messageID uuid.UUID) (*models.Message, error) {
+func (r *FriendRepository) SetVerifiedKeyVersion(ctx context.Context, userID, friendID uuid.UUID, keyVersion int) error {
+	if keyVersion == 0 {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM friend_verifications WHERE user_id = ? AND friend_id = ?`,
+			userID.String(), friendID.String())
+		return err
+	}
+
 	query := `
-		SELECT id, from_user_id, to_user_id, encrypted_content, content_type, signature, created_at
-		FROM messages WHERE id = ?
+		INSERT INTO friend_verifications (user_id, friend_id, key_version) VALUES (?, ?, ?)
+		ON CONFLICT(user_id, friend_id) DO UPDATE SET key_version = excluded.key_version
 	`
+	_, err := r.db.ExecContext(ctx, query, userID.String(), friendID.String(), keyVersion)
+	return err
+}
 
-	var msg models.Message
-	var idStr, fromUserIDStr, toUserIDStr string
-	err := r.db.QueryRowContext(ctx, query, messageID.String()).Scan(
-		&idStr, &fromUserIDStr, &toUserIDStr,
-		&msg.EncryptedContent, &msg.ContentType, &msg.Signature, &msg.CreatedAt)
+func (r *FriendRepository) GetVerifiedKeyVersion(ctx context.Context, userID, friendID uuid.UUID) (int, error) {
+	query := `SELECT key_version FROM friend_verifications WHERE user_id = ? AND friend_id = ?`
+	var keyVersion int
+	err := r.db.QueryRowContext(ctx, query, userID.String(), friendID.String()).Scan(&keyVersion)
 
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, models.ErrMessageNotFound
+		return 0, nil
 	}
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	msg.ID, _ = uuid.Parse(idStr)
-	msg.FromUserID, _ = uuid.Parse(fromUserIDStr)
-	msg.ToUserID, _ = uuid.Parse(toUserIDStr)
-	return &msg, nil
+	return keyVersion, nil
 }
 
-func (r *MessageRepository) Delete(ctx context.Context, messageID uuid.UUID) error {
-	query := `DELETE FROM messages WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, messageID.String())
-	if err != nil {
-		return err
-	}
+func (r *FriendRepository) GetFriendSettings(ctx context.Context, userID, friendID uuid.UUID) (*models.FriendSettings, error) {
+	query := `SELECT nickname, muted, pinned, message_ttl FROM friend_settings WHERE user_id = ? AND friend_id = ?`
+	var settings models.FriendSettings
+	err := r.db.QueryRowContext(ctx, query, userID.String(), friendID.String()).Scan(
+		&settings.Nickname, &settings.Muted, &settings.Pinned, &settings.MessageTTL,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.FriendSettings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *FriendRepository) SetFriendSettings(ctx context.Context, userID, friendID uuid.UUID, settings *models.FriendSettings) error {
+	query := `
+		INSERT INTO friend_settings (user_id, friend_id, nickname, muted, pinned, message_ttl) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, friend_id) DO UPDATE SET
+			nickname = excluded.nickname, muted = excluded.muted,
+			pinned = excluded.pinned, message_ttl = excluded.message_ttl
+	`
+	_, err := r.db.ExecContext(ctx, query, userID.String(), friendID.String(),
+		settings.Nickname, settings.Muted, settings.Pinned, settings.MessageTTL)
+	return err
+}
+
+// ============ BlockRepository ============
+
+type BlockRepository struct {
+	db *sql.DB
+}
+
+func (r *BlockRepository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `INSERT INTO blocks (blocker_id, blocked_id, created_at) VALUES (?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, blockerID.String(), blockedID.String(), time.Now()); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") || strings.Contains(err.Error(), "PRIMARY KEY") {
+			return models.ErrAlreadyBlocked
+		}
+		if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	userAID, userBID := blockerID.String(), blockedID.String()
+	if userAID > userBID {
+		userAID, userBID = userBID, userAID
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM friendships WHERE user_a_id = ? AND user_b_id = ?`, userAID, userBID); err != nil {
+		return err
+	}
+
+	requestQuery := `
+		DELETE FROM friend_requests
+		WHERE (from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, requestQuery, userAID, userBID, userBID, userAID); err != nil {
+		return err
+	}
+
+	messageQuery := `
+		DELETE FROM messages
+		WHERE (from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, messageQuery, userAID, userBID, userBID, userAID); err != nil {
+		return err
+	}
+
+	settingsQuery := `
+		DELETE FROM friend_settings
+		WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, settingsQuery, userAID, userBID, userBID, userAID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM streaks WHERE user_a_id = ? AND user_b_id = ?`, userAID, userBID); err != nil {
+		return err
+	}
+
+	interactionQuery := `
+		DELETE FROM interactions
+		WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, interactionQuery, userAID, userBID, userBID, userAID); err != nil {
+		return err
+	}
+
+	// Wrapped avatar keys and verifications must not carry over to a later friendship
+	avatarKeyQuery := `
+		DELETE FROM avatar_keys
+		WHERE (user_id = ? AND viewer_id = ?) OR (user_id = ? AND viewer_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, avatarKeyQuery, userAID, userBID, userBID, userAID); err != nil {
+		return err
+	}
+
+	verificationQuery := `
+		DELETE FROM friend_verifications
+		WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, verificationQuery, userAID, userBID, userBID, userAID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *BlockRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	query := `DELETE FROM blocks WHERE blocker_id = ? AND blocked_id = ?`
+	result, err := r.db.ExecContext(ctx, query, blockerID.String(), blockedID.String())
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return models.ErrNotBlocked
+	}
+
+	return nil
+}
+
+func (r *BlockRepository) IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+	query := `SELECT 1 FROM blocks WHERE blocker_id = ? AND blocked_id = ?`
+	var exists int
+	err := r.db.QueryRowContext(ctx, query, blockerID.String(), blockedID.String()).Scan(&exists)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *BlockRepository) ListBlocked(ctx context.Context, blockerID uuid.UUID) ([]models.BlockedUser, error) {
+	query := `
+		SELECT u.id, u.username, b.created_at
+		FROM blocks b
+		JOIN users u ON u.id = b.blocked_id
+		WHERE b.blocker_id = ?
+		ORDER BY b.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, blockerID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var blocked []models.BlockedUser
+	for rows.Next() {
+		var b models.BlockedUser
+		var userIDStr string
+		if err := rows.Scan(&userIDStr, &b.Username, &b.BlockedAt); err != nil {
+			return nil, err
+		}
+		b.UserID, _ = uuid.Parse(userIDStr)
+		blocked = append(blocked, b)
+	}
+
+	return blocked, rows.Err()
+}
+
+// ============ InviteRepository ============
+
+type InviteRepository struct {
+	db *sql.DB
+}
+
+func (r *InviteRepository) Create(ctx context.Context, invite *models.Invite) error {
+	invite.ID = uuid.New()
+	invite.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO invites (id, creator_id, max_uses, uses, expires_at, revoked, created_at)
+		VALUES (?, ?, ?, 0, ?, 0, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		invite.ID.String(), invite.CreatorID.String(), invite.MaxUses, invite.ExpiresAt, invite.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *InviteRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Invite, error) {
+	query := `
+		SELECT id, creator_id, max_uses, uses, expires_at, revoked, created_at
+		FROM invites WHERE id = ?
+	`
+	return scanInvite(r.db.QueryRowContext(ctx, query, id.String()))
+}
+
+func (r *InviteRepository) ListForUser(ctx context.Context, creatorID uuid.UUID) ([]models.Invite, error) {
+	query := `
+		SELECT id, creator_id, max_uses, uses, expires_at, revoked, created_at
+		FROM invites WHERE creator_id = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, creatorID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var invites []models.Invite
+	for rows.Next() {
+		invite, err := scanInvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, *invite)
+	}
+
+	return invites, rows.Err()
+}
+
+func (r *InviteRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE invites SET revoked = 1 WHERE id = ?`, id.String())
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return models.ErrInviteNotFound
+	}
+
+	return nil
+}
+
+func (r *InviteRepository) Redeem(ctx context.Context, id, redeemerID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		SELECT id, creator_id, max_uses, uses, expires_at, revoked, created_at
+		FROM invites WHERE id = ?
+	`
+	invite, err := scanInvite(tx.QueryRowContext(ctx, query, id.String()))
+	if err != nil {
+		return err
+	}
+	if !invite.Usable() {
+		return models.ErrInviteExpired
+	}
+	if invite.CreatorID == redeemerID {
+		return models.ErrCannotAddSelf
+	}
+
+	// The uses check is repeated in the update so concurrent redemptions
+	// can't go over the limit
+	result, err := tx.ExecContext(ctx, `UPDATE invites SET uses = uses + 1 WHERE id = ? AND uses < max_uses`, id.String())
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return models.ErrInviteExpired
+	}
+
+	userAID, userBID := invite.CreatorID.String(), redeemerID.String()
+	if userAID > userBID {
+		userAID, userBID = userBID, userAID
+	}
+	friendshipQuery := `INSERT INTO friendships (id, user_a_id, user_b_id, created_at) VALUES (?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, friendshipQuery, uuid.New().String(), userAID, userBID, time.Now()); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return models.ErrAlreadyFriends
+		}
+		if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	requestQuery := `
+		UPDATE friend_requests SET status = 'accepted', updated_at = ?
+		WHERE status = 'pending'
+		  AND ((from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?))
+	`
+	if _, err := tx.ExecContext(ctx, requestQuery, time.Now(), userAID, userBID, userBID, userAID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanInvite(row interface{ Scan(...any) error }) (*models.Invite, error) {
+	var invite models.Invite
+	var idStr, creatorIDStr string
+	err := row.Scan(&idStr, &creatorIDStr, &invite.MaxUses, &invite.Uses, &invite.ExpiresAt, &invite.Revoked, &invite.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrInviteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	invite.ID, _ = uuid.Parse(idStr)
+	invite.CreatorID, _ = uuid.Parse(creatorIDStr)
+	return &invite, nil
+}
+
+// ============ MessageRepository ============
+
+type MessageRepository struct {
+	db *sql.DB
+}
+
+func (r *MessageRepository) Create(ctx context.Context, msg *models.Message) error {
+	msg.ID = uuid.New()
+	msg.CreatedAt = time.Now()
+
+	// Record the recipient's key version in the same statement so a concurrent
+	// rotation can't slip between reading and inserting it
+	query := `
+		INSERT INTO messages (id, from_user_id, to_user_id, encrypted_content, content_type, signature, recipient_key_version, created_at)
+		SELECT ?, ?, ?, ?, ?, ?, key_version, ? FROM users WHERE id = ?
+		RETURNING recipient_key_version
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		msg.ID.String(), msg.FromUserID.String(), msg.ToUserID.String(),
+		msg.EncryptedContent, msg.ContentType, msg.Signature, msg.CreatedAt, msg.ToUserID.String()).Scan(&msg.RecipientKeyVersion)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrUserNotFound
+	}
+	return err
+}
+
+// CreateForDevices stores the message once with a ciphertext per device. The
+// device set is checked inside the transaction so a device registered
+// concurrently can't be left without a copy.
+func (r *MessageRepository) CreateForDevices(ctx context.Context, msg *models.Message, ciphertexts []models.DeviceCiphertext) error {
+	msg.ID = uuid.New()
+	msg.CreatedAt = time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO messages (id, from_user_id, to_user_id, encrypted_content, content_type, signature, recipient_key_version, fan_out, created_at)
+		SELECT ?, ?, ?, ?, ?, ?, key_version, 1, ? FROM users WHERE id = ?
+		RETURNING recipient_key_version
+	`
+	err = tx.QueryRowContext(ctx, query,
+		msg.ID.String(), msg.FromUserID.String(), msg.ToUserID.String(),
+		[]byte{}, msg.ContentType, msg.Signature, msg.CreatedAt, msg.ToUserID.String()).Scan(&msg.RecipientKeyVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	deliveryQuery := `
+		INSERT INTO message_deliveries (message_id, device_id, encrypted_content)
+		SELECT ?, id, ? FROM devices WHERE id = ? AND user_id = ?
+	`
+	for _, c := range ciphertexts {
+		result, err := tx.ExecContext(ctx, deliveryQuery, msg.ID.String(), c.EncryptedContent, c.DeviceID.String(), msg.ToUserID.String())
+		if err != nil {
+			if strings.Contains(err.Error(), "UNIQUE constraint") || strings.Contains(err.Error(), "PRIMARY KEY") {
+				return models.ErrDeviceMismatch
+			}
+			return err
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return models.ErrDeviceMismatch
+		}
+	}
+
+	var deviceCount int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM devices WHERE user_id = ?`, msg.ToUserID.String()).Scan(&deviceCount); err != nil {
+		return err
+	}
+	if deviceCount != len(ciphertexts) {
+		return models.ErrDeviceMismatch
+	}
+
+	return tx.Commit()
+}
+
+func (r *MessageRepository) GetPendingMessages(ctx context.Context, userID uuid.UUID) ([]models.MessageWithSender, error) {
+	query := `
+		SELECT m.id, m.from_user_id, m.to_user_id, m.encrypted_content, m.content_type, m.signature,
+		       m.recipient_key_version, m.created_at, u.username, u.public_key
+		FROM messages m
+		JOIN users u ON u.id = m.from_user_id
+		WHERE m.to_user_id = ? AND m.fan_out = 0
+		ORDER BY m.created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var messages []models.MessageWithSender
+	for rows.Next() {
+		var msg models.MessageWithSender
+		var idStr, fromUserIDStr, toUserIDStr string
+		err := rows.Scan(
+			&idStr, &fromUserIDStr, &toUserIDStr,
+			&msg.EncryptedContent, &msg.ContentType, &msg.Signature,
+			&msg.RecipientKeyVersion, &msg.CreatedAt, &msg.FromUsername, &msg.FromPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		msg.ID, _ = uuid.Parse(idStr)
+		msg.FromUserID, _ = uuid.Parse(fromUserIDStr)
+		msg.ToUserID, _ = uuid.Parse(toUserIDStr)
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *MessageRepository) GetPendingForDevice(ctx context.Context, userID, deviceID uuid.UUID) ([]models.MessageWithSender, error) {
+	query := `
+		SELECT m.id, m.from_user_id, m.to_user_id, COALESCE(d.encrypted_content, m.encrypted_content),
+		       m.content_type, m.signature, m.recipient_key_version, m.created_at, d.device_id, u.username, u.public_key
+		FROM messages m
+		JOIN users u ON u.id = m.from_user_id
+		LEFT JOIN message_deliveries d ON d.message_id = m.id AND d.device_id = ?
+		WHERE m.to_user_id = ? AND (m.fan_out = 0 OR d.device_id IS NOT NULL)
+		ORDER BY m.created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, deviceID.String(), userID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var messages []models.MessageWithSender
+	for rows.Next() {
+		var msg models.MessageWithSender
+		var idStr, fromUserIDStr, toUserIDStr string
+		var deviceIDStr sql.NullString
+		err := rows.Scan(
+			&idStr, &fromUserIDStr, &toUserIDStr,
+			&msg.EncryptedContent, &msg.ContentType, &msg.Signature,
+			&msg.RecipientKeyVersion, &msg.CreatedAt, &deviceIDStr, &msg.FromUsername, &msg.FromPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		msg.ID, _ = uuid.Parse(idStr)
+		msg.FromUserID, _ = uuid.Parse(fromUserIDStr)
+		msg.ToUserID, _ = uuid.Parse(toUserIDStr)
+		if deviceIDStr.Valid {
+			id, _ := uuid.Parse(deviceIDStr.String)
+			msg.DeviceID = &id
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *MessageRepository) GetByID(ctx context.Context, messageID uuid.UUID) (*models.Message, error) {
+	query := `
+		SELECT id, from_user_id, to_user_id, encrypted_content, content_type, signature, recipient_key_version, fan_out, created_at
+		FROM messages WHERE id = ?
+	`
+
+	var msg models.Message
+	var idStr, fromUserIDStr, toUserIDStr string
+	err := r.db.QueryRowContext(ctx, query, messageID.String()).Scan(
+		&idStr, &fromUserIDStr, &toUserIDStr,
+		&msg.EncryptedContent, &msg.ContentType, &msg.Signature, &msg.RecipientKeyVersion, &msg.FanOut, &msg.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msg.ID, _ = uuid.Parse(idStr)
+	msg.FromUserID, _ = uuid.Parse(fromUserIDStr)
+	msg.ToUserID, _ = uuid.Parse(toUserIDStr)
+	return &msg, nil
+}
+
+func (r *MessageRepository) Delete(ctx context.Context, messageID uuid.UUID) error {
+	query := `DELETE FROM messages WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, messageID.String())
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return models.ErrMessageNotFound
+	}
+
+	return nil
+}
+
+func (r *MessageRepository) AcknowledgeDelivery(ctx context.Context, messageID, deviceID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var fanOut bool
+	err = tx.QueryRowContext(ctx, `SELECT fan_out FROM messages WHERE id = ?`, messageID.String()).Scan(&fanOut)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrMessageNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if fanOut {
+		result, err := tx.ExecContext(ctx,
+			`DELETE FROM message_deliveries WHERE message_id = ? AND device_id = ?`,
+			messageID.String(), deviceID.String())
+		if err != nil {
+			return err
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return models.ErrMessageNotFound
+		}
+
+		query := `
+			DELETE FROM messages
+			WHERE id = ? AND NOT EXISTS (SELECT 1 FROM message_deliveries WHERE message_id = ?)
+		`
+		if _, err := tx.ExecContext(ctx, query, messageID.String(), messageID.String()); err != nil {
+			return err
+		}
+	} else {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM messages WHERE id = ?`, messageID.String()); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *MessageRepository) DeleteOldMessages(ctx context.Context, olderThan time.Duration) (int64, error) {
+	query := `DELETE FROM messages WHERE created_at < ?`
+	result, err := r.db.ExecContext(ctx, query, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// ============ DeviceRepository ============
+
+type DeviceRepository struct {
+	db *sql.DB
+}
+
+func (r *DeviceRepository) Create(ctx context.Context, device *models.Device) error {
+	device.ID = uuid.New()
+	device.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO devices (id, user_id, name, public_key, signature, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		device.ID.String(), device.UserID.String(), device.Name, device.PublicKey, device.Signature, device.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return models.ErrDeviceExists
+		}
+		if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *DeviceRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Device, error) {
+	query := `SELECT id, user_id, name, public_key, signature, created_at FROM devices WHERE id = ?`
+
+	var device models.Device
+	var idStr, userIDStr string
+	err := r.db.QueryRowContext(ctx, query, id.String()).Scan(
+		&idStr, &userIDStr, &device.Name, &device.PublicKey, &device.Signature, &device.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrDeviceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	device.ID, _ = uuid.Parse(idStr)
+	device.UserID, _ = uuid.Parse(userIDStr)
+	return &device, nil
+}
+
+func (r *DeviceRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	query := `
+		SELECT id, user_id, name, public_key, signature, created_at
+		FROM devices WHERE user_id = ?
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var devices []models.Device
+	for rows.Next() {
+		var device models.Device
+		var idStr, userIDStr string
+		if err := rows.Scan(&idStr, &userIDStr, &device.Name, &device.PublicKey, &device.Signature, &device.CreatedAt); err != nil {
+			return nil, err
+		}
+		device.ID, _ = uuid.Parse(idStr)
+		device.UserID, _ = uuid.Parse(userIDStr)
+		devices = append(devices, device)
+	}
+
+	return devices, rows.Err()
+}
+
+// Delete removes the device; its deliveries cascade, and per-device messages
+// that no other device still has to fetch are removed with it
+func (r *DeviceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var userIDStr string
+	err = tx.QueryRowContext(ctx, `DELETE FROM devices WHERE id = ? RETURNING user_id`, id.String()).Scan(&userIDStr)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrDeviceNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM messages
+		WHERE to_user_id = ? AND fan_out = 1
+		  AND NOT EXISTS (SELECT 1 FROM message_deliveries WHERE message_id = messages.id)
+	`
+	if _, err := tx.ExecContext(ctx, query, userIDStr); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ============ PreKeyRepository ============
+
+type PreKeyRepository struct {
+	db *sql.DB
+}
+
+func (r *PreKeyRepository) SetSignedPreKey(ctx context.Context, userID uuid.UUID, key *models.SignedPreKey) error {
+	key.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO signed_prekeys (user_id, key_id, public_key, signature, identity_key_version, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			key_id = excluded.key_id,
+			public_key = excluded.public_key,
+			signature = excluded.signature,
+			identity_key_version = excluded.identity_key_version,
+			created_at = excluded.created_at
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		userID.String(), key.KeyID, key.PublicKey, key.Signature, key.IdentityKeyVersion, key.CreatedAt)
+	return err
+}
+
+func (r *PreKeyRepository) GetSignedPreKey(ctx context.Context, userID uuid.UUID) (*models.SignedPreKey, error) {
+	query := `
+		SELECT key_id, public_key, signature, identity_key_version, created_at
+		FROM signed_prekeys WHERE user_id = ?
+	`
+
+	var key models.SignedPreKey
+	err := r.db.QueryRowContext(ctx, query, userID.String()).Scan(
+		&key.KeyID, &key.PublicKey, &key.Signature, &key.IdentityKeyVersion, &key.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrPreKeyBundleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *PreKeyRepository) AddOneTimePreKeys(ctx context.Context, userID uuid.UUID, keys []models.OneTimePreKey, replace bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if replace {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM one_time_prekeys WHERE user_id = ?`, userID.String()); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO one_time_prekeys (user_id, key_id, public_key, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+	now := time.Now()
+	for _, key := range keys {
+		if _, err := tx.ExecContext(ctx, query, userID.String(), key.KeyID, key.PublicKey, now); err != nil {
+			if strings.Contains(err.Error(), "UNIQUE constraint") || strings.Contains(err.Error(), "PRIMARY KEY") {
+				return models.ErrPreKeyIDUsed
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PreKeyRepository) CountOneTimePreKeys(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM one_time_prekeys WHERE user_id = ?`, userID.String()).Scan(&count)
+	return count, err
+}
+
+// ClaimOneTimePreKey deletes the oldest key and returns it in a single
+// statement, so two concurrent claims can never receive the same key
+func (r *PreKeyRepository) ClaimOneTimePreKey(ctx context.Context, userID uuid.UUID) (*models.OneTimePreKey, error) {
+	query := `
+		DELETE FROM one_time_prekeys
+		WHERE rowid = (
+			SELECT rowid FROM one_time_prekeys
+			WHERE user_id = ?
+			ORDER BY created_at ASC, key_id ASC
+			LIMIT 1
+		)
+		RETURNING key_id, public_key
+	`
+
+	var key models.OneTimePreKey
+	err := r.db.QueryRowContext(ctx, query, userID.String()).Scan(&key.KeyID, &key.PublicKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// ============ NotificationRepository ============
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	notification.ID = uuid.New()
+	notification.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO notifications (id, user_id, type, actor_id, actor_username, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		notification.ID.String(), notification.UserID.String(), notification.Type,
+		notification.ActorID.String(), notification.ActorUsername, notification.CreatedAt)
+
+	return err
+}
+
+func (r *NotificationRepository) GetForUser(ctx context.Context, userID uuid.UUID) ([]models.Notification, error) {
+	query := `
+		SELECT id, user_id, type, actor_id, actor_username, created_at
+		FROM notifications
+		WHERE user_id = ?
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var notifications []models.Notification
+	for rows.Next() {
+		var n models.Notification
+		var idStr, userIDStr, actorIDStr string
+		if err := rows.Scan(&idStr, &userIDStr, &n.Type, &actorIDStr, &n.ActorUsername, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		n.ID, _ = uuid.Parse(idStr)
+		n.UserID, _ = uuid.Parse(userIDStr)
+		n.ActorID, _ = uuid.Parse(actorIDStr)
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *NotificationRepository) Delete(ctx context.Context, userID, notificationID uuid.UUID) error {
+	query := `DELETE FROM notifications WHERE id = ? AND user_id = ?`
+	result, err := r.db.ExecContext(ctx, query, notificationID.String(), userID.String())
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return models.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// ============ ProfileRepository ============
+
+type ProfileRepository struct {
+	db *sql.DB
+}
+
+func (r *ProfileRepository) SetDisplayName(ctx context.Context, userID uuid.UUID, displayName string) error {
+	query := `UPDATE users SET display_name = ?, updated_at = ? WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, displayName, time.Now(), userID.String())
+	if err != nil {
+		return err
+	}
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return models.ErrMessageNotFound
+		return models.ErrUserNotFound
 	}
 
 	return nil
 }
 
-func (r *MessageRepository) DeleteOldMessages(ctx context.Context, olderThan time.Duration) (int64, error) {
-	query := `DELETE FROM messages WHERE created_at < ?`
-	result, err := r.db.ExecContext(ctx, query, time.Now().Add(-olderThan))
+func (r *ProfileRepository) GetProfile(ctx context.Context, userID, viewerID uuid.UUID) (*models.Profile, error) {
+	query := `
+		SELECT u.display_name, a.id, k.wrapped_key
+		FROM users u
+		LEFT JOIN avatars a ON a.user_id = u.id
+		LEFT JOIN avatar_keys k ON k.user_id = u.id AND k.viewer_id = ? AND k.avatar_id = a.id
+		WHERE u.id = ?
+	`
+
+	var profile models.Profile
+	var avatarID, wrappedKey sql.NullString
+	err := r.db.QueryRowContext(ctx, query, viewerID.String(), userID.String()).Scan(&profile.DisplayName, &avatarID, &wrappedKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	if avatarID.Valid && wrappedKey.Valid {
+		profile.Avatar = &models.AvatarRef{ID: avatarID.String, WrappedKey: wrappedKey.String}
+	}
+	return &profile, nil
+}
+
+func (r *ProfileRepository) SetAvatar(ctx context.Context, userID uuid.UUID, avatar *models.Avatar) error {
+	avatar.UpdatedAt = time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO avatars (user_id, id, data, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET id = excluded.id, data = excluded.data, updated_at = excluded.updated_at
+	`
+	if _, err := tx.ExecContext(ctx, query, userID.String(), avatar.ID, avatar.Data, avatar.UpdatedAt); err != nil {
+		if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
+			return models.ErrUserNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM avatar_keys WHERE user_id = ? AND avatar_id != ?`, userID.String(), avatar.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ProfileRepository) GetAvatar(ctx context.Context, userID uuid.UUID) (*models.Avatar, error) {
+	query := `SELECT id, data, updated_at FROM avatars WHERE user_id = ?`
+
+	var avatar models.Avatar
+	err := r.db.QueryRowContext(ctx, query, userID.String()).Scan(&avatar.ID, &avatar.Data, &avatar.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrAvatarNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &avatar, nil
+}
+
+func (r *ProfileRepository) DeleteAvatar(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM avatars WHERE user_id = ?`, userID.String())
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return models.ErrAvatarNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM avatar_keys WHERE user_id = ?`, userID.String()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ProfileRepository) SetAvatarKeys(ctx context.Context, userID uuid.UUID, avatarID string, keys []models.AvatarKey) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Only store keys while avatarID is still the current avatar
+	query := `
+		INSERT INTO avatar_keys (user_id, viewer_id, avatar_id, wrapped_key)
+		SELECT ?, ?, ?, ? WHERE EXISTS (SELECT 1 FROM avatars WHERE user_id = ? AND id = ?)
+		ON CONFLICT (user_id, viewer_id) DO UPDATE SET avatar_id = excluded.avatar_id, wrapped_key = excluded.wrapped_key
+	`
+	for _, key := range keys {
+		result, err := tx.ExecContext(ctx, query,
+			userID.String(), key.UserID.String(), avatarID, key.WrappedKey, userID.String(), avatarID)
+		if err != nil {
+			if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
+				return models.ErrUserNotFound
+			}
+			return err
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return models.ErrAvatarChanged
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ============ StreakRepository ============
+
+type StreakRepository struct {
+	db *sql.DB
+}
+
+func (r *StreakRepository) RecordMessage(ctx context.Context, fromUserID, toUserID uuid.UUID, day int64) error {
+	// Ensure user_a_id < user_b_id
+	a, b := fromUserID.String(), toUserID.String()
+	sentColumn := "a_sent_day"
+	if a > b {
+		a, b = b, a
+		sentColumn = "b_sent_day"
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO streaks (user_a_id, user_b_id, ` + sentColumn + `) VALUES (?, ?, ?)
+		ON CONFLICT(user_a_id, user_b_id) DO UPDATE SET ` + sentColumn + ` = excluded.` + sentColumn
+	if _, err := tx.ExecContext(ctx, query, a, b, day); err != nil {
+		return err
+	}
+
+	// Count the day once both sides have sent on it
+	query = `
+		UPDATE streaks
+		SET count = CASE WHEN last_day = ? - 1 THEN count + 1 ELSE 1 END, last_day = ?
+		WHERE user_a_id = ? AND user_b_id = ?
+		  AND a_sent_day = ? AND b_sent_day = ? AND (last_day IS NULL OR last_day < ?)
+	`
+	if _, err := tx.ExecContext(ctx, query, day, day, a, b, day, day, day); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *StreakRepository) GetStreak(ctx context.Context, userAID, userBID uuid.UUID) (*models.Streak, error) {
+	// Ensure user_a_id < user_b_id
+	if userAID.String() > userBID.String() {
+		userAID, userBID = userBID, userAID
+	}
+
+	query := `SELECT count, COALESCE(last_day, 0) FROM streaks WHERE user_a_id = ? AND user_b_id = ?`
+	var streak models.Streak
+	err := r.db.QueryRowContext(ctx, query, userAID.String(), userBID.String()).Scan(&streak.Count, &streak.LastDay)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.Streak{}, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
+	return &streak, nil
+}
+
+func (r *StreakRepository) ResetBrokenStreaks(ctx context.Context, before int64) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE streaks SET count = 0 WHERE count > 0 AND last_day < ?`, before)
+	if err != nil {
+		return 0, err
+	}
 	return result.RowsAffected()
 }
+
+// ============ InteractionRepository ============
+
+type InteractionRepository struct {
+	db *sql.DB
+}
+
+func (r *InteractionRepository) RecordInteraction(ctx context.Context, userID, friendID uuid.UUID, at time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	interaction := models.Interaction{FriendID: friendID}
+	query := `SELECT score, scored_at, last_at FROM interactions WHERE user_id = ? AND friend_id = ?`
+	err = tx.QueryRowContext(ctx, query, userID.String(), friendID.String()).Scan(
+		&interaction.Score, &interaction.ScoredAt, &interaction.LastAt,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	interaction.Record(at)
+
+	query = `
+		INSERT INTO interactions (user_id, friend_id, score, scored_at, last_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, friend_id) DO UPDATE SET
+			score = excluded.score, scored_at = excluded.scored_at, last_at = excluded.last_at
+	`
+	if _, err := tx.ExecContext(ctx, query, userID.String(), friendID.String(),
+		interaction.Score, interaction.ScoredAt, interaction.LastAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *InteractionRepository) GetInteractions(ctx context.Context, userID uuid.UUID) ([]models.Interaction, error) {
+	query := `SELECT friend_id, score, scored_at, last_at FROM interactions WHERE user_id = ?`
+	rows, err := r.db.QueryContext(ctx, query, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var interactions []models.Interaction
+	for rows.Next() {
+		var i models.Interaction
+		var friendIDStr string
+		if err := rows.Scan(&friendIDStr, &i.Score, &i.ScoredAt, &i.LastAt); err != nil {
+			return nil, err
+		}
+		i.FriendID, _ = uuid.Parse(friendIDStr)
+		interactions = append(interactions, i)
+	}
+
+	return interactions, rows.Err()
+}
+
+// ============ ReportRepository ============
+
+type ReportRepository struct {
+	db *sql.DB
+}
+
+func (r *ReportRepository) Create(ctx context.Context, report *models.Report) error {
+	report.ID = uuid.New()
+	report.Status = models.ReportOpen
+	report.CreatedAt = time.Now()
+	report.UpdatedAt = report.CreatedAt
+
+	var messageID sql.NullString
+	if report.MessageID != nil {
+		messageID = sql.NullString{String: report.MessageID.String(), Valid: true}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO reports (id, reporter_id, reported_user_id, message_id, reason, comment, content, content_type,
+		                     encrypted_content, signature, verified, sender_key_version, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		report.ID.String(), report.ReporterID.String(), report.ReportedUserID.String(), messageID,
+		report.Reason, report.Comment, report.Content, report.ContentType,
+		report.EncryptedContent, report.Signature, report.Verified, report.SenderKeyVersion,
+		report.Status, report.CreatedAt, report.UpdatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return models.ErrReportExists
+		}
+		return err
+	}
+
+	event := models.ReportEvent{ActorID: report.ReporterID, ToStatus: models.ReportOpen, CreatedAt: report.CreatedAt}
+	if err := r.addEvent(ctx, tx, report.ID, event); err != nil {
+		return err
+	}
+	report.History = []models.ReportEvent{event}
+
+	return tx.Commit()
+}
+
+func (r *ReportRepository) addEvent(ctx context.Context, tx *sql.Tx, reportID uuid.UUID, event models.ReportEvent) error {
+	query := `
+		INSERT INTO report_events (report_id, actor_id, from_status, to_status, note, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := tx.ExecContext(ctx, query, reportID.String(), event.ActorID.String(),
+		event.FromStatus, event.ToStatus, event.Note, event.CreatedAt)
+	return err
+}
+
+const reportColumns = `id, reporter_id, reported_user_id, message_id, reason, comment, content, content_type,
+	encrypted_content, signature, verified, sender_key_version, status, created_at, updated_at`
+
+func scanReport(scan func(dest ...interface{}) error) (*models.Report, error) {
+	var report models.Report
+	var id, reporterID, reportedUserID string
+	var messageID sql.NullString
+	err := scan(&id, &reporterID, &reportedUserID, &messageID, &report.Reason, &report.Comment,
+		&report.Content, &report.ContentType, &report.EncryptedContent, &report.Signature,
+		&report.Verified, &report.SenderKeyVersion, &report.Status, &report.CreatedAt, &report.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	report.ID, _ = uuid.Parse(id)
+	report.ReporterID, _ = uuid.Parse(reporterID)
+	report.ReportedUserID, _ = uuid.Parse(reportedUserID)
+	if messageID.Valid {
+		parsed, _ := uuid.Parse(messageID.String)
+		report.MessageID = &parsed
+	}
+	return &report, nil
+}
+
+func (r *ReportRepository) Get(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+reportColumns+` FROM reports WHERE id = ?`, id.String())
+	report, err := scanReport(row.Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT actor_id, from_status, to_status, note, created_at
+		FROM report_events WHERE report_id = ? ORDER BY created_at, rowid
+	`
+	rows, err := r.db.QueryContext(ctx, query, id.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var event models.ReportEvent
+		var actorID string
+		if err := rows.Scan(&actorID, &event.FromStatus, &event.ToStatus, &event.Note, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.ActorID, _ = uuid.Parse(actorID)
+		report.History = append(report.History, event)
+	}
+
+	return report, rows.Err()
+}
+
+func (r *ReportRepository) List(ctx context.Context, status models.ReportStatus, limit int) ([]models.Report, error) {
+	query := `SELECT ` + reportColumns + ` FROM reports WHERE status = ? ORDER BY created_at, id LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reports []models.Report
+	for rows.Next() {
+		report, err := scanReport(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *report)
+	}
+
+	return reports, rows.Err()
+}
+
+func (r *ReportRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.ReportStatus, actorID uuid.UUID, note string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var from models.ReportStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM reports WHERE id = ?`, id.String()).Scan(&from)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrReportNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	previous := status.PreviousStatuses()
+	query := `UPDATE reports SET status = ?, updated_at = ? WHERE id = ? AND status IN (?` +
+		strings.Repeat(", ?", len(previous)-1) + `)`
+	args := []interface{}{status, now, id.String()}
+	for _, from := range previous {
+		args = append(args, from)
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return models.ErrReportStatusConflict
+	}
+
+	event := models.ReportEvent{ActorID: actorID, FromStatus: from, ToStatus: status, Note: note, CreatedAt: now}
+	if err := r.addEvent(ctx, tx, id, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ============ StatsRepository ============
+
+type StatsRepository struct {
+	db *sql.DB
+}
+
+func (r *StatsRepository) GetStats(ctx context.Context) (*models.AdminStats, error) {
+	// Fanned-out messages hold their ciphertexts in message_deliveries, one
+	// row per device still to acknowledge
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM users),
+			(SELECT COUNT(*) FROM users WHERE suspended_at IS NOT NULL),
+			(SELECT COUNT(*) FROM messages),
+			(SELECT COALESCE(SUM(length(encrypted_content)), 0) FROM messages) +
+				(SELECT COALESCE(SUM(length(encrypted_content)), 0) FROM message_deliveries),
+			(SELECT COUNT(*) FROM friend_requests WHERE status = 'pending'),
+			(SELECT COUNT(*) FROM reports WHERE status = ?)
+	`
+
+	var stats models.AdminStats
+	err := r.db.QueryRowContext(ctx, query, models.ReportOpen).Scan(
+		&stats.Users, &stats.SuspendedUsers, &stats.PendingMessages, &stats.PendingMessageBytes,
+		&stats.PendingFriendRequests, &stats.OpenReports)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// ============ AuditRepository ============
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO audit_log (id, actor_id, action, target_id, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ID.String(), entry.ActorID.String(), entry.Action, entry.TargetID.String(), entry.Details, entry.CreatedAt)
+
+	return err
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/storage"
+)
+
+type AdminService struct {
+	userRepo  storage.UserRepo
+	statsRepo storage.StatsRepo
+	auditRepo storage.AuditRepo
+}
+
+func NewAdminService(userRepo storage.UserRepo, statsRepo storage.StatsRepo, auditRepo storage.AuditRepo) *AdminService {
+	return &AdminService{
+		userRepo:  userRepo,
+		statsRepo: statsRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+// GetUser looks a user up by ID, or by user number if ref is a number
+func (s *AdminService) GetUser(ctx context.Context, ref string) (*models.AdminUser, error) {
+	user, err := s.resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return s.adminUser(ctx, user)
+}
+
+// Suspend locks the user out: their sessions are revoked, login fails and
+// their access tokens stop working. Suspending a suspended user keeps the
+// original suspension time.
+func (s *AdminService) Suspend(ctx context.Context, adminID uuid.UUID, ref, reason string) (*models.AdminUser, error) {
+	user, err := s.resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == adminID {
+		return nil, models.ErrCannotSuspendSelf
+	}
+
+	if err := s.userRepo.SetSuspended(ctx, user.ID, true); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.DeleteAllRefreshTokens(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if err := s.auditRepo.Record(ctx, &models.AuditEntry{
+		ActorID:  adminID,
+		Action:   models.AuditUserSuspended,
+		TargetID: user.ID,
+		Details:  strings.TrimSpace(reason),
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.adminUser(ctx, user)
+}
+
+// Unsuspend lifts a suspension. The user has to log in again.
+func (s *AdminService) Unsuspend(ctx context.Context, adminID uuid.UUID, ref string) (*models.AdminUser, error) {
+	user, err := s.resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetSuspended(ctx, user.ID, false); err != nil {
+		return nil, err
+	}
+	if err := s.auditRepo.Record(ctx, &models.AuditEntry{
+		ActorID:  adminID,
+		Action:   models.AuditUserUnsuspended,
+		TargetID: user.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.adminUser(ctx, user)
+}
+
+// RevokeSessions deletes the user's refresh tokens, signing them out
+// everywhere once their access tokens expire
+func (s *AdminService) RevokeSessions(ctx context.Context, adminID uuid.UUID, ref string) error {
+	user, err := s.resolve(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.DeleteAllRefreshTokens(ctx, user.ID); err != nil {
+		return err
+	}
+	return s.auditRepo.Record(ctx, &models.AuditEntry{
+		ActorID:  adminID,
+		Action:   models.AuditSessionsRevoked,
+		TargetID: user.ID,
+	})
+}
+
+func (s *AdminService) GetStats(ctx context.Context) (*models.AdminStats, error) {
+	return s.statsRepo.GetStats(ctx)
+}
+
+// resolve finds the user ref names, by ID or user number
+func (s *AdminService) resolve(ctx context.Context, ref string) (*models.User, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		return s.userRepo.GetByID(ctx, id)
+	}
+	if number, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return s.userRepo.GetByUserNumber(ctx, number)
+	}
+	return nil, models.ErrUserNotFound
+}
+
+func (s *AdminService) adminUser(ctx context.Context, user *models.User) (*models.AdminUser, error) {
+	suspendedAt, err := s.userRepo.GetSuspension(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AdminUser{
+		ID:          user.ID,
+		UserNumber:  user.UserNumber,
+		Username:    user.Username,
+		PublicKey:   user.PublicKey,
+		KeyVersion:  user.KeyVersion,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		Suspended:   suspendedAt != nil,
+		SuspendedAt: suspendedAt,
+	}, nil
+}
@@ -5,13 +5,17 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/keys"
 	"github.com/quickpic/server/internal/models"
 	"github.com/quickpic/server/internal/storage"
-	"golang.org/x/crypto/argon2"
+	"github.com/quickpic/server/internal/username"
 )
 
 const (
@@ -20,23 +24,125 @@ const (
 )
 
 type AuthService struct {
-	userRepo  storage.UserRepo
-	jwtSecret []byte
+	userRepo      storage.UserRepo
+	friendRepo    storage.FriendRepo
+	auditRepo     storage.AuditRepo
+	notifications *NotificationService
+	tokenKeys     *TokenKeys
+	passwords     PasswordParams
+	challenges    *challengeStore
+	usernames     *username.Policy
+
+	adminsMu sync.RWMutex
+	admins   map[uuid.UUID]bool
 }
 
-func NewAuthService(userRepo storage.UserRepo, jwtSecret string) *AuthService {
+// AccessClaims are what an access token says about its bearer
+type AccessClaims struct {
+	UserID uuid.UUID
+	Role   string // RoleAdmin or empty
+}
+
+func NewAuthService(
+	userRepo storage.UserRepo,
+	friendRepo storage.FriendRepo,
+	auditRepo storage.AuditRepo,
+	notifications *NotificationService,
+	tokenKeys *TokenKeys,
+	passwordParams PasswordParams,
+	usernamePolicy *username.Policy,
+) *AuthService {
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtSecret: []byte(jwtSecret),
+		userRepo:      userRepo,
+		friendRepo:    friendRepo,
+		auditRepo:     auditRepo,
+		notifications: notifications,
+		tokenKeys:     tokenKeys,
+		passwords:     passwordParams,
+		challenges:    newChallengeStore(),
+		usernames:     usernamePolicy,
+		admins:        make(map[uuid.UUID]bool),
+	}
+}
+
+// SetAdmins replaces the users whose access tokens carry the admin role.
+// Tokens already issued keep the role they were issued with until they expire.
+func (s *AuthService) SetAdmins(userIDs []uuid.UUID) {
+	admins := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		admins[id] = true
+	}
+
+	s.adminsMu.Lock()
+	s.admins = admins
+	s.adminsMu.Unlock()
+}
+
+func (s *AuthService) isAdmin(userID uuid.UUID) bool {
+	s.adminsMu.RLock()
+	defer s.adminsMu.RUnlock()
+	return s.admins[userID]
+}
+
+// RegistrationChallenge issues a single-use nonce the client must sign with
+// its identity key when registering
+func (s *AuthService) RegistrationChallenge() (*models.RegistrationChallenge, error) {
+	nonce, expiresAt, err := s.challenges.issue()
+	if err != nil {
+		return nil, err
 	}
+	return &models.RegistrationChallenge{Nonce: nonce, ExpiresAt: expiresAt}, nil
+}
+
+// ExpireChallenges drops registration nonces that were never used in time
+func (s *AuthService) ExpireChallenges(ctx context.Context) (int64, error) {
+	return s.challenges.sweep(), nil
+}
+
+// RegistrationMessage is the byte string a client signs to prove possession
+// of its public key. Binding the username stops a signature made for one
+// registration from being replayed under another name.
+func RegistrationMessage(nonce, username string) []byte {
+	return []byte("quickpic-register\n" + nonce + "\n" + username)
+}
+
+// normalizeUsername applies the username policy, mapping its errors to the
+// ones handlers report
+func normalizeUsername(policy *username.Policy, name string) (string, error) {
+	name, err := policy.Normalize(name)
+	switch {
+	case errors.Is(err, username.ErrReserved):
+		return "", models.ErrUsernameReserved
+	case err != nil:
+		return "", models.ErrInvalidUsername
+	}
+	return name, nil
 }
 
 func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest) (*models.AuthResponse, error) {
+	// Check the username and key before consuming the nonce so either can be retried
+	name, err := normalizeUsername(s.usernames, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := keys.ParsePublicKey(req.PublicKey); err != nil {
+		return nil, models.ErrInvalidPublicKey
+	}
+	if !s.challenges.consume(req.Nonce) {
+		return nil, models.ErrInvalidChallenge
+	}
+	if err := keys.VerifyEncoded(req.PublicKey, RegistrationMessage(req.Nonce, req.Username), req.Signature); err != nil {
+		return nil, models.ErrInvalidKeySignature
+	}
+
 	// Hash password with Argon2id
-	passwordHash := s.hashPassword(req.Password)
+	passwordHash, err := hashPassword(req.Password, s.passwords)
+	if err != nil {
+		return nil, err
+	}
 
 	user := &models.User{
-		Username:     req.Username,
+		Username:     name,
 		PasswordHash: passwordHash,
 		PublicKey:    req.PublicKey,
 	}
@@ -54,10 +160,25 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 		return nil, models.ErrInvalidCredentials
 	}
 
-	if !s.verifyPassword(req.Password, user.PasswordHash) {
+	ok, needsRehash := verifyPassword(req.Password, user.PasswordHash, s.passwords)
+	if !ok {
 		return nil, models.ErrInvalidCredentials
 	}
 
+	// Only tell someone who knows the password that the account is suspended
+	if err := s.CheckActive(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	// Transparently upgrade legacy or outdated hashes while we have the password
+	if needsRehash {
+		if newHash, err := hashPassword(req.Password, s.passwords); err == nil {
+			if err := s.userRepo.UpdatePasswordHash(ctx, user.ID, newHash); err == nil {
+				user.PasswordHash = newHash
+			}
+		}
+	}
+
 	return s.generateTokens(ctx, user)
 }
 
@@ -72,6 +193,10 @@ func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken string) (*
 	// Delete the old refresh token (rotation)
 	_ = s.userRepo.DeleteRefreshToken(ctx, tokenHash)
 
+	if err := s.CheckActive(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -85,52 +210,116 @@ func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 	return s.userRepo.DeleteRefreshToken(ctx, tokenHash)
 }
 
-func (s *AuthService) ValidateAccessToken(tokenString string) (uuid.UUID, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, models.ErrInvalidToken
-		}
-		return s.jwtSecret, nil
+// DeleteAccount permanently erases the user after re-checking their password.
+// Friends are notified that the contact is gone and the deletion is audited.
+func (s *AuthService) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if ok, _ := verifyPassword(password, user.PasswordHash, s.passwords); !ok {
+		return models.ErrInvalidCredentials
+	}
+
+	// Collect friends before the friendships are removed with the account
+	friends, err := s.friendRepo.GetFriends(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Revoke sessions first: not every backend cascades them with the user
+	if err := s.userRepo.DeleteAllRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.userRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	// The account is gone, so later failures are logged rather than
+	// reported as a failed deletion
+	err = s.auditRepo.Record(ctx, &models.AuditEntry{
+		ActorID:  userID,
+		Action:   models.AuditAccountDeleted,
+		TargetID: userID,
 	})
+	if err != nil {
+		log.Printf("Failed to audit deletion of user %s: %v", userID, err)
+	}
+
+	friendIDs := make([]uuid.UUID, 0, len(friends))
+	for _, f := range friends {
+		friendIDs = append(friendIDs, f.UserID)
+	}
+	if err := s.notifications.Notify(ctx, friendIDs, models.NotificationFriendDeleted, user); err != nil {
+		log.Printf("Failed to notify friends of deleted user %s: %v", userID, err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) ValidateAccessToken(tokenString string) (*AccessClaims, error) {
+	token, err := jwt.Parse(tokenString, s.tokenKeys.keyFunc, jwt.WithValidMethods(s.tokenKeys.validMethods()))
 
 	if err != nil {
-		return uuid.Nil, models.ErrInvalidToken
+		return nil, models.ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return uuid.Nil, models.ErrInvalidToken
+		return nil, models.ErrInvalidToken
 	}
 
 	// Check expiration
 	if exp, ok := claims["exp"].(float64); ok {
 		if time.Unix(int64(exp), 0).Before(time.Now()) {
-			return uuid.Nil, models.ErrTokenExpired
+			return nil, models.ErrTokenExpired
 		}
 	}
 
 	userIDStr, ok := claims["sub"].(string)
 	if !ok {
-		return uuid.Nil, models.ErrInvalidToken
+		return nil, models.ErrInvalidToken
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return uuid.Nil, models.ErrInvalidToken
+		return nil, models.ErrInvalidToken
+	}
+
+	role, _ := claims["role"].(string)
+	return &AccessClaims{UserID: userID, Role: role}, nil
+}
+
+// CheckActive fails with ErrAccountSuspended if the user is suspended, so
+// their access tokens stop working before they expire
+func (s *AuthService) CheckActive(ctx context.Context, userID uuid.UUID) error {
+	suspendedAt, err := s.userRepo.GetSuspension(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if suspendedAt != nil {
+		return models.ErrAccountSuspended
 	}
+	return nil
+}
 
-	return userID, nil
+// JWKS returns the public keys other services can use to verify access tokens
+func (s *AuthService) JWKS() JWKSet {
+	return s.tokenKeys.JWKS()
 }
 
 func (s *AuthService) generateTokens(ctx context.Context, user *models.User) (*models.AuthResponse, error) {
 	// Generate access token
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	claims := jwt.MapClaims{
 		"sub": user.ID.String(),
 		"exp": time.Now().Add(accessTokenDuration).Unix(),
 		"iat": time.Now().Unix(),
-	})
-
-	accessTokenString, err := accessToken.SignedString(s.jwtSecret)
+	}
+	if s.isAdmin(user.ID) {
+		claims["role"] = models.RoleAdmin
+	}
+	accessTokenString, err := s.tokenKeys.sign(claims)
 	if err != nil {
 		return nil, err
 	}
@@ -157,41 +346,6 @@ func (s *AuthService) generateTokens(ctx context.Context, user *models.User) (*m
 	}, nil
 }
 
-func (s *AuthService) hashPassword(password string) string {
-	salt := make([]byte, 16)
-	if _, err := rand.Read(salt); err != nil {
-		panic("failed to generate random salt: " + err.Error())
-	}
-
-	hash := argon2.IDKey([]byte(password), salt, 1, 64*1024, 4, 32)
-
-	// Encode as base64: salt + hash
-	combined := append(salt, hash...)
-	return base64.StdEncoding.EncodeToString(combined)
-}
-
-func (s *AuthService) verifyPassword(password, encodedHash string) bool {
-	combined, err := base64.StdEncoding.DecodeString(encodedHash)
-	if err != nil || len(combined) < 48 {
-		return false
-	}
-
-	salt := combined[:16]
-	storedHash := combined[16:]
-
-	computedHash := argon2.IDKey([]byte(password), salt, 1, 64*1024, 4, 32)
-
-	// Constant-time comparison
-	if len(storedHash) != len(computedHash) {
-		return false
-	}
-	var result byte
-	for i := range storedHash {
-		result |= storedHash[i] ^ computedHash[i]
-	}
-	return result == 0
-}
-
 func (s *AuthService) hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
 	return base64.StdEncoding.EncodeToString(hash[:])
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+const challengeDuration = 5 * time.Minute
+
+// maxPendingChallenges bounds the nonces held at once. The challenge endpoint
+// is public, so when it is full the oldest nonce is evicted rather than the
+// store growing; a client that loses one simply asks again.
+const maxPendingChallenges = 100_000
+
+// challengeStore hands out single-use nonces that clients sign to prove they
+// hold a private key. Nonces are kept in memory, so a restart simply forces
+// clients to request a new one.
+type challengeStore struct {
+	mu      sync.Mutex
+	pending map[string]time.Time
+	// order holds nonces as issued. They all live for challengeDuration, so
+	// it is also expiry order. Consumed nonces stay until they reach the front.
+	order []issuedChallenge
+}
+
+type issuedChallenge struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+func newChallengeStore() *challengeStore {
+	return &challengeStore{pending: make(map[string]time.Time)}
+}
+
+func (c *challengeStore) issue() (string, time.Time, error) {
+	nonceBytes := make([]byte, 32)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return "", time.Time{}, err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(nonceBytes)
+	expiresAt := time.Now().Add(challengeDuration)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.order) >= maxPendingChallenges {
+		c.dropOldest()
+	}
+	c.pending[nonce] = expiresAt
+	c.order = append(c.order, issuedChallenge{nonce: nonce, expiresAt: expiresAt})
+
+	return nonce, expiresAt, nil
+}
+
+// consume removes the nonce and reports whether it was issued and unexpired
+func (c *challengeStore) consume(nonce string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt, ok := c.pending[nonce]
+	if !ok {
+		return false
+	}
+	delete(c.pending, nonce)
+	return time.Now().Before(expiresAt)
+}
+
+// sweep drops expired nonces and returns how many were still unused. It only
+// looks at the expired ones, at the front of the queue.
+func (c *challengeStore) sweep() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expired int64
+	now := time.Now()
+	for len(c.order) > 0 && now.After(c.order[0].expiresAt) {
+		if _, ok := c.pending[c.order[0].nonce]; ok {
+			expired++
+		}
+		c.dropOldest()
+	}
+	return expired
+}
+
+// dropOldest removes the front of the queue. The caller holds c.mu.
+func (c *challengeStore) dropOldest() {
+	delete(c.pending, c.order[0].nonce)
+	c.order[0] = issuedChallenge{}
+	c.order = c.order[1:]
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/keys"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/storage"
+)
+
+const maxDevicesPerUser = 10
+
+// DeviceService manages the devices a user receives messages on. Senders
+// encrypt one copy per device, so every device key is endorsed by the
+// account's identity key and friends are told when the list changes.
+type DeviceService struct {
+	deviceRepo    storage.DeviceRepo
+	userRepo      storage.UserRepo
+	friendRepo    storage.FriendRepo
+	auditRepo     storage.AuditRepo
+	notifications *NotificationService
+}
+
+func NewDeviceService(
+	deviceRepo storage.DeviceRepo,
+	userRepo storage.UserRepo,
+	friendRepo storage.FriendRepo,
+	auditRepo storage.AuditRepo,
+	notifications *NotificationService,
+) *DeviceService {
+	return &DeviceService{
+		deviceRepo:    deviceRepo,
+		userRepo:      userRepo,
+		friendRepo:    friendRepo,
+		auditRepo:     auditRepo,
+		notifications: notifications,
+	}
+}
+
+// DeviceMessage is the statement the identity key signs to add a device key.
+// Friends can check it against the identity key before encrypting to a device.
+func DeviceMessage(userID uuid.UUID, devicePublicKey string) []byte {
+	return []byte("quickpic-device\n" + userID.String() + "\n" + devicePublicKey)
+}
+
+func (s *DeviceService) Register(ctx context.Context, userID uuid.UUID, req *models.RegisterDeviceRequest) (*models.Device, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := keys.ParsePublicKey(req.PublicKey); err != nil {
+		return nil, models.ErrInvalidPublicKey
+	}
+	if err := keys.VerifyEncoded(user.PublicKey, DeviceMessage(user.ID, req.PublicKey), req.Signature); err != nil {
+		return nil, models.ErrInvalidKeySignature
+	}
+
+	existing, err := s.deviceRepo.ListForUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxDevicesPerUser {
+		return nil, models.ErrTooManyDevices
+	}
+
+	device := &models.Device{
+		UserID:    user.ID,
+		Name:      req.Name,
+		PublicKey: req.PublicKey,
+		Signature: req.Signature,
+	}
+	if err := s.deviceRepo.Create(ctx, device); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifyFriends(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.auditRepo.Record(ctx, &models.AuditEntry{
+		ActorID:  user.ID,
+		Action:   models.AuditDeviceAdded,
+		TargetID: user.ID,
+		Details:  "device " + device.ID.String(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+func (s *DeviceService) List(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	return s.deviceRepo.ListForUser(ctx, userID)
+}
+
+// Remove unregisters one of the user's devices and drops its undelivered messages
+func (s *DeviceService) Remove(ctx context.Context, userID, deviceID uuid.UUID) error {
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device.UserID != userID {
+		return models.ErrDeviceNotFound
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.deviceRepo.Delete(ctx, deviceID); err != nil {
+		return err
+	}
+
+	if err := s.notifyFriends(ctx, user); err != nil {
+		return err
+	}
+	return s.auditRepo.Record(ctx, &models.AuditEntry{
+		ActorID:  user.ID,
+		Action:   models.AuditDeviceRemoved,
+		TargetID: user.ID,
+		Details:  "device " + deviceID.String(),
+	})
+}
+
+func (s *DeviceService) notifyFriends(ctx context.Context, user *models.User) error {
+	friends, err := s.friendRepo.GetFriends(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	friendIDs := make([]uuid.UUID, 0, len(friends))
+	for _, f := range friends {
+		friendIDs = append(friendIDs, f.UserID)
+	}
+	return s.notifications.Notify(ctx, friendIDs, models.NotificationDevicesChanged, user)
+}
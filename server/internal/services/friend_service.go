@@ -2,21 +2,53 @@ package services
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/keys"
 	"github.com/quickpic/server/internal/models"
 	"github.com/quickpic/server/internal/storage"
 )
 
+// DefaultFriendRequestCooldown is how long a sender must wait after a
+// rejection or a cancellation before asking the same user again
+const DefaultFriendRequestCooldown = 24 * time.Hour
+
+// DefaultFriendRequestTTL is how long a request waits for an answer before
+// it expires
+const DefaultFriendRequestTTL = 30 * 24 * time.Hour
+
 type FriendService struct {
-	friendRepo storage.FriendRepo
-	userRepo   storage.UserRepo
+	friendRepo   storage.FriendRepo
+	userRepo     storage.UserRepo
+	blockRepo    storage.BlockRepo
+	streaks      *StreakService
+	interactions *InteractionService
+
+	requestCooldown time.Duration
+	requestTTL      time.Duration
 }
 
-func NewFriendService(friendRepo storage.FriendRepo, userRepo storage.UserRepo) *FriendService {
+func NewFriendService(
+	friendRepo storage.FriendRepo,
+	userRepo storage.UserRepo,
+	blockRepo storage.BlockRepo,
+	streaks *StreakService,
+	interactions *InteractionService,
+	requestCooldown time.Duration,
+	requestTTL time.Duration,
+) *FriendService {
 	return &FriendService{
-		friendRepo: friendRepo,
-		userRepo:   userRepo,
+		friendRepo:      friendRepo,
+		userRepo:        userRepo,
+		blockRepo:       blockRepo,
+		streaks:         streaks,
+		interactions:    interactions,
+		requestCooldown: requestCooldown,
+		requestTTL:      requestTTL,
 	}
 }
 
@@ -30,35 +62,148 @@ func (s *FriendService) SendFriendRequest(ctx context.Context, fromUserID uuid.U
 		return nil, models.ErrCannotAddSelf
 	}
 
+	blocked, err := s.blockRepo.IsBlocked(ctx, fromUserID, toUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, models.ErrUserBlocked
+	}
+
+	// Someone who has blocked the sender doesn't exist as far as the sender
+	// can tell, the same answer GET /users/:username gives
+	blockedBy, err := s.blockRepo.IsBlocked(ctx, toUser.ID, fromUserID)
+	if err != nil {
+		return nil, err
+	}
+	if blockedBy {
+		return nil, models.ErrUserNotFound
+	}
+
+	// Within the cooldown a rejected sender gets the same answer as when the
+	// request is still pending, so a rejection can't be told from no answer.
+	// Cancelling counts too, otherwise cancelling a rejected request would
+	// skip the wait and give the rejection away, and so does expiry, since
+	// rejected requests expire alongside unanswered ones.
+	previous, err := s.friendRepo.GetFriendRequestBetween(ctx, fromUserID, toUser.ID)
+	switch {
+	case errors.Is(err, models.ErrFriendRequestNotFound):
+	case err != nil:
+		return nil, err
+	case s.expired(previous):
+		if time.Since(previous.CreatedAt.Add(s.requestTTL)) < s.requestCooldown {
+			return nil, models.ErrFriendRequestExists
+		}
+	case previous.Status == models.FriendRequestRejected ||
+		previous.Status == models.FriendRequestCancelled ||
+		previous.Status == models.FriendRequestExpired:
+		if time.Since(previous.UpdatedAt) < s.requestCooldown {
+			return nil, models.ErrFriendRequestExists
+		}
+	}
+
+	// An expired request the sweep hasn't reached yet still holds the pair's
+	// pending slot in either direction
+	if err := s.expireBetween(ctx, fromUserID, toUser.ID); err != nil {
+		return nil, err
+	}
+	if err := s.expireBetween(ctx, toUser.ID, fromUserID); err != nil {
+		return nil, err
+	}
+
 	return s.friendRepo.CreateFriendRequest(ctx, fromUserID, toUser.ID)
 }
 
+// ExpireFriendRequests marks requests that outlived the TTL as expired. It is
+// run periodically; until then the read paths already leave them out.
+func (s *FriendService) ExpireFriendRequests(ctx context.Context) (int64, error) {
+	return s.friendRepo.ExpireFriendRequests(ctx, s.requestTTL)
+}
+
+// expired reports whether a request has outlived the TTL. Rejected requests
+// expire like pending ones so the sender sees the same thing either way.
+func (s *FriendService) expired(request *models.FriendRequest) bool {
+	if request.Status != models.FriendRequestPending && request.Status != models.FriendRequestRejected {
+		return false
+	}
+	return time.Since(request.CreatedAt) >= s.requestTTL
+}
+
+func (s *FriendService) expireBetween(ctx context.Context, fromUserID, toUserID uuid.UUID) error {
+	request, err := s.friendRepo.GetFriendRequestBetween(ctx, fromUserID, toUserID)
+	if errors.Is(err, models.ErrFriendRequestNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if request.Status != models.FriendRequestPending || !s.expired(request) {
+		return nil
+	}
+	return s.friendRepo.UpdateFriendRequestStatus(ctx, request.ID, models.FriendRequestExpired)
+}
+
 func (s *FriendService) GetPendingRequests(ctx context.Context, userID uuid.UUID) ([]models.FriendRequestWithUser, error) {
-	return s.friendRepo.GetPendingRequests(ctx, userID)
+	return s.friendRepo.GetPendingRequests(ctx, userID, s.requestTTL)
 }
 
-func (s *FriendService) AcceptFriendRequest(ctx context.Context, userID uuid.UUID, requestID uuid.UUID) error {
+// GetOutgoingRequests lists the requests the user has sent and not
+// cancelled. Rejected ones are shown as pending.
+func (s *FriendService) GetOutgoingRequests(ctx context.Context, userID uuid.UUID) ([]models.OutgoingFriendRequest, error) {
+	requests, err := s.friendRepo.GetOutgoingRequests(ctx, userID, s.requestTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range requests {
+		requests[i].Status = models.FriendRequestPending
+	}
+
+	return requests, nil
+}
+
+// CancelFriendRequest withdraws a request the user sent. A rejected request
+// can be cancelled like a pending one, so the sender can't tell them apart.
+func (s *FriendService) CancelFriendRequest(ctx context.Context, userID uuid.UUID, requestID uuid.UUID) error {
 	request, err := s.friendRepo.GetFriendRequest(ctx, requestID)
 	if err != nil {
 		return err
 	}
 
-	// Verify the current user is the recipient
-	if request.ToUserID != userID {
+	// Verify the current user is the sender
+	if request.FromUserID != userID {
 		return models.ErrUnauthorized
 	}
 
-	if request.Status != models.FriendRequestPending {
+	if request.Status != models.FriendRequestPending && request.Status != models.FriendRequestRejected {
+		return models.ErrFriendRequestNotFound
+	}
+	if s.expired(request) {
 		return models.ErrFriendRequestNotFound
 	}
 
-	// Update request status
-	if err := s.friendRepo.UpdateFriendRequestStatus(ctx, requestID, models.FriendRequestAccepted); err != nil {
+	return s.friendRepo.UpdateFriendRequestStatus(ctx, requestID, models.FriendRequestCancelled)
+}
+
+func (s *FriendService) AcceptFriendRequest(ctx context.Context, userID uuid.UUID, requestID uuid.UUID) error {
+	request, err := s.friendRepo.GetFriendRequest(ctx, requestID)
+	if err != nil {
 		return err
 	}
 
-	// Create friendship
-	return s.friendRepo.CreateFriendship(ctx, request.FromUserID, request.ToUserID)
+	// Verify the current user is the recipient
+	if request.ToUserID != userID {
+		return models.ErrUnauthorized
+	}
+
+	if request.Status != models.FriendRequestPending || s.expired(request) {
+		return models.ErrFriendRequestNotFound
+	}
+
+	// Accepting and creating the friendship is one repository step, which
+	// fails if a concurrent reject or cancel got there first
+	return s.friendRepo.AcceptFriendRequest(ctx, requestID)
 }
 
 func (s *FriendService) RejectFriendRequest(ctx context.Context, userID uuid.UUID, requestID uuid.UUID) error {
@@ -72,13 +217,208 @@ func (s *FriendService) RejectFriendRequest(ctx context.Context, userID uuid.UUI
 		return models.ErrUnauthorized
 	}
 
-	if request.Status != models.FriendRequestPending {
+	if request.Status != models.FriendRequestPending || s.expired(request) {
 		return models.ErrFriendRequestNotFound
 	}
 
 	return s.friendRepo.UpdateFriendRequestStatus(ctx, requestID, models.FriendRequestRejected)
 }
 
-func (s *FriendService) GetFriends(ctx context.Context, userID uuid.UUID) ([]models.Friend, error) {
-	return s.friendRepo.GetFriends(ctx, userID)
+// RemoveFriend ends the friendship with username. Undelivered messages between
+// the two are deleted, and either may send a new friend request afterwards.
+func (s *FriendService) RemoveFriend(ctx context.Context, userID uuid.UUID, username string) error {
+	friend, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if friend.ID == userID {
+		return models.ErrNotFriends
+	}
+
+	if err := s.friendRepo.RemoveFriendship(ctx, userID, friend.ID); err != nil {
+		return err
+	}
+	s.interactions.Invalidate(userID, friend.ID)
+	return nil
+}
+
+// GetFriends lists friends with the devices each one receives messages on
+// and their streaks, by username or in the given order, pinned friends first
+func (s *FriendService) GetFriends(ctx context.Context, userID uuid.UUID, order models.FriendSort) ([]models.Friend, error) {
+	friends, err := s.friendRepo.GetFriends(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range friends {
+		friends[i].Streak = s.streaks.current(friends[i].Streak)
+	}
+
+	if order != "" {
+		if err := s.interactions.Sort(ctx, userID, order, friends); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(friends, func(i, j int) bool {
+		return friends[i].Pinned && !friends[j].Pinned
+	})
+
+	return friends, nil
+}
+
+// UpdateFriendSettings changes the user's own settings for a friend. The
+// friend never sees them.
+func (s *FriendService) UpdateFriendSettings(ctx context.Context, userID uuid.UUID, username string, req *models.UpdateFriendSettingsRequest) (*models.FriendSettings, error) {
+	_, friend, err := s.getFriendPair(ctx, userID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.friendRepo.GetFriendSettings(ctx, userID, friend.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Nickname != nil {
+		nickname := strings.TrimSpace(*req.Nickname)
+		if !validName(nickname, models.MaxNicknameLength) {
+			return nil, models.ErrInvalidNickname
+		}
+		settings.Nickname = nickname
+	}
+	if req.Muted != nil {
+		settings.Muted = *req.Muted
+	}
+	if req.Pinned != nil {
+		settings.Pinned = *req.Pinned
+	}
+	if req.MessageTTL != nil {
+		settings.MessageTTL = *req.MessageTTL
+	}
+
+	if err := s.friendRepo.SetFriendSettings(ctx, userID, friend.ID, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetSuggestions lists people the user may know, ranked by mutual friends
+func (s *FriendService) GetSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]models.FriendSuggestion, error) {
+	return s.friendRepo.GetSuggestions(ctx, userID, limit)
+}
+
+// SafetyNumber returns the number the user and a friend compare to check
+// each other's keys, pinned to the friend's current key version
+func (s *FriendService) SafetyNumber(ctx context.Context, userID uuid.UUID, username string) (*models.SafetyNumber, error) {
+	user, friend, err := s.getFriendPair(ctx, userID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := safetyNumber(user, friend)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedVersion, err := s.friendRepo.GetVerifiedKeyVersion(ctx, userID, friend.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SafetyNumber{
+		SafetyNumber: number,
+		KeyVersion:   friend.KeyVersion,
+		Verified:     verifiedVersion == friend.KeyVersion,
+	}, nil
+}
+
+// SetVerified marks a friend verified, or clears it. Verification is tied to
+// the friend's current key version, so rotating the key resets it.
+func (s *FriendService) SetVerified(ctx context.Context, userID uuid.UUID, username string, req *models.SetVerifiedRequest) error {
+	user, friend, err := s.getFriendPair(ctx, userID, username)
+	if err != nil {
+		return err
+	}
+
+	if !req.Verified {
+		return s.friendRepo.SetVerifiedKeyVersion(ctx, userID, friend.ID, 0)
+	}
+
+	number, err := safetyNumber(user, friend)
+	if err != nil {
+		return err
+	}
+	if number != req.SafetyNumber {
+		return models.ErrSafetyNumberMismatch
+	}
+
+	return s.friendRepo.SetVerifiedKeyVersion(ctx, userID, friend.ID, friend.KeyVersion)
+}
+
+func (s *FriendService) getFriendPair(ctx context.Context, userID uuid.UUID, username string) (*models.User, *models.User, error) {
+	friend, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	areFriends, err := s.friendRepo.AreFriends(ctx, userID, friend.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !areFriends {
+		return nil, nil, models.ErrNotFriends
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, friend, nil
+}
+
+func safetyNumber(user, friend *models.User) (string, error) {
+	userKey, err := keys.ParsePublicKey(user.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	friendKey, err := keys.ParsePublicKey(friend.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return keys.SafetyNumber(user.ID.String(), userKey, friend.ID.String(), friendKey), nil
+}
+
+// Block stops username from reaching the user. Any friendship, pending
+// requests and undelivered messages between the two are removed.
+func (s *FriendService) Block(ctx context.Context, userID uuid.UUID, username string) error {
+	target, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if target.ID == userID {
+		return models.ErrCannotBlockSelf
+	}
+
+	if err := s.blockRepo.Block(ctx, userID, target.ID); err != nil {
+		return err
+	}
+	s.interactions.Invalidate(userID, target.ID)
+	return nil
+}
+
+func (s *FriendService) Unblock(ctx context.Context, userID uuid.UUID, username string) error {
+	target, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return s.blockRepo.Unblock(ctx, userID, target.ID)
+}
+
+func (s *FriendService) GetBlocked(ctx context.Context, userID uuid.UUID) ([]models.BlockedUser, error) {
+	return s.blockRepo.ListBlocked(ctx, userID)
 }
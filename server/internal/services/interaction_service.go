@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/storage"
+)
+
+// maxCachedRankings bounds the ranking cache; it is emptied when full
+const maxCachedRankings = 10000
+
+// InteractionService counts messages sent and acknowledged between friends and
+// ranks a user's friends by them. Decay scales every score of a user by the
+// same factor, so a ranking stays valid until the user's next interaction
+// and is cached until then.
+type InteractionService struct {
+	interactionRepo storage.InteractionRepo
+
+	mu       sync.Mutex
+	rankings map[rankingKey]map[uuid.UUID]int
+	// generation counts invalidations, so a ranking read before one is not
+	// cached after it
+	generation uint64
+}
+
+type rankingKey struct {
+	userID uuid.UUID
+	sort   models.FriendSort
+}
+
+func NewInteractionService(interactionRepo storage.InteractionRepo) *InteractionService {
+	return &InteractionService{
+		interactionRepo: interactionRepo,
+		rankings:        make(map[rankingKey]map[uuid.UUID]int),
+	}
+}
+
+// RecordInteraction counts an interaction by the user with a friend
+func (s *InteractionService) RecordInteraction(ctx context.Context, userID, friendID uuid.UUID) error {
+	if err := s.interactionRepo.RecordInteraction(ctx, userID, friendID, time.Now()); err != nil {
+		return err
+	}
+
+	s.Invalidate(userID)
+	return nil
+}
+
+// Invalidate drops the cached rankings of the given users, for when their
+// interactions change or are removed with a friendship
+func (s *InteractionService) Invalidate(userIDs ...uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generation++
+	for _, userID := range userIDs {
+		delete(s.rankings, rankingKey{userID: userID, sort: models.FriendSortRecent})
+		delete(s.rankings, rankingKey{userID: userID, sort: models.FriendSortFrequent})
+	}
+}
+
+// Sort orders friends by order, keeping the current order among friends the
+// user hasn't interacted with, who come last
+func (s *InteractionService) Sort(ctx context.Context, userID uuid.UUID, order models.FriendSort, friends []models.Friend) error {
+	ranking, err := s.ranking(ctx, userID, order)
+	if err != nil {
+		return err
+	}
+
+	rank := func(friendID uuid.UUID) int {
+		if r, ok := ranking[friendID]; ok {
+			return r
+		}
+		return len(ranking)
+	}
+	sort.SliceStable(friends, func(i, j int) bool {
+		return rank(friends[i].UserID) < rank(friends[j].UserID)
+	})
+	return nil
+}
+
+// ranking maps each friend the user has interacted with to their position
+func (s *InteractionService) ranking(ctx context.Context, userID uuid.UUID, order models.FriendSort) (map[uuid.UUID]int, error) {
+	key := rankingKey{userID: userID, sort: order}
+
+	s.mu.Lock()
+	ranking, ok := s.rankings[key]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok {
+		return ranking, nil
+	}
+
+	interactions, err := s.interactionRepo.GetInteractions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sort.Slice(interactions, func(i, j int) bool {
+		a, b := &interactions[i], &interactions[j]
+		if order == models.FriendSortFrequent {
+			if scoreA, scoreB := a.ScoreAt(now), b.ScoreAt(now); scoreA != scoreB {
+				return scoreA > scoreB
+			}
+		}
+		if !a.LastAt.Equal(b.LastAt) {
+			return a.LastAt.After(b.LastAt)
+		}
+		return a.FriendID.String() < b.FriendID.String()
+	})
+
+	ranking = make(map[uuid.UUID]int, len(interactions))
+	for i, interaction := range interactions {
+		ranking[interaction.FriendID] = i
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		if len(s.rankings) >= maxCachedRankings {
+			s.rankings = make(map[rankingKey]map[uuid.UUID]int)
+		}
+		s.rankings[key] = ranking
+	}
+	s.mu.Unlock()
+
+	return ranking, nil
+}
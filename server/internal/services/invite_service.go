@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/storage"
+)
+
+const (
+	defaultInviteLifetime = 7 * 24 * time.Hour
+	inviteTokenType       = "invite"
+)
+
+// InviteService issues friend invites. An invite's token is signed with the
+// access token keys but carries no subject, so it can never pass as an
+// access token, and access tokens carry no invite type.
+type InviteService struct {
+	inviteRepo storage.InviteRepo
+	userRepo   storage.UserRepo
+	blockRepo  storage.BlockRepo
+	tokenKeys  *TokenKeys
+}
+
+func NewInviteService(inviteRepo storage.InviteRepo, userRepo storage.UserRepo, blockRepo storage.BlockRepo, tokenKeys *TokenKeys) *InviteService {
+	return &InviteService{
+		inviteRepo: inviteRepo,
+		userRepo:   userRepo,
+		blockRepo:  blockRepo,
+		tokenKeys:  tokenKeys,
+	}
+}
+
+// Create makes an invite that expires after the requested number of hours
+// (a week by default) and can be redeemed max_uses times (once by default)
+func (s *InviteService) Create(ctx context.Context, userID uuid.UUID, req *models.CreateInviteRequest) (*models.CreatedInvite, error) {
+	lifetime := defaultInviteLifetime
+	if req.ExpiresInHours > 0 {
+		lifetime = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	invite := &models.Invite{
+		CreatorID: userID,
+		MaxUses:   max(req.MaxUses, 1),
+		ExpiresAt: time.Now().Add(lifetime).Truncate(time.Second),
+	}
+	if err := s.inviteRepo.Create(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	token, err := s.tokenKeys.sign(jwt.MapClaims{
+		"typ": inviteTokenType,
+		"jti": invite.ID.String(),
+		"exp": invite.ExpiresAt.Unix(),
+		"iat": time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedInvite{Invite: *invite, Token: token}, nil
+}
+
+func (s *InviteService) List(ctx context.Context, userID uuid.UUID) ([]models.Invite, error) {
+	return s.inviteRepo.ListForUser(ctx, userID)
+}
+
+func (s *InviteService) Revoke(ctx context.Context, userID, inviteID uuid.UUID) error {
+	invite, err := s.inviteRepo.GetByID(ctx, inviteID)
+	if err != nil {
+		return err
+	}
+	if invite.CreatorID != userID {
+		return models.ErrInviteNotFound
+	}
+
+	return s.inviteRepo.Revoke(ctx, inviteID)
+}
+
+// Redeem makes the caller and the invite's creator friends. A creator who has
+// blocked the caller is indistinguishable from an expired invite.
+func (s *InviteService) Redeem(ctx context.Context, userID uuid.UUID, token string) (*models.Friend, error) {
+	inviteID, err := s.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := s.inviteRepo.GetByID(ctx, inviteID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := s.blockRepo.IsBlocked(ctx, userID, invite.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, models.ErrUserBlocked
+	}
+
+	blockedBy, err := s.blockRepo.IsBlocked(ctx, invite.CreatorID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if blockedBy {
+		return nil, models.ErrInviteExpired
+	}
+
+	if err := s.inviteRepo.Redeem(ctx, invite.ID, userID); err != nil {
+		return nil, err
+	}
+
+	creator, err := s.userRepo.GetByID(ctx, invite.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Friend{
+		UserID:     creator.ID,
+		Username:   creator.Username,
+		PublicKey:  creator.PublicKey,
+		KeyVersion: creator.KeyVersion,
+		Since:      time.Now(),
+	}, nil
+}
+
+func (s *InviteService) parseToken(token string) (uuid.UUID, error) {
+	parsed, err := jwt.Parse(token, s.tokenKeys.keyFunc, jwt.WithValidMethods(s.tokenKeys.validMethods()))
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return uuid.Nil, models.ErrInviteExpired
+	}
+	if err != nil {
+		return uuid.Nil, models.ErrInvalidInvite
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || !parsed.Valid || claims["typ"] != inviteTokenType {
+		return uuid.Nil, models.ErrInvalidInvite
+	}
+
+	jti, _ := claims["jti"].(string)
+	inviteID, err := uuid.Parse(jti)
+	if err != nil {
+		return uuid.Nil, models.ErrInvalidInvite
+	}
+
+	return inviteID, nil
+}
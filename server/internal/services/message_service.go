@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 	"github.com/quickpic/server/internal/models"
@@ -9,18 +10,40 @@ import (
 )
 
 type MessageService struct {
-	messageRepo storage.MessageRepo
-	friendRepo  storage.FriendRepo
+	messageRepo  storage.MessageRepo
+	friendRepo   storage.FriendRepo
+	deviceRepo   storage.DeviceRepo
+	blockRepo    storage.BlockRepo
+	streaks      *StreakService
+	interactions *InteractionService
 }
 
-func NewMessageService(messageRepo storage.MessageRepo, friendRepo storage.FriendRepo) *MessageService {
+func NewMessageService(messageRepo storage.MessageRepo, friendRepo storage.FriendRepo, deviceRepo storage.DeviceRepo, blockRepo storage.BlockRepo, streaks *StreakService, interactions *InteractionService) *MessageService {
 	return &MessageService{
-		messageRepo: messageRepo,
-		friendRepo:  friendRepo,
+		messageRepo:  messageRepo,
+		friendRepo:   friendRepo,
+		deviceRepo:   deviceRepo,
+		blockRepo:    blockRepo,
+		streaks:      streaks,
+		interactions: interactions,
 	}
 }
 
+// SendMessage stores a single ciphertext for recipients without devices, or
+// fans out one ciphertext per device when the recipient has registered any.
+// In the latter case the request must cover exactly the current device list.
+// A recipient who has blocked the sender is reported as not found, as user
+// lookups do. Delivered messages count toward the pair's streak and the sender's
+// interactions with the recipient.
 func (s *MessageService) SendMessage(ctx context.Context, fromUserID uuid.UUID, toUserID uuid.UUID, req *models.SendMessageRequest) (*models.Message, error) {
+	blockedBy, err := s.blockRepo.IsBlocked(ctx, toUserID, fromUserID)
+	if err != nil {
+		return nil, err
+	}
+	if blockedBy {
+		return nil, models.ErrUserNotFound
+	}
+
 	// Verify users are friends
 	areFriends, err := s.friendRepo.AreFriends(ctx, fromUserID, toUserID)
 	if err != nil {
@@ -30,6 +53,11 @@ func (s *MessageService) SendMessage(ctx context.Context, fromUserID uuid.UUID,
 		return nil, models.ErrNotFriends
 	}
 
+	devices, err := s.deviceRepo.ListForUser(ctx, toUserID)
+	if err != nil {
+		return nil, err
+	}
+
 	msg := &models.Message{
 		FromUserID:       fromUserID,
 		ToUserID:         toUserID,
@@ -38,13 +66,109 @@ func (s *MessageService) SendMessage(ctx context.Context, fromUserID uuid.UUID,
 		Signature:        req.Signature,
 	}
 
-	if err := s.messageRepo.Create(ctx, msg); err != nil {
+	if len(devices) == 0 {
+		if len(req.Ciphertexts) > 0 || len(req.EncryptedContent) == 0 {
+			return nil, models.ErrDeviceMismatch
+		}
+		if err := s.messageRepo.Create(ctx, msg); err != nil {
+			return nil, err
+		}
+		if err := s.recordSent(ctx, fromUserID, toUserID); err != nil {
+			return nil, err
+		}
+		return msg, nil
+	}
+
+	if len(req.Ciphertexts) != len(devices) {
+		return nil, models.ErrDeviceMismatch
+	}
+	pending := make(map[uuid.UUID]bool, len(devices))
+	for _, d := range devices {
+		pending[d.ID] = true
+	}
+	for _, c := range req.Ciphertexts {
+		if !pending[c.DeviceID] {
+			return nil, models.ErrDeviceMismatch
+		}
+		delete(pending, c.DeviceID)
+	}
+
+	msg.EncryptedContent = nil
+	if err := s.messageRepo.CreateForDevices(ctx, msg, req.Ciphertexts); err != nil {
+		return nil, err
+	}
+	if err := s.recordSent(ctx, fromUserID, toUserID); err != nil {
 		return nil, err
 	}
 
 	return msg, nil
 }
 
-func (s *MessageService) GetPendingMessages(ctx context.Context, userID uuid.UUID) ([]models.MessageWithSender, error) {
-	return s.messageRepo.GetPendingMessages(ctx, userID)
+func (s *MessageService) recordSent(ctx context.Context, fromUserID, toUserID uuid.UUID) error {
+	if err := s.streaks.RecordMessage(ctx, fromUserID, toUserID); err != nil {
+		return err
+	}
+	return s.interactions.RecordInteraction(ctx, fromUserID, toUserID)
+}
+
+// GetPendingMessages returns the account-level inbox, or with a device ID,
+// that device's inbox which also includes the account-level messages
+func (s *MessageService) GetPendingMessages(ctx context.Context, userID uuid.UUID, deviceID *uuid.UUID) ([]models.MessageWithSender, error) {
+	if deviceID == nil {
+		return s.messageRepo.GetPendingMessages(ctx, userID)
+	}
+
+	if err := s.checkDevice(ctx, userID, *deviceID); err != nil {
+		return nil, err
+	}
+	return s.messageRepo.GetPendingForDevice(ctx, userID, *deviceID)
+}
+
+// Acknowledge deletes a received message. With a device ID only that device's
+// copy is removed and other devices can still fetch theirs. The message counts
+// as an interaction with its sender once no copies remain.
+func (s *MessageService) Acknowledge(ctx context.Context, userID, messageID uuid.UUID, deviceID *uuid.UUID) error {
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return err
+	}
+	if msg.ToUserID != userID {
+		return models.ErrMessageNotFound
+	}
+
+	if deviceID == nil {
+		// Deleting a per-device message outright would drop the copies of
+		// devices that haven't fetched it yet
+		if msg.FanOut {
+			return models.ErrDeviceIDRequired
+		}
+		if err := s.messageRepo.Delete(ctx, messageID); err != nil {
+			return err
+		}
+		return s.interactions.RecordInteraction(ctx, userID, msg.FromUserID)
+	}
+
+	if err := s.checkDevice(ctx, userID, *deviceID); err != nil {
+		return err
+	}
+	if err := s.messageRepo.AcknowledgeDelivery(ctx, messageID, *deviceID); err != nil {
+		return err
+	}
+
+	// Count the message once, when its last device copy is acknowledged
+	if _, err := s.messageRepo.GetByID(ctx, messageID); !errors.Is(err, models.ErrMessageNotFound) {
+		return err
+	}
+	return s.interactions.RecordInteraction(ctx, userID, msg.FromUserID)
+}
+
+func (s *MessageService) checkDevice(ctx context.Context, userID, deviceID uuid.UUID) error {
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device.UserID != userID {
+		return models.ErrDeviceNotFound
+	}
+	return nil
 }
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/storage"
+)
+
+type NotificationService struct {
+	notificationRepo storage.NotificationRepo
+}
+
+func NewNotificationService(notificationRepo storage.NotificationRepo) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+// Notify delivers a notification of the given type about actor to each recipient
+func (s *NotificationService) Notify(ctx context.Context, recipients []uuid.UUID, notificationType models.NotificationType, actor *models.User) error {
+	for _, recipientID := range recipients {
+		notification := &models.Notification{
+			UserID:        recipientID,
+			Type:          notificationType,
+			ActorID:       actor.ID,
+			ActorUsername: actor.Username,
+		}
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NotificationService) GetNotifications(ctx context.Context, userID uuid.UUID) ([]models.Notification, error) {
+	return s.notificationRepo.GetForUser(ctx, userID)
+}
+
+func (s *NotificationService) Acknowledge(ctx context.Context, userID, notificationID uuid.UUID) error {
+	return s.notificationRepo.Delete(ctx, userID, notificationID)
+}
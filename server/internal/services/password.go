@@ -0,0 +1,150 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// PasswordParams are the Argon2id cost parameters used for new hashes.
+// Hashes are stored in PHC string format, so the parameters a hash was
+// created with travel with it and can be changed without breaking logins.
+type PasswordParams struct {
+	Time       uint32 // iterations
+	Memory     uint32 // KiB
+	Threads    uint8
+	SaltLength uint32
+	KeyLength  uint32
+}
+
+// legacyPasswordParams describes hashes stored as raw base64(salt||hash):
+// the parameters the old code hardcoded (t=1, m=64MB, p=4). They must never
+// change, or legacy hashes stop verifying.
+var legacyPasswordParams = PasswordParams{
+	Time:       1,
+	Memory:     64 * 1024,
+	Threads:    4,
+	SaltLength: 16,
+	KeyLength:  32,
+}
+
+// DefaultPasswordParams are the legacy parameters. To tune the defaults,
+// return new values here rather than editing legacyPasswordParams.
+func DefaultPasswordParams() PasswordParams {
+	return legacyPasswordParams
+}
+
+// maxPasswordMemory caps the Argon2id memory cost, in KiB (4 GiB)
+const maxPasswordMemory = 4 * 1024 * 1024
+
+// NewPasswordParams returns the default parameters with the given costs,
+// or an error if Argon2id can't use them
+func NewPasswordParams(time, memoryKB, threads int) (PasswordParams, error) {
+	if err := checkPasswordCost(time, memoryKB, threads); err != nil {
+		return PasswordParams{}, err
+	}
+
+	params := DefaultPasswordParams()
+	params.Time = uint32(time)
+	params.Memory = uint32(memoryKB)
+	params.Threads = uint8(threads)
+	return params, nil
+}
+
+// checkPasswordCost rejects costs that make argon2.IDKey panic (zero time or
+// threads) or that don't fit the parameter types
+func checkPasswordCost(time, memoryKB, threads int) error {
+	if time < 1 || time > math.MaxUint32 {
+		return fmt.Errorf("argon2 time must be at least 1, got %d", time)
+	}
+	if threads < 1 || threads > math.MaxUint8 {
+		return fmt.Errorf("argon2 threads must be between 1 and %d, got %d", math.MaxUint8, threads)
+	}
+	if memoryKB < 8*threads || memoryKB > maxPasswordMemory {
+		return fmt.Errorf("argon2 memory must be between %d and %d KiB for %d threads, got %d",
+			8*threads, maxPasswordMemory, threads, memoryKB)
+	}
+	return nil
+}
+
+// hashPassword returns a PHC-formatted Argon2id hash:
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>
+func hashPassword(password string, params PasswordParams) (string, error) {
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate random salt: %w", err)
+	}
+
+	hash := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Time, params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// verifyPassword checks password against a PHC or legacy encoded hash.
+// needsRehash is true when the password matched but the stored hash uses
+// the legacy format or parameters other than current.
+func verifyPassword(password, encodedHash string, current PasswordParams) (ok bool, needsRehash bool) {
+	params, salt, storedHash, legacy, err := decodePasswordHash(encodedHash)
+	if err != nil {
+		return false, false
+	}
+
+	computedHash := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(storedHash)))
+
+	// Constant-time comparison
+	if subtle.ConstantTimeCompare(storedHash, computedHash) != 1 {
+		return false, false
+	}
+
+	return true, legacy || params != current
+}
+
+func decodePasswordHash(encodedHash string) (params PasswordParams, salt, hash []byte, legacy bool, err error) {
+	if !strings.HasPrefix(encodedHash, "$") {
+		combined, err := base64.StdEncoding.DecodeString(encodedHash)
+		if err != nil || len(combined) < 48 {
+			return params, nil, nil, false, fmt.Errorf("invalid legacy password hash")
+		}
+		return legacyPasswordParams, combined[:16], combined[16:], true, nil
+	}
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, false, fmt.Errorf("unsupported password hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, false, fmt.Errorf("unsupported argon2 version")
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, false, fmt.Errorf("invalid argon2 parameters: %w", err)
+	}
+	if err := checkPasswordCost(int(params.Time), int(params.Memory), int(params.Threads)); err != nil {
+		return params, nil, nil, false, fmt.Errorf("invalid argon2 parameters: %w", err)
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, false, fmt.Errorf("invalid salt: %w", err)
+	}
+	hash, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(hash) == 0 {
+		return params, nil, nil, false, fmt.Errorf("invalid hash")
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(hash))
+	return params, salt, hash, false, nil
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/keys"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/storage"
+)
+
+const (
+	maxPreKeysPerUpload = 100
+	maxStoredPreKeys    = 500
+	// Clients should rotate their signed prekey at least this often
+	signedPreKeyMaxAge = 30 * 24 * time.Hour
+)
+
+// PreKeyService hosts X3DH prekeys so senders can establish a session with
+// fresh key material instead of encrypting to the long-term identity key
+type PreKeyService struct {
+	prekeyRepo storage.PreKeyRepo
+	userRepo   storage.UserRepo
+	friendRepo storage.FriendRepo
+}
+
+func NewPreKeyService(prekeyRepo storage.PreKeyRepo, userRepo storage.UserRepo, friendRepo storage.FriendRepo) *PreKeyService {
+	return &PreKeyService{
+		prekeyRepo: prekeyRepo,
+		userRepo:   userRepo,
+		friendRepo: friendRepo,
+	}
+}
+
+// Upload replaces the user's signed prekey and all unclaimed one-time prekeys
+func (s *PreKeyService) Upload(ctx context.Context, userID uuid.UUID, req *models.UploadPreKeysRequest) (*models.PreKeyStatus, error) {
+	return s.store(ctx, userID, &req.SignedPreKey, req.OneTimePreKeys, true)
+}
+
+// Replenish adds one-time prekeys and, if given, rotates the signed prekey
+func (s *PreKeyService) Replenish(ctx context.Context, userID uuid.UUID, req *models.ReplenishPreKeysRequest) (*models.PreKeyStatus, error) {
+	if req.SignedPreKey == nil && len(req.OneTimePreKeys) == 0 {
+		return nil, models.ErrInvalidPreKey
+	}
+	return s.store(ctx, userID, req.SignedPreKey, req.OneTimePreKeys, false)
+}
+
+func (s *PreKeyService) store(ctx context.Context, userID uuid.UUID, signed *models.SignedPreKey, oneTime []models.OneTimePreKey, replace bool) (*models.PreKeyStatus, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(oneTime) > maxPreKeysPerUpload {
+		return nil, models.ErrTooManyPreKeys
+	}
+	seen := make(map[uint32]bool, len(oneTime))
+	for _, key := range oneTime {
+		if _, err := keys.ParsePublicKey(key.PublicKey); err != nil {
+			return nil, models.ErrInvalidPreKey
+		}
+		if seen[key.KeyID] {
+			return nil, models.ErrPreKeyIDUsed
+		}
+		seen[key.KeyID] = true
+	}
+
+	if !replace {
+		count, err := s.prekeyRepo.CountOneTimePreKeys(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if count+len(oneTime) > maxStoredPreKeys {
+			return nil, models.ErrTooManyPreKeys
+		}
+	}
+
+	if signed != nil {
+		if err := verifySignedPreKey(user, signed); err != nil {
+			return nil, err
+		}
+		signed.IdentityKeyVersion = user.KeyVersion
+		if err := s.prekeyRepo.SetSignedPreKey(ctx, userID, signed); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(oneTime) > 0 || replace {
+		if err := s.prekeyRepo.AddOneTimePreKeys(ctx, userID, oneTime, replace); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.Status(ctx, userID)
+}
+
+// Status reports how many one-time prekeys remain and whether the signed
+// prekey needs replacing
+func (s *PreKeyService) Status(ctx context.Context, userID uuid.UUID) (*models.PreKeyStatus, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.prekeyRepo.CountOneTimePreKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.PreKeyStatus{OneTimePreKeys: count, SignedPreKeyStale: true}
+
+	signed, err := s.prekeyRepo.GetSignedPreKey(ctx, userID)
+	if err != nil && !errors.Is(err, models.ErrPreKeyBundleNotFound) {
+		return nil, err
+	}
+	if signed != nil {
+		status.SignedPreKeyID = &signed.KeyID
+		status.SignedPreKeyStale = signed.IdentityKeyVersion != user.KeyVersion ||
+			time.Since(signed.CreatedAt) > signedPreKeyMaxAge
+	}
+
+	return status, nil
+}
+
+// ClaimBundle hands out a prekey bundle for username. Only friends may claim,
+// so strangers can't drain a user's one-time prekeys. Each one-time prekey is
+// given to at most one caller; once they run out the bundle has none.
+func (s *PreKeyService) ClaimBundle(ctx context.Context, requesterID uuid.UUID, username string) (*models.PreKeyBundle, error) {
+	target, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.ID != requesterID {
+		areFriends, err := s.friendRepo.AreFriends(ctx, requesterID, target.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !areFriends {
+			return nil, models.ErrNotFriends
+		}
+	}
+
+	// Check the signed prekey first so a one-time key isn't burnt for a
+	// bundle that can't be used. A signed prekey from before the last identity
+	// key rotation no longer verifies, so it is treated as missing.
+	signed, err := s.prekeyRepo.GetSignedPreKey(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if signed.IdentityKeyVersion != target.KeyVersion {
+		return nil, models.ErrPreKeyBundleNotFound
+	}
+
+	oneTime, err := s.prekeyRepo.ClaimOneTimePreKey(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PreKeyBundle{
+		UserID:             target.ID,
+		Username:           target.Username,
+		IdentityKey:        target.PublicKey,
+		IdentityKeyVersion: target.KeyVersion,
+		SignedPreKey:       *signed,
+		OneTimePreKey:      oneTime,
+	}, nil
+}
+
+// verifySignedPreKey checks the prekey is a valid X25519 point signed by the
+// user's current identity key over its raw bytes
+func verifySignedPreKey(user *models.User, signed *models.SignedPreKey) error {
+	key, err := keys.ParsePublicKey(signed.PublicKey)
+	if err != nil {
+		return models.ErrInvalidPreKey
+	}
+
+	identityKey, err := keys.ParsePublicKey(user.PublicKey)
+	if err != nil {
+		return models.ErrInvalidKeySignature
+	}
+	sig, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil || !keys.Verify(identityKey, key[:], sig) {
+		return models.ErrInvalidKeySignature
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/keys"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/storage"
+)
+
+type ReportService struct {
+	reportRepo  storage.ReportRepo
+	userRepo    storage.UserRepo
+	messageRepo storage.MessageRepo
+}
+
+func NewReportService(reportRepo storage.ReportRepo, userRepo storage.UserRepo, messageRepo storage.MessageRepo) *ReportService {
+	return &ReportService{
+		reportRepo:  reportRepo,
+		userRepo:    userRepo,
+		messageRepo: messageRepo,
+	}
+}
+
+// CreateReport files a report against the sender named in req. When the
+// reporter includes the message's ciphertext and signature, the report is
+// marked verified if the sender signed it. A message still on the server must
+// have been sent by the sender to the reporter, and its stored signature
+// stands in for or must match the reported one.
+func (s *ReportService) CreateReport(ctx context.Context, reporterID uuid.UUID, req *models.CreateReportRequest) (*models.Report, error) {
+	sender, err := s.userRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if sender.ID == reporterID {
+		return nil, models.ErrCannotReportSelf
+	}
+
+	report := &models.Report{
+		ReporterID:       reporterID,
+		ReportedUserID:   sender.ID,
+		MessageID:        req.MessageID,
+		Reason:           req.Reason,
+		Comment:          strings.TrimSpace(req.Comment),
+		Content:          req.Content,
+		ContentType:      req.ContentType,
+		EncryptedContent: req.EncryptedContent,
+		Signature:        req.Signature,
+	}
+
+	matchesStored := true
+	if req.MessageID != nil {
+		msg, err := s.messageRepo.GetByID(ctx, *req.MessageID)
+		switch {
+		case err == nil:
+			if msg.ToUserID != reporterID || msg.FromUserID != sender.ID {
+				return nil, models.ErrMessageNotFound
+			}
+			if len(report.EncryptedContent) == 0 {
+				report.EncryptedContent = msg.EncryptedContent
+			}
+			if report.Signature == "" {
+				report.Signature = msg.Signature
+			}
+			// Fanned-out messages keep their ciphertexts per device
+			matchesStored = report.Signature == msg.Signature &&
+				(len(msg.EncryptedContent) == 0 || bytes.Equal(report.EncryptedContent, msg.EncryptedContent))
+		case errors.Is(err, models.ErrMessageNotFound):
+			// Acknowledged messages are gone; only the reporter's copy is left
+		default:
+			return nil, err
+		}
+	}
+
+	if matchesStored && len(report.EncryptedContent) > 0 && report.Signature != "" {
+		if report.SenderKeyVersion, err = s.signingKeyVersion(ctx, sender.ID, report.EncryptedContent, report.Signature); err != nil {
+			return nil, err
+		}
+		report.Verified = report.SenderKeyVersion > 0
+	}
+
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// signingKeyVersion returns the version of the sender's identity key, current
+// or rotated out, that made signature over data, or 0 if none did
+func (s *ReportService) signingKeyVersion(ctx context.Context, senderID uuid.UUID, data []byte, signature string) (int, error) {
+	history, err := s.userRepo.GetKeyHistory(ctx, senderID)
+	if err != nil {
+		return 0, err
+	}
+	for _, version := range history {
+		if keys.VerifyEncoded(version.PublicKey, data, signature) == nil {
+			return version.Version, nil
+		}
+	}
+	return 0, nil
+}
+
+// ListReports returns the review queue for status, oldest first
+func (s *ReportService) ListReports(ctx context.Context, status models.ReportStatus, limit int) ([]models.Report, error) {
+	if status == "" {
+		status = models.ReportOpen
+	}
+	if limit == 0 {
+		limit = models.DefaultReportLimit
+	}
+	return s.reportRepo.List(ctx, status, limit)
+}
+
+// GetReport returns a report with its audit trail
+func (s *ReportService) GetReport(ctx context.Context, reportID uuid.UUID) (*models.Report, error) {
+	return s.reportRepo.Get(ctx, reportID)
+}
+
+// UpdateReport moves a report through the queue on a moderator's behalf,
+// recording who did it and why in the report's history
+func (s *ReportService) UpdateReport(ctx context.Context, moderatorID, reportID uuid.UUID, req *models.UpdateReportRequest) (*models.Report, error) {
+	if err := s.reportRepo.UpdateStatus(ctx, reportID, req.Status, moderatorID, strings.TrimSpace(req.Note)); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.Get(ctx, reportID)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/models"
+	"github.com/quickpic/server/internal/storage"
+)
+
+// DefaultStreakDay is the length of a streak day. Days start at midnight UTC.
+const DefaultStreakDay = 24 * time.Hour
+
+// StreakService tracks the days on which two friends both messaged each other.
+// Only the sender, recipient and time of each message are used.
+type StreakService struct {
+	streakRepo storage.StreakRepo
+	day        time.Duration
+}
+
+func NewStreakService(streakRepo storage.StreakRepo, day time.Duration) *StreakService {
+	return &StreakService{streakRepo: streakRepo, day: day}
+}
+
+// today numbers the current day counting from the Unix epoch
+func (s *StreakService) today() int64 {
+	return time.Now().UnixNano() / int64(s.day)
+}
+
+// RecordMessage counts a message from one friend to another toward their streak
+func (s *StreakService) RecordMessage(ctx context.Context, fromUserID, toUserID uuid.UUID) error {
+	return s.streakRepo.RecordMessage(ctx, fromUserID, toUserID, s.today())
+}
+
+// GetStreak returns the streak between a user and a friend. A streak lasts
+// until the end of the day after its last counted day, and clients are warned
+// in the last sixth of that day (four hours for a calendar day).
+func (s *StreakService) GetStreak(ctx context.Context, userID, friendID uuid.UUID) (*models.Streak, error) {
+	streak, err := s.streakRepo.GetStreak(ctx, userID, friendID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := s.current(*streak)
+	return &current, nil
+}
+
+// current turns a stored streak into what clients see, setting its expiry
+func (s *StreakService) current(streak models.Streak) models.Streak {
+	// Broken streaks read as zero even before the reset job gets to them
+	if streak.Count == 0 || streak.LastDay < s.today()-1 {
+		return models.Streak{}
+	}
+
+	expiresAt := time.Unix(0, (streak.LastDay+2)*int64(s.day)).UTC()
+	warningAt := expiresAt.Add(-s.day / 6)
+	streak.ExpiresAt = &expiresAt
+	streak.WarningAt = &warningAt
+	return streak
+}
+
+// ResetBrokenStreaks zeroes streaks that missed a day and returns how many
+func (s *StreakService) ResetBrokenStreaks(ctx context.Context) (int64, error) {
+	return s.streakRepo.ResetBrokenStreaks(ctx, s.today()-1)
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWK is a public JSON Web Key as published on the JWKS endpoint
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+// TokenKeys holds the key used to sign access tokens and every key that is
+// still accepted when verifying them. Asymmetric keys (Ed25519 or P-256) are
+// identified by their RFC 7638 thumbprint in the token's "kid" header, so a
+// new signing key can be rolled out while tokens signed by the previous one
+// remain valid until they expire.
+type TokenKeys struct {
+	method  jwt.SigningMethod
+	kid     string
+	signKey interface{}
+	verify  map[string]verificationKey
+	jwks    JWKSet
+}
+
+// NewHMACTokenKeys returns HS256 keys derived from a shared secret. The secret
+// is never published, so the JWKS document is empty in this mode.
+func NewHMACTokenKeys(secret string) *TokenKeys {
+	return &TokenKeys{
+		method:  jwt.SigningMethodHS256,
+		signKey: []byte(secret),
+		verify: map[string]verificationKey{
+			"": {method: jwt.SigningMethodHS256, key: []byte(secret)},
+		},
+		jwks: JWKSet{Keys: []JWK{}},
+	}
+}
+
+// NewTokenKeys returns asymmetric keys that sign with signer and additionally
+// accept tokens signed by any of the retired public keys
+func NewTokenKeys(signer crypto.Signer, retired ...crypto.PublicKey) (*TokenKeys, error) {
+	keys := &TokenKeys{
+		verify: make(map[string]verificationKey),
+		jwks:   JWKSet{Keys: []JWK{}},
+	}
+
+	for i, pub := range append([]crypto.PublicKey{signer.Public()}, retired...) {
+		method, jwk, err := publicJWK(pub)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := keys.verify[jwk.Kid]; dup {
+			continue
+		}
+		keys.verify[jwk.Kid] = verificationKey{method: method, key: pub}
+		keys.jwks.Keys = append(keys.jwks.Keys, jwk)
+
+		if i == 0 {
+			keys.method = method
+			keys.kid = jwk.Kid
+		}
+	}
+	keys.signKey = signer
+
+	return keys, nil
+}
+
+// LoadTokenKeys reads a PEM (PKCS#8) signing key and any number of PEM
+// verification keys (PKIX public keys, or private keys whose public half is
+// used) for tokens signed before the last rotation
+func LoadTokenKeys(signingKeyFile string, verifyKeyFiles []string) (*TokenKeys, error) {
+	block, err := readPEM(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", signingKeyFile, err)
+	}
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key type in %s", signingKeyFile)
+	}
+
+	var retired []crypto.PublicKey
+	for _, file := range verifyKeyFiles {
+		block, err := readPEM(file)
+		if err != nil {
+			return nil, err
+		}
+		if pub, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+			retired = append(retired, pub)
+			continue
+		}
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse verification key %s: %w", file, err)
+		}
+		s, ok := priv.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported verification key type in %s", file)
+		}
+		retired = append(retired, s.Public())
+	}
+
+	return NewTokenKeys(signer, retired...)
+}
+
+// JWKS returns the public verification keys
+func (k *TokenKeys) JWKS() JWKSet {
+	return k.jwks
+}
+
+func (k *TokenKeys) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.kid != "" {
+		token.Header["kid"] = k.kid
+	}
+	return token.SignedString(k.signKey)
+}
+
+func (k *TokenKeys) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	vk, ok := k.verify[kid]
+	if !ok || token.Method.Alg() != vk.method.Alg() {
+		return nil, fmt.Errorf("unknown signing key")
+	}
+	return vk.key, nil
+}
+
+func (k *TokenKeys) validMethods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, vk := range k.verify {
+		if alg := vk.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+func publicJWK(pub crypto.PublicKey) (jwt.SigningMethod, JWK, error) {
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	var method jwt.SigningMethod
+	var jwk JWK
+	var thumbprintInput string
+
+	switch key := pub.(type) {
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+		jwk = JWK{Kty: "OKP", Crv: "Ed25519", X: b64(key), Alg: "EdDSA", Use: "sig"}
+		thumbprintInput = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, jwk.X)
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, JWK{}, fmt.Errorf("unsupported ECDSA curve %s (only P-256)", key.Curve.Params().Name)
+		}
+		x, y := make([]byte, 32), make([]byte, 32)
+		key.X.FillBytes(x)
+		key.Y.FillBytes(y)
+		method = jwt.SigningMethodES256
+		jwk = JWK{Kty: "EC", Crv: "P-256", X: b64(x), Y: b64(y), Alg: "ES256", Use: "sig"}
+		thumbprintInput = fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":"%s","y":"%s"}`, jwk.X, jwk.Y)
+	default:
+		return nil, JWK{}, fmt.Errorf("unsupported public key type %T (use Ed25519 or P-256)", pub)
+	}
+
+	thumbprint := sha256.Sum256([]byte(thumbprintInput))
+	jwk.Kid = b64(thumbprint[:])
+	return method, jwk, nil
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", file)
+	}
+	return block, nil
+}
@@ -2,25 +2,354 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/keys"
 	"github.com/quickpic/server/internal/models"
 	"github.com/quickpic/server/internal/storage"
+	"github.com/quickpic/server/internal/username"
 )
 
+// DefaultUsernameReservation is how long a user's old username stays held for
+// them after a rename
+const DefaultUsernameReservation = 30 * 24 * time.Hour
+
 type UserService struct {
-	userRepo storage.UserRepo
+	userRepo      storage.UserRepo
+	friendRepo    storage.FriendRepo
+	deviceRepo    storage.DeviceRepo
+	blockRepo     storage.BlockRepo
+	profileRepo   storage.ProfileRepo
+	auditRepo     storage.AuditRepo
+	notifications *NotificationService
+	usernames     *username.Policy
+	reservation   time.Duration
 }
 
-func NewUserService(userRepo storage.UserRepo) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(
+	userRepo storage.UserRepo,
+	friendRepo storage.FriendRepo,
+	deviceRepo storage.DeviceRepo,
+	blockRepo storage.BlockRepo,
+	profileRepo storage.ProfileRepo,
+	auditRepo storage.AuditRepo,
+	notifications *NotificationService,
+	usernamePolicy *username.Policy,
+	usernameReservation time.Duration,
+) *UserService {
+	return &UserService{
+		userRepo:      userRepo,
+		friendRepo:    friendRepo,
+		deviceRepo:    deviceRepo,
+		blockRepo:     blockRepo,
+		profileRepo:   profileRepo,
+		auditRepo:     auditRepo,
+		notifications: notifications,
+		usernames:     usernamePolicy,
+		reservation:   usernameReservation,
+	}
 }
 
-func (s *UserService) GetByUsername(ctx context.Context, username string) (*models.UserPublic, error) {
-	user, err := s.userRepo.GetByUsername(ctx, username)
+// GetByUsername looks up a user as seen by viewerID. Users who have blocked
+// the viewer are reported as not found.
+func (s *UserService) GetByUsername(ctx context.Context, viewerID uuid.UUID, username string) (*models.UserPublic, error) {
+	user, err := s.lookup(ctx, viewerID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	public := user.ToPublic()
+	if public.Devices, err = s.deviceRepo.ListForUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if public.Profile, err = s.profileFor(ctx, user.ID, viewerID); err != nil {
+		return nil, err
+	}
+	return &public, nil
+}
+
+// profileFor returns the user's profile as seen by viewerID. The avatar is
+// only shown to the user and their friends.
+func (s *UserService) profileFor(ctx context.Context, userID, viewerID uuid.UUID) (models.Profile, error) {
+	profile, err := s.profileRepo.GetProfile(ctx, userID, viewerID)
+	if err != nil {
+		return models.Profile{}, err
+	}
+
+	if profile.Avatar != nil && userID != viewerID {
+		friends, err := s.friendRepo.AreFriends(ctx, userID, viewerID)
+		if err != nil {
+			return models.Profile{}, err
+		}
+		if !friends {
+			profile.Avatar = nil
+		}
+	}
+	return *profile, nil
+}
+
+// UpdateProfile sets the user's display name; an empty one clears it
+func (s *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *models.UpdateProfileRequest) (*models.Profile, error) {
+	displayName := strings.TrimSpace(req.DisplayName)
+	if !validName(displayName, models.MaxDisplayNameLength) {
+		return nil, models.ErrInvalidDisplayName
+	}
+
+	if err := s.profileRepo.SetDisplayName(ctx, userID, displayName); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profileFor(ctx, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// validName checks a display name or nickname: valid UTF-8 of at most
+// maxLength characters, none of them control characters
+func validName(name string, maxLength int) bool {
+	if !utf8.ValidString(name) || utf8.RuneCountInString(name) > maxLength {
+		return false
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetAvatar stores a new encrypted avatar. Friends can't decrypt it until
+// the user wraps its key for them with SetAvatarKeys.
+func (s *UserService) SetAvatar(ctx context.Context, userID uuid.UUID, data []byte) (*models.Avatar, error) {
+	sum := sha256.Sum256(data)
+	avatar := &models.Avatar{ID: hex.EncodeToString(sum[:]), Data: data}
+
+	if err := s.profileRepo.SetAvatar(ctx, userID, avatar); err != nil {
+		return nil, err
+	}
+	return avatar, nil
+}
+
+// SetAvatarKeys shares the current avatar with the given friends
+func (s *UserService) SetAvatarKeys(ctx context.Context, userID uuid.UUID, req *models.SetAvatarKeysRequest) error {
+	for _, key := range req.Keys {
+		if key.UserID == userID {
+			continue
+		}
+		friends, err := s.friendRepo.AreFriends(ctx, userID, key.UserID)
+		if err != nil {
+			return err
+		}
+		if !friends {
+			return models.ErrNotFriends
+		}
+	}
+
+	return s.profileRepo.SetAvatarKeys(ctx, userID, req.AvatarID, req.Keys)
+}
+
+func (s *UserService) DeleteAvatar(ctx context.Context, userID uuid.UUID) error {
+	return s.profileRepo.DeleteAvatar(ctx, userID)
+}
+
+// GetAvatar returns a user's encrypted avatar to the user or a friend
+func (s *UserService) GetAvatar(ctx context.Context, viewerID uuid.UUID, username string) (*models.Avatar, error) {
+	user, err := s.lookup(ctx, viewerID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID != viewerID {
+		friends, err := s.friendRepo.AreFriends(ctx, user.ID, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if !friends {
+			return nil, models.ErrNotFriends
+		}
+	}
+
+	return s.profileRepo.GetAvatar(ctx, user.ID)
+}
+
+// Search finds discoverable users by username prefix, one page at a time
+func (s *UserService) Search(ctx context.Context, viewerID uuid.UUID, query *models.UserSearchQuery) ([]models.UserPublic, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = models.DefaultSearchLimit
+	}
+
+	// Usernames are stored lowercase
+	return s.userRepo.Search(ctx, viewerID, strings.ToLower(query.Q), strings.ToLower(query.After), limit)
+}
+
+func (s *UserService) GetPrivacySettings(ctx context.Context, userID uuid.UUID) (*models.PrivacySettings, error) {
+	return s.userRepo.GetPrivacySettings(ctx, userID)
+}
+
+// UpdatePrivacySettings applies the settings present in req and returns the
+// result
+func (s *UserService) UpdatePrivacySettings(ctx context.Context, userID uuid.UUID, req *models.UpdatePrivacyRequest) (*models.PrivacySettings, error) {
+	settings, err := s.userRepo.GetPrivacySettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ShowInSuggestions != nil {
+		settings.ShowInSuggestions = *req.ShowInSuggestions
+	}
+	if req.Discoverable != nil {
+		settings.Discoverable = *req.Discoverable
+	}
+
+	if err := s.userRepo.UpdatePrivacySettings(ctx, userID, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// ChangeUsername renames the user, holding the old name for them for the
+// reservation period, and tells their friends. The ID and user number stay
+// the same.
+func (s *UserService) ChangeUsername(ctx context.Context, userID uuid.UUID, req *models.ChangeUsernameRequest) (*models.UserPublic, error) {
+	name, err := normalizeUsername(s.usernames, req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if name == user.Username {
+		public := user.ToPublic()
+		return &public, nil
+	}
+
+	if err := s.userRepo.ChangeUsername(ctx, user.ID, name, time.Now().Add(s.reservation)); err != nil {
+		return nil, err
+	}
+	oldUsername := user.Username
+	if user, err = s.userRepo.GetByID(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	friends, err := s.friendRepo.GetFriends(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
+	friendIDs := make([]uuid.UUID, 0, len(friends))
+	for _, f := range friends {
+		friendIDs = append(friendIDs, f.UserID)
+	}
+	if err := s.notifications.Notify(ctx, friendIDs, models.NotificationUsernameChanged, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditRepo.Record(ctx, &models.AuditEntry{
+		ActorID:  user.ID,
+		Action:   models.AuditUsernameChanged,
+		TargetID: user.ID,
+		Details:  fmt.Sprintf("from %s to %s", oldUsername, user.Username),
+	}); err != nil {
+		return nil, err
+	}
 
 	public := user.ToPublic()
 	return &public, nil
 }
+
+// KeyRotationMessage is the statement both the current and the new key sign
+// to rotate an identity key. Including the version being replaced makes each
+// signature valid for exactly one rotation.
+func KeyRotationMessage(userID uuid.UUID, currentVersion int, newPublicKey string) []byte {
+	return []byte("quickpic-rotate-key\n" + userID.String() + "\n" + strconv.Itoa(currentVersion) + "\n" + newPublicKey)
+}
+
+// RotatePublicKey replaces the user's identity key after checking that the
+// request is signed by both the current key and the new one, then tells the
+// user's friends that the key changed
+func (s *UserService) RotatePublicKey(ctx context.Context, userID uuid.UUID, req *models.RotatePublicKeyRequest) (*models.PublicKeyVersion, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := keys.ParsePublicKey(req.PublicKey); err != nil || req.PublicKey == user.PublicKey {
+		return nil, models.ErrInvalidPublicKey
+	}
+
+	message := KeyRotationMessage(user.ID, user.KeyVersion, req.PublicKey)
+	if err := keys.VerifyEncoded(user.PublicKey, message, req.Signature); err != nil {
+		return nil, models.ErrInvalidKeySignature
+	}
+	if err := keys.VerifyEncoded(req.PublicKey, message, req.NewKeySignature); err != nil {
+		return nil, models.ErrInvalidKeySignature
+	}
+
+	record, err := s.userRepo.RotatePublicKey(ctx, user.ID, user.KeyVersion, req.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	friends, err := s.friendRepo.GetFriends(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	friendIDs := make([]uuid.UUID, 0, len(friends))
+	for _, f := range friends {
+		friendIDs = append(friendIDs, f.UserID)
+	}
+	if err := s.notifications.Notify(ctx, friendIDs, models.NotificationKeyChanged, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditRepo.Record(ctx, &models.AuditEntry{
+		ActorID:  user.ID,
+		Action:   models.AuditKeyRotated,
+		TargetID: user.ID,
+		Details:  fmt.Sprintf("version %d", record.Version),
+	}); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// GetKeyHistory returns every key the user has published, oldest first
+func (s *UserService) GetKeyHistory(ctx context.Context, viewerID uuid.UUID, username string) ([]models.PublicKeyVersion, error) {
+	user, err := s.lookup(ctx, viewerID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetKeyHistory(ctx, user.ID)
+}
+
+// lookup resolves username for viewerID, hiding users who have blocked the viewer
+func (s *UserService) lookup(ctx context.Context, viewerID uuid.UUID, username string) (*models.User, error) {
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedBy, err := s.blockRepo.IsBlocked(ctx, user.ID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if blockedBy {
+		return nil, models.ErrUserNotFound
+	}
+
+	return user, nil
+}
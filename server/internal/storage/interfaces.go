@@ -10,35 +10,222 @@ import (
 
 // UserRepo defines the interface for user data operations
 type UserRepo interface {
+	// Create fails with ErrUsernameExists or ErrUsernameConfusable if the
+	// username or a lookalike is taken, and ErrUsernameReserved while one is
+	// held by ChangeUsername
 	Create(ctx context.Context, user *models.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetByUserNumber(ctx context.Context, userNumber int64) (*models.User, error)
+	UpdatePasswordHash(ctx context.Context, id uuid.UUID, passwordHash string) error
+	// ChangeUsername renames the user, keeping their ID and user number, and
+	// holds the old username and its lookalikes for them until reservedUntil.
+	// The user may take back a name held for them; one held for someone else
+	// fails with ErrUsernameReserved.
+	ChangeUsername(ctx context.Context, id uuid.UUID, newUsername string, reservedUntil time.Time) error
+	RotatePublicKey(ctx context.Context, id uuid.UUID, expectedVersion int, publicKey string) (*models.PublicKeyVersion, error)
+	GetKeyHistory(ctx context.Context, id uuid.UUID) ([]models.PublicKeyVersion, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 	StoreRefreshToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
 	ValidateRefreshToken(ctx context.Context, tokenHash string) (uuid.UUID, error)
 	DeleteRefreshToken(ctx context.Context, tokenHash string) error
 	DeleteAllRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	// Search returns up to limit discoverable users whose username starts
+	// with prefix and sorts after the given username, in username order.
+	// viewerID and users who have blocked them are left out.
+	Search(ctx context.Context, viewerID uuid.UUID, prefix, after string, limit int) ([]models.UserPublic, error)
+	GetPrivacySettings(ctx context.Context, id uuid.UUID) (*models.PrivacySettings, error)
+	UpdatePrivacySettings(ctx context.Context, id uuid.UUID, settings *models.PrivacySettings) error
+	// SetSuspended suspends the user as of now, keeping the time of an
+	// existing suspension, or lifts their suspension
+	SetSuspended(ctx context.Context, id uuid.UUID, suspended bool) error
+	// GetSuspension returns when the user was suspended, or nil if they are
+	// not. It fails with ErrUserNotFound for users that don't exist.
+	GetSuspension(ctx context.Context, id uuid.UUID) (*time.Time, error)
 }
 
 // FriendRepo defines the interface for friend-related operations
 type FriendRepo interface {
+	// CreateFriendRequest fails with ErrFriendRequestExists while a request
+	// is pending in either direction. An answered request from the same
+	// sender is replaced by the new one.
 	CreateFriendRequest(ctx context.Context, fromUserID, toUserID uuid.UUID) (*models.FriendRequest, error)
-	GetPendingRequests(ctx context.Context, userID uuid.UUID) ([]models.FriendRequestWithUser, error)
+	// GetPendingRequests leaves out requests older than maxAge, which have
+	// expired even if ExpireFriendRequests has not marked them yet
+	GetPendingRequests(ctx context.Context, userID uuid.UUID, maxAge time.Duration) ([]models.FriendRequestWithUser, error)
+	// GetOutgoingRequests returns the requests the user has sent that are
+	// pending or were rejected and are at most maxAge old, newest first
+	GetOutgoingRequests(ctx context.Context, userID uuid.UUID, maxAge time.Duration) ([]models.OutgoingFriendRequest, error)
 	GetFriendRequest(ctx context.Context, requestID uuid.UUID) (*models.FriendRequest, error)
+	// GetFriendRequestBetween returns the latest request from one user to another
+	GetFriendRequestBetween(ctx context.Context, fromUserID, toUserID uuid.UUID) (*models.FriendRequest, error)
+	// UpdateFriendRequestStatus fails with ErrFriendRequestNotFound unless the
+	// request is in one of status.PreviousStatuses(), so of two concurrent
+	// answers only the first applies
 	UpdateFriendRequestStatus(ctx context.Context, requestID uuid.UUID, status models.FriendRequestStatus) error
+	// AcceptFriendRequest marks a pending request accepted and creates the
+	// friendship in one step
+	AcceptFriendRequest(ctx context.Context, requestID uuid.UUID) error
+	// ExpireFriendRequests marks pending and rejected requests older than
+	// olderThan as expired and returns how many it marked
+	ExpireFriendRequests(ctx context.Context, olderThan time.Duration) (int64, error)
 	CreateFriendship(ctx context.Context, userAID, userBID uuid.UUID) error
+	// RemoveFriendship ends a friendship, forgets the requests between the pair
+	// and deletes their undelivered messages to each other
+	RemoveFriendship(ctx context.Context, userAID, userBID uuid.UUID) error
 	AreFriends(ctx context.Context, userAID, userBID uuid.UUID) (bool, error)
+	// GetFriends returns the user's friends in username order, each with
+	// their devices, their profile as the user sees it, the user's settings
+	// for them and their stored streak with the user (Count and LastDay only)
 	GetFriends(ctx context.Context, userID uuid.UUID) ([]models.Friend, error)
+	// GetSuggestions ranks friends of the user's friends by how many friends
+	// they share, at most limit of them. Friends, users with a pending or
+	// rejected request either way, blocks either way and users hidden from
+	// suggestions are left out.
+	GetSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]models.FriendSuggestion, error)
+	// SetVerifiedKeyVersion records the friend's key version the user has
+	// verified; 0 clears it. A friend is verified while it matches their
+	// current key version.
+	SetVerifiedKeyVersion(ctx context.Context, userID, friendID uuid.UUID, keyVersion int) error
+	GetVerifiedKeyVersion(ctx context.Context, userID, friendID uuid.UUID) (int, error)
+	// GetFriendSettings returns the user's settings for a friend, or the
+	// defaults if none were saved. Settings are removed with the friendship.
+	GetFriendSettings(ctx context.Context, userID, friendID uuid.UUID) (*models.FriendSettings, error)
+	SetFriendSettings(ctx context.Context, userID, friendID uuid.UUID, settings *models.FriendSettings) error
+}
+
+// BlockRepo defines the interface for user blocks
+type BlockRepo interface {
+	// Block records the block and, in the same step, ends any friendship and
+	// deletes requests and undelivered messages between the pair
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error)
+	ListBlocked(ctx context.Context, blockerID uuid.UUID) ([]models.BlockedUser, error)
 }
 
 // MessageRepo defines the interface for message operations
 type MessageRepo interface {
 	Create(ctx context.Context, msg *models.Message) error
+	// CreateForDevices stores one ciphertext per recipient device
+	CreateForDevices(ctx context.Context, msg *models.Message, ciphertexts []models.DeviceCiphertext) error
+	// GetPendingMessages returns account-level messages, i.e. those not addressed to individual devices
 	GetPendingMessages(ctx context.Context, userID uuid.UUID) ([]models.MessageWithSender, error)
+	// GetPendingForDevice returns account-level messages plus the device's own unacknowledged ciphertexts
+	GetPendingForDevice(ctx context.Context, userID, deviceID uuid.UUID) ([]models.MessageWithSender, error)
+	// AcknowledgeDelivery removes a device's copy, deleting the message once no device copies
+	// remain. Account-level messages are deleted outright.
+	AcknowledgeDelivery(ctx context.Context, messageID, deviceID uuid.UUID) error
 	GetByID(ctx context.Context, messageID uuid.UUID) (*models.Message, error)
 	Delete(ctx context.Context, messageID uuid.UUID) error
 	DeleteOldMessages(ctx context.Context, olderThan time.Duration) (int64, error)
 }
 
+// DeviceRepo defines the interface for a user's registered devices
+type DeviceRepo interface {
+	Create(ctx context.Context, device *models.Device) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Device, error)
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]models.Device, error)
+	// Delete removes the device along with its undelivered ciphertexts
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// PreKeyRepo defines the interface for X3DH prekey storage
+type PreKeyRepo interface {
+	SetSignedPreKey(ctx context.Context, userID uuid.UUID, key *models.SignedPreKey) error
+	GetSignedPreKey(ctx context.Context, userID uuid.UUID) (*models.SignedPreKey, error)
+	// AddOneTimePreKeys appends keys, first discarding unclaimed ones if replace is set
+	AddOneTimePreKeys(ctx context.Context, userID uuid.UUID, keys []models.OneTimePreKey, replace bool) error
+	CountOneTimePreKeys(ctx context.Context, userID uuid.UUID) (int, error)
+	// ClaimOneTimePreKey atomically removes and returns one key, or nil if none are left
+	ClaimOneTimePreKey(ctx context.Context, userID uuid.UUID) (*models.OneTimePreKey, error)
+}
+
+// NotificationRepo defines the interface for per-user notification delivery
+type NotificationRepo interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	GetForUser(ctx context.Context, userID uuid.UUID) ([]models.Notification, error)
+	Delete(ctx context.Context, userID, notificationID uuid.UUID) error
+}
+
+// InviteRepo defines the interface for friend invites
+type InviteRepo interface {
+	Create(ctx context.Context, invite *models.Invite) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Invite, error)
+	ListForUser(ctx context.Context, creatorID uuid.UUID) ([]models.Invite, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	// Redeem uses up one redemption and makes the redeemer and the creator
+	// friends in the same step. Pending requests between the two are accepted.
+	Redeem(ctx context.Context, id, redeemerID uuid.UUID) error
+}
+
+// ProfileRepo defines the interface for display names and encrypted avatars.
+// Avatars are ciphertext; their content key is stored wrapped per viewer.
+type ProfileRepo interface {
+	SetDisplayName(ctx context.Context, userID uuid.UUID, displayName string) error
+	// GetProfile returns the user's profile as seen by viewerID. Avatar is set
+	// only if the user has wrapped the current avatar's key for the viewer.
+	GetProfile(ctx context.Context, userID, viewerID uuid.UUID) (*models.Profile, error)
+	// SetAvatar replaces the user's avatar. Keys wrapped for the previous one
+	// no longer apply.
+	SetAvatar(ctx context.Context, userID uuid.UUID, avatar *models.Avatar) error
+	GetAvatar(ctx context.Context, userID uuid.UUID) (*models.Avatar, error)
+	DeleteAvatar(ctx context.Context, userID uuid.UUID) error
+	// SetAvatarKeys adds or replaces keys wrapped for viewers, failing with
+	// ErrAvatarChanged unless avatarID is the current avatar
+	SetAvatarKeys(ctx context.Context, userID uuid.UUID, avatarID string, keys []models.AvatarKey) error
+}
+
+// StreakRepo tracks message streaks between friends. Days are numbered by the
+// caller; a streak is removed with the friendship.
+type StreakRepo interface {
+	// RecordMessage notes that fromUserID messaged toUserID on day. The first
+	// day both have messaged each other extends the streak if it follows the
+	// last counted day, and starts a new one otherwise.
+	RecordMessage(ctx context.Context, fromUserID, toUserID uuid.UUID, day int64) error
+	// GetStreak returns the streak between two users, zero if there is none
+	GetStreak(ctx context.Context, userAID, userBID uuid.UUID) (*models.Streak, error)
+	// ResetBrokenStreaks zeroes streaks last counted before day and returns
+	// how many it reset
+	ResetBrokenStreaks(ctx context.Context, before int64) (int64, error)
+}
+
+// InteractionRepo tracks how often and how recently each user exchanges
+// messages with each friend. Interactions are removed with the friendship.
+type InteractionRepo interface {
+	// RecordInteraction counts an interaction by userID with friendID at t
+	RecordInteraction(ctx context.Context, userID, friendID uuid.UUID, at time.Time) error
+	// GetInteractions returns the user's interactions with each friend they
+	// have interacted with
+	GetInteractions(ctx context.Context, userID uuid.UUID) ([]models.Interaction, error)
+}
+
+// ReportRepo defines the interface for abuse reports and their review queue
+type ReportRepo interface {
+	// Create stores an open report with its creation event, failing with
+	// ErrReportExists if the reporter has already reported the message
+	Create(ctx context.Context, report *models.Report) error
+	// Get returns the report with its history
+	Get(ctx context.Context, id uuid.UUID) (*models.Report, error)
+	// List returns up to limit reports in status, oldest first, without
+	// their history
+	List(ctx context.Context, status models.ReportStatus, limit int) ([]models.Report, error)
+	// UpdateStatus moves a report to status and records the event, failing
+	// with ErrReportStatusConflict unless it is in one of
+	// status.PreviousStatuses()
+	UpdateStatus(ctx context.Context, id uuid.UUID, status models.ReportStatus, actorID uuid.UUID, note string) error
+}
+
+// StatsRepo defines the interface for aggregate figures shown to operators
+type StatsRepo interface {
+	GetStats(ctx context.Context) (*models.AdminStats, error)
+}
+
+// AuditRepo defines the interface for the append-only audit log
+type AuditRepo interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+}
+
 // Backend represents a complete storage backend with all repositories
 // Use type assertions to get the concrete repository types from backends
 type Backend interface {
@@ -53,7 +240,18 @@ type SQLiteBackend interface {
 	Backend
 	Users() UserRepo
 	Friends() FriendRepo
+	Blocks() BlockRepo
+	Invites() InviteRepo
 	Messages() MessageRepo
+	Devices() DeviceRepo
+	PreKeys() PreKeyRepo
+	Notifications() NotificationRepo
+	Profiles() ProfileRepo
+	Streaks() StreakRepo
+	Interactions() InteractionRepo
+	Reports() ReportRepo
+	Stats() StatsRepo
+	Audit() AuditRepo
 }
 
 // BlockchainBackend is implemented by the blockchain backend
@@ -61,12 +259,34 @@ type BlockchainBackend interface {
 	Backend
 	Users() UserRepo
 	Friends() FriendRepo
+	Blocks() BlockRepo
+	Invites() InviteRepo
 	Messages() MessageRepo
+	Devices() DeviceRepo
+	PreKeys() PreKeyRepo
+	Notifications() NotificationRepo
+	Profiles() ProfileRepo
+	Streaks() StreakRepo
+	Interactions() InteractionRepo
+	Reports() ReportRepo
+	Stats() StatsRepo
+	Audit() AuditRepo
 }
 
 // Repositories holds all repository interfaces for a backend
 type Repositories struct {
-	Users    UserRepo
-	Friends  FriendRepo
-	Messages MessageRepo
+	Users         UserRepo
+	Friends       FriendRepo
+	Blocks        BlockRepo
+	Invites       InviteRepo
+	Messages      MessageRepo
+	Devices       DeviceRepo
+	PreKeys       PreKeyRepo
+	Notifications NotificationRepo
+	Profiles      ProfileRepo
+	Streaks       StreakRepo
+	Interactions  InteractionRepo
+	Reports       ReportRepo
+	Stats         StatsRepo
+	Audit         AuditRepo
 }
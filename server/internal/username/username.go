@@ -0,0 +1,106 @@
+// Package username decides which usernames can be registered. Names are
+// restricted to a URL-safe ASCII charset, checked against a reserved list, and
+// reduced to a confusable skeleton so that lookalikes of an existing or
+// reserved name ("a1ice" for "alice") can be turned away.
+package username
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	MinLength = 3
+	MaxLength = 32
+)
+
+var (
+	ErrInvalid  = errors.New("invalid username")
+	ErrReserved = errors.New("username is reserved")
+)
+
+// DefaultReserved are names that could be mistaken for the service itself
+var DefaultReserved = []string{
+	"admin", "administrator", "api", "help", "me", "moderator", "null",
+	"official", "quickpic", "root", "security", "staff", "support", "system",
+	"undefined",
+}
+
+// Policy validates usernames for registration
+type Policy struct {
+	reserved map[string]struct{} // skeletons of reserved names
+}
+
+// NewPolicy returns a policy that reserves DefaultReserved plus any extra
+// names. Lookalikes of a reserved name are reserved too.
+func NewPolicy(extraReserved ...string) *Policy {
+	p := &Policy{reserved: make(map[string]struct{})}
+	for _, name := range append(append([]string{}, DefaultReserved...), extraReserved...) {
+		if name = strings.TrimSpace(name); name != "" {
+			p.reserved[Skeleton(name)] = struct{}{}
+		}
+	}
+	return p
+}
+
+// Normalize lowercases name and checks it against the charset and the
+// reserved list, returning the form to store
+func (p *Policy) Normalize(name string) (string, error) {
+	name = strings.ToLower(name)
+	if !valid(name) {
+		return "", ErrInvalid
+	}
+	if _, ok := p.reserved[Skeleton(name)]; ok {
+		return "", ErrReserved
+	}
+	return name, nil
+}
+
+func valid(name string) bool {
+	if len(name) < MinLength || len(name) > MaxLength {
+		return false
+	}
+	if name[0] < 'a' || name[0] > 'z' {
+		return false
+	}
+	if strings.HasSuffix(name, ".") || strings.Contains(name, "..") {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// lookalikes folds characters of the username charset onto the letter they
+// render like. The charset is ASCII, so homoglyphs from other scripts never
+// get this far.
+var lookalikes = map[rune]rune{
+	'0': 'o', '1': 'l', 'i': 'l', '5': 's',
+}
+
+// multiLookalikes are letter pairs that read as a single letter
+var multiLookalikes = strings.NewReplacer("rn", "m", "vv", "w")
+
+// Skeleton reduces name to a canonical form shared by its lookalikes, in the
+// spirit of the Unicode confusables skeleton: lowercase, drop separators,
+// then fold lookalike characters. Two names with the same skeleton are
+// considered confusable.
+func Skeleton(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r == '.' || r == '_' || unicode.IsSpace(r) {
+			continue
+		}
+		if folded, ok := lookalikes[r]; ok {
+			r = folded
+		}
+		b.WriteRune(r)
+	}
+	return multiLookalikes.Replace(b.String())
+}
@@ -1,8 +1,27 @@
 package e2e
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/quickpic/server/internal/api/middleware"
+	"github.com/quickpic/server/internal/keys"
+	"github.com/quickpic/server/internal/models"
+	"golang.org/x/crypto/argon2"
 )
 
 // =============================================================================
@@ -28,11 +47,7 @@ func TestHealthCheck(t *testing.T) {
 func TestRegister_Success(t *testing.T) {
 	client := NewTestClient(t)
 
-	req := RegisterRequest{
-		Username:  uniqueUsername(),
-		Password:  "password123",
-		PublicKey: fakePublicKey(),
-	}
+	req := newRegisterRequest(t, client, uniqueUsername(), "password123")
 
 	resp := client.Post("/auth/register", req)
 	client.ExpectStatus(resp, http.StatusCreated)
@@ -59,20 +74,74 @@ func TestRegister_DuplicateUsername(t *testing.T) {
 	username := uniqueUsername()
 
 	// First registration should succeed
-	req := RegisterRequest{
-		Username:  username,
-		Password:  "password123",
-		PublicKey: fakePublicKey(),
-	}
+	req := newRegisterRequest(t, client, username, "password123")
 
 	resp := client.Post("/auth/register", req)
 	client.ExpectStatus(resp, http.StatusCreated)
 	_ = resp.Body.Close()
 
 	// Second registration with same username should fail
-	resp = client.Post("/auth/register", req)
+	resp = client.Post("/auth/register", newRegisterRequest(t, client, username, "password123"))
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+}
+
+func TestRegister_UsernamePolicy(t *testing.T) {
+	client := NewTestClient(t)
+
+	for _, username := range []string{"has space", "slash/name", "emoji😀", "1leadingdigit", "_underscore", "trailing.", "double..dot", "ünicode"} {
+		t.Run("invalid "+username, func(t *testing.T) {
+			resp := client.Post("/auth/register", newRegisterRequest(t, client, username, "password123"))
+			client.ExpectStatus(resp, http.StatusBadRequest)
+			_ = resp.Body.Close()
+		})
+	}
+
+	// Reserved names, their lookalikes, and names reserved by configuration
+	for _, username := range []string{"admin", "Support", "adm1n", "quick.pic", testReservedUsername} {
+		t.Run("reserved "+username, func(t *testing.T) {
+			resp := client.Post("/auth/register", newRegisterRequest(t, client, username, "password123"))
+			client.ExpectStatus(resp, http.StatusConflict)
+			_ = resp.Body.Close()
+		})
+	}
+
+	// Usernames are stored lowercase
+	mixed := createNamedUser(t, client, "Policy.Alice")
+	if mixed.User.Username != "policy.alice" {
+		t.Errorf("Expected username 'policy.alice', got '%s'", mixed.User.Username)
+	}
+
+	// Lookalikes of an existing user are turned away
+	for _, username := range []string{"policy_a1ice", "policyalice", "po1icy.alice"} {
+		t.Run("confusable "+username, func(t *testing.T) {
+			resp := client.Post("/auth/register", newRegisterRequest(t, client, username, "password123"))
+			client.ExpectStatus(resp, http.StatusConflict)
+			var errResp map[string]string
+			client.ParseJSON(resp, &errResp)
+			if !strings.Contains(errResp["error"], "too similar") {
+				t.Errorf("Expected a confusable error, got '%s'", errResp["error"])
+			}
+		})
+	}
+
+	// An exact duplicate still reads as taken
+	resp := client.Post("/auth/register", newRegisterRequest(t, client, "policy.alice", "password123"))
 	client.ExpectStatus(resp, http.StatusConflict)
+	var errResp map[string]string
+	client.ParseJSON(resp, &errResp)
+	if errResp["error"] != "username already exists" {
+		t.Errorf("Expected 'username already exists', got '%s'", errResp["error"])
+	}
+
+	// Once the user is gone, so is the hold on their lookalikes
+	client.SetAccessToken(mixed.AccessToken)
+	resp = client.Delete("/users/me", map[string]string{"password": "password123"})
+	client.ExpectStatus(resp, http.StatusOK)
 	_ = resp.Body.Close()
+	client.SetAccessToken("")
+
+	createNamedUser(t, client, "policy_a1ice")
 }
 
 func TestRegister_InvalidInput(t *testing.T) {
@@ -116,17 +185,104 @@ func TestRegister_InvalidInput(t *testing.T) {
 	}
 }
 
+func TestRegister_RejectsInvalidPublicKey(t *testing.T) {
+	client := NewTestClient(t)
+
+	// A correctly signed request whose key is not a 32-byte X25519 point
+	req := newRegisterRequest(t, client, uniqueUsername(), "password123")
+	req.PublicKey = fakePublicKey()
+
+	resp := client.Post("/auth/register", req)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// The all-zero key is a small-order point
+	req = newRegisterRequest(t, client, uniqueUsername(), "password123")
+	req.PublicKey = base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	resp = client.Post("/auth/register", req)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+}
+
+func TestRegister_RequiresProofOfPossession(t *testing.T) {
+	client := NewTestClient(t)
+
+	// Someone else's public key, signed with our own private key
+	victim := newRegisterRequest(t, client, uniqueUsername(), "password123")
+	req := newRegisterRequest(t, client, uniqueUsername(), "password123")
+	req.PublicKey = victim.PublicKey
+
+	resp := client.Post("/auth/register", req)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// A signature made for a different username
+	req = newRegisterRequest(t, client, uniqueUsername(), "password123")
+	req.Username = uniqueUsername()
+
+	resp = client.Post("/auth/register", req)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// A nonce the server never issued
+	req = newRegisterRequest(t, client, uniqueUsername(), "password123")
+	req.Nonce = "not-a-real-nonce"
+
+	resp = client.Post("/auth/register", req)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+}
+
+func TestRegister_ChallengeIsSingleUse(t *testing.T) {
+	client := NewTestClient(t)
+
+	req := newRegisterRequest(t, client, uniqueUsername(), "password123")
+	resp := client.Post("/auth/register", req)
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	// Replaying the same signed nonce under a new name is rejected
+	replay := req
+	replay.Username = uniqueUsername()
+	replay.Signature = signRegistration(t, req.PrivateKey, req.Nonce, replay.Username)
+
+	resp = client.Post("/auth/register", replay)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+}
+
+func TestChallenge_RateLimited(t *testing.T) {
+	// The suite's router allows far more, so check the limit on its own route
+	router := gin.New()
+	router.POST("/auth/challenge", middleware.RateLimit(middleware.NewRateLimiter(2, time.Minute)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	for i, expected := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		resp, err := http.Post(server.URL+"/auth/challenge", "application/json", nil)
+		if err != nil {
+			t.Fatalf("Request %d failed: %v", i, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != expected {
+			t.Errorf("Request %d: expected status %d, got %d", i, expected, resp.StatusCode)
+		}
+		if expected == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+			t.Error("Expected a Retry-After header")
+		}
+	}
+}
+
 func TestLogin_Success(t *testing.T) {
 	client := NewTestClient(t)
 	username := uniqueUsername()
 	password := "password123"
 
 	// Register first
-	regReq := RegisterRequest{
-		Username:  username,
-		Password:  password,
-		PublicKey: fakePublicKey(),
-	}
+	regReq := newRegisterRequest(t, client, username, password)
 	resp := client.Post("/auth/register", regReq)
 	_ = resp.Body.Close()
 
@@ -155,11 +311,7 @@ func TestLogin_InvalidCredentials(t *testing.T) {
 	username := uniqueUsername()
 
 	// Register first
-	regReq := RegisterRequest{
-		Username:  username,
-		Password:  "password123",
-		PublicKey: fakePublicKey(),
-	}
+	regReq := newRegisterRequest(t, client, username, "password123")
 	resp := client.Post("/auth/register", regReq)
 	_ = resp.Body.Close()
 
@@ -187,15 +339,62 @@ func TestLogin_NonexistentUser(t *testing.T) {
 	_ = resp.Body.Close()
 }
 
+func TestRegister_StoresPHCHash(t *testing.T) {
+	client := NewTestClient(t)
+
+	user := createAuthenticatedUser(t, client)
+
+	stored, err := testBackend.Users().GetByUsername(context.Background(), user.User.Username)
+	if err != nil {
+		t.Fatalf("Failed to load user: %v", err)
+	}
+	if !strings.HasPrefix(stored.PasswordHash, "$argon2id$v=19$m=65536,t=1,p=4$") {
+		t.Errorf("Expected PHC-formatted hash, got %q", stored.PasswordHash)
+	}
+}
+
+func TestLogin_RehashesLegacyHash(t *testing.T) {
+	client := NewTestClient(t)
+	ctx := context.Background()
+
+	// Legacy format: base64(salt || argon2id(t=1, m=64MB, p=4))
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		t.Fatal(err)
+	}
+	hash := argon2.IDKey([]byte("password123"), salt, 1, 64*1024, 4, 32)
+	legacyUser := &models.User{
+		Username:     uniqueUsername(),
+		PasswordHash: base64.StdEncoding.EncodeToString(append(salt, hash...)),
+		PublicKey:    fakePublicKey(),
+	}
+	if err := testBackend.Users().Create(ctx, legacyUser); err != nil {
+		t.Fatalf("Failed to create legacy user: %v", err)
+	}
+
+	resp := client.Post("/auth/login", LoginRequest{Username: legacyUser.Username, Password: "password123"})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	stored, err := testBackend.Users().GetByID(ctx, legacyUser.ID)
+	if err != nil {
+		t.Fatalf("Failed to load user: %v", err)
+	}
+	if !strings.HasPrefix(stored.PasswordHash, "$argon2id$") {
+		t.Fatalf("Expected hash to be upgraded to PHC format, got %q", stored.PasswordHash)
+	}
+
+	// The upgraded hash still verifies
+	resp = client.Post("/auth/login", LoginRequest{Username: legacyUser.Username, Password: "password123"})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+}
+
 func TestRefreshToken_Success(t *testing.T) {
 	client := NewTestClient(t)
 
 	// Register and get tokens
-	regReq := RegisterRequest{
-		Username:  uniqueUsername(),
-		Password:  "password123",
-		PublicKey: fakePublicKey(),
-	}
+	regReq := newRegisterRequest(t, client, uniqueUsername(), "password123")
 
 	resp := client.Post("/auth/register", regReq)
 	var authResp AuthResponse
@@ -239,11 +438,7 @@ func TestLogout_Success(t *testing.T) {
 	client := NewTestClient(t)
 
 	// Register and get tokens
-	regReq := RegisterRequest{
-		Username:  uniqueUsername(),
-		Password:  "password123",
-		PublicKey: fakePublicKey(),
-	}
+	regReq := newRegisterRequest(t, client, uniqueUsername(), "password123")
 
 	resp := client.Post("/auth/register", regReq)
 	var authResp AuthResponse
@@ -268,6 +463,44 @@ func TestLogout_Success(t *testing.T) {
 	_ = resp.Body.Close()
 }
 
+func TestJWKS_PublishesSigningKey(t *testing.T) {
+	client := NewTestClient(t)
+
+	resp := client.Get("/.well-known/jwks.json")
+	client.ExpectStatus(resp, http.StatusOK)
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Crv string `json:"crv"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+		} `json:"keys"`
+	}
+	client.ParseJSON(resp, &jwks)
+
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("Expected 1 key, got %d", len(jwks.Keys))
+	}
+	if jwks.Keys[0].Kty != "OKP" || jwks.Keys[0].Crv != "Ed25519" || jwks.Keys[0].Alg != "EdDSA" {
+		t.Errorf("Unexpected key: %+v", jwks.Keys[0])
+	}
+
+	// Access tokens reference the published key by kid
+	user := createAuthenticatedUser(t, client)
+	headerJSON, err := base64.RawURLEncoding.DecodeString(strings.Split(user.AccessToken, ".")[0])
+	if err != nil {
+		t.Fatalf("Failed to decode token header: %v", err)
+	}
+	var header map[string]string
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		t.Fatalf("Failed to parse token header: %v", err)
+	}
+	if header["alg"] != "EdDSA" || header["kid"] != jwks.Keys[0].Kid {
+		t.Errorf("Expected EdDSA token with kid %s, got %v", jwks.Keys[0].Kid, header)
+	}
+}
+
 // =============================================================================
 // FRIEND TESTS
 // =============================================================================
@@ -448,149 +681,2643 @@ func TestFriends_RejectRequest(t *testing.T) {
 	}
 }
 
-func TestFriends_GetFriends(t *testing.T) {
+func TestFriends_ResendAfterRejection(t *testing.T) {
 	client := NewTestClient(t)
 
 	user1 := createAuthenticatedUser(t, client)
 	user2 := createAuthenticatedUser(t, client)
 
-	// Make them friends
-	makeFriends(t, client, user1, user2)
-
-	// User1 checks friends list
 	client.SetAccessToken(user1.AccessToken)
-	resp := client.Get("/friends")
+	resp := client.Post("/friends/request", SendFriendRequest{Username: user2.User.Username})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var friendRequest FriendRequest
+	client.ParseJSON(resp, &friendRequest)
+
+	// Resending a pending request is a conflict
+	resp = client.Post("/friends/request", SendFriendRequest{Username: user2.User.Username})
+	client.ExpectStatus(resp, http.StatusConflict)
+	var pendingErr map[string]string
+	client.ParseJSON(resp, &pendingErr)
+
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Post("/friends/reject", FriendRequestAction{RequestID: friendRequest.ID})
 	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
 
-	var friends []Friend
-	client.ParseJSON(resp, &friends)
+	// Within the cooldown the sender sees the same answer as while pending
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Post("/friends/request", SendFriendRequest{Username: user2.User.Username})
+	client.ExpectStatus(resp, http.StatusConflict)
+	var rejectedErr map[string]string
+	client.ParseJSON(resp, &rejectedErr)
+	if rejectedErr["error"] != pendingErr["error"] {
+		t.Errorf("Expected the rejection to be hidden, got %q instead of %q", rejectedErr["error"], pendingErr["error"])
+	}
 
-	if len(friends) != 1 {
-		t.Errorf("Expected 1 friend, got %d", len(friends))
+	time.Sleep(testFriendRequestCooldown)
+
+	resp = client.Post("/friends/request", SendFriendRequest{Username: user2.User.Username})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var resent FriendRequest
+	client.ParseJSON(resp, &resent)
+	if resent.ID == friendRequest.ID || resent.Status != "pending" {
+		t.Errorf("Expected a new pending request, got %+v", resent)
 	}
-	if len(friends) > 0 {
-		if friends[0].Username != user2.User.Username {
-			t.Errorf("Expected friend '%s', got '%s'", user2.User.Username, friends[0].Username)
-		}
-		if friends[0].PublicKey == "" {
-			t.Error("Expected friend's public key to be included")
-		}
+
+	// The new request can be accepted
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Get("/friends/requests")
+	client.ExpectStatus(resp, http.StatusOK)
+	var requests []FriendRequest
+	client.ParseJSON(resp, &requests)
+	if len(requests) != 1 || requests[0].ID != resent.ID {
+		t.Fatalf("Expected the resent request to be pending, got %+v", requests)
 	}
-}
 
-// =============================================================================
-// MESSAGE TESTS
-// =============================================================================
+	resp = client.Post("/friends/accept", FriendRequestAction{RequestID: resent.ID})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+}
 
-func TestMessages_Send_Success(t *testing.T) {
+func TestFriends_OutgoingAndCancel(t *testing.T) {
 	client := NewTestClient(t)
 
 	user1 := createAuthenticatedUser(t, client)
 	user2 := createAuthenticatedUser(t, client)
+	user3 := createAuthenticatedUser(t, client)
 
-	// Make them friends first
-	makeFriends(t, client, user1, user2)
-
-	// User1 sends message to User2
 	client.SetAccessToken(user1.AccessToken)
-
-	msgReq := SendMessageRequest{
-		ToUsername:       user2.User.Username,
-		EncryptedContent: "ZW5jcnlwdGVkLWNvbnRlbnQtaGVyZQ==", // base64 encoded
-		ContentType:      "text",
-		Signature:        "c2lnbmF0dXJlLWhlcmU=",
+	requestIDs := make(map[string]string)
+	for _, to := range []AuthResponse{user2, user3} {
+		resp := client.Post("/friends/request", SendFriendRequest{Username: to.User.Username})
+		client.ExpectStatus(resp, http.StatusCreated)
+		var request FriendRequest
+		client.ParseJSON(resp, &request)
+		requestIDs[to.User.Username] = request.ID
 	}
 
-	resp := client.Post("/messages", msgReq)
-	client.ExpectStatus(resp, http.StatusCreated)
+	client.SetAccessToken(user2.AccessToken)
+	resp := client.Post("/friends/reject", FriendRequestAction{RequestID: requestIDs[user2.User.Username]})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
 
-	var msgResp MessageResponse
-	client.ParseJSON(resp, &msgResp)
+	// Only the sender may cancel
+	resp = client.Post("/friends/cancel", FriendRequestAction{RequestID: requestIDs[user3.User.Username]})
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
 
-	if msgResp.ID == "" {
-		t.Error("Expected message ID to be set")
+	// The rejected request still shows as pending to the sender
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Get("/friends/requests/outgoing")
+	client.ExpectStatus(resp, http.StatusOK)
+	var outgoing []OutgoingFriendRequest
+	client.ParseJSON(resp, &outgoing)
+	if len(outgoing) != 2 {
+		t.Fatalf("Expected 2 outgoing requests, got %d", len(outgoing))
+	}
+	for _, request := range outgoing {
+		if request.Status != "pending" {
+			t.Errorf("Expected request to %s to show as pending, got %s", request.ToUser.Username, request.Status)
+		}
+		if request.ID != requestIDs[request.ToUser.Username] {
+			t.Errorf("Unexpected outgoing request %+v", request)
+		}
 	}
-}
 
-func TestMessages_Send_NotFriends(t *testing.T) {
-	client := NewTestClient(t)
+	resp = client.Post("/friends/cancel", FriendRequestAction{RequestID: requestIDs[user3.User.Username]})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
 
-	user1 := createAuthenticatedUser(t, client)
-	user2 := createAuthenticatedUser(t, client)
+	resp = client.Post("/friends/cancel", FriendRequestAction{RequestID: requestIDs[user3.User.Username]})
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
 
-	// Don't make them friends - try to send message
-	client.SetAccessToken(user1.AccessToken)
+	resp = client.Get("/friends/requests/outgoing")
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &outgoing)
+	if len(outgoing) != 1 || outgoing[0].ToUser.Username != user2.User.Username {
+		t.Errorf("Expected only the request to %s to remain, got %+v", user2.User.Username, outgoing)
+	}
 
-	msgReq := SendMessageRequest{
-		ToUsername:       user2.User.Username,
-		EncryptedContent: "ZW5jcnlwdGVkLWNvbnRlbnQ=",
-		ContentType:      "text",
-		Signature:        "c2lnbmF0dXJl",
+	// The recipient no longer sees a cancelled request
+	client.SetAccessToken(user3.AccessToken)
+	resp = client.Get("/friends/requests")
+	client.ExpectStatus(resp, http.StatusOK)
+	var incoming []FriendRequest
+	client.ParseJSON(resp, &incoming)
+	if len(incoming) != 0 {
+		t.Errorf("Expected no incoming requests after cancel, got %d", len(incoming))
 	}
 
-	resp := client.Post("/messages", msgReq)
-	client.ExpectStatus(resp, http.StatusForbidden)
+	resp = client.Post("/friends/accept", FriendRequestAction{RequestID: requestIDs[user3.User.Username]})
+	client.ExpectStatus(resp, http.StatusNotFound)
 	_ = resp.Body.Close()
-}
-
-func TestMessages_Send_UserNotFound(t *testing.T) {
-	client := NewTestClient(t)
 
-	user := createAuthenticatedUser(t, client)
-	client.SetAccessToken(user.AccessToken)
+	// Cancelling starts the same cooldown as a rejection
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Post("/friends/request", SendFriendRequest{Username: user3.User.Username})
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
 
-	msgReq := SendMessageRequest{
-		ToUsername:       "nonexistentuser",
-		EncryptedContent: "ZW5jcnlwdGVkLWNvbnRlbnQ=",
-		ContentType:      "text",
-		Signature:        "c2lnbmF0dXJl",
-	}
+	time.Sleep(testFriendRequestCooldown)
 
-	resp := client.Post("/messages", msgReq)
-	client.ExpectStatus(resp, http.StatusNotFound)
+	resp = client.Post("/friends/request", SendFriendRequest{Username: user3.User.Username})
+	client.ExpectStatus(resp, http.StatusCreated)
 	_ = resp.Body.Close()
 }
 
-func TestMessages_GetPending(t *testing.T) {
+func TestFriends_RequestsExpire(t *testing.T) {
 	client := NewTestClient(t)
 
 	user1 := createAuthenticatedUser(t, client)
 	user2 := createAuthenticatedUser(t, client)
+	user3 := createAuthenticatedUser(t, client)
+	user4 := createAuthenticatedUser(t, client)
 
-	makeFriends(t, client, user1, user2)
-
-	// User1 sends message to User2
+	// user2 never answers, user3 rejects
 	client.SetAccessToken(user1.AccessToken)
-	msgReq := SendMessageRequest{
-		ToUsername:       user2.User.Username,
-		EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
-		ContentType:      "text",
-		Signature:        "c2lnbmF0dXJl",
+	for _, to := range []AuthResponse{user2, user3} {
+		resp := client.Post("/friends/request", SendFriendRequest{Username: to.User.Username})
+		client.ExpectStatus(resp, http.StatusCreated)
+		if to.User.Username == user3.User.Username {
+			var request FriendRequest
+			client.ParseJSON(resp, &request)
+			client.SetAccessToken(user3.AccessToken)
+			resp = client.Post("/friends/reject", FriendRequestAction{RequestID: request.ID})
+			client.ExpectStatus(resp, http.StatusOK)
+		}
+		_ = resp.Body.Close()
 	}
-	resp := client.Post("/messages", msgReq)
-	_ = resp.Body.Close()
 
-	// User2 fetches pending messages
+	client.SetAccessToken(user4.AccessToken)
+	resp := client.Post("/friends/request", SendFriendRequest{Username: user1.User.Username})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var incoming FriendRequest
+	client.ParseJSON(resp, &incoming)
+
+	time.Sleep(testFriendRequestTTL)
+
+	// Expired requests drop out of both lists before any sweep
 	client.SetAccessToken(user2.AccessToken)
-	resp = client.Get("/messages")
+	resp = client.Get("/friends/requests")
 	client.ExpectStatus(resp, http.StatusOK)
+	var requests []FriendRequest
+	client.ParseJSON(resp, &requests)
+	if len(requests) != 0 {
+		t.Errorf("Expected no pending requests after expiry, got %+v", requests)
+	}
 
-	var messages []Message
-	client.ParseJSON(resp, &messages)
-
-	if len(messages) != 1 {
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Get("/friends/requests/outgoing")
+	client.ExpectStatus(resp, http.StatusOK)
+	var outgoing []OutgoingFriendRequest
+	client.ParseJSON(resp, &outgoing)
+	if len(outgoing) != 0 {
+		t.Errorf("Expected no outgoing requests after expiry, got %+v", outgoing)
+	}
+
+	resp = client.Post("/friends/accept", FriendRequestAction{RequestID: incoming.ID})
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	// An expired incoming request doesn't stop a request the other way
+	resp = client.Post("/friends/request", SendFriendRequest{Username: user4.User.Username})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	// Expiry starts the cooldown, the same way for unanswered and rejected requests
+	var errs []map[string]string
+	for _, to := range []AuthResponse{user2, user3} {
+		resp = client.Post("/friends/request", SendFriendRequest{Username: to.User.Username})
+		client.ExpectStatus(resp, http.StatusConflict)
+		var errResp map[string]string
+		client.ParseJSON(resp, &errResp)
+		errs = append(errs, errResp)
+	}
+	if errs[0]["error"] != errs[1]["error"] {
+		t.Errorf("Expected the rejection to be hidden, got %q and %q", errs[0]["error"], errs[1]["error"])
+	}
+
+	expired, err := testFriendService.ExpireFriendRequests(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to expire friend requests: %v", err)
+	}
+	if expired != 2 {
+		t.Errorf("Expected 2 requests to be marked expired, got %d", expired)
+	}
+
+	time.Sleep(testFriendRequestCooldown)
+
+	for _, to := range []AuthResponse{user2, user3} {
+		resp = client.Post("/friends/request", SendFriendRequest{Username: to.User.Username})
+		client.ExpectStatus(resp, http.StatusCreated)
+		_ = resp.Body.Close()
+	}
+
+	resp = client.Get("/friends/requests/outgoing")
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &outgoing)
+	if len(outgoing) != 3 {
+		t.Errorf("Expected 3 outgoing requests, got %+v", outgoing)
+	}
+}
+
+func TestUsers_Search(t *testing.T) {
+	client := NewTestClient(t)
+
+	viewer := createNamedUser(t, client, "searchviewer")
+	alan := createNamedUser(t, client, "searchalan")
+	alice := createNamedUser(t, client, "searchalice")
+	alex := createNamedUser(t, client, "searchalex")
+	amy := createNamedUser(t, client, "searchamy")
+	createNamedUser(t, client, "searchbob")
+
+	// alex opts out of search, amy blocks the viewer
+	hidden := false
+	client.SetAccessToken(alex.AccessToken)
+	resp := client.Put("/users/me/privacy", UpdatePrivacyRequest{Discoverable: &hidden})
+	client.ExpectStatus(resp, http.StatusOK)
+	var privacy PrivacySettings
+	client.ParseJSON(resp, &privacy)
+	if privacy.Discoverable || !privacy.ShowInSuggestions {
+		t.Errorf("Expected only discoverable to change, got %+v", privacy)
+	}
+
+	client.SetAccessToken(amy.AccessToken)
+	resp = client.Post("/users/"+viewer.User.Username+"/block", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	search := func(query string) []string {
+		t.Helper()
+		resp := client.Get("/users?" + query)
+		client.ExpectStatus(resp, http.StatusOK)
+		var users []UserPublic
+		client.ParseJSON(resp, &users)
+		names := make([]string, len(users))
+		for i, u := range users {
+			names[i] = u.Username
+		}
+		return names
+	}
+
+	client.SetAccessToken(viewer.AccessToken)
+	if got := search("q=SearchAL"); strings.Join(got, ",") != "searchalan,searchalice" {
+		t.Errorf("Expected case-insensitive prefix matches in order, got %v", got)
+	}
+	if got := search("q=search"); strings.Join(got, ",") != "searchalan,searchalice,searchbob" {
+		t.Errorf("Expected the viewer, hidden and blocking users left out, got %v", got)
+	}
+
+	// Paging with the last username seen
+	if got := search("q=searchal&limit=1"); len(got) != 1 || got[0] != alan.User.Username {
+		t.Errorf("Expected the first page to be [searchalan], got %v", got)
+	}
+	if got := search("q=searchal&limit=1&after=searchalan"); len(got) != 1 || got[0] != alice.User.Username {
+		t.Errorf("Expected the second page to be [searchalice], got %v", got)
+	}
+	if got := search("q=searchal&limit=1&after=searchalice"); len(got) != 0 {
+		t.Errorf("Expected no third page, got %v", got)
+	}
+
+	// Not discoverable still allows an exact lookup
+	resp = client.Get("/users/" + alex.User.Username)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	for _, query := range []string{"", "q=", "q=searchal&limit=51"} {
+		resp = client.Get("/users?" + query)
+		client.ExpectStatus(resp, http.StatusBadRequest)
+		_ = resp.Body.Close()
+	}
+}
+
+func TestUsers_ChangeUsername(t *testing.T) {
+	client := NewTestClient(t)
+	user := createNamedUser(t, client, "renameold")
+	friend := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user, friend)
+	other := createAuthenticatedUser(t, client)
+
+	client.SetAccessToken(user.AccessToken)
+	resp := client.Put("/users/me/username", map[string]string{"username": "RenameNew"})
+	client.ExpectStatus(resp, http.StatusOK)
+	var renamed UserPublic
+	client.ParseJSON(resp, &renamed)
+	if renamed.Username != "renamenew" || renamed.ID != user.User.ID || renamed.UserNumber != user.User.UserNumber {
+		t.Errorf("Expected renamenew with the same ID and user number, got %+v", renamed)
+	}
+
+	// The new name resolves, the old one doesn't, and login uses the new name
+	resp = client.Get("/users/renamenew")
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+	resp = client.Get("/users/renameold")
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/auth/login", LoginRequest{Username: "renamenew", Password: "password123"})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	// Friends see the new name and are told about it
+	client.SetAccessToken(friend.AccessToken)
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+	if len(friends) != 1 || friends[0].Username != "renamenew" {
+		t.Errorf("Expected friend renamenew, got %+v", friends)
+	}
+
+	resp = client.Get("/notifications")
+	client.ExpectStatus(resp, http.StatusOK)
+	var notifications []Notification
+	client.ParseJSON(resp, &notifications)
+	if len(notifications) != 1 || notifications[0].Type != "username_changed" || notifications[0].ActorUsername != "renamenew" {
+		t.Errorf("Expected a username_changed notification for renamenew, got %+v", notifications)
+	}
+
+	// The old name and its lookalikes are held from others, by rename or registration
+	client.SetAccessToken(other.AccessToken)
+	for _, name := range []string{"renameold", "rename0ld"} {
+		resp = client.Put("/users/me/username", map[string]string{"username": name})
+		client.ExpectStatus(resp, http.StatusConflict)
+		_ = resp.Body.Close()
+	}
+	client.SetAccessToken("")
+	resp = client.Post("/auth/register", newRegisterRequest(t, client, "renameold", "password123"))
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	// Taken, confusable, reserved and invalid names are refused
+	client.SetAccessToken(other.AccessToken)
+	for name, status := range map[string]int{
+		"renamenew":  http.StatusConflict,
+		"renarnenew": http.StatusConflict,
+		"support":    http.StatusConflict,
+		"bad name":   http.StatusBadRequest,
+	} {
+		resp = client.Put("/users/me/username", map[string]string{"username": name})
+		client.ExpectStatus(resp, status)
+		_ = resp.Body.Close()
+	}
+
+	// The owner can take their old name back while it is held
+	client.SetAccessToken(user.AccessToken)
+	resp = client.Put("/users/me/username", map[string]string{"username": "renameold"})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+	resp = client.Put("/users/me/username", map[string]string{"username": "renamenew"})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	// Once the hold lapses the old name is free
+	time.Sleep(testUsernameReservation + 100*time.Millisecond)
+	client.SetAccessToken(other.AccessToken)
+	resp = client.Put("/users/me/username", map[string]string{"username": "renameold"})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+}
+
+func TestUsers_ProfileAndAvatar(t *testing.T) {
+	client := NewTestClient(t)
+	user := createAuthenticatedUser(t, client)
+	friend := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user, friend)
+	stranger := createAuthenticatedUser(t, client)
+
+	client.SetAccessToken(user.AccessToken)
+	resp := client.Put("/users/me/profile", map[string]string{"display_name": "  Ada Lovelace  "})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	for _, name := range []string{strings.Repeat("x", 65), "tab\tname"} {
+		resp = client.Put("/users/me/profile", map[string]string{"display_name": name})
+		client.ExpectStatus(resp, http.StatusBadRequest)
+		_ = resp.Body.Close()
+	}
+
+	ciphertext := []byte("encrypted-avatar-bytes")
+	resp = client.PutBytes("/users/me/avatar", ciphertext)
+	client.ExpectStatus(resp, http.StatusOK)
+	var avatar Avatar
+	client.ParseJSON(resp, &avatar)
+	if len(avatar.ID) != 64 {
+		t.Fatalf("Expected a hex SHA-256 avatar id, got %q", avatar.ID)
+	}
+
+	// Keys can only be wrapped for friends
+	wrapped := base64.StdEncoding.EncodeToString([]byte("key-wrapped-for-friend"))
+	resp = client.Put("/users/me/avatar/keys", map[string]interface{}{
+		"avatar_id": avatar.ID,
+		"keys":      []map[string]string{{"user_id": stranger.User.ID, "wrapped_key": wrapped}},
+	})
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
+
+	resp = client.Put("/users/me/avatar/keys", map[string]interface{}{
+		"avatar_id": avatar.ID,
+		"keys":      []map[string]string{{"user_id": friend.User.ID, "wrapped_key": wrapped}},
+	})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	// The friend sees the display name and their wrapped key, in both places
+	client.SetAccessToken(friend.AccessToken)
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	if resp.Header.Get("ETag") == "" || resp.Header.Get("Cache-Control") != "private, no-cache" {
+		t.Errorf("Expected private caching headers, got %v", resp.Header)
+	}
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+	if len(friends) != 1 || friends[0].DisplayName != "Ada Lovelace" || friends[0].Avatar == nil ||
+		friends[0].Avatar.ID != avatar.ID || friends[0].Avatar.WrappedKey != wrapped {
+		t.Errorf("Expected the profile and wrapped key in the friend list, got %+v", friends)
+	}
+
+	resp = client.Get("/users/" + user.User.Username)
+	client.ExpectStatus(resp, http.StatusOK)
+	etag := resp.Header.Get("ETag")
+	var profile UserPublic
+	client.ParseJSON(resp, &profile)
+	if profile.DisplayName != "Ada Lovelace" || profile.Avatar == nil || profile.Avatar.WrappedKey != wrapped {
+		t.Errorf("Expected the profile and wrapped key, got %+v", profile)
+	}
+
+	resp = client.GetIfNoneMatch("/users/"+user.User.Username, etag)
+	client.ExpectStatus(resp, http.StatusNotModified)
+	_ = resp.Body.Close()
+
+	// The blob itself is served as-is and revalidated by its id
+	resp = client.Get("/users/" + user.User.Username + "/avatar")
+	client.ExpectStatus(resp, http.StatusOK)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != string(ciphertext) || resp.Header.Get("ETag") != `"`+avatar.ID+`"` {
+		t.Errorf("Expected the avatar ciphertext tagged with its id, got %q (ETag %s)", body, resp.Header.Get("ETag"))
+	}
+
+	resp = client.GetIfNoneMatch("/users/"+user.User.Username+"/avatar", `"`+avatar.ID+`"`)
+	client.ExpectStatus(resp, http.StatusNotModified)
+	_ = resp.Body.Close()
+
+	// Strangers see the display name only and can't fetch the blob
+	client.SetAccessToken(stranger.AccessToken)
+	resp = client.Get("/users/" + user.User.Username)
+	client.ExpectStatus(resp, http.StatusOK)
+	profile = UserPublic{}
+	client.ParseJSON(resp, &profile)
+	if profile.DisplayName != "Ada Lovelace" || profile.Avatar != nil {
+		t.Errorf("Expected the display name without an avatar, got %+v", profile)
+	}
+
+	resp = client.Get("/users/" + user.User.Username + "/avatar")
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
+
+	// A new avatar drops the keys wrapped for the old one
+	client.SetAccessToken(user.AccessToken)
+	resp = client.PutBytes("/users/me/avatar", []byte("another-encrypted-avatar"))
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Put("/users/me/avatar/keys", map[string]interface{}{
+		"avatar_id": avatar.ID,
+		"keys":      []map[string]string{{"user_id": friend.User.ID, "wrapped_key": wrapped}},
+	})
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(friend.AccessToken)
+	resp = client.Get("/users/" + user.User.Username)
+	client.ExpectStatus(resp, http.StatusOK)
+	profile = UserPublic{}
+	client.ParseJSON(resp, &profile)
+	if profile.Avatar != nil {
+		t.Errorf("Expected no avatar until a key is wrapped for it, got %+v", profile.Avatar)
+	}
+
+	client.SetAccessToken(user.AccessToken)
+	resp = client.PutBytes("/users/me/avatar", make([]byte, 512<<10+1))
+	client.ExpectStatus(resp, http.StatusRequestEntityTooLarge)
+	_ = resp.Body.Close()
+
+	resp = client.Delete("/users/me/avatar", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(friend.AccessToken)
+	resp = client.Get("/users/" + user.User.Username + "/avatar")
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+}
+
+func TestFriends_ConcurrentAnswers(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testConcurrentAnswers(t, NewTestClient(t))
+	})
+
+	// Pooled connections to a database file lock against each other, which
+	// the single connection to the in-memory database never does
+	t.Run("file", func(t *testing.T) {
+		env, err := newTestEnv(filepath.Join(t.TempDir(), "quickpic.db"))
+		if err != nil {
+			t.Fatalf("Failed to create file-backed test environment: %v", err)
+		}
+		t.Cleanup(env.Close)
+
+		testConcurrentAnswers(t, &TestClient{t: t, baseURL: env.server.URL})
+	})
+}
+
+func testConcurrentAnswers(t *testing.T, client *TestClient) {
+	recipient := createAuthenticatedUser(t, client)
+
+	// Each round races an accept against a reject, a cancel by the sender or
+	// a second accept. Exactly one answer applies, and the friendship exists
+	// exactly when the accept won.
+	for round, rival := range []string{"reject", "cancel", "accept", "reject", "cancel", "accept"} {
+		sender := createAuthenticatedUser(t, client)
+
+		client.SetAccessToken(sender.AccessToken)
+		resp := client.Post("/friends/request", SendFriendRequest{Username: recipient.User.Username})
+		client.ExpectStatus(resp, http.StatusCreated)
+		var request FriendRequest
+		client.ParseJSON(resp, &request)
+
+		rivalToken := recipient.AccessToken
+		if rival == "cancel" {
+			rivalToken = sender.AccessToken
+		}
+		answers := []struct{ path, token string }{
+			{"/friends/accept", recipient.AccessToken},
+			{"/friends/" + rival, rivalToken},
+		}
+
+		var wg sync.WaitGroup
+		statuses := make([]int, len(answers))
+		for i, answer := range answers {
+			wg.Add(1)
+			go func(i int, path, token string) {
+				defer wg.Done()
+				// A separate client per goroutine; NewTestClient would reset the database
+				c := &TestClient{t: t, baseURL: client.baseURL, accessToken: token}
+				resp := c.Post(path, FriendRequestAction{RequestID: request.ID})
+				statuses[i] = resp.StatusCode
+				_ = resp.Body.Close()
+			}(i, answer.path, answer.token)
+		}
+		wg.Wait()
+
+		wins := 0
+		for _, status := range statuses {
+			switch status {
+			case http.StatusOK:
+				wins++
+			case http.StatusNotFound:
+			default:
+				t.Errorf("Round %d (accept vs %s): unexpected status %d", round, rival, status)
+			}
+		}
+		if wins != 1 {
+			t.Errorf("Round %d (accept vs %s): expected exactly one answer to apply, got statuses %v", round, rival, statuses)
+		}
+		accepted := statuses[0] == http.StatusOK || (rival == "accept" && statuses[1] == http.StatusOK)
+
+		client.SetAccessToken(recipient.AccessToken)
+		resp = client.Get("/friends")
+		client.ExpectStatus(resp, http.StatusOK)
+		var friends []Friend
+		client.ParseJSON(resp, &friends)
+		befriended := false
+		for _, friend := range friends {
+			if friend.Username == sender.User.Username {
+				befriended = true
+			}
+		}
+		if befriended != accepted {
+			t.Errorf("Round %d (accept vs %s): accepted=%v but friendship exists=%v", round, rival, accepted, befriended)
+		}
+
+		// The losing answer left nothing behind for the recipient to act on
+		resp = client.Get("/friends/requests")
+		client.ExpectStatus(resp, http.StatusOK)
+		var requests []FriendRequest
+		client.ParseJSON(resp, &requests)
+		if len(requests) != 0 {
+			t.Errorf("Round %d (accept vs %s): expected no pending requests, got %+v", round, rival, requests)
+		}
+	}
+}
+
+func TestFriends_Suggestions(t *testing.T) {
+	client := NewTestClient(t)
+
+	user := createAuthenticatedUser(t, client)
+	friendA := createAuthenticatedUser(t, client)
+	friendB := createAuthenticatedUser(t, client)
+	bothMutual := createAuthenticatedUser(t, client)
+	oneMutual := createAuthenticatedUser(t, client)
+	blocked := createAuthenticatedUser(t, client)
+	requested := createAuthenticatedUser(t, client)
+	hidden := createAuthenticatedUser(t, client)
+
+	makeFriends(t, client, user, friendA)
+	makeFriends(t, client, user, friendB)
+	makeFriends(t, client, friendA, bothMutual)
+	makeFriends(t, client, friendB, bothMutual)
+	makeFriends(t, client, friendA, oneMutual)
+	makeFriends(t, client, friendA, blocked)
+	makeFriends(t, client, friendB, requested)
+	makeFriends(t, client, friendB, hidden)
+
+	client.SetAccessToken(user.AccessToken)
+	resp := client.Post("/users/"+blocked.User.Username+"/block", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/friends/request", SendFriendRequest{Username: requested.User.Username})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	// Suggestions are on by default
+	client.SetAccessToken(hidden.AccessToken)
+	resp = client.Get("/users/me/privacy")
+	client.ExpectStatus(resp, http.StatusOK)
+	var privacy PrivacySettings
+	client.ParseJSON(resp, &privacy)
+	if !privacy.ShowInSuggestions {
+		t.Errorf("Expected suggestions to be on by default")
+	}
+
+	off := false
+	resp = client.Put("/users/me/privacy", UpdatePrivacyRequest{ShowInSuggestions: &off})
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &privacy)
+	if privacy.ShowInSuggestions {
+		t.Errorf("Expected suggestions to be off after opting out")
+	}
+
+	// Ranked by mutual friends; friends, blocked, requested and opted-out users left out
+	client.SetAccessToken(user.AccessToken)
+	resp = client.Get("/friends/suggestions")
+	client.ExpectStatus(resp, http.StatusOK)
+	var suggestions []FriendSuggestion
+	client.ParseJSON(resp, &suggestions)
+	if len(suggestions) != 2 ||
+		suggestions[0].Username != bothMutual.User.Username || suggestions[0].MutualFriends != 2 ||
+		suggestions[1].Username != oneMutual.User.Username || suggestions[1].MutualFriends != 1 {
+		t.Fatalf("Unexpected suggestions: %+v", suggestions)
+	}
+
+	resp = client.Get("/friends/suggestions?limit=1")
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &suggestions)
+	if len(suggestions) != 1 || suggestions[0].Username != bothMutual.User.Username {
+		t.Errorf("Expected only the top suggestion, got %+v", suggestions)
+	}
+
+	resp = client.Get("/friends/suggestions?limit=500")
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// Opting back in, and an empty update leaving settings alone
+	on := true
+	client.SetAccessToken(hidden.AccessToken)
+	resp = client.Put("/users/me/privacy", UpdatePrivacyRequest{ShowInSuggestions: &on})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+	resp = client.Put("/users/me/privacy", UpdatePrivacyRequest{})
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &privacy)
+	if !privacy.ShowInSuggestions {
+		t.Errorf("Expected an empty update to keep suggestions on")
+	}
+
+	client.SetAccessToken(user.AccessToken)
+	resp = client.Get("/friends/suggestions")
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &suggestions)
+	if len(suggestions) != 3 || suggestions[0].Username != bothMutual.User.Username {
+		t.Errorf("Expected the opted-in user to be suggested again, got %+v", suggestions)
+	}
+}
+
+func TestInvites_Redeem(t *testing.T) {
+	client := NewTestClient(t)
+
+	creator := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	user3 := createAuthenticatedUser(t, client)
+	user4 := createAuthenticatedUser(t, client)
+
+	// A pending request is settled by redeeming
+	client.SetAccessToken(creator.AccessToken)
+	resp := client.Post("/friends/request", SendFriendRequest{Username: user2.User.Username})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/friends/invites", CreateInviteRequest{MaxUses: 2})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var invite Invite
+	client.ParseJSON(resp, &invite)
+	if invite.Token == "" || invite.MaxUses != 2 || invite.Uses != 0 {
+		t.Fatalf("Unexpected invite %+v", invite)
+	}
+
+	resp = client.Post("/friends/invites/redeem", RedeemInviteRequest{Token: invite.Token})
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Post("/friends/invites/redeem", RedeemInviteRequest{Token: invite.Token})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var friend Friend
+	client.ParseJSON(resp, &friend)
+	if friend.Username != creator.User.Username {
+		t.Errorf("Expected to befriend %s, got %s", creator.User.Username, friend.Username)
+	}
+
+	resp = client.Get("/friends/requests")
+	client.ExpectStatus(resp, http.StatusOK)
+	var requests []FriendRequest
+	client.ParseJSON(resp, &requests)
+	if len(requests) != 0 {
+		t.Errorf("Expected the pending request to be settled, got %d", len(requests))
+	}
+
+	resp = client.Post("/friends/invites/redeem", RedeemInviteRequest{Token: invite.Token})
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(user3.AccessToken)
+	resp = client.Post("/friends/invites/redeem", RedeemInviteRequest{Token: invite.Token})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	// Both uses are gone
+	client.SetAccessToken(user4.AccessToken)
+	resp = client.Post("/friends/invites/redeem", RedeemInviteRequest{Token: invite.Token})
+	client.ExpectStatus(resp, http.StatusGone)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(creator.AccessToken)
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+	if len(friends) != 2 {
+		t.Errorf("Expected 2 friends from the invite, got %d", len(friends))
+	}
+}
+
+func TestInvites_InvalidTokens(t *testing.T) {
+	client := NewTestClient(t)
+
+	user := createAuthenticatedUser(t, client)
+	client.SetAccessToken(user.AccessToken)
+
+	for _, token := range []string{"not-a-token", user.AccessToken} {
+		resp := client.Post("/friends/invites/redeem", RedeemInviteRequest{Token: token})
+		client.ExpectStatus(resp, http.StatusBadRequest)
+		_ = resp.Body.Close()
+	}
+
+	// An invite token is not an access token
+	resp := client.Post("/friends/invites", CreateInviteRequest{})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var invite Invite
+	client.ParseJSON(resp, &invite)
+
+	client.SetAccessToken(invite.Token)
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusUnauthorized)
+	_ = resp.Body.Close()
+}
+
+func TestInvites_Revoke(t *testing.T) {
+	client := NewTestClient(t)
+
+	creator := createAuthenticatedUser(t, client)
+	other := createAuthenticatedUser(t, client)
+
+	client.SetAccessToken(creator.AccessToken)
+	resp := client.Post("/friends/invites", CreateInviteRequest{ExpiresInHours: 1})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var invite Invite
+	client.ParseJSON(resp, &invite)
+	if invite.MaxUses != 1 {
+		t.Errorf("Expected a single-use invite by default, got %d uses", invite.MaxUses)
+	}
+
+	// Only the creator can revoke
+	client.SetAccessToken(other.AccessToken)
+	resp = client.Delete("/friends/invites/"+invite.ID, nil)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(creator.AccessToken)
+	resp = client.Delete("/friends/invites/"+invite.ID, nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/friends/invites")
+	client.ExpectStatus(resp, http.StatusOK)
+	var invites []Invite
+	client.ParseJSON(resp, &invites)
+	if len(invites) != 1 || !invites[0].Revoked || invites[0].Token != "" {
+		t.Errorf("Expected one revoked invite without its token, got %+v", invites)
+	}
+
+	client.SetAccessToken(other.AccessToken)
+	resp = client.Post("/friends/invites/redeem", RedeemInviteRequest{Token: invite.Token})
+	client.ExpectStatus(resp, http.StatusGone)
+	_ = resp.Body.Close()
+}
+
+func TestFriends_GetFriends(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+
+	// Make them friends
+	makeFriends(t, client, user1, user2)
+
+	// User1 checks friends list
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+
+	if len(friends) != 1 {
+		t.Errorf("Expected 1 friend, got %d", len(friends))
+	}
+	if len(friends) > 0 {
+		if friends[0].Username != user2.User.Username {
+			t.Errorf("Expected friend '%s', got '%s'", user2.User.Username, friends[0].Username)
+		}
+		if friends[0].PublicKey == "" {
+			t.Error("Expected friend's public key to be included")
+		}
+	}
+}
+
+func TestFriends_Remove(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	// Undelivered messages in both directions
+	for _, pair := range [][2]AuthResponse{{user1, user2}, {user2, user1}} {
+		client.SetAccessToken(pair[0].AccessToken)
+		resp := client.Post("/messages", SendMessageRequest{
+			ToUsername:       pair[1].User.Username,
+			EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+			ContentType:      "text",
+			Signature:        "c2lnbmF0dXJl",
+		})
+		client.ExpectStatus(resp, http.StatusCreated)
+		_ = resp.Body.Close()
+	}
+
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.Delete("/friends/"+user2.User.Username, nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Delete("/friends/"+user2.User.Username, nil)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	for _, user := range []AuthResponse{user1, user2} {
+		client.SetAccessToken(user.AccessToken)
+
+		resp = client.Get("/friends")
+		client.ExpectStatus(resp, http.StatusOK)
+		var friends []Friend
+		client.ParseJSON(resp, &friends)
+		if len(friends) != 0 {
+			t.Errorf("Expected %s to have no friends, got %d", user.User.Username, len(friends))
+		}
+
+		resp = client.Get("/messages")
+		client.ExpectStatus(resp, http.StatusOK)
+		var messages []Message
+		client.ParseJSON(resp, &messages)
+		if len(messages) != 0 {
+			t.Errorf("Expected %s's undelivered messages to be deleted, got %d", user.User.Username, len(messages))
+		}
+	}
+
+	// Messaging is no longer allowed
+	resp = client.Post("/messages", SendMessageRequest{
+		ToUsername:       user1.User.Username,
+		EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
+
+	// Either side can become friends again, in either direction
+	makeFriends(t, client, user1, user2)
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Delete("/friends/"+user1.User.Username, nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+	makeFriends(t, client, user2, user1)
+}
+
+func TestFriends_Remove_NotFriends(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.Delete("/friends/"+user2.User.Username, nil)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	resp = client.Delete("/friends/nonexistentuser", nil)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+}
+
+func TestFriends_Settings(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	user3 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+	makeFriends(t, client, user1, user3)
+
+	// Pin the friend that sorts last by username
+	last := user2
+	if user3.User.Username > user2.User.Username {
+		last = user3
+	}
+
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.Patch("/friends/"+last.User.Username, map[string]interface{}{
+		"nickname":    "  Best friend  ",
+		"pinned":      true,
+		"muted":       true,
+		"message_ttl": 3600,
+	})
+	client.ExpectStatus(resp, http.StatusOK)
+	var settings FriendSettings
+	client.ParseJSON(resp, &settings)
+	if settings != (FriendSettings{Nickname: "Best friend", Muted: true, Pinned: true, MessageTTL: 3600}) {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+
+	// Only the fields sent are changed
+	resp = client.Patch("/friends/"+last.User.Username, map[string]interface{}{"muted": false})
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &settings)
+	if settings != (FriendSettings{Nickname: "Best friend", Pinned: true, MessageTTL: 3600}) {
+		t.Errorf("Expected only muted to change, got %+v", settings)
+	}
+
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+	if len(friends) != 2 || friends[0].Username != last.User.Username {
+		t.Fatalf("Expected pinned friend %s first, got %+v", last.User.Username, friends)
+	}
+	if friends[0].Nickname != "Best friend" || friends[0].MessageTTL != 3600 {
+		t.Errorf("Expected settings in friends list, got %+v", friends[0].FriendSettings)
+	}
+	if friends[1].FriendSettings != (FriendSettings{}) {
+		t.Errorf("Expected default settings for the other friend, got %+v", friends[1].FriendSettings)
+	}
+
+	// The friend doesn't see them
+	client.SetAccessToken(last.AccessToken)
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	var theirFriends []Friend
+	client.ParseJSON(resp, &theirFriends)
+	if len(theirFriends) != 1 || theirFriends[0].FriendSettings != (FriendSettings{}) {
+		t.Errorf("Expected settings to be private, got %+v", theirFriends)
+	}
+
+	client.SetAccessToken(user1.AccessToken)
+	for _, body := range []map[string]interface{}{
+		{"nickname": "bad\nname"},
+		{"nickname": strings.Repeat("n", 65)},
+		{"message_ttl": -1},
+		{"message_ttl": 8 * 24 * 60 * 60},
+	} {
+		resp = client.Patch("/friends/"+last.User.Username, body)
+		client.ExpectStatus(resp, http.StatusBadRequest)
+		_ = resp.Body.Close()
+	}
+
+	resp = client.Patch("/friends/nonexistentuser", map[string]interface{}{"muted": true})
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	// Unfriending forgets the settings
+	resp = client.Delete("/friends/"+last.User.Username, nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Patch("/friends/"+last.User.Username, map[string]interface{}{"muted": true})
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	makeFriends(t, client, user1, last)
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	var refriended []Friend
+	client.ParseJSON(resp, &refriended)
+	for _, friend := range refriended {
+		if friend.FriendSettings != (FriendSettings{}) {
+			t.Errorf("Expected settings to be cleared after unfriending, got %+v", friend.FriendSettings)
+		}
+	}
+}
+
+func TestFriends_Streaks(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	send := func(from, to AuthResponse) {
+		t.Helper()
+		client.SetAccessToken(from.AccessToken)
+		resp := client.Post("/messages", SendMessageRequest{
+			ToUsername:       to.User.Username,
+			EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+			ContentType:      "text",
+			Signature:        "c2lnbmF0dXJl",
+		})
+		client.ExpectStatus(resp, http.StatusCreated)
+		_ = resp.Body.Close()
+	}
+	streak := func(user AuthResponse) Streak {
+		t.Helper()
+		client.SetAccessToken(user.AccessToken)
+		resp := client.Get("/friends")
+		client.ExpectStatus(resp, http.StatusOK)
+		var friends []Friend
+		client.ParseJSON(resp, &friends)
+		if len(friends) != 1 {
+			t.Fatalf("Expected 1 friend, got %d", len(friends))
+		}
+		return friends[0].Streak
+	}
+	// Start each step just after a day begins so its messages share the day
+	nextDay := func() int64 {
+		day := int64(testStreakDay)
+		next := (time.Now().UnixNano()/day + 1) * day
+		time.Sleep(time.Until(time.Unix(0, next)) + 100*time.Millisecond)
+		return next / day
+	}
+
+	day := nextDay()
+	send(user1, user2)
+	if got := streak(user1); got.Streak != 0 || got.StreakExpiresAt != nil {
+		t.Errorf("Expected no streak until both have sent, got %+v", got)
+	}
+
+	send(user2, user1)
+	send(user1, user2)
+	got := streak(user1)
+	if got.Streak != 1 {
+		t.Fatalf("Expected streak 1, got %d", got.Streak)
+	}
+	expiresAt := time.Unix(0, (day+2)*int64(testStreakDay))
+	if got.StreakExpiresAt == nil || !got.StreakExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected streak to expire at %v, got %v", expiresAt, got.StreakExpiresAt)
+	}
+	if got.StreakWarningAt == nil || !got.StreakWarningAt.Before(expiresAt) || got.StreakWarningAt.Before(time.Unix(0, (day+1)*int64(testStreakDay))) {
+		t.Errorf("Expected a warning on the streak's last day, got %v", got.StreakWarningAt)
+	}
+
+	nextDay()
+	send(user2, user1)
+	send(user1, user2)
+	for _, user := range []AuthResponse{user1, user2} {
+		if got := streak(user); got.Streak != 2 {
+			t.Errorf("Expected %s to see streak 2, got %d", user.User.Username, got.Streak)
+		}
+	}
+
+	// A day without messages breaks it
+	nextDay()
+	nextDay()
+	if got := streak(user1); got.Streak != 0 || got.StreakExpiresAt != nil {
+		t.Errorf("Expected broken streak to read as 0, got %+v", got)
+	}
+	reset, err := testStreakService.ResetBrokenStreaks(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to reset streaks: %v", err)
+	}
+	if reset != 1 {
+		t.Errorf("Expected 1 streak reset, got %d", reset)
+	}
+
+	send(user1, user2)
+	send(user2, user1)
+	if got := streak(user2); got.Streak != 1 {
+		t.Errorf("Expected a new streak of 1, got %d", got.Streak)
+	}
+
+	// Unfriending drops it
+	resp := client.Delete("/friends/"+user1.User.Username, nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+	makeFriends(t, client, user1, user2)
+	if got := streak(user1); got.Streak != 0 {
+		t.Errorf("Expected streak to be dropped with the friendship, got %d", got.Streak)
+	}
+}
+
+func TestFriends_Sort(t *testing.T) {
+	client := NewTestClient(t)
+
+	me := createAuthenticatedUser(t, client)
+	alice := createNamedUser(t, client, "alice")
+	bob := createNamedUser(t, client, "bob")
+	carol := createNamedUser(t, client, "carol")
+	for _, friend := range []AuthResponse{alice, bob, carol} {
+		makeFriends(t, client, me, friend)
+	}
+
+	send := func(from, to AuthResponse) MessageResponse {
+		t.Helper()
+		client.SetAccessToken(from.AccessToken)
+		resp := client.Post("/messages", SendMessageRequest{
+			ToUsername:       to.User.Username,
+			EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+			ContentType:      "text",
+			Signature:        "c2lnbmF0dXJl",
+		})
+		client.ExpectStatus(resp, http.StatusCreated)
+		var sent MessageResponse
+		client.ParseJSON(resp, &sent)
+		return sent
+	}
+	order := func(query string) []string {
+		t.Helper()
+		client.SetAccessToken(me.AccessToken)
+		resp := client.Get("/friends" + query)
+		client.ExpectStatus(resp, http.StatusOK)
+		var friends []Friend
+		client.ParseJSON(resp, &friends)
+		var usernames []string
+		for _, friend := range friends {
+			usernames = append(usernames, friend.Username)
+		}
+		return usernames
+	}
+	expectOrder := func(query string, expected ...string) {
+		t.Helper()
+		if got := order(query); strings.Join(got, ",") != strings.Join(expected, ",") {
+			t.Errorf("GET /friends%s: expected %v, got %v", query, expected, got)
+		}
+	}
+
+	send(me, alice)
+	for i := 0; i < 3; i++ {
+		send(me, bob)
+	}
+	// Acknowledging a message counts as an interaction with its sender
+	fromCarol := send(carol, me)
+	client.SetAccessToken(me.AccessToken)
+	resp := client.Post("/messages/"+fromCarol.ID+"/ack", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	expectOrder("", "alice", "bob", "carol")
+	expectOrder("?sort=recent", "carol", "bob", "alice")
+	expectOrder("?sort=frequent", "bob", "carol", "alice")
+
+	// A new interaction reorders the cached ranking
+	send(me, alice)
+	expectOrder("?sort=recent", "alice", "carol", "bob")
+	expectOrder("?sort=frequent", "bob", "alice", "carol")
+
+	// A message to me only counts once I acknowledge it
+	send(bob, me)
+	expectOrder("?sort=recent", "alice", "carol", "bob")
+
+	// Pinned friends stay on top
+	resp = client.Patch("/friends/carol", map[string]interface{}{"pinned": true})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+	expectOrder("?sort=frequent", "carol", "bob", "alice")
+
+	// Unfriending forgets the interactions
+	resp = client.Delete("/friends/bob", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+	makeFriends(t, client, me, bob)
+	expectOrder("?sort=frequent", "carol", "alice", "bob")
+
+	resp = client.Get("/friends?sort=oldest")
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+}
+
+// =============================================================================
+// BLOCK TESTS
+// =============================================================================
+
+func TestBlocks_DropsRequestsMessagesAndLookups(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	// An undelivered message from the soon-to-be blocked user
+	client.SetAccessToken(user2.AccessToken)
+	resp := client.Post("/messages", SendMessageRequest{
+		ToUsername:       user1.User.Username,
+		EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Post("/users/"+user2.User.Username+"/block", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/users/"+user2.User.Username+"/block", nil)
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/users/me/blocked")
+	client.ExpectStatus(resp, http.StatusOK)
+	var blocked []BlockedUser
+	client.ParseJSON(resp, &blocked)
+	if len(blocked) != 1 || blocked[0].Username != user2.User.Username {
+		t.Fatalf("Expected %s in the blocked list, got %+v", user2.User.Username, blocked)
+	}
+
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+	if len(friends) != 0 {
+		t.Errorf("Expected blocking to remove the friendship, got %d friends", len(friends))
+	}
+
+	resp = client.Get("/messages")
+	client.ExpectStatus(resp, http.StatusOK)
+	var messages []Message
+	client.ParseJSON(resp, &messages)
+	if len(messages) != 0 {
+		t.Errorf("Expected undelivered messages to be deleted, got %d", len(messages))
+	}
+
+	// The blocker can't send a request to someone they blocked
+	resp = client.Post("/friends/request", SendFriendRequest{Username: user2.User.Username})
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
+
+	// To the blocked user the blocker looks like they don't exist, whether
+	// looked up, sent a request or messaged
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Post("/friends/request", SendFriendRequest{Username: user1.User.Username})
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/friends/requests/outgoing")
+	client.ExpectStatus(resp, http.StatusOK)
+	var outgoing []OutgoingFriendRequest
+	client.ParseJSON(resp, &outgoing)
+	if len(outgoing) != 0 {
+		t.Errorf("Expected no outgoing requests, got %d", len(outgoing))
+	}
+
+	resp = client.Post("/messages", SendMessageRequest{
+		ToUsername:       user1.User.Username,
+		EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/users/" + user1.User.Username)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/users/" + user1.User.Username + "/keys")
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Get("/friends/requests")
+	client.ExpectStatus(resp, http.StatusOK)
+	var requests []FriendRequest
+	client.ParseJSON(resp, &requests)
+	if len(requests) != 0 {
+		t.Errorf("Expected the blocked user's request to be dropped, got %d", len(requests))
+	}
+
+	resp = client.Get("/messages")
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &messages)
+	if len(messages) != 0 {
+		t.Errorf("Expected the blocked user's message to be dropped, got %d", len(messages))
+	}
+
+	// The blocked user can still be looked up by the blocker
+	resp = client.Get("/users/" + user2.User.Username)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+}
+
+func TestBlocks_Unblock(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.Post("/users/"+user1.User.Username+"/block", nil)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/users/nonexistentuser/block", nil)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	resp = client.Delete("/users/"+user2.User.Username+"/block", nil)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/users/"+user2.User.Username+"/block", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Delete("/users/"+user2.User.Username+"/block", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/users/me/blocked")
+	client.ExpectStatus(resp, http.StatusOK)
+	var blocked []BlockedUser
+	client.ParseJSON(resp, &blocked)
+	if len(blocked) != 0 {
+		t.Errorf("Expected an empty blocked list, got %d", len(blocked))
+	}
+
+	// Once unblocked the two can become friends again
+	makeFriends(t, client, user2, user1)
+}
+
+// =============================================================================
+// MESSAGE TESTS
+// =============================================================================
+
+func TestMessages_Send_Success(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+
+	// Make them friends first
+	makeFriends(t, client, user1, user2)
+
+	// User1 sends message to User2
+	client.SetAccessToken(user1.AccessToken)
+
+	msgReq := SendMessageRequest{
+		ToUsername:       user2.User.Username,
+		EncryptedContent: "ZW5jcnlwdGVkLWNvbnRlbnQtaGVyZQ==", // base64 encoded
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJlLWhlcmU=",
+	}
+
+	resp := client.Post("/messages", msgReq)
+	client.ExpectStatus(resp, http.StatusCreated)
+
+	var msgResp MessageResponse
+	client.ParseJSON(resp, &msgResp)
+
+	if msgResp.ID == "" {
+		t.Error("Expected message ID to be set")
+	}
+}
+
+func TestMessages_Send_NotFriends(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+
+	// Don't make them friends - try to send message
+	client.SetAccessToken(user1.AccessToken)
+
+	msgReq := SendMessageRequest{
+		ToUsername:       user2.User.Username,
+		EncryptedContent: "ZW5jcnlwdGVkLWNvbnRlbnQ=",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	}
+
+	resp := client.Post("/messages", msgReq)
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
+}
+
+func TestMessages_Send_UserNotFound(t *testing.T) {
+	client := NewTestClient(t)
+
+	user := createAuthenticatedUser(t, client)
+	client.SetAccessToken(user.AccessToken)
+
+	msgReq := SendMessageRequest{
+		ToUsername:       "nonexistentuser",
+		EncryptedContent: "ZW5jcnlwdGVkLWNvbnRlbnQ=",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	}
+
+	resp := client.Post("/messages", msgReq)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+}
+
+func TestMessages_GetPending(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+
+	makeFriends(t, client, user1, user2)
+
+	// User1 sends message to User2
+	client.SetAccessToken(user1.AccessToken)
+	msgReq := SendMessageRequest{
+		ToUsername:       user2.User.Username,
+		EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	}
+	resp := client.Post("/messages", msgReq)
+	_ = resp.Body.Close()
+
+	// User2 fetches pending messages
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Get("/messages")
+	client.ExpectStatus(resp, http.StatusOK)
+
+	var messages []Message
+	client.ParseJSON(resp, &messages)
+
+	if len(messages) != 1 {
 		t.Errorf("Expected 1 message, got %d", len(messages))
 	}
-	if len(messages) > 0 {
-		if messages[0].FromUsername != user1.User.Username {
-			t.Errorf("Expected message from '%s', got '%s'", user1.User.Username, messages[0].FromUsername)
+	if len(messages) > 0 {
+		if messages[0].FromUsername != user1.User.Username {
+			t.Errorf("Expected message from '%s', got '%s'", user1.User.Username, messages[0].FromUsername)
+		}
+		if messages[0].ContentType != "text" {
+			t.Errorf("Expected content type 'text', got '%s'", messages[0].ContentType)
+		}
+	}
+}
+
+
+// =============================================================================
+// KEY ROTATION TESTS
+// =============================================================================
+
+func TestRotatePublicKey_RecordsHistoryAndNotifiesFriends(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	// A message encrypted to user1's first key
+	client.SetAccessToken(user2.AccessToken)
+	resp := client.Post("/messages", SendMessageRequest{
+		ToUsername:       user1.User.Username,
+		EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	// user1 rotates to a new key
+	rotateReq, newPriv := newRotateRequest(t, user1, 1)
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Put("/users/me/public-key", rotateReq)
+	client.ExpectStatus(resp, http.StatusOK)
+
+	var record PublicKeyVersion
+	client.ParseJSON(resp, &record)
+	if record.Version != 2 || record.PublicKey != rotateReq.PublicKey {
+		t.Fatalf("Expected version 2 with the new key, got %+v", record)
+	}
+
+	// A message encrypted to the new key
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Post("/messages", SendMessageRequest{
+		ToUsername:       user1.User.Username,
+		EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	// Each message reports the key version it was encrypted to
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Get("/messages")
+	client.ExpectStatus(resp, http.StatusOK)
+	var messages []Message
+	client.ParseJSON(resp, &messages)
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+	if messages[0].RecipientKeyVersion != 1 || messages[1].RecipientKeyVersion != 2 {
+		t.Errorf("Expected key versions 1 and 2, got %d and %d", messages[0].RecipientKeyVersion, messages[1].RecipientKeyVersion)
+	}
+
+	// The history keeps the old key with a closed validity period
+	resp = client.Get("/users/" + user1.User.Username + "/keys")
+	client.ExpectStatus(resp, http.StatusOK)
+	var history []PublicKeyVersion
+	client.ParseJSON(resp, &history)
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 keys in history, got %d", len(history))
+	}
+	if history[0].PublicKey != user1.User.PublicKey || history[0].ValidUntil == nil {
+		t.Errorf("Expected the original key to be closed, got %+v", history[0])
+	}
+	if history[1].PublicKey != rotateReq.PublicKey || history[1].ValidUntil != nil {
+		t.Errorf("Expected the new key to be current, got %+v", history[1])
+	}
+
+	// The friend is told the key changed and sees the new key
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Get("/notifications")
+	client.ExpectStatus(resp, http.StatusOK)
+	var notifications []Notification
+	client.ParseJSON(resp, &notifications)
+	if len(notifications) != 1 || notifications[0].Type != "key_changed" || notifications[0].ActorID != user1.User.ID {
+		t.Errorf("Expected a key_changed notification about %s, got %+v", user1.User.ID, notifications)
+	}
+
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+	if len(friends) != 1 || friends[0].PublicKey != rotateReq.PublicKey || friends[0].KeyVersion != 2 {
+		t.Errorf("Expected friend with the rotated key at version 2, got %+v", friends)
+	}
+
+	// The next rotation must be signed by the new key
+	user1.IdentityKey = newPriv
+	rotateReq, _ = newRotateRequest(t, user1, 2)
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Put("/users/me/public-key", rotateReq)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+}
+
+func TestSafetyNumber_VerifyAndResetOnRotation(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	stranger := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	// Both sides see the same number
+	numbers := make([]SafetyNumber, 2)
+	for i, pair := range [][2]AuthResponse{{user1, user2}, {user2, user1}} {
+		client.SetAccessToken(pair[0].AccessToken)
+		resp := client.Get("/friends/" + pair[1].User.Username + "/safety-number")
+		client.ExpectStatus(resp, http.StatusOK)
+		client.ParseJSON(resp, &numbers[i])
+	}
+	if numbers[0].SafetyNumber != numbers[1].SafetyNumber || len(numbers[0].SafetyNumber) != 60 {
+		t.Fatalf("Expected matching 60-digit safety numbers, got %q and %q", numbers[0].SafetyNumber, numbers[1].SafetyNumber)
+	}
+
+	key1, _ := keys.ParsePublicKey(user1.User.PublicKey)
+	key2, _ := keys.ParsePublicKey(user2.User.PublicKey)
+	if expected := keys.SafetyNumber(user2.User.ID, key2, user1.User.ID, key1); numbers[0].SafetyNumber != expected {
+		t.Errorf("Expected the server to match keys.SafetyNumber, got %q want %q", numbers[0].SafetyNumber, expected)
+	}
+
+	client.SetAccessToken(stranger.AccessToken)
+	resp := client.Get("/friends/" + user1.User.Username + "/safety-number")
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Put("/friends/"+user2.User.Username+"/verified", SetVerifiedRequest{Verified: true, SafetyNumber: strings.Repeat("0", 60)})
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	resp = client.Put("/friends/"+user2.User.Username+"/verified", SetVerifiedRequest{Verified: true, SafetyNumber: numbers[0].SafetyNumber})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	friendVerified := func(user AuthResponse) bool {
+		client.SetAccessToken(user.AccessToken)
+		resp := client.Get("/friends")
+		client.ExpectStatus(resp, http.StatusOK)
+		var friends []Friend
+		client.ParseJSON(resp, &friends)
+		if len(friends) != 1 {
+			t.Fatalf("Expected 1 friend, got %d", len(friends))
+		}
+		return friends[0].Verified
+	}
+
+	if !friendVerified(user1) {
+		t.Error("Expected user2 to be verified for user1")
+	}
+	if friendVerified(user2) {
+		t.Error("Verification should only apply to the user who set it")
+	}
+
+	// user2 rotates their key, which resets user1's verification
+	rotateReq, _ := newRotateRequest(t, user2, 1)
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Put("/users/me/public-key", rotateReq)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	if friendVerified(user1) {
+		t.Error("Expected the key rotation to reset verification")
+	}
+
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Get("/friends/" + user2.User.Username + "/safety-number")
+	client.ExpectStatus(resp, http.StatusOK)
+	var rotated SafetyNumber
+	client.ParseJSON(resp, &rotated)
+	if rotated.SafetyNumber == numbers[0].SafetyNumber || rotated.KeyVersion != 2 || rotated.Verified {
+		t.Errorf("Expected a new unverified safety number for key version 2, got %+v", rotated)
+	}
+
+	// The old number no longer verifies
+	resp = client.Put("/friends/"+user2.User.Username+"/verified", SetVerifiedRequest{Verified: true, SafetyNumber: numbers[0].SafetyNumber})
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+}
+
+func TestFriends_UnfriendForgetsAvatarKeysAndVerification(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.PutBytes("/users/me/avatar", []byte("encrypted-avatar-bytes"))
+	client.ExpectStatus(resp, http.StatusOK)
+	var avatar Avatar
+	client.ParseJSON(resp, &avatar)
+
+	resp = client.Put("/users/me/avatar/keys", map[string]interface{}{
+		"avatar_id": avatar.ID,
+		"keys":      []map[string]string{{"user_id": user2.User.ID, "wrapped_key": base64.StdEncoding.EncodeToString([]byte("wrapped"))}},
+	})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Get("/friends/" + user1.User.Username + "/safety-number")
+	client.ExpectStatus(resp, http.StatusOK)
+	var number SafetyNumber
+	client.ParseJSON(resp, &number)
+
+	resp = client.Put("/friends/"+user1.User.Username+"/verified", SetVerifiedRequest{Verified: true, SafetyNumber: number.SafetyNumber})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Delete("/friends/"+user1.User.Username, nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	// Becoming friends again starts from scratch
+	makeFriends(t, client, user1, user2)
+
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+	if len(friends) != 1 || friends[0].Verified || friends[0].Avatar != nil {
+		t.Errorf("Expected an unverified friend without an avatar key, got %+v", friends)
+	}
+}
+
+func TestRotatePublicKey_RequiresBothSignatures(t *testing.T) {
+	client := NewTestClient(t)
+
+	user := createAuthenticatedUser(t, client)
+	other := createAuthenticatedUser(t, client)
+	client.SetAccessToken(user.AccessToken)
+
+	// Signed by someone else's key instead of the current one
+	forged := user
+	forged.IdentityKey = other.IdentityKey
+	req, _ := newRotateRequest(t, forged, 1)
+	resp := client.Put("/users/me/public-key", req)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// New key signature missing proof of possession
+	req, _ = newRotateRequest(t, user, 1)
+	req.NewKeySignature = req.Signature
+	resp = client.Put("/users/me/public-key", req)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// Signed for the wrong current version, so an old signature can't be replayed
+	req, _ = newRotateRequest(t, user, 0)
+	resp = client.Put("/users/me/public-key", req)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// Nothing changed
+	resp = client.Get("/users/" + user.User.Username + "/keys")
+	client.ExpectStatus(resp, http.StatusOK)
+	var history []PublicKeyVersion
+	client.ParseJSON(resp, &history)
+	if len(history) != 1 {
+		t.Errorf("Expected 1 key in history, got %d", len(history))
+	}
+}
+
+// =============================================================================
+// PREKEY TESTS
+// =============================================================================
+
+func TestPreKeys_UploadCountAndClaim(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	// No prekeys yet
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.Get("/keys/prekeys/count")
+	client.ExpectStatus(resp, http.StatusOK)
+	var status PreKeyStatus
+	client.ParseJSON(resp, &status)
+	if status.OneTimePreKeys != 0 || !status.SignedPreKeyStale {
+		t.Errorf("Expected no prekeys and a stale signed prekey, got %+v", status)
+	}
+
+	upload := newPreKeyUpload(t, user1, 1, 2)
+	resp = client.Put("/keys/prekeys", upload)
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &status)
+	if status.OneTimePreKeys != 2 || status.SignedPreKeyStale {
+		t.Errorf("Expected 2 one-time prekeys and a fresh signed prekey, got %+v", status)
+	}
+
+	// Replenish adds to the existing supply; reused IDs are rejected
+	more := newPreKeyUpload(t, user1, 3, 1)
+	resp = client.Post("/keys/prekeys", map[string]interface{}{"one_time_prekeys": more.OneTimePreKeys})
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &status)
+	if status.OneTimePreKeys != 3 {
+		t.Errorf("Expected 3 one-time prekeys, got %d", status.OneTimePreKeys)
+	}
+
+	resp = client.Post("/keys/prekeys", map[string]interface{}{"one_time_prekeys": more.OneTimePreKeys})
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	// The friend claims every one-time prekey exactly once
+	client.SetAccessToken(user2.AccessToken)
+	claimed := make(map[uint32]bool)
+	for i := 0; i < 3; i++ {
+		resp = client.Post("/users/"+user1.User.Username+"/prekey-bundle", nil)
+		client.ExpectStatus(resp, http.StatusOK)
+		var bundle PreKeyBundle
+		client.ParseJSON(resp, &bundle)
+
+		if bundle.IdentityKey != user1.User.PublicKey || bundle.SignedPreKey.PublicKey != upload.SignedPreKey.PublicKey {
+			t.Fatalf("Unexpected bundle %+v", bundle)
+		}
+		identityKey, _ := keys.ParsePublicKey(bundle.IdentityKey)
+		spk, _ := base64.StdEncoding.DecodeString(bundle.SignedPreKey.PublicKey)
+		sig, _ := base64.StdEncoding.DecodeString(bundle.SignedPreKey.Signature)
+		if !keys.Verify(identityKey, spk, sig) {
+			t.Error("Expected signed prekey to verify against the identity key")
+		}
+
+		if bundle.OneTimePreKey == nil {
+			t.Fatalf("Expected a one-time prekey on claim %d", i+1)
+		}
+		if claimed[bundle.OneTimePreKey.KeyID] {
+			t.Errorf("One-time prekey %d handed out twice", bundle.OneTimePreKey.KeyID)
+		}
+		claimed[bundle.OneTimePreKey.KeyID] = true
+	}
+
+	// Once exhausted the bundle still has the signed prekey
+	resp = client.Post("/users/"+user1.User.Username+"/prekey-bundle", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	var bundle PreKeyBundle
+	client.ParseJSON(resp, &bundle)
+	if bundle.OneTimePreKey != nil {
+		t.Errorf("Expected no one-time prekey, got %+v", bundle.OneTimePreKey)
+	}
+
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Get("/keys/prekeys/count")
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &status)
+	if status.OneTimePreKeys != 0 {
+		t.Errorf("Expected 0 one-time prekeys left, got %d", status.OneTimePreKeys)
+	}
+}
+
+func TestPreKeys_RejectsInvalidUploads(t *testing.T) {
+	client := NewTestClient(t)
+
+	user := createAuthenticatedUser(t, client)
+	other := createAuthenticatedUser(t, client)
+	client.SetAccessToken(user.AccessToken)
+
+	// Signed prekey signed by someone else's identity key
+	upload := newPreKeyUpload(t, other, 1, 1)
+	resp := client.Put("/keys/prekeys", upload)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// One-time prekey that isn't a valid X25519 point
+	upload = newPreKeyUpload(t, user, 1, 1)
+	upload.OneTimePreKeys[0].PublicKey = fakePublicKey()
+	resp = client.Put("/keys/prekeys", upload)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// Duplicate IDs within one upload
+	upload = newPreKeyUpload(t, user, 1, 2)
+	upload.OneTimePreKeys[1].KeyID = upload.OneTimePreKeys[0].KeyID
+	resp = client.Put("/keys/prekeys", upload)
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+}
+
+func TestPreKeys_ClaimRequiresFriendship(t *testing.T) {
+	client := NewTestClient(t)
+
+	user := createAuthenticatedUser(t, client)
+	stranger := createAuthenticatedUser(t, client)
+
+	client.SetAccessToken(user.AccessToken)
+	resp := client.Put("/keys/prekeys", newPreKeyUpload(t, user, 1, 1))
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(stranger.AccessToken)
+	resp = client.Post("/users/"+user.User.Username+"/prekey-bundle", nil)
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
+}
+
+func TestPreKeys_ConcurrentClaimsNeverShareAKey(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	const keyCount = 10
+	const claimers = 20
+
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.Put("/keys/prekeys", newPreKeyUpload(t, user1, 1, keyCount))
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	var wg sync.WaitGroup
+	bundles := make([]PreKeyBundle, claimers)
+	for i := 0; i < claimers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// A separate client per goroutine; NewTestClient would reset the database
+			c := &TestClient{t: t, baseURL: testServer.URL, accessToken: user2.AccessToken}
+			resp := c.Post("/users/"+user1.User.Username+"/prekey-bundle", nil)
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Claim %d: expected status 200, got %d", i, resp.StatusCode)
+				_ = resp.Body.Close()
+				return
+			}
+			defer func() { _ = resp.Body.Close() }()
+			if err := json.NewDecoder(resp.Body).Decode(&bundles[i]); err != nil {
+				t.Errorf("Claim %d: failed to decode bundle: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[uint32]bool)
+	for _, b := range bundles {
+		if b.OneTimePreKey == nil {
+			continue
+		}
+		if seen[b.OneTimePreKey.KeyID] {
+			t.Errorf("One-time prekey %d handed out twice", b.OneTimePreKey.KeyID)
+		}
+		seen[b.OneTimePreKey.KeyID] = true
+	}
+	if len(seen) != keyCount {
+		t.Errorf("Expected all %d one-time prekeys to be claimed, got %d", keyCount, len(seen))
+	}
+}
+
+// =============================================================================
+// MULTI-DEVICE TESTS
+// =============================================================================
+
+func registerDevice(t *testing.T, client *TestClient, user AuthResponse, name string) Device {
+	t.Helper()
+
+	client.SetAccessToken(user.AccessToken)
+	resp := client.Post("/devices", newDeviceRequest(t, user, name))
+	client.ExpectStatus(resp, http.StatusCreated)
+
+	var device Device
+	client.ParseJSON(resp, &device)
+	return device
+}
+
+func TestDevices_FanOutDeliveryAckedPerDevice(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	phone := registerDevice(t, client, user2, "phone")
+	tablet := registerDevice(t, client, user2, "tablet")
+
+	// Both the public profile and the friend list expose the devices
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.Get("/users/" + user2.User.Username)
+	client.ExpectStatus(resp, http.StatusOK)
+	var profile UserPublic
+	client.ParseJSON(resp, &profile)
+	if len(profile.Devices) != 2 {
+		t.Fatalf("Expected 2 devices on the profile, got %d", len(profile.Devices))
+	}
+
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusOK)
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+	if len(friends) != 1 || len(friends[0].Devices) != 2 {
+		t.Fatalf("Expected the friend to list 2 devices, got %+v", friends)
+	}
+
+	// Every device must get its own ciphertext
+	resp = client.Post("/messages", SendMessageRequest{
+		ToUsername:  user2.User.Username,
+		Ciphertexts: []DeviceCiphertext{{DeviceID: phone.ID, EncryptedContent: "cGhvbmU="}},
+		ContentType: "text",
+		Signature:   "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/messages", SendMessageRequest{
+		ToUsername:       user2.User.Username,
+		EncryptedContent: "YWNjb3VudA==",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/messages", SendMessageRequest{
+		ToUsername: user2.User.Username,
+		Ciphertexts: []DeviceCiphertext{
+			{DeviceID: phone.ID, EncryptedContent: "cGhvbmU="},
+			{DeviceID: tablet.ID, EncryptedContent: "dGFibGV0"},
+		},
+		ContentType: "text",
+		Signature:   "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var sent MessageResponse
+	client.ParseJSON(resp, &sent)
+
+	// Each device sees only its own ciphertext
+	client.SetAccessToken(user2.AccessToken)
+	for _, tc := range []struct {
+		device  Device
+		content string
+	}{{phone, "cGhvbmU="}, {tablet, "dGFibGV0"}} {
+		resp = client.Get("/messages?device_id=" + tc.device.ID)
+		client.ExpectStatus(resp, http.StatusOK)
+		var messages []Message
+		client.ParseJSON(resp, &messages)
+		if len(messages) != 1 {
+			t.Fatalf("Expected 1 message for %s, got %d", tc.device.Name, len(messages))
+		}
+		if messages[0].EncryptedContent != tc.content || messages[0].DeviceID != tc.device.ID {
+			t.Errorf("Expected %s's ciphertext, got %+v", tc.device.Name, messages[0])
+		}
+	}
+
+	// Per-device messages are not in the account-level inbox
+	resp = client.Get("/messages")
+	client.ExpectStatus(resp, http.StatusOK)
+	var accountMessages []Message
+	client.ParseJSON(resp, &accountMessages)
+	if len(accountMessages) != 0 {
+		t.Errorf("Expected no account-level messages, got %d", len(accountMessages))
+	}
+
+	// Acknowledging without a device can't drop every device's copy at once
+	resp = client.Post("/messages/"+sent.ID+"/ack", nil)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	// Acknowledging on the phone leaves the tablet's copy in place
+	resp = client.Post("/messages/"+sent.ID+"/ack?device_id="+phone.ID, nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/messages?device_id=" + phone.ID)
+	var phoneMessages []Message
+	client.ParseJSON(resp, &phoneMessages)
+	if len(phoneMessages) != 0 {
+		t.Errorf("Expected the phone's inbox to be empty, got %d", len(phoneMessages))
+	}
+
+	resp = client.Get("/messages?device_id=" + tablet.ID)
+	var tabletMessages []Message
+	client.ParseJSON(resp, &tabletMessages)
+	if len(tabletMessages) != 1 {
+		t.Fatalf("Expected the tablet to still have the message, got %d", len(tabletMessages))
+	}
+
+	// Once the last device acknowledges, the message is gone
+	resp = client.Post("/messages/"+sent.ID+"/ack?device_id="+tablet.ID, nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/messages/"+sent.ID+"/ack?device_id="+tablet.ID, nil)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+}
+
+func TestDevices_RegistrationAndRemoval(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	// The device key must be endorsed by the identity key, not self-signed
+	client.SetAccessToken(user2.AccessToken)
+	req := newDeviceRequest(t, user2, "phone")
+	forged := newDeviceRequest(t, user1, "phone")
+	req.Signature = forged.Signature
+	resp := client.Post("/devices", req)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	req = newDeviceRequest(t, user2, "phone")
+	resp = client.Post("/devices", req)
+	client.ExpectStatus(resp, http.StatusCreated)
+	var phone Device
+	client.ParseJSON(resp, &phone)
+
+	resp = client.Post("/devices", req)
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	// Friends are told the device list changed
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Get("/notifications")
+	client.ExpectStatus(resp, http.StatusOK)
+	var notifications []Notification
+	client.ParseJSON(resp, &notifications)
+	if len(notifications) != 1 || notifications[0].Type != "devices_changed" {
+		t.Errorf("Expected a devices_changed notification, got %+v", notifications)
+	}
+
+	// Other users can't read or remove the device
+	resp = client.Get("/messages?device_id=" + phone.ID)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	resp = client.Delete("/devices/"+phone.ID, nil)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	// A message addressed to the only device disappears when it is removed
+	resp = client.Post("/messages", SendMessageRequest{
+		ToUsername:  user2.User.Username,
+		Ciphertexts: []DeviceCiphertext{{DeviceID: phone.ID, EncryptedContent: "cGhvbmU="}},
+		ContentType: "text",
+		Signature:   "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var sent MessageResponse
+	client.ParseJSON(resp, &sent)
+
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Delete("/devices/"+phone.ID, nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/devices")
+	client.ExpectStatus(resp, http.StatusOK)
+	var devices []Device
+	client.ParseJSON(resp, &devices)
+	if len(devices) != 0 {
+		t.Errorf("Expected no devices, got %d", len(devices))
+	}
+
+	resp = client.Post("/messages/"+sent.ID+"/ack", nil)
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	// Without devices, senders fall back to a single ciphertext
+	client.SetAccessToken(user1.AccessToken)
+	resp = client.Post("/messages", SendMessageRequest{
+		ToUsername:       user2.User.Username,
+		EncryptedContent: "YWNjb3VudA==",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+}
+
+// =============================================================================
+// ACCOUNT DELETION TESTS
+// =============================================================================
+
+func TestDeleteAccount_WrongPassword(t *testing.T) {
+	client := NewTestClient(t)
+
+	user := createAuthenticatedUser(t, client)
+	client.SetAccessToken(user.AccessToken)
+
+	resp := client.Delete("/users/me", map[string]string{"password": "wrongpassword"})
+	client.ExpectStatus(resp, http.StatusUnauthorized)
+	_ = resp.Body.Close()
+
+	// Account must still exist
+	resp = client.Post("/auth/login", LoginRequest{Username: user.User.Username, Password: "password123"})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+}
+
+func TestDeleteAccount_ErasesDataAndNotifiesFriends(t *testing.T) {
+	client := NewTestClient(t)
+
+	user1 := createAuthenticatedUser(t, client)
+	user2 := createAuthenticatedUser(t, client)
+	makeFriends(t, client, user1, user2)
+
+	// User1 leaves an undelivered message for User2
+	client.SetAccessToken(user1.AccessToken)
+	resp := client.Post("/messages", SendMessageRequest{
+		ToUsername:       user2.User.Username,
+		EncryptedContent: "ZW5jcnlwdGVkLW1lc3NhZ2U=",
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	resp = client.Delete("/users/me", map[string]string{"password": "password123"})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	// Credentials and refresh tokens are gone
+	resp = client.Post("/auth/login", LoginRequest{Username: user1.User.Username, Password: "password123"})
+	client.ExpectStatus(resp, http.StatusUnauthorized)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/auth/refresh", map[string]string{"refresh_token": user1.RefreshToken})
+	client.ExpectStatus(resp, http.StatusUnauthorized)
+	_ = resp.Body.Close()
+
+	// Friendship and pending messages were cascade-deleted
+	client.SetAccessToken(user2.AccessToken)
+	resp = client.Get("/friends")
+	var friends []Friend
+	client.ParseJSON(resp, &friends)
+	if len(friends) != 0 {
+		t.Errorf("Expected 0 friends, got %d", len(friends))
+	}
+
+	resp = client.Get("/messages")
+	var messages []Message
+	client.ParseJSON(resp, &messages)
+	if len(messages) != 0 {
+		t.Errorf("Expected 0 messages, got %d", len(messages))
+	}
+
+	// User2 was told the contact is gone
+	resp = client.Get("/notifications")
+	client.ExpectStatus(resp, http.StatusOK)
+	var notifications []Notification
+	client.ParseJSON(resp, &notifications)
+	if len(notifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(notifications))
+	}
+	if notifications[0].Type != "friend_deleted" || notifications[0].ActorUsername != user1.User.Username {
+		t.Errorf("Unexpected notification: %+v", notifications[0])
+	}
+
+	resp = client.Post("/notifications/ack", map[string]string{"notification_id": notifications[0].ID})
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	// The username is free again
+	resp = client.Post("/auth/register", newRegisterRequest(t, client, user1.User.Username, "password123"))
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+}
+
+// =============================================================================
+// REPORT TESTS
+// =============================================================================
+
+func TestReports(t *testing.T) {
+	client := NewTestClient(t)
+
+	alice := createNamedUser(t, client, "alice")
+	bob := createNamedUser(t, client, "bob")
+	carol := createNamedUser(t, client, "carol")
+	makeFriends(t, client, alice, bob)
+
+	// sendSigned sends a message signed over its ciphertext with the sender's identity key
+	sendSigned := func(from, to AuthResponse, ciphertext []byte) (MessageResponse, string) {
+		t.Helper()
+		sig, err := keys.Sign(from.IdentityKey, ciphertext)
+		if err != nil {
+			t.Fatalf("Failed to sign message: %v", err)
 		}
-		if messages[0].ContentType != "text" {
-			t.Errorf("Expected content type 'text', got '%s'", messages[0].ContentType)
+		signature := base64.StdEncoding.EncodeToString(sig)
+		client.SetAccessToken(from.AccessToken)
+		resp := client.Post("/messages", SendMessageRequest{
+			ToUsername:       to.User.Username,
+			EncryptedContent: base64.StdEncoding.EncodeToString(ciphertext),
+			ContentType:      "text",
+			Signature:        signature,
+		})
+		client.ExpectStatus(resp, http.StatusCreated)
+		var sent MessageResponse
+		client.ParseJSON(resp, &sent)
+		return sent, signature
+	}
+	type reportResponse struct {
+		ID       string `json:"id"`
+		Status   string `json:"status"`
+		Verified bool   `json:"verified"`
+	}
+	report := func(reporter AuthResponse, body map[string]interface{}, expected int) reportResponse {
+		t.Helper()
+		client.SetAccessToken(reporter.AccessToken)
+		resp := client.Post("/reports", body)
+		client.ExpectStatus(resp, expected)
+		var created reportResponse
+		if expected == http.StatusCreated {
+			client.ParseJSON(resp, &created)
+		} else {
+			_ = resp.Body.Close()
+		}
+		return created
+	}
+
+	// A message still on the server is verified from its stored signature
+	pending, _ := sendSigned(bob, alice, []byte("pending-ciphertext"))
+	first := report(alice, map[string]interface{}{"username": "bob", "message_id": pending.ID, "reason": "spam"}, http.StatusCreated)
+	if first.Status != "open" || !first.Verified {
+		t.Errorf("Expected an open, verified report, got %+v", first)
+	}
+	report(alice, map[string]interface{}{"username": "bob", "message_id": pending.ID, "reason": "spam"}, http.StatusConflict)
+
+	// Only the recipient can report a stored message
+	report(carol, map[string]interface{}{"username": "bob", "message_id": pending.ID, "reason": "spam"}, http.StatusNotFound)
+
+	// After acknowledging, the reporter supplies their copy
+	ciphertext := []byte("acked-ciphertext")
+	acked, signature := sendSigned(bob, alice, ciphertext)
+	client.SetAccessToken(alice.AccessToken)
+	resp := client.Post("/messages/"+acked.ID+"/ack", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	second := report(alice, map[string]interface{}{
+		"username":          "bob",
+		"message_id":        acked.ID,
+		"reason":            "harassment",
+		"comment":           "  threatening  ",
+		"content":           base64.StdEncoding.EncodeToString([]byte("decrypted text")),
+		"content_type":      "text",
+		"encrypted_content": base64.StdEncoding.EncodeToString(ciphertext),
+		"signature":         signature,
+	}, http.StatusCreated)
+	if !second.Verified {
+		t.Error("Expected the sender's signature to verify")
+	}
+
+	// A signature the sender didn't make is kept but not verified
+	forged, err := keys.Sign(alice.IdentityKey, []byte("forged"))
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	third := report(alice, map[string]interface{}{
+		"username":          "bob",
+		"reason":            "other",
+		"encrypted_content": base64.StdEncoding.EncodeToString([]byte("forged")),
+		"signature":         base64.StdEncoding.EncodeToString(forged),
+	}, http.StatusCreated)
+	if third.Verified {
+		t.Error("Expected a forged signature not to verify")
+	}
+
+	report(alice, map[string]interface{}{"username": "alice", "reason": "spam"}, http.StatusBadRequest)
+	report(alice, map[string]interface{}{"username": "nonexistentuser", "reason": "spam"}, http.StatusNotFound)
+	report(alice, map[string]interface{}{"username": "bob", "reason": "rude"}, http.StatusBadRequest)
+	report(alice, map[string]interface{}{"username": "bob", "reason": "spam", "content": "aGk="}, http.StatusBadRequest)
+
+	// The review queue lists open reports oldest first
+	ctx := context.Background()
+	queue, err := testReportService.ListReports(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("Failed to list reports: %v", err)
+	}
+	if len(queue) != 3 || queue[0].ID.String() != first.ID || queue[2].ID.String() != third.ID {
+		t.Fatalf("Expected the 3 reports in order, got %+v", queue)
+	}
+	if string(queue[1].Content) != "decrypted text" || queue[1].Comment != "threatening" || queue[1].SenderKeyVersion != 1 {
+		t.Errorf("Unexpected stored report %+v", queue[1])
+	}
+
+	moderatorID := uuid.New()
+	reportID := queue[1].ID
+	for _, status := range []models.ReportStatus{models.ReportReviewing, models.ReportActioned} {
+		if _, err := testReportService.UpdateReport(ctx, moderatorID, reportID, &models.UpdateReportRequest{Status: status, Note: "checked"}); err != nil {
+			t.Fatalf("Failed to move report to %s: %v", status, err)
 		}
 	}
+	if _, err := testReportService.UpdateReport(ctx, moderatorID, reportID, &models.UpdateReportRequest{Status: models.ReportReviewing}); !errors.Is(err, models.ErrReportStatusConflict) {
+		t.Errorf("Expected a closed report to stay closed, got %v", err)
+	}
+
+	reviewed, err := testReportService.GetReport(ctx, reportID)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if reviewed.Status != models.ReportActioned || len(reviewed.History) != 3 {
+		t.Fatalf("Expected an actioned report with 3 events, got %+v", reviewed)
+	}
+	last := reviewed.History[2]
+	if last.ActorID != moderatorID || last.FromStatus != models.ReportReviewing || last.ToStatus != models.ReportActioned || last.Note != "checked" {
+		t.Errorf("Unexpected audit event %+v", last)
+	}
+
+	queue, err = testReportService.ListReports(ctx, models.ReportOpen, 0)
+	if err != nil {
+		t.Fatalf("Failed to list reports: %v", err)
+	}
+	if len(queue) != 2 {
+		t.Errorf("Expected 2 open reports left, got %d", len(queue))
+	}
 }
 
+// =============================================================================
+// ADMIN TESTS
+// =============================================================================
+
+func TestAdmin(t *testing.T) {
+	client := NewTestClient(t)
+
+	ops := createNamedUser(t, client, "ops")
+	alice := createNamedUser(t, client, "alice")
+	bob := createNamedUser(t, client, "bob")
+	carol := createNamedUser(t, client, "carol")
+	makeFriends(t, client, alice, bob)
+
+	// The role is granted when a token is issued, so ops' first token is a regular one
+	client.SetAccessToken(ops.AccessToken)
+	resp := client.Get("/admin/stats")
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
+
+	testAuthService.SetAdmins([]uuid.UUID{uuid.MustParse(ops.User.ID)})
+	t.Cleanup(func() { testAuthService.SetAdmins(nil) })
+
+	login := func(username string, expected int) AuthResponse {
+		t.Helper()
+		client.ClearAccessToken()
+		resp := client.Post("/auth/login", LoginRequest{Username: username, Password: "password123"})
+		client.ExpectStatus(resp, expected)
+		var auth AuthResponse
+		if expected == http.StatusOK {
+			client.ParseJSON(resp, &auth)
+		} else {
+			_ = resp.Body.Close()
+		}
+		return auth
+	}
+	admin := login("ops", http.StatusOK)
+
+	// Bob has a message waiting and carol a friend request
+	client.SetAccessToken(alice.AccessToken)
+	resp = client.Post("/messages", SendMessageRequest{
+		ToUsername:       "bob",
+		EncryptedContent: base64.StdEncoding.EncodeToString([]byte("ciphertext")),
+		ContentType:      "text",
+		Signature:        "c2lnbmF0dXJl",
+	})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var sent MessageResponse
+	client.ParseJSON(resp, &sent)
+
+	resp = client.Post("/friends/request", SendFriendRequest{Username: "carol"})
+	client.ExpectStatus(resp, http.StatusCreated)
+	_ = resp.Body.Close()
+
+	client.SetAccessToken(bob.AccessToken)
+	resp = client.Post("/reports", map[string]interface{}{"username": "alice", "message_id": sent.ID, "reason": "spam"})
+	client.ExpectStatus(resp, http.StatusCreated)
+	var created struct {
+		ID string `json:"id"`
+	}
+	client.ParseJSON(resp, &created)
+
+	getStats := func() models.AdminStats {
+		t.Helper()
+		client.SetAccessToken(admin.AccessToken)
+		resp := client.Get("/admin/stats")
+		client.ExpectStatus(resp, http.StatusOK)
+		var stats models.AdminStats
+		client.ParseJSON(resp, &stats)
+		return stats
+	}
+	stats := getStats()
+	expected := models.AdminStats{
+		Users:                 4,
+		PendingMessages:       1,
+		PendingMessageBytes:   int64(len("ciphertext")),
+		PendingFriendRequests: 1,
+		OpenReports:           1,
+	}
+	if stats != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, stats)
+	}
+
+	// Users can be looked up by user number or ID
+	getUser := func(ref string, expected int) models.AdminUser {
+		t.Helper()
+		client.SetAccessToken(admin.AccessToken)
+		resp := client.Get("/admin/users/" + ref)
+		client.ExpectStatus(resp, expected)
+		var user models.AdminUser
+		if expected == http.StatusOK {
+			client.ParseJSON(resp, &user)
+		} else {
+			_ = resp.Body.Close()
+		}
+		return user
+	}
+	byNumber := getUser(strconv.FormatInt(alice.User.UserNumber, 10), http.StatusOK)
+	byID := getUser(alice.User.ID, http.StatusOK)
+	if byNumber.Username != "alice" || byID.Username != "alice" || byID.Suspended {
+		t.Errorf("Unexpected lookups %+v and %+v", byNumber, byID)
+	}
+	getUser("999999", http.StatusNotFound)
+	getUser(uuid.NewString(), http.StatusNotFound)
+	getUser("alice", http.StatusNotFound)
+
+	// Suspending alice cuts off her existing tokens and her login
+	client.SetAccessToken(admin.AccessToken)
+	resp = client.Post("/admin/users/"+alice.User.ID+"/suspension", map[string]string{"reason": "spam"})
+	client.ExpectStatus(resp, http.StatusOK)
+	var suspended models.AdminUser
+	client.ParseJSON(resp, &suspended)
+	if !suspended.Suspended || suspended.SuspendedAt == nil {
+		t.Errorf("Expected alice to be suspended, got %+v", suspended)
+	}
+
+	client.SetAccessToken(alice.AccessToken)
+	resp = client.Get("/friends")
+	client.ExpectStatus(resp, http.StatusForbidden)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/auth/refresh", map[string]string{"refresh_token": alice.RefreshToken})
+	client.ExpectStatus(resp, http.StatusUnauthorized)
+	_ = resp.Body.Close()
+
+	login("alice", http.StatusForbidden)
+
+	// A wrong password doesn't reveal the suspension
+	client.ClearAccessToken()
+	resp = client.Post("/auth/login", LoginRequest{Username: "alice", Password: "wrongpassword"})
+	client.ExpectStatus(resp, http.StatusUnauthorized)
+	_ = resp.Body.Close()
+
+	if stats := getStats(); stats.SuspendedUsers != 1 {
+		t.Errorf("Expected 1 suspended user, got %d", stats.SuspendedUsers)
+	}
+
+	client.SetAccessToken(admin.AccessToken)
+	resp = client.Post("/admin/users/"+admin.User.ID+"/suspension", nil)
+	client.ExpectStatus(resp, http.StatusBadRequest)
+	_ = resp.Body.Close()
+
+	resp = client.Delete("/admin/users/"+strconv.FormatInt(alice.User.UserNumber, 10)+"/suspension", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	var restored models.AdminUser
+	client.ParseJSON(resp, &restored)
+	if restored.Suspended || restored.SuspendedAt != nil {
+		t.Errorf("Expected alice to be active, got %+v", restored)
+	}
+	login("alice", http.StatusOK)
+
+	// Revoking sessions logs bob out once his access token expires
+	client.SetAccessToken(admin.AccessToken)
+	resp = client.Delete("/admin/users/"+bob.User.ID+"/sessions", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	_ = resp.Body.Close()
+
+	resp = client.Post("/auth/refresh", map[string]string{"refresh_token": bob.RefreshToken})
+	client.ExpectStatus(resp, http.StatusUnauthorized)
+	_ = resp.Body.Close()
+
+	// Reports are reviewed through /admin
+	client.SetAccessToken(admin.AccessToken)
+	resp = client.Get("/admin/reports")
+	client.ExpectStatus(resp, http.StatusOK)
+	var queue []models.Report
+	client.ParseJSON(resp, &queue)
+	if len(queue) != 1 || queue[0].ID.String() != created.ID {
+		t.Fatalf("Expected bob's report in the queue, got %+v", queue)
+	}
+
+	resp = client.Patch("/admin/reports/"+created.ID, map[string]string{"status": "dismissed", "note": "not spam"})
+	client.ExpectStatus(resp, http.StatusOK)
+	var dismissed models.Report
+	client.ParseJSON(resp, &dismissed)
+	if dismissed.Status != models.ReportDismissed || len(dismissed.History) != 2 || dismissed.History[1].ActorID.String() != admin.User.ID {
+		t.Errorf("Unexpected dismissed report %+v", dismissed)
+	}
+
+	resp = client.Patch("/admin/reports/"+created.ID, map[string]string{"status": "reviewing"})
+	client.ExpectStatus(resp, http.StatusConflict)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/admin/reports/" + uuid.NewString())
+	client.ExpectStatus(resp, http.StatusNotFound)
+	_ = resp.Body.Close()
+
+	resp = client.Get("/admin/reports?status=dismissed")
+	client.ExpectStatus(resp, http.StatusOK)
+	client.ParseJSON(resp, &queue)
+	if len(queue) != 1 {
+		t.Errorf("Expected 1 dismissed report, got %d", len(queue))
+	}
+
+	// Everyone else is kept out
+	client.SetAccessToken(carol.AccessToken)
+	for _, path := range []string{"/admin/stats", "/admin/users/" + alice.User.ID, "/admin/reports"} {
+		resp = client.Get(path)
+		client.ExpectStatus(resp, http.StatusForbidden)
+		_ = resp.Body.Close()
+	}
+}
 
 // =============================================================================
 // PROTECTED ROUTES TESTS
@@ -609,9 +3336,23 @@ func TestProtectedRoutes_RequireAuth(t *testing.T) {
 		{"POST", "/friends/request"},
 		{"POST", "/friends/accept"},
 		{"POST", "/friends/reject"},
+		{"GET", "/friends/requests/outgoing"},
+		{"POST", "/friends/cancel"},
+		{"POST", "/friends/invites"},
+		{"POST", "/friends/invites/redeem"},
+		{"GET", "/friends/someuser/safety-number"},
+		{"GET", "/friends/suggestions"},
 		{"GET", "/messages"},
 		{"POST", "/messages"},
+		{"GET", "/devices"},
+		{"POST", "/devices"},
+		{"GET", "/users?q=some"},
 		{"GET", "/users/someuser"},
+		{"GET", "/users/me/blocked"},
+		{"GET", "/users/me/privacy"},
+		{"POST", "/users/someuser/block"},
+		{"POST", "/reports"},
+		{"GET", "/admin/stats"},
 	}
 
 	for _, route := range routes {
@@ -646,11 +3387,7 @@ func TestFullUserJourney(t *testing.T) {
 
 	// 1. Alice registers
 	aliceUsername := uniqueUsername()
-	aliceReq := RegisterRequest{
-		Username:  aliceUsername,
-		Password:  "alicepassword123",
-		PublicKey: fakePublicKey(),
-	}
+	aliceReq := newRegisterRequest(t, client, aliceUsername, "alicepassword123")
 	resp := client.Post("/auth/register", aliceReq)
 	client.ExpectStatus(resp, http.StatusCreated)
 	var aliceAuth AuthResponse
@@ -659,11 +3396,7 @@ func TestFullUserJourney(t *testing.T) {
 
 	// 2. Bob registers
 	bobUsername := uniqueUsername()
-	bobReq := RegisterRequest{
-		Username:  bobUsername,
-		Password:  "bobpassword123",
-		PublicKey: fakePublicKey(),
-	}
+	bobReq := newRegisterRequest(t, client, bobUsername, "bobpassword123")
 	resp = client.Post("/auth/register", bobReq)
 	client.ExpectStatus(resp, http.StatusCreated)
 	var bobAuth AuthResponse
@@ -744,11 +3477,11 @@ func TestFullUserJourney(t *testing.T) {
 // =============================================================================
 
 func createAuthenticatedUser(t *testing.T, client *TestClient) AuthResponse {
-	req := RegisterRequest{
-		Username:  uniqueUsername(),
-		Password:  "password123",
-		PublicKey: fakePublicKey(),
-	}
+	return createNamedUser(t, client, uniqueUsername())
+}
+
+func createNamedUser(t *testing.T, client *TestClient, username string) AuthResponse {
+	req := newRegisterRequest(t, client, username, "password123")
 
 	resp := client.Post("/auth/register", req)
 	if resp.StatusCode != http.StatusCreated {
@@ -757,6 +3490,7 @@ func createAuthenticatedUser(t *testing.T, client *TestClient) AuthResponse {
 
 	var authResp AuthResponse
 	client.ParseJSON(resp, &authResp)
+	authResp.IdentityKey = req.PrivateKey
 	return authResp
 }
 
@@ -2,24 +2,54 @@ package e2e
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/quickpic/server/internal/api"
+	"github.com/quickpic/server/internal/api/middleware"
 	"github.com/quickpic/server/internal/backend"
+	"github.com/quickpic/server/internal/keys"
 	"github.com/quickpic/server/internal/repository/sqlite"
 	"github.com/quickpic/server/internal/services"
+	"github.com/quickpic/server/internal/username"
 )
 
+// testFriendRequestCooldown keeps the rejection cooldown short enough to wait out
+const testFriendRequestCooldown = time.Second
+
+// testFriendRequestTTL lets requests expire within a test
+const testFriendRequestTTL = 3 * time.Second
+
+// testUsernameReservation lets an old username's hold lapse within a test
+const testUsernameReservation = 2 * time.Second
+
+// testStreakDay shortens streak days so a streak can grow and break within a test
+const testStreakDay = 2 * time.Second
+
+// testChallengeRateLimit is high enough for every registration in the suite
+// from one address; TestChallenge_RateLimited checks the limit itself
+const testChallengeRateLimit = 100_000
+
+// testReservedUsername is reserved on top of the built-in list, as with RESERVED_USERNAMES
+const testReservedUsername = "quickpicbot"
+
 var (
-	testServer  *httptest.Server
-	testRouter  *gin.Engine
-	testBackend *sqlite.Backend
+	testServer        *httptest.Server
+	testBackend       *sqlite.Backend
+	testFriendService *services.FriendService
+	testStreakService *services.StreakService
+	testReportService *services.ReportService
+	testAuthService   *services.AuthService
 )
 
 // TestMain sets up and tears down the test environment
@@ -27,43 +57,103 @@ func TestMain(m *testing.M) {
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
 
-	jwtSecret := "test-secret-key-for-testing"
-
 	// Initialize in-memory SQLite backend
-	cfg := backend.DefaultSQLiteConfig(":memory:")
-	result, err := backend.New(cfg)
+	env, err := newTestEnv(":memory:")
 	if err != nil {
-		fmt.Printf("Failed to create test backend: %v\n", err)
+		fmt.Printf("Failed to create test environment: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Store reference to backend for reset functionality
-	testBackend = result.Backend.(*sqlite.Backend)
+	testServer = env.server
+	testBackend = env.backend
+	testFriendService = env.friendService
+	testStreakService = env.streakService
+	testReportService = env.reportService
+	testAuthService = env.authService
+
+	// Run tests
+	code := m.Run()
+
+	// Cleanup
+	env.Close()
+
+	os.Exit(code)
+}
+
+// testEnv is a test server over its own SQLite database, wired like cmd/server
+type testEnv struct {
+	server        *httptest.Server
+	backend       *sqlite.Backend
+	authService   *services.AuthService
+	friendService *services.FriendService
+	streakService *services.StreakService
+	reportService *services.ReportService
+}
+
+func newTestEnv(dataSourceName string) (*testEnv, error) {
+	// Sign with a throwaway Ed25519 key so tests exercise the kid/JWKS path
+	_, signingKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	tokenKeys, err := services.NewTokenKeys(signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token keys: %w", err)
+	}
+
+	cfg := backend.DefaultSQLiteConfig(dataSourceName)
+	result, err := backend.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test backend: %w", err)
+	}
+
+	env := &testEnv{
+		// Kept for reset functionality
+		backend: result.Backend.(*sqlite.Backend),
+	}
 
 	// Initialize services
-	authService := services.NewAuthService(result.Repos.Users, jwtSecret)
-	userService := services.NewUserService(result.Repos.Users)
-	friendService := services.NewFriendService(result.Repos.Friends, result.Repos.Users)
-	messageService := services.NewMessageService(result.Repos.Messages, result.Repos.Friends)
+	notificationService := services.NewNotificationService(result.Repos.Notifications)
+	env.streakService = services.NewStreakService(result.Repos.Streaks, testStreakDay)
+	interactionService := services.NewInteractionService(result.Repos.Interactions)
+	usernamePolicy := username.NewPolicy(testReservedUsername)
+	env.authService = services.NewAuthService(result.Repos.Users, result.Repos.Friends, result.Repos.Audit, notificationService, tokenKeys, services.DefaultPasswordParams(), usernamePolicy)
+	userService := services.NewUserService(result.Repos.Users, result.Repos.Friends, result.Repos.Devices, result.Repos.Blocks, result.Repos.Profiles, result.Repos.Audit, notificationService, usernamePolicy, testUsernameReservation)
+	env.friendService = services.NewFriendService(result.Repos.Friends, result.Repos.Users, result.Repos.Blocks, env.streakService, interactionService, testFriendRequestCooldown, testFriendRequestTTL)
+	messageService := services.NewMessageService(result.Repos.Messages, result.Repos.Friends, result.Repos.Devices, result.Repos.Blocks, env.streakService, interactionService)
+	prekeyService := services.NewPreKeyService(result.Repos.PreKeys, result.Repos.Users, result.Repos.Friends)
+	deviceService := services.NewDeviceService(result.Repos.Devices, result.Repos.Users, result.Repos.Friends, result.Repos.Audit, notificationService)
+	inviteService := services.NewInviteService(result.Repos.Invites, result.Repos.Users, result.Repos.Blocks, tokenKeys)
+	env.reportService = services.NewReportService(result.Repos.Reports, result.Repos.Users, result.Repos.Messages)
+	adminService := services.NewAdminService(result.Repos.Users, result.Repos.Stats, result.Repos.Audit)
 
 	// Initialize router
-	testRouter = gin.New()
-	testRouter.Use(gin.Recovery())
+	router := gin.New()
+	router.Use(gin.Recovery())
 
 	// Setup routes
-	api.SetupRoutes(testRouter, authService, userService, friendService, messageService, result.Repos.Users)
+	api.SetupRoutes(router, api.Services{
+		Auth:          env.authService,
+		Users:         userService,
+		Friends:       env.friendService,
+		Messages:      messageService,
+		Notifications: notificationService,
+		PreKeys:       prekeyService,
+		Devices:       deviceService,
+		Invites:       inviteService,
+		Reports:       env.reportService,
+		Admin:         adminService,
+	}, middleware.NewRateLimiter(testChallengeRateLimit, time.Minute), result.Repos.Users)
 
 	// Create test server
-	testServer = httptest.NewServer(testRouter)
-
-	// Run tests
-	code := m.Run()
+	env.server = httptest.NewServer(router)
 
-	// Cleanup
-	testServer.Close()
-	_ = result.Backend.Close()
+	return env, nil
+}
 
-	os.Exit(code)
+func (e *testEnv) Close() {
+	e.server.Close()
+	_ = e.backend.Close()
 }
 
 // TestClient provides helper methods for making HTTP requests
@@ -103,6 +193,18 @@ func (c *TestClient) Get(path string) *http.Response {
 	return c.request("GET", path, nil)
 }
 
+func (c *TestClient) Put(path string, body interface{}) *http.Response {
+	return c.request("PUT", path, body)
+}
+
+func (c *TestClient) Patch(path string, body interface{}) *http.Response {
+	return c.request("PATCH", path, body)
+}
+
+func (c *TestClient) Delete(path string, body interface{}) *http.Response {
+	return c.request("DELETE", path, body)
+}
+
 func (c *TestClient) request(method, path string, body interface{}) *http.Response {
 	var req *http.Request
 	var err error
@@ -126,6 +228,30 @@ func (c *TestClient) request(method, path string, body interface{}) *http.Respon
 		}
 	}
 
+	return c.do(req)
+}
+
+// PutBytes sends a raw request body, e.g. an avatar blob
+func (c *TestClient) PutBytes(path string, data []byte) *http.Response {
+	req, err := http.NewRequest("PUT", c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		c.t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	return c.do(req)
+}
+
+// GetIfNoneMatch makes a conditional GET for a response cached under etag
+func (c *TestClient) GetIfNoneMatch(path, etag string) *http.Response {
+	req, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		c.t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("If-None-Match", etag)
+	return c.do(req)
+}
+
+func (c *TestClient) do(req *http.Request) *http.Response {
 	if c.accessToken != "" {
 		req.Header.Set("Authorization", "Bearer "+c.accessToken)
 	}
@@ -159,6 +285,16 @@ type RegisterRequest struct {
 	Username  string `json:"username"`
 	Password  string `json:"password"`
 	PublicKey string `json:"public_key"`
+	Nonce     string `json:"nonce"`
+	Signature string `json:"signature"`
+
+	// PrivateKey is the X25519 identity key behind PublicKey
+	PrivateKey [32]byte `json:"-"`
+}
+
+type RegistrationChallenge struct {
+	Nonce     string `json:"nonce"`
+	ExpiresAt string `json:"expires_at"`
 }
 
 type LoginRequest struct {
@@ -171,10 +307,14 @@ type AuthResponse struct {
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
 	User         struct {
-		ID        string `json:"id"`
-		Username  string `json:"username"`
-		PublicKey string `json:"public_key"`
+		ID         string `json:"id"`
+		UserNumber int64  `json:"user_number"`
+		Username   string `json:"username"`
+		PublicKey  string `json:"public_key"`
 	} `json:"user"`
+
+	// IdentityKey is the user's X25519 private key, kept for signing in tests
+	IdentityKey [32]byte `json:"-"`
 }
 
 type SendFriendRequest struct {
@@ -197,18 +337,137 @@ type FriendRequest struct {
 	} `json:"from_user"`
 }
 
+type OutgoingFriendRequest struct {
+	ID       string `json:"id"`
+	ToUserID string `json:"to_user_id"`
+	Status   string `json:"status"`
+	ToUser   struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"to_user"`
+}
+
+type CreateInviteRequest struct {
+	MaxUses        int `json:"max_uses,omitempty"`
+	ExpiresInHours int `json:"expires_in_hours,omitempty"`
+}
+
+type Invite struct {
+	ID        string `json:"id"`
+	MaxUses   int    `json:"max_uses"`
+	Uses      int    `json:"uses"`
+	ExpiresAt string `json:"expires_at"`
+	Revoked   bool   `json:"revoked"`
+	Token     string `json:"token,omitempty"`
+}
+
+type RedeemInviteRequest struct {
+	Token string `json:"token"`
+}
+
 type Friend struct {
+	UserID      string     `json:"user_id"`
+	Username    string     `json:"username"`
+	PublicKey   string     `json:"public_key"`
+	KeyVersion  int        `json:"key_version"`
+	Devices     []Device   `json:"devices"`
+	Since       string     `json:"since"`
+	Verified    bool       `json:"verified"`
+	DisplayName string     `json:"display_name"`
+	Avatar      *AvatarRef `json:"avatar"`
+	FriendSettings
+	Streak
+}
+
+type FriendSettings struct {
+	Nickname   string `json:"nickname"`
+	Muted      bool   `json:"muted"`
+	Pinned     bool   `json:"pinned"`
+	MessageTTL int    `json:"message_ttl"`
+}
+
+type Streak struct {
+	Streak          int        `json:"streak"`
+	StreakExpiresAt *time.Time `json:"streak_expires_at"`
+	StreakWarningAt *time.Time `json:"streak_warning_at"`
+}
+
+type SafetyNumber struct {
+	SafetyNumber string `json:"safety_number"`
+	KeyVersion   int    `json:"key_version"`
+	Verified     bool   `json:"verified"`
+}
+
+type SetVerifiedRequest struct {
+	Verified     bool   `json:"verified"`
+	SafetyNumber string `json:"safety_number,omitempty"`
+}
+
+type BlockedUser struct {
 	UserID    string `json:"user_id"`
 	Username  string `json:"username"`
-	PublicKey string `json:"public_key"`
-	Since     string `json:"since"`
+	BlockedAt string `json:"blocked_at"`
+}
+
+type FriendSuggestion struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	MutualFriends int    `json:"mutual_friends"`
+}
+
+type PrivacySettings struct {
+	ShowInSuggestions bool `json:"show_in_suggestions"`
+	Discoverable      bool `json:"discoverable"`
+}
+
+type UpdatePrivacyRequest struct {
+	ShowInSuggestions *bool `json:"show_in_suggestions,omitempty"`
+	Discoverable      *bool `json:"discoverable,omitempty"`
 }
 
 type SendMessageRequest struct {
-	ToUsername       string `json:"to_username"`
+	ToUsername       string             `json:"to_username"`
+	EncryptedContent string             `json:"encrypted_content,omitempty"`
+	Ciphertexts      []DeviceCiphertext `json:"ciphertexts,omitempty"`
+	ContentType      string             `json:"content_type"`
+	Signature        string             `json:"signature"`
+}
+
+type DeviceCiphertext struct {
+	DeviceID         string `json:"device_id"`
 	EncryptedContent string `json:"encrypted_content"`
-	ContentType      string `json:"content_type"`
-	Signature        string `json:"signature"`
+}
+
+type RegisterDeviceRequest struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+type Device struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+type UserPublic struct {
+	ID          string     `json:"id"`
+	UserNumber  int64      `json:"user_number"`
+	Username    string     `json:"username"`
+	Devices     []Device   `json:"devices"`
+	DisplayName string     `json:"display_name"`
+	Avatar      *AvatarRef `json:"avatar"`
+}
+
+type AvatarRef struct {
+	ID         string `json:"id"`
+	WrappedKey string `json:"wrapped_key"`
+}
+
+type Avatar struct {
+	ID        string `json:"id"`
+	UpdatedAt string `json:"updated_at"`
 }
 
 type MessageResponse struct {
@@ -225,7 +484,63 @@ type Message struct {
 	Signature        string `json:"signature"`
 	CreatedAt        string `json:"created_at"`
 	FromUsername     string `json:"from_username"`
-	FromPublicKey    string `json:"from_public_key"`
+	// RecipientKeyVersion is the key version the message was encrypted to
+	RecipientKeyVersion int    `json:"recipient_key_version"`
+	FromPublicKey       string `json:"from_public_key"`
+	DeviceID            string `json:"device_id"`
+}
+
+type RotatePublicKeyRequest struct {
+	PublicKey       string `json:"public_key"`
+	Signature       string `json:"signature"`
+	NewKeySignature string `json:"new_key_signature"`
+}
+
+type PublicKeyVersion struct {
+	Version    int     `json:"version"`
+	PublicKey  string  `json:"public_key"`
+	ValidFrom  string  `json:"valid_from"`
+	ValidUntil *string `json:"valid_until"`
+}
+
+type SignedPreKey struct {
+	KeyID              uint32 `json:"key_id"`
+	PublicKey          string `json:"public_key"`
+	Signature          string `json:"signature"`
+	IdentityKeyVersion int    `json:"identity_key_version,omitempty"`
+}
+
+type OneTimePreKey struct {
+	KeyID     uint32 `json:"key_id"`
+	PublicKey string `json:"public_key"`
+}
+
+type UploadPreKeysRequest struct {
+	SignedPreKey   SignedPreKey    `json:"signed_prekey"`
+	OneTimePreKeys []OneTimePreKey `json:"one_time_prekeys"`
+}
+
+type PreKeyStatus struct {
+	OneTimePreKeys    int     `json:"one_time_prekeys"`
+	SignedPreKeyID    *uint32 `json:"signed_prekey_id"`
+	SignedPreKeyStale bool    `json:"signed_prekey_stale"`
+}
+
+type PreKeyBundle struct {
+	UserID             string         `json:"user_id"`
+	Username           string         `json:"username"`
+	IdentityKey        string         `json:"identity_key"`
+	IdentityKeyVersion int            `json:"identity_key_version"`
+	SignedPreKey       SignedPreKey   `json:"signed_prekey"`
+	OneTimePreKey      *OneTimePreKey `json:"one_time_prekey"`
+}
+
+type Notification struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	ActorID       string `json:"actor_id"`
+	ActorUsername string `json:"actor_username"`
+	CreatedAt     string `json:"created_at"`
 }
 
 // Helper to generate fake public key (base64 encoded 32 bytes)
@@ -233,6 +548,127 @@ func fakePublicKey() string {
 	return "dGVzdC1wdWJsaWMta2V5LWZvci10ZXN0aW5nLXB1cnBvc2Vz"
 }
 
+// newRegisterRequest generates an X25519 identity key, fetches a registration
+// challenge and signs it, producing a request the server will accept
+func newRegisterRequest(t *testing.T, client *TestClient, username, password string) RegisterRequest {
+	t.Helper()
+
+	var priv [32]byte
+	if _, err := rand.Read(priv[:]); err != nil {
+		t.Fatalf("Failed to generate identity key: %v", err)
+	}
+	pub := keys.PublicKey(priv)
+
+	resp := client.Post("/auth/challenge", nil)
+	client.ExpectStatus(resp, http.StatusOK)
+	var challenge RegistrationChallenge
+	client.ParseJSON(resp, &challenge)
+
+	return RegisterRequest{
+		Username:   username,
+		Password:   password,
+		PublicKey:  base64.StdEncoding.EncodeToString(pub[:]),
+		Nonce:      challenge.Nonce,
+		Signature:  signRegistration(t, priv, challenge.Nonce, username),
+		PrivateKey: priv,
+	}
+}
+
+func signRegistration(t *testing.T, priv [32]byte, nonce, username string) string {
+	t.Helper()
+
+	sig, err := keys.Sign(priv, services.RegistrationMessage(nonce, username))
+	if err != nil {
+		t.Fatalf("Failed to sign registration challenge: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+// newRotateRequest generates a new identity key and signs the rotation
+// statement with both the current and the new key
+func newRotateRequest(t *testing.T, user AuthResponse, currentVersion int) (RotatePublicKeyRequest, [32]byte) {
+	t.Helper()
+
+	var newPriv [32]byte
+	if _, err := rand.Read(newPriv[:]); err != nil {
+		t.Fatalf("Failed to generate identity key: %v", err)
+	}
+	newPub := keys.PublicKey(newPriv)
+	newPubB64 := base64.StdEncoding.EncodeToString(newPub[:])
+
+	message := services.KeyRotationMessage(uuid.MustParse(user.User.ID), currentVersion, newPubB64)
+	oldSig, err := keys.Sign(user.IdentityKey, message)
+	if err != nil {
+		t.Fatalf("Failed to sign rotation: %v", err)
+	}
+	newSig, err := keys.Sign(newPriv, message)
+	if err != nil {
+		t.Fatalf("Failed to sign rotation: %v", err)
+	}
+
+	return RotatePublicKeyRequest{
+		PublicKey:       newPubB64,
+		Signature:       base64.StdEncoding.EncodeToString(oldSig),
+		NewKeySignature: base64.StdEncoding.EncodeToString(newSig),
+	}, newPriv
+}
+
+// newPreKeyUpload generates a signed prekey and count one-time prekeys with
+// IDs starting at firstID, signed by the user's identity key
+func newPreKeyUpload(t *testing.T, user AuthResponse, firstID uint32, count int) UploadPreKeysRequest {
+	t.Helper()
+
+	req := UploadPreKeysRequest{
+		SignedPreKey:   SignedPreKey{KeyID: 1, PublicKey: randomPublicKey(t)},
+		OneTimePreKeys: make([]OneTimePreKey, 0, count),
+	}
+
+	spk, _ := base64.StdEncoding.DecodeString(req.SignedPreKey.PublicKey)
+	sig, err := keys.Sign(user.IdentityKey, spk)
+	if err != nil {
+		t.Fatalf("Failed to sign prekey: %v", err)
+	}
+	req.SignedPreKey.Signature = base64.StdEncoding.EncodeToString(sig)
+
+	for i := 0; i < count; i++ {
+		req.OneTimePreKeys = append(req.OneTimePreKeys, OneTimePreKey{
+			KeyID:     firstID + uint32(i),
+			PublicKey: randomPublicKey(t),
+		})
+	}
+
+	return req
+}
+
+// newDeviceRequest generates a device key and endorses it with the user's
+// identity key
+func newDeviceRequest(t *testing.T, user AuthResponse, name string) RegisterDeviceRequest {
+	t.Helper()
+
+	publicKey := randomPublicKey(t)
+	sig, err := keys.Sign(user.IdentityKey, services.DeviceMessage(uuid.MustParse(user.User.ID), publicKey))
+	if err != nil {
+		t.Fatalf("Failed to sign device key: %v", err)
+	}
+
+	return RegisterDeviceRequest{
+		Name:      name,
+		PublicKey: publicKey,
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}
+}
+
+func randomPublicKey(t *testing.T) string {
+	t.Helper()
+
+	var priv [32]byte
+	if _, err := rand.Read(priv[:]); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	pub := keys.PublicKey(priv)
+	return base64.StdEncoding.EncodeToString(pub[:])
+}
+
 // Helper to generate unique username
 var userCounter = 0
 
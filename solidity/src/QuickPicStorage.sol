@@ -9,7 +9,7 @@ pragma solidity ^0.8.20;
 contract QuickPicStorage {
     // ============ Enums ============
 
-    enum FriendRequestStatus { Pending, Accepted, Rejected }
+    enum FriendRequestStatus { Pending, Accepted, Rejected, Cancelled, Expired }
     enum ContentType { Text, Image }
 
     // ============ Structs ============
@@ -25,12 +25,70 @@ contract QuickPicStorage {
         bool exists;
     }
 
+    struct UsernameReservation {
+        bytes32 userId;          // the user who renamed away from the name
+        uint256 reservedUntil;
+    }
+
+    struct Avatar {
+        bytes32 id;              // SHA-256 of the ciphertext
+        bytes data;              // encrypted image; the key is wrapped per viewer
+        uint256 updatedAt;
+    }
+
+    struct PublicKeyVersion {
+        string publicKey;
+        uint256 validFrom;
+        uint256 validUntil;  // 0 while the key is current
+    }
+
+    struct SignedPreKey {
+        uint256 keyId;
+        string publicKey;
+        string signature;            // XEdDSA signature by the identity key
+        uint256 identityKeyVersion;  // identity key version that signed it
+        uint256 createdAt;
+        bool exists;
+    }
+
+    struct OneTimePreKey {
+        uint256 keyId;
+        string publicKey;
+    }
+
+    struct Device {
+        bytes32 id;
+        bytes32 userId;
+        string name;
+        string publicKey;  // Base64-encoded X25519 public key of this device
+        string signature;  // XEdDSA signature by the account's identity key
+        uint256 createdAt;
+        bool exists;
+    }
+
+    struct DeviceCiphertext {
+        bytes32 deviceId;
+        bytes ciphertext;
+    }
+
     struct FriendRequest {
         bytes32 id;
         bytes32 fromUserId;
         bytes32 toUserId;
         FriendRequestStatus status;
         uint256 createdAt;
+        uint256 updatedAt;  // When the status last changed
+        bool exists;
+    }
+
+    struct Invite {
+        bytes32 id;
+        bytes32 creatorId;
+        uint256 maxUses;
+        uint256 uses;
+        uint256 expiresAt;
+        uint256 createdAt;
+        bool revoked;
         bool exists;
     }
 
@@ -61,33 +119,93 @@ contract QuickPicStorage {
     // User storage
     mapping(bytes32 => User) public users;           // id => User
     mapping(string => bytes32) public usernameToId;  // username => id
+    mapping(bytes32 => bool) public deletedUsers;    // id => tombstone
+    mapping(bytes32 => bytes32) public skeletonToId;  // keccak256(username skeleton) => id, blocks lookalike usernames
+    mapping(bytes32 => bytes32) internal userSkeleton; // id => keccak256(username skeleton)
+    mapping(bytes32 => UsernameReservation) public usernameReservations;  // keccak256(old username skeleton) => hold after a rename
     bytes32[] public userIds;
+    mapping(bytes32 => PublicKeyVersion[]) internal keyHistory;  // id => keys, version = index + 1
+
+    // Prekey storage
+    mapping(bytes32 => SignedPreKey) internal signedPreKeys;     // userId => current signed prekey
+    mapping(bytes32 => OneTimePreKey[]) internal oneTimePreKeys; // userId => unclaimed one-time prekeys
+    mapping(bytes32 => uint256) internal preKeyEpoch;            // userId => bumped when one-time prekeys are replaced
+    mapping(bytes32 => mapping(uint256 => mapping(uint256 => bool))) internal preKeyIdUsed;  // userId => epoch => keyId => used
+
+    // Device storage
+    mapping(bytes32 => Device) public devices;            // id => Device
+    mapping(bytes32 => bytes32[]) internal userDevices;   // userId => deviceIds[]
+
+    // Privacy storage: bit 0 hides the user from friend suggestions, bit 1 from
+    // username search. Zero, the default, means visible everywhere.
+    mapping(bytes32 => uint256) public privacyFlags;  // userId => flags
+
+    // Moderation storage
+    mapping(bytes32 => uint256) internal suspendedAt;  // userId => when an operator suspended them, 0 if active
+
+    // Profile storage
+    mapping(bytes32 => string) public displayNames;  // userId => display name
+    mapping(bytes32 => Avatar) internal avatars;     // userId => encrypted avatar
+    mapping(bytes32 => mapping(bytes32 => mapping(bytes32 => bytes))) internal avatarKeys;  // userId => avatarId => viewerId => wrapped key
+
+    // Block storage
+    mapping(bytes32 => mapping(bytes32 => uint256)) public blockedAt;  // blockerId => blockedId => timestamp, 0 if not blocked
+    mapping(bytes32 => bytes32[]) internal blockedUsers;               // blockerId => blockedIds[]
 
     // Friend request storage
     mapping(bytes32 => FriendRequest) public friendRequests;  // id => FriendRequest
     mapping(bytes32 => mapping(bytes32 => bytes32)) public friendRequestByUsers;  // fromUserId => toUserId => requestId
     mapping(bytes32 => bytes32[]) public pendingRequestsTo;   // toUserId => requestIds[]
+    mapping(bytes32 => bytes32[]) internal requestsFrom;       // fromUserId => requestIds[]
     bytes32[] public friendRequestIds;
+    uint256 public expiryCursor;  // friendRequestIds before this index have been swept
+
+    // Invite storage
+    mapping(bytes32 => Invite) public invites;           // id => Invite
+    mapping(bytes32 => bytes32[]) internal userInvites;  // creatorId => inviteIds[]
 
     // Friendship storage
     mapping(bytes32 => Friendship) public friendships;  // id => Friendship
     mapping(bytes32 => mapping(bytes32 => bytes32)) public friendshipByUsers;  // userAId => userBId => friendshipId
     mapping(bytes32 => bytes32[]) public userFriendships;  // userId => friendshipIds[]
+    mapping(bytes32 => mapping(bytes32 => uint256)) public verifiedKeyVersion;  // userId => friendId => friend's key version the user verified, 0 if none
     bytes32[] public friendshipIds;
 
     // Message storage
     mapping(bytes32 => Message) public messages;  // id => Message
     mapping(bytes32 => bytes32[]) public messagesToUser;  // toUserId => messageIds[]
     mapping(bytes32 => bytes32[]) public messagesFromUser;  // fromUserId => messageIds[]
+    mapping(bytes32 => uint256) public messageKeyVersion;  // id => recipient key version at send time
+    mapping(bytes32 => bytes32[]) internal messageDevices;  // id => devices that haven't acknowledged yet (empty for account-level messages)
+    mapping(bytes32 => mapping(bytes32 => bytes)) internal deliveryCiphertext;  // id => deviceId => ciphertext
     bytes32[] public messageIds;
 
     // ============ Events ============
 
     event UserCreated(bytes32 indexed id, uint256 userNumber, string username);
     event UserUpdated(bytes32 indexed id);
+    event UserDeleted(bytes32 indexed id);
+    event UsernameChanged(bytes32 indexed id, string oldUsername, string newUsername);
+    event PublicKeyRotated(bytes32 indexed id, uint256 version);
+    event SignedPreKeyUpdated(bytes32 indexed userId, uint256 keyId);
+    event OneTimePreKeysAdded(bytes32 indexed userId, uint256 count);
+    event PreKeyClaimed(bytes32 indexed userId, uint256 keyId, string publicKey);
+    event DeviceAdded(bytes32 indexed id, bytes32 indexed userId);
+    event DeviceRemoved(bytes32 indexed id, bytes32 indexed userId);
+    event DeliveryAcknowledged(bytes32 indexed messageId, bytes32 indexed deviceId);
+    event PrivacyFlagsUpdated(bytes32 indexed userId, uint256 flags);
+    event UserSuspended(bytes32 indexed userId, bool suspended);
+    event ProfileUpdated(bytes32 indexed userId);
+    event UserBlocked(bytes32 indexed blockerId, bytes32 indexed blockedId);
+    event UserUnblocked(bytes32 indexed blockerId, bytes32 indexed blockedId);
     event FriendRequestCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId);
     event FriendRequestUpdated(bytes32 indexed id, FriendRequestStatus status);
+    event InviteCreated(bytes32 indexed id, bytes32 indexed creatorId);
+    event InviteRevoked(bytes32 indexed id);
+    event InviteRedeemed(bytes32 indexed id, bytes32 indexed redeemerId);
     event FriendshipCreated(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId);
+    event FriendVerified(bytes32 indexed userId, bytes32 indexed friendId, uint256 keyVersion);
+    event FriendshipRemoved(bytes32 indexed id, bytes32 indexed userAId, bytes32 indexed userBId);
     event MessageCreated(bytes32 indexed id, bytes32 indexed fromUserId, bytes32 indexed toUserId);
     event MessageDeleted(bytes32 indexed id);
 
@@ -110,11 +228,15 @@ contract QuickPicStorage {
     function createUser(
         bytes32 id,
         string calldata username,
+        bytes32 usernameSkeleton,
         string calldata passwordHash,
         string calldata publicKey
     ) external onlyOwner returns (uint256 userNumber) {
         require(!users[id].exists, "User already exists");
+        require(!deletedUsers[id], "User was deleted");
         require(usernameToId[username] == bytes32(0), "Username already taken");
+        require(skeletonToId[usernameSkeleton] == bytes32(0), "Username too similar to an existing one");
+        require(usernameReservations[usernameSkeleton].reservedUntil <= block.timestamp, "Username is reserved");
         require(bytes(username).length > 0, "Username cannot be empty");
 
         userNumber = nextUserNumber++;
@@ -131,7 +253,14 @@ contract QuickPicStorage {
         });
 
         usernameToId[username] = id;
+        skeletonToId[usernameSkeleton] = id;
+        userSkeleton[id] = usernameSkeleton;
         userIds.push(id);
+        keyHistory[id].push(PublicKeyVersion({
+            publicKey: publicKey,
+            validFrom: block.timestamp,
+            validUntil: 0
+        }));
 
         emit UserCreated(id, userNumber, username);
     }
@@ -197,6 +326,228 @@ contract QuickPicStorage {
         emit UserUpdated(id);
     }
 
+    /**
+     * @notice Renames a user, keeping their id and user number. The old name's
+     * skeleton is held for the user until reservedUntil so nobody else can
+     * take it or a lookalike; the user may take back a name held for them.
+     */
+    function changeUsername(
+        bytes32 id,
+        string calldata newUsername,
+        bytes32 newSkeleton,
+        uint256 reservedUntil
+    ) external onlyOwner {
+        User storage user = users[id];
+        require(user.exists, "User not found");
+        require(bytes(newUsername).length > 0, "Username cannot be empty");
+        require(usernameToId[newUsername] == bytes32(0), "Username already taken");
+        require(
+            skeletonToId[newSkeleton] == bytes32(0) || skeletonToId[newSkeleton] == id,
+            "Username too similar to an existing one"
+        );
+        UsernameReservation storage hold = usernameReservations[newSkeleton];
+        require(hold.reservedUntil <= block.timestamp || hold.userId == id, "Username is reserved");
+
+        string memory oldUsername = user.username;
+        bytes32 oldSkeleton = userSkeleton[id];
+
+        delete usernameToId[oldUsername];
+        delete skeletonToId[oldSkeleton];
+        delete usernameReservations[newSkeleton];
+        if (oldSkeleton != bytes32(0) && oldSkeleton != newSkeleton) {
+            usernameReservations[oldSkeleton] = UsernameReservation({userId: id, reservedUntil: reservedUntil});
+        }
+
+        user.username = newUsername;
+        user.updatedAt = block.timestamp;
+        usernameToId[newUsername] = id;
+        skeletonToId[newSkeleton] = id;
+        userSkeleton[id] = newSkeleton;
+
+        emit UsernameChanged(id, oldUsername, newUsername);
+    }
+
+    /**
+     * @notice Replaces the user's public key, closing the validity period of the
+     * current key. expectedVersion guards against concurrent rotations.
+     */
+    function rotatePublicKey(
+        bytes32 id,
+        uint256 expectedVersion,
+        string calldata publicKey
+    ) external onlyOwner returns (uint256 version) {
+        require(users[id].exists, "User not found");
+        require(bytes(publicKey).length > 0, "Public key cannot be empty");
+
+        PublicKeyVersion[] storage history = keyHistory[id];
+        require(history.length == expectedVersion, "Key version mismatch");
+
+        history[history.length - 1].validUntil = block.timestamp;
+        history.push(PublicKeyVersion({
+            publicKey: publicKey,
+            validFrom: block.timestamp,
+            validUntil: 0
+        }));
+        version = history.length;
+
+        users[id].publicKey = publicKey;
+        users[id].updatedAt = block.timestamp;
+
+        emit PublicKeyRotated(id, version);
+        emit UserUpdated(id);
+    }
+
+    function getKeyVersion(bytes32 id) external view returns (uint256) {
+        require(users[id].exists, "User not found");
+        return keyHistory[id].length;
+    }
+
+    function getKeyHistory(bytes32 id) external view returns (
+        string[] memory publicKeys,
+        uint256[] memory validFrom,
+        uint256[] memory validUntil
+    ) {
+        require(users[id].exists, "User not found");
+
+        PublicKeyVersion[] storage history = keyHistory[id];
+        publicKeys = new string[](history.length);
+        validFrom = new uint256[](history.length);
+        validUntil = new uint256[](history.length);
+
+        for (uint256 i = 0; i < history.length; i++) {
+            publicKeys[i] = history[i].publicKey;
+            validFrom[i] = history[i].validFrom;
+            validUntil[i] = history[i].validUntil;
+        }
+    }
+
+    /**
+     * @notice Tombstones a user: clears their data, frees the username and
+     * removes their friendships, friend requests either way and undelivered messages
+     */
+    function deleteUser(bytes32 id) external onlyOwner {
+        User storage user = users[id];
+        require(user.exists, "User not found");
+
+        delete usernameToId[user.username];
+        delete skeletonToId[userSkeleton[id]];
+        delete userSkeleton[id];
+
+        bytes32[] storage fships = userFriendships[id];
+        while (fships.length > 0) {
+            _removeFriendship(fships[fships.length - 1]);
+        }
+
+        _deleteMessages(messagesToUser[id]);
+        _deleteMessages(messagesFromUser[id]);
+
+        // Drop requests both ways, so the other side stops listing them
+        bytes32[] storage outgoing = requestsFrom[id];
+        while (outgoing.length > 0) {
+            _dropRequest(outgoing[outgoing.length - 1]);
+        }
+        bytes32[] storage incoming = pendingRequestsTo[id];
+        while (incoming.length > 0) {
+            _dropRequest(incoming[incoming.length - 1]);
+        }
+        delete keyHistory[id];
+        delete signedPreKeys[id];
+        delete oneTimePreKeys[id];
+        delete privacyFlags[id];
+        delete suspendedAt[id];
+        delete displayNames[id];
+        delete avatars[id];
+
+        bytes32[] storage devs = userDevices[id];
+        for (uint256 i = 0; i < devs.length; i++) {
+            devices[devs[i]].exists = false;
+            emit DeviceRemoved(devs[i], id);
+        }
+        delete userDevices[id];
+
+        user.username = "";
+        user.passwordHash = "";
+        user.publicKey = "";
+        user.updatedAt = block.timestamp;
+        user.exists = false;
+        deletedUsers[id] = true;
+
+        emit UserDeleted(id);
+    }
+
+    function setPrivacyFlags(bytes32 userId, uint256 flags) external onlyOwner {
+        require(users[userId].exists, "User not found");
+        privacyFlags[userId] = flags;
+        emit PrivacyFlagsUpdated(userId, flags);
+    }
+
+    /**
+     * @notice Suspends a user, keeping the time of an existing suspension, or
+     * lifts their suspension
+     */
+    function setSuspended(bytes32 userId, bool suspended) external onlyOwner {
+        require(users[userId].exists, "User not found");
+        if (!suspended) {
+            delete suspendedAt[userId];
+        } else if (suspendedAt[userId] == 0) {
+            suspendedAt[userId] = block.timestamp;
+        }
+        emit UserSuspended(userId, suspended);
+    }
+
+    /**
+     * @notice When the user was suspended, 0 if they are active
+     */
+    function getSuspension(bytes32 userId) external view returns (bool exists, uint256 since) {
+        return (users[userId].exists, suspendedAt[userId]);
+    }
+
+    /**
+     * @notice Aggregate figures for operators. Scans every user, pending
+     * request and message, so it is meant for off-chain calls only. Message
+     * bytes count the ciphertexts still held for devices yet to acknowledge.
+     */
+    function getStats() external view returns (
+        uint256 userCount,
+        uint256 suspendedCount,
+        uint256 pendingMessages,
+        uint256 pendingMessageBytes,
+        uint256 pendingRequests
+    ) {
+        for (uint256 i = 0; i < userIds.length; i++) {
+            if (users[userIds[i]].exists) {
+                userCount++;
+                if (suspendedAt[userIds[i]] != 0) {
+                    suspendedCount++;
+                }
+            }
+        }
+
+        // Requests before the expiry cursor have all been answered or expired
+        for (uint256 i = expiryCursor; i < friendRequestIds.length; i++) {
+            FriendRequest storage request = friendRequests[friendRequestIds[i]];
+            if (
+                request.exists && request.status == FriendRequestStatus.Pending
+                    && users[request.fromUserId].exists && users[request.toUserId].exists
+            ) {
+                pendingRequests++;
+            }
+        }
+
+        for (uint256 i = 0; i < messageIds.length; i++) {
+            bytes32 id = messageIds[i];
+            if (!messages[id].exists) {
+                continue;
+            }
+            pendingMessages++;
+            pendingMessageBytes += messages[id].encryptedContent.length;
+            bytes32[] storage devs = messageDevices[id];
+            for (uint256 j = 0; j < devs.length; j++) {
+                pendingMessageBytes += deliveryCiphertext[id][devs[j]].length;
+            }
+        }
+    }
+
     function userExists(bytes32 id) external view returns (bool) {
         return users[id].exists;
     }
@@ -209,6 +560,261 @@ contract QuickPicStorage {
         return userIds.length;
     }
 
+    // ============ Profile Functions ============
+
+    function setDisplayName(bytes32 userId, string calldata displayName) external onlyOwner {
+        require(users[userId].exists, "User not found");
+        displayNames[userId] = displayName;
+        users[userId].updatedAt = block.timestamp;
+        emit ProfileUpdated(userId);
+    }
+
+    /**
+     * @notice Replaces the user's avatar. Keys are stored per avatar id, so
+     * keys wrapped for the previous avatar stop applying.
+     */
+    function setAvatar(bytes32 userId, bytes32 avatarId, bytes calldata data) external onlyOwner {
+        require(users[userId].exists, "User not found");
+        require(avatarId != bytes32(0), "Avatar id cannot be empty");
+        avatars[userId] = Avatar({id: avatarId, data: data, updatedAt: block.timestamp});
+        emit ProfileUpdated(userId);
+    }
+
+    function deleteAvatar(bytes32 userId) external onlyOwner {
+        require(avatars[userId].id != bytes32(0), "Avatar not found");
+        delete avatars[userId];
+        emit ProfileUpdated(userId);
+    }
+
+    function setAvatarKeys(
+        bytes32 userId,
+        bytes32 avatarId,
+        bytes32[] calldata viewerIds,
+        bytes[] calldata wrappedKeys
+    ) external onlyOwner {
+        require(viewerIds.length == wrappedKeys.length, "Length mismatch");
+        require(avatarId != bytes32(0) && avatars[userId].id == avatarId, "Avatar changed");
+        for (uint256 i = 0; i < viewerIds.length; i++) {
+            require(users[viewerIds[i]].exists, "User not found");
+            avatarKeys[userId][avatarId][viewerIds[i]] = wrappedKeys[i];
+        }
+    }
+
+    function getAvatar(bytes32 userId) external view returns (bytes32 id, bytes memory data, uint256 updatedAt) {
+        Avatar storage avatar = avatars[userId];
+        require(avatar.id != bytes32(0), "Avatar not found");
+        return (avatar.id, avatar.data, avatar.updatedAt);
+    }
+
+    /**
+     * @notice Returns the user's profile as seen by viewerId. avatarId is zero
+     * unless the current avatar's key has been wrapped for the viewer.
+     */
+    function getProfile(bytes32 userId, bytes32 viewerId) external view returns (
+        string memory displayName,
+        bytes32 avatarId,
+        bytes memory wrappedKey
+    ) {
+        require(users[userId].exists, "User not found");
+        displayName = displayNames[userId];
+        bytes32 current = avatars[userId].id;
+        if (current != bytes32(0)) {
+            wrappedKey = avatarKeys[userId][current][viewerId];
+            if (wrappedKey.length > 0) {
+                avatarId = current;
+            }
+        }
+    }
+
+    // ============ Prekey Functions ============
+
+    function setSignedPreKey(
+        bytes32 userId,
+        uint256 keyId,
+        string calldata publicKey,
+        string calldata signature,
+        uint256 identityKeyVersion
+    ) external onlyOwner {
+        require(users[userId].exists, "User not found");
+
+        signedPreKeys[userId] = SignedPreKey({
+            keyId: keyId,
+            publicKey: publicKey,
+            signature: signature,
+            identityKeyVersion: identityKeyVersion,
+            createdAt: block.timestamp,
+            exists: true
+        });
+
+        emit SignedPreKeyUpdated(userId, keyId);
+    }
+
+    /**
+     * @notice Appends one-time prekeys. With replace set, unclaimed one-time
+     * prekeys are discarded first. Key IDs may not repeat until the next replace.
+     */
+    function addOneTimePreKeys(
+        bytes32 userId,
+        uint256[] calldata keyIds,
+        string[] calldata publicKeys,
+        bool replace
+    ) external onlyOwner {
+        require(users[userId].exists, "User not found");
+        require(keyIds.length == publicKeys.length, "Prekey length mismatch");
+
+        if (replace) {
+            delete oneTimePreKeys[userId];
+            preKeyEpoch[userId]++;
+        }
+
+        uint256 epoch = preKeyEpoch[userId];
+        for (uint256 i = 0; i < keyIds.length; i++) {
+            require(!preKeyIdUsed[userId][epoch][keyIds[i]], "Prekey ID already used");
+            preKeyIdUsed[userId][epoch][keyIds[i]] = true;
+            oneTimePreKeys[userId].push(OneTimePreKey({
+                keyId: keyIds[i],
+                publicKey: publicKeys[i]
+            }));
+        }
+
+        emit OneTimePreKeysAdded(userId, keyIds.length);
+    }
+
+    function getSignedPreKey(bytes32 userId) external view returns (
+        uint256 keyId,
+        string memory publicKey,
+        string memory signature,
+        uint256 identityKeyVersion,
+        uint256 createdAt
+    ) {
+        SignedPreKey storage key = signedPreKeys[userId];
+        require(key.exists, "Signed prekey not found");
+
+        return (key.keyId, key.publicKey, key.signature, key.identityKeyVersion, key.createdAt);
+    }
+
+    function getOneTimePreKeyCount(bytes32 userId) external view returns (uint256) {
+        return oneTimePreKeys[userId].length;
+    }
+
+    /**
+     * @notice Removes and returns one unclaimed one-time prekey. The claimed
+     * key is reported through PreKeyClaimed; no event means none were left.
+     */
+    function claimOneTimePreKey(bytes32 userId) external onlyOwner returns (bool claimed, uint256 keyId, string memory publicKey) {
+        require(users[userId].exists, "User not found");
+
+        OneTimePreKey[] storage keys = oneTimePreKeys[userId];
+        if (keys.length == 0) {
+            return (false, 0, "");
+        }
+
+        OneTimePreKey storage last = keys[keys.length - 1];
+        keyId = last.keyId;
+        publicKey = last.publicKey;
+        keys.pop();
+
+        emit PreKeyClaimed(userId, keyId, publicKey);
+        return (true, keyId, publicKey);
+    }
+
+    // ============ Device Functions ============
+
+    function addDevice(
+        bytes32 id,
+        bytes32 userId,
+        string calldata name,
+        string calldata publicKey,
+        string calldata signature
+    ) external onlyOwner {
+        require(users[userId].exists, "User not found");
+        require(!devices[id].exists, "Device already exists");
+
+        bytes32[] storage devs = userDevices[userId];
+        for (uint256 i = 0; i < devs.length; i++) {
+            require(keccak256(bytes(devices[devs[i]].publicKey)) != keccak256(bytes(publicKey)), "Device key already registered");
+        }
+
+        devices[id] = Device({
+            id: id,
+            userId: userId,
+            name: name,
+            publicKey: publicKey,
+            signature: signature,
+            createdAt: block.timestamp,
+            exists: true
+        });
+        devs.push(id);
+
+        emit DeviceAdded(id, userId);
+    }
+
+    /**
+     * @notice Removes a device and drops its undelivered ciphertexts; messages
+     * left with no pending devices are deleted
+     */
+    function removeDevice(bytes32 id) external onlyOwner {
+        Device storage device = devices[id];
+        require(device.exists, "Device not found");
+
+        bytes32 userId = device.userId;
+        bytes32[] storage inbox = messagesToUser[userId];
+        for (uint256 i = 0; i < inbox.length; i++) {
+            if (messages[inbox[i]].exists && deliveryCiphertext[inbox[i]][id].length > 0) {
+                _acknowledgeDelivery(inbox[i], id);
+            }
+        }
+
+        _removeFromArray(userDevices[userId], id);
+        device.exists = false;
+
+        emit DeviceRemoved(id, userId);
+    }
+
+    function getDevices(bytes32 userId) external view returns (bytes32[] memory) {
+        return userDevices[userId];
+    }
+
+    // ============ Block Functions ============
+
+    /**
+     * @notice Blocks a user: ends any friendship, drops requests in either
+     * direction and deletes undelivered messages between the pair
+     */
+    function blockUser(bytes32 blockerId, bytes32 blockedId) external onlyOwner {
+        require(users[blockerId].exists && users[blockedId].exists, "User not found");
+        require(blockerId != blockedId, "Cannot block self");
+        require(blockedAt[blockerId][blockedId] == 0, "Already blocked");
+
+        blockedAt[blockerId][blockedId] = block.timestamp;
+        blockedUsers[blockerId].push(blockedId);
+
+        (bytes32 userA, bytes32 userB) = _orderUserIds(blockerId, blockedId);
+        bytes32 friendshipId = friendshipByUsers[userA][userB];
+        if (friendshipId != bytes32(0)) {
+            _removeFriendship(friendshipId);
+        }
+        _clearRequest(blockerId, blockedId);
+        _clearRequest(blockedId, blockerId);
+        _deleteMessagesFrom(messagesToUser[blockerId], blockedId);
+        _deleteMessagesFrom(messagesToUser[blockedId], blockerId);
+
+        emit UserBlocked(blockerId, blockedId);
+    }
+
+    function unblockUser(bytes32 blockerId, bytes32 blockedId) external onlyOwner {
+        require(blockedAt[blockerId][blockedId] != 0, "Not blocked");
+
+        delete blockedAt[blockerId][blockedId];
+        _removeFromArray(blockedUsers[blockerId], blockedId);
+
+        emit UserUnblocked(blockerId, blockedId);
+    }
+
+    function getBlockedUsers(bytes32 blockerId) external view returns (bytes32[] memory) {
+        return blockedUsers[blockerId];
+    }
+
     // ============ Friend Request Functions ============
 
     function createFriendRequest(
@@ -220,7 +826,20 @@ contract QuickPicStorage {
         require(users[fromUserId].exists, "From user not found");
         require(users[toUserId].exists, "To user not found");
         require(fromUserId != toUserId, "Cannot send request to self");
-        require(friendRequestByUsers[fromUserId][toUserId] == bytes32(0), "Request already exists");
+
+        // An answered request may be replaced by a new one; the cooldown
+        // between the two is enforced by the server
+        bytes32 existing = friendRequestByUsers[fromUserId][toUserId];
+        require(
+            existing == bytes32(0) || friendRequests[existing].status != FriendRequestStatus.Pending,
+            "Request already exists"
+        );
+        bytes32 reverse = friendRequestByUsers[toUserId][fromUserId];
+        require(
+            reverse == bytes32(0) || friendRequests[reverse].status != FriendRequestStatus.Pending,
+            "Request already exists"
+        );
+        _clearRequest(fromUserId, toUserId);
 
         // Check if already friends
         (bytes32 userA, bytes32 userB) = _orderUserIds(fromUserId, toUserId);
@@ -232,11 +851,13 @@ contract QuickPicStorage {
             toUserId: toUserId,
             status: FriendRequestStatus.Pending,
             createdAt: block.timestamp,
+            updatedAt: block.timestamp,
             exists: true
         });
 
         friendRequestByUsers[fromUserId][toUserId] = id;
         pendingRequestsTo[toUserId].push(id);
+        requestsFrom[fromUserId].push(id);
         friendRequestIds.push(id);
 
         emit FriendRequestCreated(id, fromUserId, toUserId);
@@ -247,7 +868,8 @@ contract QuickPicStorage {
         bytes32 fromUserId,
         bytes32 toUserId,
         FriendRequestStatus status,
-        uint256 createdAt
+        uint256 createdAt,
+        uint256 updatedAt
     ) {
         FriendRequest storage request = friendRequests[id];
         require(request.exists, "Friend request not found");
@@ -257,25 +879,67 @@ contract QuickPicStorage {
             request.fromUserId,
             request.toUserId,
             request.status,
-            request.createdAt
+            request.createdAt,
+            request.updatedAt
         );
     }
 
+    /**
+     * @notice Only open requests change: a pending one can be answered, and a
+     * rejected one can still be cancelled or expire. Of two racing answers the
+     * second reverts.
+     */
     function updateFriendRequestStatus(bytes32 id, FriendRequestStatus status) external onlyOwner {
         require(friendRequests[id].exists, "Friend request not found");
+        FriendRequestStatus current = friendRequests[id].status;
+        require(
+            current == FriendRequestStatus.Pending ||
+                (current == FriendRequestStatus.Rejected &&
+                    (status == FriendRequestStatus.Cancelled || status == FriendRequestStatus.Expired)),
+            "Friend request not open"
+        );
+
+        if (status == FriendRequestStatus.Expired) {
+            _expireRequest(id);
+            return;
+        }
 
         friendRequests[id].status = status;
+        friendRequests[id].updatedAt = block.timestamp;
 
         emit FriendRequestUpdated(id, status);
     }
 
-    function getPendingRequestsForUser(bytes32 userId) external view returns (bytes32[] memory) {
+    /**
+     * @notice Accepts a pending request and creates the friendship in the
+     * same transaction
+     */
+    function acceptFriendRequest(bytes32 id, bytes32 friendshipId) external onlyOwner {
+        FriendRequest storage request = friendRequests[id];
+        require(request.exists, "Friend request not found");
+        require(request.status == FriendRequestStatus.Pending, "Friend request not open");
+
+        request.status = FriendRequestStatus.Accepted;
+        request.updatedAt = block.timestamp;
+        emit FriendRequestUpdated(id, FriendRequestStatus.Accepted);
+
+        (bytes32 userA, bytes32 userB) = _orderUserIds(request.fromUserId, request.toUserId);
+        if (friendshipByUsers[userA][userB] == bytes32(0)) {
+            _createFriendship(friendshipId, userA, userB);
+        }
+    }
+
+    /**
+     * @notice Pending requests to the user created after createdAfter; older
+     * ones have expired even if no sweep has marked them yet
+     */
+    function getPendingRequestsForUser(bytes32 userId, uint256 createdAfter) external view returns (bytes32[] memory) {
         bytes32[] storage allRequests = pendingRequestsTo[userId];
 
         // Count pending requests
         uint256 pendingCount = 0;
         for (uint256 i = 0; i < allRequests.length; i++) {
-            if (friendRequests[allRequests[i]].status == FriendRequestStatus.Pending) {
+            if (_isPendingAfter(allRequests[i], createdAfter)) {
                 pendingCount++;
             }
         }
@@ -284,7 +948,7 @@ contract QuickPicStorage {
         bytes32[] memory result = new bytes32[](pendingCount);
         uint256 resultIndex = 0;
         for (uint256 i = 0; i < allRequests.length; i++) {
-            if (friendRequests[allRequests[i]].status == FriendRequestStatus.Pending) {
+            if (_isPendingAfter(allRequests[i], createdAfter)) {
                 result[resultIndex++] = allRequests[i];
             }
         }
@@ -292,6 +956,104 @@ contract QuickPicStorage {
         return result;
     }
 
+    /**
+     * @notice Sweeps up to limit requests created before createdBefore, oldest
+     * first. Pending and rejected ones become Expired; every swept request is
+     * dropped from the per-user lists so they stop growing.
+     */
+    function expireFriendRequests(uint256 createdBefore, uint256 limit) external onlyOwner {
+        uint256 cursor = expiryCursor;
+        uint256 end = friendRequestIds.length;
+        for (uint256 swept = 0; swept < limit && cursor < end; swept++) {
+            FriendRequest storage request = friendRequests[friendRequestIds[cursor]];
+            if (request.createdAt >= createdBefore) {
+                break;
+            }
+            if (request.exists) {
+                _expireRequest(request.id);
+            }
+            cursor++;
+        }
+        expiryCursor = cursor;
+    }
+
+    function hasExpiredFriendRequests(uint256 createdBefore) external view returns (bool) {
+        return expiryCursor < friendRequestIds.length
+            && friendRequests[friendRequestIds[expiryCursor]].createdAt < createdBefore;
+    }
+
+    /**
+     * @notice Requests the user has sent that are still on record, in any
+     * status; callers filter by status
+     */
+    function getRequestsFromUser(bytes32 userId) external view returns (bytes32[] memory) {
+        return requestsFrom[userId];
+    }
+
+    // ============ Invite Functions ============
+
+    function createInvite(
+        bytes32 id,
+        bytes32 creatorId,
+        uint256 maxUses,
+        uint256 expiresAt
+    ) external onlyOwner {
+        require(!invites[id].exists, "Invite already exists");
+        require(users[creatorId].exists, "User not found");
+        require(maxUses > 0, "Invalid max uses");
+
+        invites[id] = Invite({
+            id: id,
+            creatorId: creatorId,
+            maxUses: maxUses,
+            uses: 0,
+            expiresAt: expiresAt,
+            createdAt: block.timestamp,
+            revoked: false,
+            exists: true
+        });
+        userInvites[creatorId].push(id);
+
+        emit InviteCreated(id, creatorId);
+    }
+
+    function revokeInvite(bytes32 id) external onlyOwner {
+        require(invites[id].exists, "Invite not found");
+
+        invites[id].revoked = true;
+
+        emit InviteRevoked(id);
+    }
+
+    function getUserInvites(bytes32 creatorId) external view returns (bytes32[] memory) {
+        return userInvites[creatorId];
+    }
+
+    /**
+     * @notice Uses up one redemption of an invite and makes the redeemer and
+     * the creator friends. Pending requests between the two are accepted.
+     */
+    function redeemInvite(bytes32 id, bytes32 redeemerId, bytes32 friendshipId) external onlyOwner {
+        Invite storage invite = invites[id];
+        require(invite.exists, "Invite not found");
+        require(
+            !invite.revoked && invite.uses < invite.maxUses && block.timestamp < invite.expiresAt,
+            "Invite expired"
+        );
+        require(users[redeemerId].exists, "User not found");
+        require(invite.creatorId != redeemerId, "Cannot redeem own invite");
+
+        (bytes32 userA, bytes32 userB) = _orderUserIds(invite.creatorId, redeemerId);
+        require(friendshipByUsers[userA][userB] == bytes32(0), "Already friends");
+
+        invite.uses++;
+        _createFriendship(friendshipId, userA, userB);
+        _acceptPendingRequest(userA, userB);
+        _acceptPendingRequest(userB, userA);
+
+        emit InviteRedeemed(id, redeemerId);
+    }
+
     // ============ Friendship Functions ============
 
     function createFriendship(
@@ -299,6 +1061,10 @@ contract QuickPicStorage {
         bytes32 userAId,
         bytes32 userBId
     ) external onlyOwner {
+        _createFriendship(id, userAId, userBId);
+    }
+
+    function _createFriendship(bytes32 id, bytes32 userAId, bytes32 userBId) internal {
         require(!friendships[id].exists, "Friendship already exists");
         require(users[userAId].exists, "User A not found");
         require(users[userBId].exists, "User B not found");
@@ -323,6 +1089,23 @@ contract QuickPicStorage {
         emit FriendshipCreated(id, orderedA, orderedB);
     }
 
+    /**
+     * @notice Ends a friendship: clears the friendship indexes, forgets the
+     * requests between the pair so they can send a new one, and deletes
+     * their undelivered messages to each other
+     */
+    function removeFriendship(bytes32 userId1, bytes32 userId2) external onlyOwner {
+        (bytes32 userA, bytes32 userB) = _orderUserIds(userId1, userId2);
+        bytes32 id = friendshipByUsers[userA][userB];
+        require(id != bytes32(0), "Friendship not found");
+
+        _removeFriendship(id);
+        _clearRequest(userA, userB);
+        _clearRequest(userB, userA);
+        _deleteMessagesFrom(messagesToUser[userA], userB);
+        _deleteMessagesFrom(messagesToUser[userB], userA);
+    }
+
     function getFriendship(bytes32 id) external view returns (
         bytes32 friendshipId,
         bytes32 userAId,
@@ -345,6 +1128,17 @@ contract QuickPicStorage {
         return friendshipByUsers[userA][userB] != bytes32(0);
     }
 
+    /**
+     * @notice Records which of the friend's key versions the user compared
+     * safety numbers for. The friend counts as verified only while that is
+     * still their current version, so a key rotation resets it.
+     */
+    function setVerifiedKeyVersion(bytes32 userId, bytes32 friendId, uint256 keyVersion) external onlyOwner {
+        verifiedKeyVersion[userId][friendId] = keyVersion;
+
+        emit FriendVerified(userId, friendId, keyVersion);
+    }
+
     function getUserFriendships(bytes32 userId) external view returns (bytes32[] memory) {
         return userFriendships[userId];
     }
@@ -388,6 +1182,52 @@ contract QuickPicStorage {
             exists: true
         });
 
+        messageKeyVersion[id] = keyHistory[toUserId].length;
+        messagesToUser[toUserId].push(id);
+        messagesFromUser[fromUserId].push(id);
+        messageIds.push(id);
+
+        emit MessageCreated(id, fromUserId, toUserId);
+    }
+
+    /**
+     * @notice Stores a message as one ciphertext per recipient device. Every
+     * current device of the recipient must be covered exactly once.
+     */
+    function createDeviceMessage(
+        bytes32 id,
+        bytes32 fromUserId,
+        bytes32 toUserId,
+        ContentType contentType,
+        string calldata signature,
+        DeviceCiphertext[] calldata deliveries
+    ) external onlyOwner {
+        require(!messages[id].exists, "Message already exists");
+        require(users[fromUserId].exists, "From user not found");
+        require(users[toUserId].exists, "To user not found");
+        require(deliveries.length > 0 && deliveries.length == userDevices[toUserId].length, "Device list mismatch");
+
+        messages[id] = Message({
+            id: id,
+            fromUserId: fromUserId,
+            toUserId: toUserId,
+            encryptedContent: "",
+            contentType: contentType,
+            signature: signature,
+            createdAt: block.timestamp,
+            exists: true
+        });
+
+        for (uint256 i = 0; i < deliveries.length; i++) {
+            bytes32 deviceId = deliveries[i].deviceId;
+            require(devices[deviceId].exists && devices[deviceId].userId == toUserId, "Device list mismatch");
+            require(deliveryCiphertext[id][deviceId].length == 0, "Device list mismatch");
+            require(deliveries[i].ciphertext.length > 0, "Empty ciphertext");
+            deliveryCiphertext[id][deviceId] = deliveries[i].ciphertext;
+            messageDevices[id].push(deviceId);
+        }
+
+        messageKeyVersion[id] = keyHistory[toUserId].length;
         messagesToUser[toUserId].push(id);
         messagesFromUser[fromUserId].push(id);
         messageIds.push(id);
@@ -395,6 +1235,33 @@ contract QuickPicStorage {
         emit MessageCreated(id, fromUserId, toUserId);
     }
 
+    function getMessageDevices(bytes32 id) external view returns (bytes32[] memory) {
+        return messageDevices[id];
+    }
+
+    function getDeliveryCiphertext(bytes32 id, bytes32 deviceId) external view returns (bytes memory) {
+        require(messages[id].exists && deliveryCiphertext[id][deviceId].length > 0, "Delivery not found");
+        return deliveryCiphertext[id][deviceId];
+    }
+
+    /**
+     * @notice Acknowledges a message on one device. Account-level messages are
+     * deleted outright; per-device messages are deleted once every device has
+     * acknowledged.
+     */
+    function acknowledgeDelivery(bytes32 id, bytes32 deviceId) external onlyOwner {
+        require(messages[id].exists, "Message not found");
+
+        if (messageDevices[id].length == 0) {
+            messages[id].exists = false;
+            emit MessageDeleted(id);
+            return;
+        }
+
+        require(deliveryCiphertext[id][deviceId].length > 0, "Delivery not found");
+        _acknowledgeDelivery(id, deviceId);
+    }
+
     function getMessage(bytes32 id) external view returns (
         bytes32 messageId,
         bytes32 fromUserId,
@@ -449,6 +1316,31 @@ contract QuickPicStorage {
         return result;
     }
 
+    /**
+     * @notice Messages a device still has to fetch: account-level messages
+     * plus per-device messages it hasn't acknowledged
+     */
+    function getMessagesForDevice(bytes32 userId, bytes32 deviceId) external view returns (bytes32[] memory) {
+        bytes32[] storage allMessages = messagesToUser[userId];
+
+        uint256 count = 0;
+        for (uint256 i = 0; i < allMessages.length; i++) {
+            if (_pendingForDevice(allMessages[i], deviceId)) {
+                count++;
+            }
+        }
+
+        bytes32[] memory result = new bytes32[](count);
+        uint256 resultIndex = 0;
+        for (uint256 i = 0; i < allMessages.length; i++) {
+            if (_pendingForDevice(allMessages[i], deviceId)) {
+                result[resultIndex++] = allMessages[i];
+            }
+        }
+
+        return result;
+    }
+
     function getMessagesSentByUser(bytes32 userId) external view returns (bytes32[] memory) {
         bytes32[] storage allMessages = messagesFromUser[userId];
 
@@ -474,6 +1366,123 @@ contract QuickPicStorage {
 
     // ============ Internal Functions ============
 
+    function _removeFriendship(bytes32 id) internal {
+        Friendship storage friendship = friendships[id];
+
+        delete friendshipByUsers[friendship.userAId][friendship.userBId];
+        _removeFromArray(userFriendships[friendship.userAId], id);
+        _removeFromArray(userFriendships[friendship.userBId], id);
+        friendship.exists = false;
+
+        // Verifications and wrapped avatar keys must not carry over to a later friendship
+        bytes32 userA = friendship.userAId;
+        bytes32 userB = friendship.userBId;
+        delete verifiedKeyVersion[userA][userB];
+        delete verifiedKeyVersion[userB][userA];
+        delete avatarKeys[userA][avatars[userA].id][userB];
+        delete avatarKeys[userB][avatars[userB].id][userA];
+
+        emit FriendshipRemoved(id, friendship.userAId, friendship.userBId);
+    }
+
+    function _deleteMessages(bytes32[] storage ids) internal {
+        for (uint256 i = 0; i < ids.length; i++) {
+            if (messages[ids[i]].exists) {
+                messages[ids[i]].exists = false;
+                emit MessageDeleted(ids[i]);
+            }
+        }
+    }
+
+    function _acknowledgeDelivery(bytes32 id, bytes32 deviceId) internal {
+        delete deliveryCiphertext[id][deviceId];
+        _removeFromArray(messageDevices[id], deviceId);
+        emit DeliveryAcknowledged(id, deviceId);
+
+        if (messageDevices[id].length == 0) {
+            messages[id].exists = false;
+            emit MessageDeleted(id);
+        }
+    }
+
+    function _pendingForDevice(bytes32 id, bytes32 deviceId) internal view returns (bool) {
+        if (!messages[id].exists) {
+            return false;
+        }
+        return messageDevices[id].length == 0 || deliveryCiphertext[id][deviceId].length > 0;
+    }
+
+    function _acceptPendingRequest(bytes32 fromUserId, bytes32 toUserId) internal {
+        bytes32 id = friendRequestByUsers[fromUserId][toUserId];
+        if (id == bytes32(0) || friendRequests[id].status != FriendRequestStatus.Pending) {
+            return;
+        }
+
+        friendRequests[id].status = FriendRequestStatus.Accepted;
+        friendRequests[id].updatedAt = block.timestamp;
+        emit FriendRequestUpdated(id, FriendRequestStatus.Accepted);
+    }
+
+    function _isPendingAfter(bytes32 id, uint256 createdAfter) internal view returns (bool) {
+        FriendRequest storage request = friendRequests[id];
+        return request.status == FriendRequestStatus.Pending && request.createdAt > createdAfter;
+    }
+
+    // Keeps friendRequestByUsers so the cooldown still applies to the pair
+    function _expireRequest(bytes32 id) internal {
+        FriendRequest storage request = friendRequests[id];
+        _removeFromArray(pendingRequestsTo[request.toUserId], id);
+        _removeFromArray(requestsFrom[request.fromUserId], id);
+
+        if (request.status == FriendRequestStatus.Pending || request.status == FriendRequestStatus.Rejected) {
+            request.status = FriendRequestStatus.Expired;
+            request.updatedAt = block.timestamp;
+            emit FriendRequestUpdated(id, FriendRequestStatus.Expired);
+        }
+    }
+
+    function _clearRequest(bytes32 fromUserId, bytes32 toUserId) internal {
+        bytes32 id = friendRequestByUsers[fromUserId][toUserId];
+        if (id == bytes32(0)) {
+            return;
+        }
+
+        _removeFromArray(pendingRequestsTo[toUserId], id);
+        _removeFromArray(requestsFrom[fromUserId], id);
+        delete friendRequestByUsers[fromUserId][toUserId];
+        friendRequests[id].exists = false;
+    }
+
+    function _dropRequest(bytes32 id) internal {
+        FriendRequest storage request = friendRequests[id];
+        _removeFromArray(pendingRequestsTo[request.toUserId], id);
+        _removeFromArray(requestsFrom[request.fromUserId], id);
+        if (friendRequestByUsers[request.fromUserId][request.toUserId] == id) {
+            delete friendRequestByUsers[request.fromUserId][request.toUserId];
+        }
+        request.exists = false;
+    }
+
+    function _deleteMessagesFrom(bytes32[] storage ids, bytes32 fromUserId) internal {
+        for (uint256 i = 0; i < ids.length; i++) {
+            Message storage message = messages[ids[i]];
+            if (message.exists && message.fromUserId == fromUserId) {
+                message.exists = false;
+                emit MessageDeleted(ids[i]);
+            }
+        }
+    }
+
+    function _removeFromArray(bytes32[] storage arr, bytes32 value) internal {
+        for (uint256 i = 0; i < arr.length; i++) {
+            if (arr[i] == value) {
+                arr[i] = arr[arr.length - 1];
+                arr.pop();
+                return;
+            }
+        }
+    }
+
     function _orderUserIds(bytes32 id1, bytes32 id2) internal pure returns (bytes32, bytes32) {
         if (id1 < id2) {
             return (id1, id2);